@@ -25,6 +25,11 @@ func main() {
 	// Load config from environment
 	cfg := config.Load()
 
+	// Subcommands share the configuration and exit when done
+	if len(os.Args) > 1 && os.Args[1] == "reprocess" {
+		os.Exit(runReprocess(cfg, os.Args[2:]))
+	}
+
 	log.Printf("config: %+v", cfg)
 
 	// Initialize database
@@ -64,6 +69,9 @@ func main() {
 		StoragePath:   cfg.DataDir,
 		TempUploadDir: cfg.TempUploadDir,
 		Interval:      cfg.JanitorInterval,
+		TrashRetention: cfg.TrashRetention,
+		FailedJobRetention: cfg.FailedJobRetention,
+		ShareLinkRetention: cfg.ShareLinkRetention,
 	})
 	ctx, cancel := context.WithCancel(context.Background())
 	defer cancel()
@@ -84,6 +92,9 @@ func main() {
 		IdleTimeout:  120 * time.Second,
 	}
 
+	// End live progress streams so Shutdown doesn't wait on them
+	srv.RegisterOnShutdown(bgWorker.Events().Close)
+
 	// Graceful shutdown
 	go func() {
 		log.Printf("FamilyShare starting on %s", cfg.ServerAddr)
@@ -106,5 +117,9 @@ func main() {
 		log.Fatalf("Server forced to shutdown: %v", err)
 	}
 
+	// Stop taking new jobs; jobs in flight finish or go back to the queue
+	cancel()
+	bgWorker.Stop()
+
 	log.Println("Server exited")
 }
@@ -0,0 +1,94 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"familyshare/internal/config"
+	"familyshare/internal/db"
+	"familyshare/internal/db/sqlc"
+	"familyshare/internal/storage"
+	"familyshare/internal/worker"
+)
+
+// runReprocess implements `familyshare reprocess`: it queues the selected
+// photos for re-encoding with the current settings and processes them,
+// reporting progress until they are done. A running server shares the queue
+// and may process some of them. It returns the process exit code.
+func runReprocess(cfg *config.Config, args []string) int {
+	fs := flag.NewFlagSet("reprocess", flag.ContinueOnError)
+	albumID := fs.Int64("album", 0, "only reprocess photos of this album ID")
+	format := fs.String("format", "", "only reprocess photos currently stored in this format (webp or avif)")
+	fs.Usage = func() {
+		fmt.Fprintln(fs.Output(), "Usage: familyshare reprocess [-album ID] [-format webp|avif]")
+		fmt.Fprintln(fs.Output(), "Re-encodes photos with the current IMAGE_FORMAT and album processing profiles,")
+		fmt.Fprintln(fs.Output(), "from archived originals when available.")
+		fs.PrintDefaults()
+	}
+	if err := fs.Parse(args); err != nil {
+		return 2
+	}
+	if *format != "" && *format != "webp" && *format != "avif" {
+		fmt.Fprintln(os.Stderr, "format must be webp or avif")
+		return 2
+	}
+
+	database, err := db.InitDB(cfg.DatabasePath)
+	if err != nil {
+		log.Printf("Failed to open database: %v", err)
+		return 1
+	}
+	defer database.Close()
+
+	ctx, cancel := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+	defer cancel()
+
+	jobs, err := worker.EnqueueReprocess(ctx, database, worker.ReprocessFilter{AlbumID: *albumID, Format: *format})
+	if err != nil {
+		log.Printf("Failed to queue photos: %v", err)
+		return 1
+	}
+	if len(jobs) == 0 {
+		fmt.Println("No photos to reprocess")
+		return 0
+	}
+	fmt.Printf("Queued %d photos for reprocessing\n", len(jobs))
+
+	bgWorker := worker.NewWorker(database, storage.New(cfg.DataDir), cfg)
+	bgWorker.StartPool(ctx)
+	bgWorker.TriggerSignal()
+	defer bgWorker.Stop()
+
+	q := sqlc.New(database)
+	ticker := time.NewTicker(2 * time.Second)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			fmt.Println("Interrupted; the remaining photos stay queued for the server")
+			return 1
+		case <-ticker.C:
+		}
+
+		status, err := q.GetReprocessStatus(context.Background(), jobs[0].ID)
+		if err != nil {
+			log.Printf("Failed to read progress: %v", err)
+			continue
+		}
+		total := status.PendingCount + status.ProcessingCount + status.FailedCount + status.CompletedCount
+		fmt.Printf("Reprocessed %d of %d photos (%d failed)\n", status.CompletedCount, total, status.FailedCount)
+		if status.PendingCount+status.ProcessingCount == 0 {
+			if status.FailedCount > 0 {
+				fmt.Println("Some photos failed; see Failed Uploads in the admin to retry them")
+				return 1
+			}
+			return 0
+		}
+	}
+}
@@ -0,0 +1,70 @@
+package botdetect
+
+import (
+	"net/netip"
+	"testing"
+)
+
+func TestIsBot_UserAgents(t *testing.T) {
+	c := New([]string{" AcmeScanner "}, nil)
+
+	bots := []string{
+		"WhatsApp/2.23.20.0 A",
+		"facebookexternalhit/1.1 Facebot Twitterbot/1.0",
+		"TelegramBot (like TwitterBot)",
+		"Slackbot-LinkExpanding 1.0 (+https://api.slack.com/robots)",
+		"Mozilla/5.0 (compatible; Discordbot/2.0; +https://discordapp.com)",
+		"Mozilla/5.0 (compatible; Googlebot/2.1; +http://www.google.com/bot.html)",
+		"Mozilla/5.0 (X11; Linux x86_64) AppleWebKit/537.36 (KHTML, like Gecko) HeadlessChrome/120.0.0.0 Safari/537.36",
+		"curl/8.4.0",
+		"python-requests/2.31.0",
+		"acmescanner/3.0",
+	}
+	for _, ua := range bots {
+		if !c.IsBot(ua, "203.0.113.7") {
+			t.Errorf("expected %q to be a bot", ua)
+		}
+	}
+
+	people := []string{
+		"",
+		"Mozilla/5.0 (iPhone; CPU iPhone OS 17_1 like Mac OS X) AppleWebKit/605.1.15 (KHTML, like Gecko) Version/17.1 Mobile/15E148 Safari/604.1",
+		"Mozilla/5.0 (Windows NT 10.0; Win64; x64) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/120.0.0.0 Safari/537.36",
+		"Mozilla/5.0 (Linux; Android 12; CUBOT_X30 Build/SP1A) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/119.0 Mobile Safari/537.36",
+	}
+	for _, ua := range people {
+		if c.IsBot(ua, "203.0.113.7") {
+			t.Errorf("expected %q not to be a bot", ua)
+		}
+	}
+}
+
+func TestIsBot_IPRanges(t *testing.T) {
+	c := New(nil, []netip.Prefix{netip.MustParsePrefix("198.51.100.0/24"), netip.MustParsePrefix("2001:db8::/32")})
+	browser := "Mozilla/5.0 (Windows NT 10.0; Win64; x64) Firefox/121.0"
+
+	for _, ip := range []string{"198.51.100.20", "::ffff:198.51.100.20", "2001:db8::1"} {
+		if !c.IsBot(browser, ip) {
+			t.Errorf("expected %s to be a bot", ip)
+		}
+	}
+	for _, ip := range []string{"198.51.101.20", "2001:db9::1", "", "not-an-ip"} {
+		if c.IsBot(browser, ip) {
+			t.Errorf("expected %q not to be a bot", ip)
+		}
+	}
+}
+
+func TestIsBot_Nil(t *testing.T) {
+	var c *Classifier
+	if c.IsBot("curl/8.4.0", "198.51.100.20") {
+		t.Errorf("expected a nil classifier to classify nothing")
+	}
+}
+
+func TestParseUserAgents(t *testing.T) {
+	got := ParseUserAgents(" scanner/1 ,, Link Checker ")
+	if len(got) != 2 || got[0] != "scanner/1" || got[1] != "Link Checker" {
+		t.Errorf("unexpected user agents %q", got)
+	}
+}
@@ -6,9 +6,12 @@ import (
 	"os"
 	"strconv"
 	"time"
+	_ "time/tzdata" // timezones work without the system's database
 
 	"github.com/joho/godotenv"
 
+	"familyshare/internal/botdetect"
+	"familyshare/internal/i18n"
 	"familyshare/internal/requestip"
 )
 
@@ -23,12 +26,15 @@ type Config struct {
 	ImageFormat    string
 	Debug          bool
 	CSRFSecret     string
+	Timezone       *time.Location // admin form inputs are read and times shown in it
+	Locale         string         // language of the admin, and of share pages by default
 
 	TrustedProxyCIDRs []netip.Prefix
 
 	// Rate limiting configuration
-	RateLimitShare int // requests per minute for share links
-	RateLimitAdmin int // requests per minute for admin endpoints
+	RateLimitShare    int // requests per minute for share links
+	RateLimitAdmin    int // requests per minute for admin endpoints
+	RateLimitComments int // guest comment posts per minute per client
 
 	// Admin authentication
 	AdminPasswordHash       string // bcrypt hash of admin password
@@ -36,7 +42,28 @@ type Config struct {
 	RequireViewerHashSecret bool   // require viewer hash secret (fail if missing)
 
 	// Janitor configuration
-	JanitorInterval time.Duration // interval for cleanup tasks
+	JanitorInterval    time.Duration // interval for cleanup tasks
+	TrashRetention     time.Duration // how long trashed photos and albums are kept
+	FailedJobRetention time.Duration // how long failed uploads keep their input for retries
+	ShareLinkRetention time.Duration // how long expired and revoked share links are kept
+
+	// Background processing
+	JobMaxRetries  int // automatic retries of a job after a transient error
+	WorkerCount    int // photos processed concurrently
+	WorkerMemoryMB int // memory budget shared by concurrently processed photos
+
+	// Animated uploads
+	AnimationMaxFrames int // frames of the largest animation kept; 0 stores only stills
+	AnimationMaxMB     int // size of the largest animation kept
+
+	// Photo map
+	MapTileURL         string // tile URL template ({z}/{x}/{y}); empty shows a list of places
+	MapTileAttribution string // attribution shown on the map, as required by most tile servers
+
+	// Bot detection
+	BotDetection  bool           // serve crawlers and link scanners a page that doesn't count as a view
+	BotUserAgents []string       // user agent fragments of bots, in addition to the built-in ones
+	BotIPRanges   []netip.Prefix // client IP ranges of bots, such as a mail scanner's
 }
 
 func Load() *Config {
@@ -50,6 +77,23 @@ func Load() *Config {
 		log.Printf("invalid TRUSTED_PROXY_CIDRS: %v", err)
 	}
 
+	botIPRanges, err := requestip.ParseCIDRs(getEnv("BOT_IP_RANGES", ""))
+	if err != nil {
+		log.Printf("invalid BOT_IP_RANGES: %v", err)
+	}
+
+	timezone, err := time.LoadLocation(getEnv("TIMEZONE", "UTC"))
+	if err != nil {
+		log.Printf("invalid TIMEZONE, using UTC: %v", err)
+		timezone = time.UTC
+	}
+
+	locale := getEnv("LOCALE", i18n.Default)
+	if !i18n.Supported(locale) {
+		log.Printf("unsupported LOCALE %q, using %s", locale, i18n.Default)
+		locale = i18n.Default
+	}
+
 	return &Config{
 		ServerAddr:              getEnv("SERVER_ADDR", ":8080"),
 		DatabasePath:            getEnv("DATABASE_PATH", "./data/familyshare.db"),
@@ -61,13 +105,29 @@ func Load() *Config {
 		ImageFormat:             getEnv("IMAGE_FORMAT", "webp"),
 		Debug:                   getEnvBool("DEBUG", false),
 		CSRFSecret:              getEnv("CSRF_SECRET", ""),
+		Timezone:                timezone,
+		Locale:                  locale,
 		TrustedProxyCIDRs:       trustedProxyCIDRs,
 		RateLimitShare:          getEnvInt("RATE_LIMIT_SHARE", 60),
 		RateLimitAdmin:          getEnvInt("RATE_LIMIT_ADMIN", 10),
+		RateLimitComments:       getEnvInt("RATE_LIMIT_COMMENTS", 5),
 		AdminPasswordHash:       getEnv("ADMIN_PASSWORD_HASH", ""),
 		ViewerHashSecret:        getEnv("VIEWER_HASH_SECRET", ""),
 		RequireViewerHashSecret: requireViewerHashSecret,
 		JanitorInterval:         getEnvDuration("JANITOR_INTERVAL", 6*time.Hour),
+		TrashRetention:          getEnvDuration("TRASH_RETENTION", 30*24*time.Hour),
+		FailedJobRetention:      getEnvDuration("FAILED_JOB_RETENTION", 7*24*time.Hour),
+		ShareLinkRetention:      getEnvDuration("SHARE_LINK_RETENTION", 30*24*time.Hour),
+		JobMaxRetries:           getEnvInt("JOB_MAX_RETRIES", 3),
+		WorkerCount:             getEnvInt("WORKER_COUNT", 2),
+		WorkerMemoryMB:          getEnvInt("WORKER_MEMORY_MB", 512),
+		AnimationMaxFrames:      getEnvInt("ANIMATION_MAX_FRAMES", 300),
+		AnimationMaxMB:          getEnvInt("ANIMATION_MAX_MB", 20),
+		MapTileURL:              getEnv("MAP_TILE_URL", ""),
+		MapTileAttribution:      getEnv("MAP_TILE_ATTRIBUTION", ""),
+		BotDetection:            getEnvBool("BOT_DETECTION", true),
+		BotUserAgents:           botdetect.ParseUserAgents(getEnv("BOT_USER_AGENTS", "")),
+		BotIPRanges:             botIPRanges,
 	}
 }
 
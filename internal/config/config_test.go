@@ -24,6 +24,11 @@ func TestLoad_WithEnvVars(t *testing.T) {
 	os.Setenv("FORCE_HTTPS", "true")
 	os.Setenv("COOKIE_SAMESITE", "Strict")
 	os.Setenv("TRUSTED_PROXY_CIDRS", "10.0.0.0/8, 192.168.0.0/16")
+	os.Setenv("BOT_DETECTION", "off")
+	os.Setenv("BOT_USER_AGENTS", "scanner/1, Link Checker")
+	os.Setenv("BOT_IP_RANGES", "198.51.100.0/24")
+	os.Setenv("TIMEZONE", "Europe/Lisbon")
+	os.Setenv("LOCALE", "pt")
 	defer func() {
 		os.Unsetenv("SERVER_ADDR")
 		os.Unsetenv("DATABASE_PATH")
@@ -39,6 +44,11 @@ func TestLoad_WithEnvVars(t *testing.T) {
 		os.Unsetenv("FORCE_HTTPS")
 		os.Unsetenv("COOKIE_SAMESITE")
 		os.Unsetenv("TRUSTED_PROXY_CIDRS")
+		os.Unsetenv("BOT_DETECTION")
+		os.Unsetenv("BOT_USER_AGENTS")
+		os.Unsetenv("BOT_IP_RANGES")
+		os.Unsetenv("TIMEZONE")
+		os.Unsetenv("LOCALE")
 	}()
 
 	cfg := config.Load()
@@ -88,6 +98,39 @@ func TestLoad_WithEnvVars(t *testing.T) {
 	if cfg.TrustedProxyCIDRs[0] != netip.MustParsePrefix("10.0.0.0/8") {
 		t.Errorf("expected first trusted CIDR 10.0.0.0/8, got %s", cfg.TrustedProxyCIDRs[0])
 	}
+	if cfg.BotDetection {
+		t.Errorf("expected BOT_DETECTION off")
+	}
+	if len(cfg.BotUserAgents) != 2 || cfg.BotUserAgents[1] != "Link Checker" {
+		t.Errorf("expected 2 bot user agents, got %q", cfg.BotUserAgents)
+	}
+	if len(cfg.BotIPRanges) != 1 || cfg.BotIPRanges[0] != netip.MustParsePrefix("198.51.100.0/24") {
+		t.Errorf("expected bot IP range 198.51.100.0/24, got %v", cfg.BotIPRanges)
+	}
+	if cfg.Timezone.String() != "Europe/Lisbon" {
+		t.Errorf("expected TIMEZONE Europe/Lisbon, got %v", cfg.Timezone)
+	}
+	if cfg.Locale != "pt" {
+		t.Errorf("expected LOCALE pt, got %s", cfg.Locale)
+	}
+}
+
+func TestLoad_InvalidTimezoneFallbackToUTC(t *testing.T) {
+	os.Setenv("TIMEZONE", "Mars/Olympus_Mons")
+	defer os.Unsetenv("TIMEZONE")
+
+	if cfg := config.Load(); cfg.Timezone != time.UTC {
+		t.Errorf("expected UTC for an invalid TIMEZONE, got %v", cfg.Timezone)
+	}
+}
+
+func TestLoad_UnsupportedLocaleFallbackToEnglish(t *testing.T) {
+	os.Setenv("LOCALE", "tlh")
+	defer os.Unsetenv("LOCALE")
+
+	if cfg := config.Load(); cfg.Locale != "en" {
+		t.Errorf("expected en for an unsupported LOCALE, got %s", cfg.Locale)
+	}
 }
 
 func TestLoad_Defaults(t *testing.T) {
@@ -133,6 +176,27 @@ func TestLoad_Defaults(t *testing.T) {
 	if cfg.JanitorInterval != 6*time.Hour {
 		t.Errorf("expected default JANITOR_INTERVAL 6h, got %v", cfg.JanitorInterval)
 	}
+	if cfg.TrashRetention != 30*24*time.Hour {
+		t.Errorf("expected default TRASH_RETENTION 720h, got %v", cfg.TrashRetention)
+	}
+	if cfg.FailedJobRetention != 7*24*time.Hour {
+		t.Errorf("expected default FAILED_JOB_RETENTION 168h, got %v", cfg.FailedJobRetention)
+	}
+	if cfg.Timezone != time.UTC {
+		t.Errorf("expected default TIMEZONE UTC, got %v", cfg.Timezone)
+	}
+	if cfg.Locale != "en" {
+		t.Errorf("expected default LOCALE en, got %s", cfg.Locale)
+	}
+	if cfg.ShareLinkRetention != 30*24*time.Hour {
+		t.Errorf("expected default SHARE_LINK_RETENTION 720h, got %v", cfg.ShareLinkRetention)
+	}
+	if cfg.JobMaxRetries != 3 {
+		t.Errorf("expected default JOB_MAX_RETRIES 3, got %d", cfg.JobMaxRetries)
+	}
+	if cfg.WorkerCount != 2 || cfg.WorkerMemoryMB != 512 {
+		t.Errorf("expected default WORKER_COUNT 2 and WORKER_MEMORY_MB 512, got %d and %d", cfg.WorkerCount, cfg.WorkerMemoryMB)
+	}
 	if cfg.Environment != "development" {
 		t.Errorf("expected default APP_ENV development, got %s", cfg.Environment)
 	}
@@ -151,6 +215,9 @@ func TestLoad_Defaults(t *testing.T) {
 	if len(cfg.TrustedProxyCIDRs) != 0 {
 		t.Errorf("expected default TRUSTED_PROXY_CIDRS empty, got %d", len(cfg.TrustedProxyCIDRs))
 	}
+	if !cfg.BotDetection {
+		t.Errorf("expected bot detection on by default")
+	}
 }
 
 func TestLoad_ViewerHashSecretRequiredInProduction(t *testing.T) {
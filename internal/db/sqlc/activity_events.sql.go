@@ -69,6 +69,18 @@ func (q *Queries) CountPhotoViewsSince(ctx context.Context, createdAt sql.NullTi
 	return count, err
 }
 
+const countReactionsSince = `-- name: CountReactionsSince :one
+SELECT COUNT(*) FROM activity_events
+WHERE event_type = 'reaction_add' AND created_at >= ?
+`
+
+func (q *Queries) CountReactionsSince(ctx context.Context, createdAt sql.NullTime) (int64, error) {
+	row := q.db.QueryRowContext(ctx, countReactionsSince, createdAt)
+	var count int64
+	err := row.Scan(&count)
+	return count, err
+}
+
 const countShareViewsSince = `-- name: CountShareViewsSince :one
 SELECT COUNT(*) FROM activity_events
 WHERE event_type = 'share_view' AND created_at >= ?
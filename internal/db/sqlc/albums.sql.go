@@ -21,8 +21,19 @@ func (q *Queries) ClearAlbumCoverIfPhoto(ctx context.Context, coverPhotoID sql.N
 	return err
 }
 
+const clearAlbumProcessingProfile = `-- name: ClearAlbumProcessingProfile :exec
+UPDATE albums
+SET processing_profile_id = NULL
+WHERE processing_profile_id = ?
+`
+
+func (q *Queries) ClearAlbumProcessingProfile(ctx context.Context, processingProfileID sql.NullInt64) error {
+	_, err := q.db.ExecContext(ctx, clearAlbumProcessingProfile, processingProfileID)
+	return err
+}
+
 const countAlbums = `-- name: CountAlbums :one
-SELECT COUNT(*) FROM albums
+SELECT COUNT(*) FROM albums WHERE deleted_at IS NULL
 `
 
 func (q *Queries) CountAlbums(ctx context.Context) (int64, error) {
@@ -35,7 +46,7 @@ func (q *Queries) CountAlbums(ctx context.Context) (int64, error) {
 const createAlbum = `-- name: CreateAlbum :one
 INSERT INTO albums (title, description)
 VALUES (?, ?)
-RETURNING id, title, description, cover_photo_id, created_at, updated_at
+RETURNING id, title, description, cover_photo_id, created_at, updated_at, sort_mode, deleted_at, processing_profile_id, cover_header
 `
 
 type CreateAlbumParams struct {
@@ -53,6 +64,10 @@ func (q *Queries) CreateAlbum(ctx context.Context, arg CreateAlbumParams) (Album
 		&i.CoverPhotoID,
 		&i.CreatedAt,
 		&i.UpdatedAt,
+		&i.SortMode,
+		&i.DeletedAt,
+		&i.ProcessingProfileID,
+		&i.CoverHeader,
 	)
 	return i, err
 }
@@ -67,7 +82,7 @@ func (q *Queries) DeleteAlbum(ctx context.Context, id int64) error {
 }
 
 const getAlbum = `-- name: GetAlbum :one
-SELECT id, title, description, cover_photo_id, created_at, updated_at FROM albums WHERE id = ?
+SELECT id, title, description, cover_photo_id, created_at, updated_at, sort_mode, deleted_at, processing_profile_id, cover_header FROM albums WHERE id = ? AND deleted_at IS NULL
 `
 
 func (q *Queries) GetAlbum(ctx context.Context, id int64) (Album, error) {
@@ -80,28 +95,58 @@ func (q *Queries) GetAlbum(ctx context.Context, id int64) (Album, error) {
 		&i.CoverPhotoID,
 		&i.CreatedAt,
 		&i.UpdatedAt,
+		&i.SortMode,
+		&i.DeletedAt,
+		&i.ProcessingProfileID,
+		&i.CoverHeader,
+	)
+	return i, err
+}
+
+const getAlbumIncludingDeleted = `-- name: GetAlbumIncludingDeleted :one
+SELECT id, title, description, cover_photo_id, created_at, updated_at, sort_mode, deleted_at, processing_profile_id, cover_header FROM albums WHERE id = ?
+`
+
+func (q *Queries) GetAlbumIncludingDeleted(ctx context.Context, id int64) (Album, error) {
+	row := q.db.QueryRowContext(ctx, getAlbumIncludingDeleted, id)
+	var i Album
+	err := row.Scan(
+		&i.ID,
+		&i.Title,
+		&i.Description,
+		&i.CoverPhotoID,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.SortMode,
+		&i.DeletedAt,
+		&i.ProcessingProfileID,
+		&i.CoverHeader,
 	)
 	return i, err
 }
 
 const getAlbumWithPhotoCount = `-- name: GetAlbumWithPhotoCount :one
 SELECT 
-    a.id, a.title, a.description, a.cover_photo_id, a.created_at, a.updated_at,
+    a.id, a.title, a.description, a.cover_photo_id, a.created_at, a.updated_at, a.sort_mode, a.deleted_at, a.processing_profile_id, a.cover_header,
     COUNT(p.id) as photo_count
 FROM albums a
-LEFT JOIN photos p ON p.album_id = a.id
-WHERE a.id = ?
+LEFT JOIN photos p ON p.album_id = a.id AND p.deleted_at IS NULL
+WHERE a.id = ? AND a.deleted_at IS NULL
 GROUP BY a.id
 `
 
 type GetAlbumWithPhotoCountRow struct {
-	ID           int64          `json:"id"`
-	Title        string         `json:"title"`
-	Description  sql.NullString `json:"description"`
-	CoverPhotoID sql.NullInt64  `json:"cover_photo_id"`
-	CreatedAt    sql.NullTime   `json:"created_at"`
-	UpdatedAt    sql.NullTime   `json:"updated_at"`
-	PhotoCount   int64          `json:"photo_count"`
+	ID                  int64          `json:"id"`
+	Title               string         `json:"title"`
+	Description         sql.NullString `json:"description"`
+	CoverPhotoID        sql.NullInt64  `json:"cover_photo_id"`
+	CreatedAt           sql.NullTime   `json:"created_at"`
+	UpdatedAt           sql.NullTime   `json:"updated_at"`
+	SortMode            string         `json:"sort_mode"`
+	DeletedAt           sql.NullTime   `json:"deleted_at"`
+	ProcessingProfileID sql.NullInt64  `json:"processing_profile_id"`
+	CoverHeader         bool           `json:"cover_header"`
+	PhotoCount          int64          `json:"photo_count"`
 }
 
 func (q *Queries) GetAlbumWithPhotoCount(ctx context.Context, id int64) (GetAlbumWithPhotoCountRow, error) {
@@ -114,13 +159,17 @@ func (q *Queries) GetAlbumWithPhotoCount(ctx context.Context, id int64) (GetAlbu
 		&i.CoverPhotoID,
 		&i.CreatedAt,
 		&i.UpdatedAt,
+		&i.SortMode,
+		&i.DeletedAt,
+		&i.ProcessingProfileID,
+		&i.CoverHeader,
 		&i.PhotoCount,
 	)
 	return i, err
 }
 
 const getPhotosForAlbum = `-- name: GetPhotosForAlbum :many
-SELECT id, album_id, filename, width, height, size_bytes, format, created_at FROM photos WHERE album_id = ?
+SELECT id, album_id, filename, width, height, size_bytes, format, created_at, caption, position, taken_at, deleted_at, original_format, edits, animation_format, latitude, longitude FROM photos WHERE album_id = ?
 `
 
 func (q *Queries) GetPhotosForAlbum(ctx context.Context, albumID int64) ([]Photo, error) {
@@ -141,6 +190,15 @@ func (q *Queries) GetPhotosForAlbum(ctx context.Context, albumID int64) ([]Photo
 			&i.SizeBytes,
 			&i.Format,
 			&i.CreatedAt,
+			&i.Caption,
+			&i.Position,
+			&i.TakenAt,
+			&i.DeletedAt,
+			&i.OriginalFormat,
+			&i.Edits,
+			&i.AnimationFormat,
+			&i.Latitude,
+			&i.Longitude,
 		); err != nil {
 			return nil, err
 		}
@@ -156,7 +214,8 @@ func (q *Queries) GetPhotosForAlbum(ctx context.Context, albumID int64) ([]Photo
 }
 
 const listAlbums = `-- name: ListAlbums :many
-SELECT id, title, description, cover_photo_id, created_at, updated_at FROM albums
+SELECT id, title, description, cover_photo_id, created_at, updated_at, sort_mode, deleted_at, processing_profile_id, cover_header FROM albums
+WHERE deleted_at IS NULL
 ORDER BY created_at DESC
 LIMIT ? OFFSET ?
 `
@@ -182,6 +241,10 @@ func (q *Queries) ListAlbums(ctx context.Context, arg ListAlbumsParams) ([]Album
 			&i.CoverPhotoID,
 			&i.CreatedAt,
 			&i.UpdatedAt,
+			&i.SortMode,
+			&i.DeletedAt,
+			&i.ProcessingProfileID,
+			&i.CoverHeader,
 		); err != nil {
 			return nil, err
 		}
@@ -204,9 +267,14 @@ SELECT
     a.cover_photo_id,
     a.created_at,
     a.updated_at,
+    a.sort_mode,
+    a.deleted_at,
+    a.processing_profile_id,
+    a.cover_header,
     COUNT(p.id) as photo_count
 FROM albums a
-LEFT JOIN photos p ON p.album_id = a.id
+LEFT JOIN photos p ON p.album_id = a.id AND p.deleted_at IS NULL
+WHERE a.deleted_at IS NULL
 GROUP BY a.id
 ORDER BY a.created_at DESC
 LIMIT ? OFFSET ?
@@ -218,13 +286,17 @@ type ListAlbumsWithPhotoCountParams struct {
 }
 
 type ListAlbumsWithPhotoCountRow struct {
-	ID           int64          `json:"id"`
-	Title        string         `json:"title"`
-	Description  sql.NullString `json:"description"`
-	CoverPhotoID sql.NullInt64  `json:"cover_photo_id"`
-	CreatedAt    sql.NullTime   `json:"created_at"`
-	UpdatedAt    sql.NullTime   `json:"updated_at"`
-	PhotoCount   int64          `json:"photo_count"`
+	ID                  int64          `json:"id"`
+	Title               string         `json:"title"`
+	Description         sql.NullString `json:"description"`
+	CoverPhotoID        sql.NullInt64  `json:"cover_photo_id"`
+	CreatedAt           sql.NullTime   `json:"created_at"`
+	UpdatedAt           sql.NullTime   `json:"updated_at"`
+	SortMode            string         `json:"sort_mode"`
+	DeletedAt           sql.NullTime   `json:"deleted_at"`
+	ProcessingProfileID sql.NullInt64  `json:"processing_profile_id"`
+	CoverHeader         bool           `json:"cover_header"`
+	PhotoCount          int64          `json:"photo_count"`
 }
 
 func (q *Queries) ListAlbumsWithPhotoCount(ctx context.Context, arg ListAlbumsWithPhotoCountParams) ([]ListAlbumsWithPhotoCountRow, error) {
@@ -243,6 +315,10 @@ func (q *Queries) ListAlbumsWithPhotoCount(ctx context.Context, arg ListAlbumsWi
 			&i.CoverPhotoID,
 			&i.CreatedAt,
 			&i.UpdatedAt,
+			&i.SortMode,
+			&i.DeletedAt,
+			&i.ProcessingProfileID,
+			&i.CoverHeader,
 			&i.PhotoCount,
 		); err != nil {
 			return nil, err
@@ -258,6 +334,104 @@ func (q *Queries) ListAlbumsWithPhotoCount(ctx context.Context, arg ListAlbumsWi
 	return items, nil
 }
 
+const listTrashedAlbums = `-- name: ListTrashedAlbums :many
+SELECT
+    a.id, a.title, a.description, a.cover_photo_id, a.created_at, a.updated_at, a.sort_mode, a.deleted_at, a.processing_profile_id, a.cover_header,
+    COUNT(p.id) as photo_count
+FROM albums a
+LEFT JOIN photos p ON p.album_id = a.id
+WHERE a.deleted_at IS NOT NULL
+GROUP BY a.id
+ORDER BY a.deleted_at DESC
+`
+
+type ListTrashedAlbumsRow struct {
+	ID                  int64          `json:"id"`
+	Title               string         `json:"title"`
+	Description         sql.NullString `json:"description"`
+	CoverPhotoID        sql.NullInt64  `json:"cover_photo_id"`
+	CreatedAt           sql.NullTime   `json:"created_at"`
+	UpdatedAt           sql.NullTime   `json:"updated_at"`
+	SortMode            string         `json:"sort_mode"`
+	DeletedAt           sql.NullTime   `json:"deleted_at"`
+	ProcessingProfileID sql.NullInt64  `json:"processing_profile_id"`
+	CoverHeader         bool           `json:"cover_header"`
+	PhotoCount          int64          `json:"photo_count"`
+}
+
+func (q *Queries) ListTrashedAlbums(ctx context.Context) ([]ListTrashedAlbumsRow, error) {
+	rows, err := q.db.QueryContext(ctx, listTrashedAlbums)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []ListTrashedAlbumsRow{}
+	for rows.Next() {
+		var i ListTrashedAlbumsRow
+		if err := rows.Scan(
+			&i.ID,
+			&i.Title,
+			&i.Description,
+			&i.CoverPhotoID,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.SortMode,
+			&i.DeletedAt,
+			&i.ProcessingProfileID,
+			&i.CoverHeader,
+			&i.PhotoCount,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const purgeTrashedAlbums = `-- name: PurgeTrashedAlbums :many
+DELETE FROM albums
+WHERE deleted_at < ?
+RETURNING id
+`
+
+func (q *Queries) PurgeTrashedAlbums(ctx context.Context, deletedAt sql.NullTime) ([]int64, error) {
+	rows, err := q.db.QueryContext(ctx, purgeTrashedAlbums, deletedAt)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []int64{}
+	for rows.Next() {
+		var id int64
+		if err := rows.Scan(&id); err != nil {
+			return nil, err
+		}
+		items = append(items, id)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const restoreAlbum = `-- name: RestoreAlbum :exec
+UPDATE albums SET deleted_at = NULL WHERE id = ?
+`
+
+func (q *Queries) RestoreAlbum(ctx context.Context, id int64) error {
+	_, err := q.db.ExecContext(ctx, restoreAlbum, id)
+	return err
+}
+
 const setAlbumCover = `-- name: SetAlbumCover :exec
 UPDATE albums
 SET cover_photo_id = ?, updated_at = CURRENT_TIMESTAMP
@@ -274,6 +448,63 @@ func (q *Queries) SetAlbumCover(ctx context.Context, arg SetAlbumCoverParams) er
 	return err
 }
 
+const setAlbumCoverHeader = `-- name: SetAlbumCoverHeader :exec
+UPDATE albums
+SET cover_header = ?, updated_at = CURRENT_TIMESTAMP
+WHERE id = ?
+`
+
+type SetAlbumCoverHeaderParams struct {
+	CoverHeader bool  `json:"cover_header"`
+	ID          int64 `json:"id"`
+}
+
+func (q *Queries) SetAlbumCoverHeader(ctx context.Context, arg SetAlbumCoverHeaderParams) error {
+	_, err := q.db.ExecContext(ctx, setAlbumCoverHeader, arg.CoverHeader, arg.ID)
+	return err
+}
+
+const setAlbumProcessingProfile = `-- name: SetAlbumProcessingProfile :exec
+UPDATE albums
+SET processing_profile_id = ?, updated_at = CURRENT_TIMESTAMP
+WHERE id = ?
+`
+
+type SetAlbumProcessingProfileParams struct {
+	ProcessingProfileID sql.NullInt64 `json:"processing_profile_id"`
+	ID                  int64         `json:"id"`
+}
+
+func (q *Queries) SetAlbumProcessingProfile(ctx context.Context, arg SetAlbumProcessingProfileParams) error {
+	_, err := q.db.ExecContext(ctx, setAlbumProcessingProfile, arg.ProcessingProfileID, arg.ID)
+	return err
+}
+
+const setAlbumSortMode = `-- name: SetAlbumSortMode :exec
+UPDATE albums
+SET sort_mode = ?, updated_at = CURRENT_TIMESTAMP
+WHERE id = ?
+`
+
+type SetAlbumSortModeParams struct {
+	SortMode string `json:"sort_mode"`
+	ID       int64  `json:"id"`
+}
+
+func (q *Queries) SetAlbumSortMode(ctx context.Context, arg SetAlbumSortModeParams) error {
+	_, err := q.db.ExecContext(ctx, setAlbumSortMode, arg.SortMode, arg.ID)
+	return err
+}
+
+const trashAlbum = `-- name: TrashAlbum :exec
+UPDATE albums SET deleted_at = CURRENT_TIMESTAMP WHERE id = ? AND deleted_at IS NULL
+`
+
+func (q *Queries) TrashAlbum(ctx context.Context, id int64) error {
+	_, err := q.db.ExecContext(ctx, trashAlbum, id)
+	return err
+}
+
 const updateAlbum = `-- name: UpdateAlbum :exec
 UPDATE albums
 SET title = ?, description = ?, cover_photo_id = ?, updated_at = CURRENT_TIMESTAMP
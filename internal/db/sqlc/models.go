@@ -19,25 +19,76 @@ type ActivityEvent struct {
 }
 
 type Album struct {
-	ID           int64          `json:"id"`
-	Title        string         `json:"title"`
-	Description  sql.NullString `json:"description"`
-	CoverPhotoID sql.NullInt64  `json:"cover_photo_id"`
-	CreatedAt    sql.NullTime   `json:"created_at"`
-	UpdatedAt    sql.NullTime   `json:"updated_at"`
+	ID                  int64          `json:"id"`
+	Title               string         `json:"title"`
+	Description         sql.NullString `json:"description"`
+	CoverPhotoID        sql.NullInt64  `json:"cover_photo_id"`
+	CreatedAt           sql.NullTime   `json:"created_at"`
+	UpdatedAt           sql.NullTime   `json:"updated_at"`
+	SortMode            string         `json:"sort_mode"`
+	DeletedAt           sql.NullTime   `json:"deleted_at"`
+	ProcessingProfileID sql.NullInt64  `json:"processing_profile_id"`
+	CoverHeader         bool           `json:"cover_header"`
 }
 
 type Photo struct {
-	ID        int64        `json:"id"`
-	AlbumID   int64        `json:"album_id"`
-	Filename  string       `json:"filename"`
-	Width     int64        `json:"width"`
-	Height    int64        `json:"height"`
-	SizeBytes int64        `json:"size_bytes"`
-	Format    string       `json:"format"`
+	ID              int64           `json:"id"`
+	AlbumID         int64           `json:"album_id"`
+	Filename        string          `json:"filename"`
+	Width           int64           `json:"width"`
+	Height          int64           `json:"height"`
+	SizeBytes       int64           `json:"size_bytes"`
+	Format          string          `json:"format"`
+	CreatedAt       sql.NullTime    `json:"created_at"`
+	Caption         sql.NullString  `json:"caption"`
+	Position        int64           `json:"position"`
+	TakenAt         sql.NullTime    `json:"taken_at"`
+	DeletedAt       sql.NullTime    `json:"deleted_at"`
+	OriginalFormat  sql.NullString  `json:"original_format"`
+	Edits           sql.NullString  `json:"edits"`
+	AnimationFormat sql.NullString  `json:"animation_format"`
+	Latitude        sql.NullFloat64 `json:"latitude"`
+	Longitude       sql.NullFloat64 `json:"longitude"`
+}
+
+type PhotoComment struct {
+	ID          int64        `json:"id"`
+	PhotoID     int64        `json:"photo_id"`
+	ShareLinkID int64        `json:"share_link_id"`
+	DisplayName string       `json:"display_name"`
+	Body        string       `json:"body"`
+	HiddenAt    sql.NullTime `json:"hidden_at"`
+	CreatedAt   sql.NullTime `json:"created_at"`
+}
+
+type PhotoReaction struct {
+	ID          int64         `json:"id"`
+	PhotoID     int64         `json:"photo_id"`
+	ShareLinkID sql.NullInt64 `json:"share_link_id"`
+	ViewerHash  string        `json:"viewer_hash"`
+	Reaction    string        `json:"reaction"`
+	CreatedAt   sql.NullTime  `json:"created_at"`
+}
+
+type PhotoTag struct {
+	PhotoID   int64        `json:"photo_id"`
+	TagID     int64        `json:"tag_id"`
 	CreatedAt sql.NullTime `json:"created_at"`
 }
 
+type ProcessingProfile struct {
+	ID            int64        `json:"id"`
+	Name          string       `json:"name"`
+	MaxDimension  int64        `json:"max_dimension"`
+	Format        string       `json:"format"`
+	Quality       int64        `json:"quality"`
+	AvifSpeed     int64        `json:"avif_speed"`
+	KeepOriginals bool         `json:"keep_originals"`
+	StripMetadata bool         `json:"strip_metadata"`
+	CreatedAt     sql.NullTime `json:"created_at"`
+	UpdatedAt     sql.NullTime `json:"updated_at"`
+}
+
 type ProcessingQueue struct {
 	ID               int64          `json:"id"`
 	AlbumID          int64          `json:"album_id"`
@@ -47,6 +98,12 @@ type ProcessingQueue struct {
 	ErrorMessage     sql.NullString `json:"error_message"`
 	CreatedAt        sql.NullTime   `json:"created_at"`
 	UpdatedAt        sql.NullTime   `json:"updated_at"`
+	PhotoID          sql.NullInt64  `json:"photo_id"`
+	Attempts         int64          `json:"attempts"`
+	NextAttemptAt    sql.NullTime   `json:"next_attempt_at"`
+	LeaseExpiresAt   sql.NullTime   `json:"lease_expires_at"`
+	HeartbeatAt      sql.NullTime   `json:"heartbeat_at"`
+	Kind             string         `json:"kind"`
 }
 
 type SchemaMigration struct {
@@ -54,6 +111,14 @@ type SchemaMigration struct {
 	AppliedAt sql.NullTime `json:"applied_at"`
 }
 
+type SearchIndex struct {
+	Kind    string `json:"kind"`
+	AlbumID string `json:"album_id"`
+	PhotoID string `json:"photo_id"`
+	Title   string `json:"title"`
+	Body    string `json:"body"`
+}
+
 type Session struct {
 	ID        string       `json:"id"`
 	UserID    string       `json:"user_id"`
@@ -61,21 +126,48 @@ type Session struct {
 	CreatedAt sql.NullTime `json:"created_at"`
 }
 
+type Setting struct {
+	Key       string       `json:"key"`
+	Value     string       `json:"value"`
+	UpdatedAt sql.NullTime `json:"updated_at"`
+}
+
 type ShareLink struct {
-	ID         int64          `json:"id"`
-	Token      string         `json:"token"`
-	TargetType string         `json:"target_type"`
-	TargetID   int64          `json:"target_id"`
-	MaxViews   sql.NullInt64  `json:"max_views"`
-	ExpiresAt  sql.NullTime   `json:"expires_at"`
-	CreatedAt  sql.NullTime   `json:"created_at"`
-	RevokedAt  sql.NullTime   `json:"revoked_at"`
-	Message    sql.NullString `json:"message"`
+	ID            int64          `json:"id"`
+	Token         string         `json:"token"`
+	TargetType    string         `json:"target_type"`
+	TargetID      int64          `json:"target_id"`
+	MaxViews      sql.NullInt64  `json:"max_views"`
+	ExpiresAt     sql.NullTime   `json:"expires_at"`
+	CreatedAt     sql.NullTime   `json:"created_at"`
+	RevokedAt     sql.NullTime   `json:"revoked_at"`
+	Message       sql.NullString `json:"message"`
+	AllowComments bool           `json:"allow_comments"`
+	ShowMap       bool           `json:"show_map"`
+	OpenGraph     bool           `json:"open_graph"`
+	Slug          sql.NullString `json:"slug"`
+	Locale        sql.NullString `json:"locale"`
 }
 
-type ShareLinkView struct {
+type ShareLinkRecipient struct {
 	ID          int64        `json:"id"`
 	ShareLinkID int64        `json:"share_link_id"`
-	ViewerHash  string       `json:"viewer_hash"`
+	Name        string       `json:"name"`
+	Token       string       `json:"token"`
 	CreatedAt   sql.NullTime `json:"created_at"`
+	RevokedAt   sql.NullTime `json:"revoked_at"`
+}
+
+type ShareLinkView struct {
+	ID          int64         `json:"id"`
+	ShareLinkID int64         `json:"share_link_id"`
+	ViewerHash  string        `json:"viewer_hash"`
+	CreatedAt   sql.NullTime  `json:"created_at"`
+	RecipientID sql.NullInt64 `json:"recipient_id"`
+}
+
+type Tag struct {
+	ID        int64        `json:"id"`
+	Name      string       `json:"name"`
+	CreatedAt sql.NullTime `json:"created_at"`
 }
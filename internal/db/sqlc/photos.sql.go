@@ -10,8 +10,100 @@ import (
 	"database/sql"
 )
 
+const clusterPhotoLocations = `-- name: ClusterPhotoLocations :many
+SELECT
+    CAST((p.latitude + 90) / ?1 AS INTEGER) as cell_y,
+    CAST((p.longitude + 180) / ?1 AS INTEGER) as cell_x,
+    COUNT(*) as count,
+    CAST(AVG(p.latitude) AS REAL) as latitude,
+    CAST(AVG(p.longitude) AS REAL) as longitude,
+    CAST(MAX(p.id) AS INTEGER) as photo_id
+FROM photos p
+JOIN albums a ON p.album_id = a.id
+WHERE p.latitude IS NOT NULL AND p.deleted_at IS NULL AND a.deleted_at IS NULL
+  AND p.latitude BETWEEN ?2 AND ?3
+  AND p.longitude BETWEEN ?4 AND ?5
+  AND (?6 IS NULL OR p.album_id = ?6)
+GROUP BY cell_y, cell_x
+ORDER BY count DESC
+LIMIT ?7
+`
+
+type ClusterPhotoLocationsParams struct {
+	Cell        float64       `json:"cell"`
+	South       float64       `json:"south"`
+	North       float64       `json:"north"`
+	West        float64       `json:"west"`
+	East        float64       `json:"east"`
+	AlbumID     sql.NullInt64 `json:"album_id"`
+	MaxClusters int64         `json:"max_clusters"`
+}
+
+type ClusterPhotoLocationsRow struct {
+	CellY     int64   `json:"cell_y"`
+	CellX     int64   `json:"cell_x"`
+	Count     int64   `json:"count"`
+	Latitude  float64 `json:"latitude"`
+	Longitude float64 `json:"longitude"`
+	PhotoID   int64   `json:"photo_id"`
+}
+
+// Geotagged photos within a bounding box, grouped into a grid of cells of
+// cell degrees. Each cluster is placed at the mean location of its photos
+// and represented by its newest photo. album_id limits it to one album.
+func (q *Queries) ClusterPhotoLocations(ctx context.Context, arg ClusterPhotoLocationsParams) ([]ClusterPhotoLocationsRow, error) {
+	rows, err := q.db.QueryContext(ctx, clusterPhotoLocations,
+		arg.Cell,
+		arg.South,
+		arg.North,
+		arg.West,
+		arg.East,
+		arg.AlbumID,
+		arg.MaxClusters,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []ClusterPhotoLocationsRow{}
+	for rows.Next() {
+		var i ClusterPhotoLocationsRow
+		if err := rows.Scan(
+			&i.CellY,
+			&i.CellX,
+			&i.Count,
+			&i.Latitude,
+			&i.Longitude,
+			&i.PhotoID,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const countAlbumPhotos = `-- name: CountAlbumPhotos :one
+SELECT COUNT(*) FROM photos WHERE album_id = ? AND deleted_at IS NULL
+`
+
+func (q *Queries) CountAlbumPhotos(ctx context.Context, albumID int64) (int64, error) {
+	row := q.db.QueryRowContext(ctx, countAlbumPhotos, albumID)
+	var count int64
+	err := row.Scan(&count)
+	return count, err
+}
+
 const countPhotos = `-- name: CountPhotos :one
-SELECT COUNT(*) FROM photos
+SELECT COUNT(*) FROM photos p
+JOIN albums a ON a.id = p.album_id
+WHERE p.deleted_at IS NULL AND a.deleted_at IS NULL
 `
 
 func (q *Queries) CountPhotos(ctx context.Context) (int64, error) {
@@ -24,7 +116,7 @@ func (q *Queries) CountPhotos(ctx context.Context) (int64, error) {
 const createPhoto = `-- name: CreatePhoto :one
 INSERT INTO photos (album_id, filename, width, height, size_bytes, format)
 VALUES (?, ?, ?, ?, ?, ?)
-RETURNING id, album_id, filename, width, height, size_bytes, format, created_at
+RETURNING id, album_id, filename, width, height, size_bytes, format, created_at, caption, position, taken_at, deleted_at, original_format, edits, animation_format, latitude, longitude
 `
 
 type CreatePhotoParams struct {
@@ -55,6 +147,15 @@ func (q *Queries) CreatePhoto(ctx context.Context, arg CreatePhotoParams) (Photo
 		&i.SizeBytes,
 		&i.Format,
 		&i.CreatedAt,
+		&i.Caption,
+		&i.Position,
+		&i.TakenAt,
+		&i.DeletedAt,
+		&i.OriginalFormat,
+		&i.Edits,
+		&i.AnimationFormat,
+		&i.Latitude,
+		&i.Longitude,
 	)
 	return i, err
 }
@@ -62,15 +163,17 @@ func (q *Queries) CreatePhoto(ctx context.Context, arg CreatePhotoParams) (Photo
 const deleteOrphanedPhotos = `-- name: DeleteOrphanedPhotos :many
 DELETE FROM photos 
 WHERE album_id NOT IN (SELECT id FROM albums)
-RETURNING id, album_id, filename, format, created_at
+RETURNING id, album_id, filename, format, created_at, original_format, animation_format
 `
 
 type DeleteOrphanedPhotosRow struct {
-	ID        int64        `json:"id"`
-	AlbumID   int64        `json:"album_id"`
-	Filename  string       `json:"filename"`
-	Format    string       `json:"format"`
-	CreatedAt sql.NullTime `json:"created_at"`
+	ID              int64          `json:"id"`
+	AlbumID         int64          `json:"album_id"`
+	Filename        string         `json:"filename"`
+	Format          string         `json:"format"`
+	CreatedAt       sql.NullTime   `json:"created_at"`
+	OriginalFormat  sql.NullString `json:"original_format"`
+	AnimationFormat sql.NullString `json:"animation_format"`
 }
 
 func (q *Queries) DeleteOrphanedPhotos(ctx context.Context) ([]DeleteOrphanedPhotosRow, error) {
@@ -88,6 +191,8 @@ func (q *Queries) DeleteOrphanedPhotos(ctx context.Context) ([]DeleteOrphanedPho
 			&i.Filename,
 			&i.Format,
 			&i.CreatedAt,
+			&i.OriginalFormat,
+			&i.AnimationFormat,
 		); err != nil {
 			return nil, err
 		}
@@ -111,8 +216,21 @@ func (q *Queries) DeletePhoto(ctx context.Context, id int64) error {
 	return err
 }
 
+const getMaxPhotoPosition = `-- name: GetMaxPhotoPosition :one
+SELECT CAST(COALESCE(MAX(position), 0) AS INTEGER) FROM photos WHERE album_id = ?
+`
+
+func (q *Queries) GetMaxPhotoPosition(ctx context.Context, albumID int64) (int64, error) {
+	row := q.db.QueryRowContext(ctx, getMaxPhotoPosition, albumID)
+	var column_1 int64
+	err := row.Scan(&column_1)
+	return column_1, err
+}
+
 const getPhoto = `-- name: GetPhoto :one
-SELECT id, album_id, filename, width, height, size_bytes, format, created_at FROM photos WHERE id = ?
+SELECT p.id, p.album_id, p.filename, p.width, p.height, p.size_bytes, p.format, p.created_at, p.caption, p.position, p.taken_at, p.deleted_at, p.original_format, p.edits, p.animation_format, p.latitude, p.longitude FROM photos p
+JOIN albums a ON a.id = p.album_id
+WHERE p.id = ? AND p.deleted_at IS NULL AND a.deleted_at IS NULL
 `
 
 func (q *Queries) GetPhoto(ctx context.Context, id int64) (Photo, error) {
@@ -127,6 +245,44 @@ func (q *Queries) GetPhoto(ctx context.Context, id int64) (Photo, error) {
 		&i.SizeBytes,
 		&i.Format,
 		&i.CreatedAt,
+		&i.Caption,
+		&i.Position,
+		&i.TakenAt,
+		&i.DeletedAt,
+		&i.OriginalFormat,
+		&i.Edits,
+		&i.AnimationFormat,
+		&i.Latitude,
+		&i.Longitude,
+	)
+	return i, err
+}
+
+const getPhotoIncludingDeleted = `-- name: GetPhotoIncludingDeleted :one
+SELECT id, album_id, filename, width, height, size_bytes, format, created_at, caption, position, taken_at, deleted_at, original_format, edits, animation_format, latitude, longitude FROM photos WHERE id = ?
+`
+
+func (q *Queries) GetPhotoIncludingDeleted(ctx context.Context, id int64) (Photo, error) {
+	row := q.db.QueryRowContext(ctx, getPhotoIncludingDeleted, id)
+	var i Photo
+	err := row.Scan(
+		&i.ID,
+		&i.AlbumID,
+		&i.Filename,
+		&i.Width,
+		&i.Height,
+		&i.SizeBytes,
+		&i.Format,
+		&i.CreatedAt,
+		&i.Caption,
+		&i.Position,
+		&i.TakenAt,
+		&i.DeletedAt,
+		&i.OriginalFormat,
+		&i.Edits,
+		&i.AnimationFormat,
+		&i.Latitude,
+		&i.Longitude,
 	)
 	return i, err
 }
@@ -144,10 +300,11 @@ func (q *Queries) GetTotalStorageBytes(ctx context.Context) (interface{}, error)
 
 const listAllPhotosWithAlbum = `-- name: ListAllPhotosWithAlbum :many
 SELECT 
-    p.id, p.album_id, p.filename, p.width, p.height, p.size_bytes, p.format, p.created_at,
+    p.id, p.album_id, p.filename, p.width, p.height, p.size_bytes, p.format, p.created_at, p.caption, p.position, p.taken_at, p.deleted_at, p.original_format, p.edits, p.animation_format, p.latitude, p.longitude,
     a.title as album_title
 FROM photos p
 JOIN albums a ON p.album_id = a.id
+WHERE p.deleted_at IS NULL AND a.deleted_at IS NULL
 ORDER BY p.created_at DESC
 LIMIT ? OFFSET ?
 `
@@ -158,15 +315,24 @@ type ListAllPhotosWithAlbumParams struct {
 }
 
 type ListAllPhotosWithAlbumRow struct {
-	ID         int64        `json:"id"`
-	AlbumID    int64        `json:"album_id"`
-	Filename   string       `json:"filename"`
-	Width      int64        `json:"width"`
-	Height     int64        `json:"height"`
-	SizeBytes  int64        `json:"size_bytes"`
-	Format     string       `json:"format"`
-	CreatedAt  sql.NullTime `json:"created_at"`
-	AlbumTitle string       `json:"album_title"`
+	ID              int64           `json:"id"`
+	AlbumID         int64           `json:"album_id"`
+	Filename        string          `json:"filename"`
+	Width           int64           `json:"width"`
+	Height          int64           `json:"height"`
+	SizeBytes       int64           `json:"size_bytes"`
+	Format          string          `json:"format"`
+	CreatedAt       sql.NullTime    `json:"created_at"`
+	Caption         sql.NullString  `json:"caption"`
+	Position        int64           `json:"position"`
+	TakenAt         sql.NullTime    `json:"taken_at"`
+	DeletedAt       sql.NullTime    `json:"deleted_at"`
+	OriginalFormat  sql.NullString  `json:"original_format"`
+	Edits           sql.NullString  `json:"edits"`
+	AnimationFormat sql.NullString  `json:"animation_format"`
+	Latitude        sql.NullFloat64 `json:"latitude"`
+	Longitude       sql.NullFloat64 `json:"longitude"`
+	AlbumTitle      string          `json:"album_title"`
 }
 
 func (q *Queries) ListAllPhotosWithAlbum(ctx context.Context, arg ListAllPhotosWithAlbumParams) ([]ListAllPhotosWithAlbumRow, error) {
@@ -187,7 +353,183 @@ func (q *Queries) ListAllPhotosWithAlbum(ctx context.Context, arg ListAllPhotosW
 			&i.SizeBytes,
 			&i.Format,
 			&i.CreatedAt,
+			&i.Caption,
+			&i.Position,
+			&i.TakenAt,
+			&i.DeletedAt,
+			&i.OriginalFormat,
+			&i.Edits,
+			&i.AnimationFormat,
+			&i.Latitude,
+			&i.Longitude,
+			&i.AlbumTitle,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listAllPhotosWithAlbumByTag = `-- name: ListAllPhotosWithAlbumByTag :many
+SELECT 
+    p.id, p.album_id, p.filename, p.width, p.height, p.size_bytes, p.format, p.created_at, p.caption, p.position, p.taken_at, p.deleted_at, p.original_format, p.edits, p.animation_format, p.latitude, p.longitude,
+    a.title as album_title
+FROM photos p
+JOIN albums a ON p.album_id = a.id
+JOIN photo_tags pt ON pt.photo_id = p.id
+WHERE pt.tag_id = ? AND p.deleted_at IS NULL AND a.deleted_at IS NULL
+ORDER BY p.created_at DESC
+LIMIT ? OFFSET ?
+`
+
+type ListAllPhotosWithAlbumByTagParams struct {
+	TagID  int64 `json:"tag_id"`
+	Limit  int64 `json:"limit"`
+	Offset int64 `json:"offset"`
+}
+
+type ListAllPhotosWithAlbumByTagRow struct {
+	ID              int64           `json:"id"`
+	AlbumID         int64           `json:"album_id"`
+	Filename        string          `json:"filename"`
+	Width           int64           `json:"width"`
+	Height          int64           `json:"height"`
+	SizeBytes       int64           `json:"size_bytes"`
+	Format          string          `json:"format"`
+	CreatedAt       sql.NullTime    `json:"created_at"`
+	Caption         sql.NullString  `json:"caption"`
+	Position        int64           `json:"position"`
+	TakenAt         sql.NullTime    `json:"taken_at"`
+	DeletedAt       sql.NullTime    `json:"deleted_at"`
+	OriginalFormat  sql.NullString  `json:"original_format"`
+	Edits           sql.NullString  `json:"edits"`
+	AnimationFormat sql.NullString  `json:"animation_format"`
+	Latitude        sql.NullFloat64 `json:"latitude"`
+	Longitude       sql.NullFloat64 `json:"longitude"`
+	AlbumTitle      string          `json:"album_title"`
+}
+
+func (q *Queries) ListAllPhotosWithAlbumByTag(ctx context.Context, arg ListAllPhotosWithAlbumByTagParams) ([]ListAllPhotosWithAlbumByTagRow, error) {
+	rows, err := q.db.QueryContext(ctx, listAllPhotosWithAlbumByTag, arg.TagID, arg.Limit, arg.Offset)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []ListAllPhotosWithAlbumByTagRow{}
+	for rows.Next() {
+		var i ListAllPhotosWithAlbumByTagRow
+		if err := rows.Scan(
+			&i.ID,
+			&i.AlbumID,
+			&i.Filename,
+			&i.Width,
+			&i.Height,
+			&i.SizeBytes,
+			&i.Format,
+			&i.CreatedAt,
+			&i.Caption,
+			&i.Position,
+			&i.TakenAt,
+			&i.DeletedAt,
+			&i.OriginalFormat,
+			&i.Edits,
+			&i.AnimationFormat,
+			&i.Latitude,
+			&i.Longitude,
+			&i.AlbumTitle,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listOnThisDayPhotos = `-- name: ListOnThisDayPhotos :many
+SELECT
+    p.id, p.album_id, p.filename, p.width, p.height, p.size_bytes, p.format, p.created_at, p.caption, p.position, p.taken_at, p.deleted_at, p.original_format, p.edits, p.animation_format, p.latitude, p.longitude,
+    a.title as album_title,
+    substr(COALESCE(p.taken_at, p.created_at), 1, 19) as sort_key
+FROM photos p
+JOIN albums a ON p.album_id = a.id
+WHERE p.deleted_at IS NULL AND a.deleted_at IS NULL
+  AND substr(COALESCE(p.taken_at, p.created_at), 6, 5) = ?
+  AND substr(COALESCE(p.taken_at, p.created_at), 1, 4) < ?
+ORDER BY substr(COALESCE(p.taken_at, p.created_at), 1, 19) DESC, p.id DESC
+LIMIT ?
+`
+
+type ListOnThisDayPhotosParams struct {
+	MonthDay string `json:"month_day"`
+	Year     string `json:"year"`
+	Limit    int64  `json:"limit"`
+}
+
+type ListOnThisDayPhotosRow struct {
+	ID              int64           `json:"id"`
+	AlbumID         int64           `json:"album_id"`
+	Filename        string          `json:"filename"`
+	Width           int64           `json:"width"`
+	Height          int64           `json:"height"`
+	SizeBytes       int64           `json:"size_bytes"`
+	Format          string          `json:"format"`
+	CreatedAt       sql.NullTime    `json:"created_at"`
+	Caption         sql.NullString  `json:"caption"`
+	Position        int64           `json:"position"`
+	TakenAt         sql.NullTime    `json:"taken_at"`
+	DeletedAt       sql.NullTime    `json:"deleted_at"`
+	OriginalFormat  sql.NullString  `json:"original_format"`
+	Edits           sql.NullString  `json:"edits"`
+	AnimationFormat sql.NullString  `json:"animation_format"`
+	Latitude        sql.NullFloat64 `json:"latitude"`
+	Longitude       sql.NullFloat64 `json:"longitude"`
+	AlbumTitle      string          `json:"album_title"`
+	SortKey         string          `json:"sort_key"`
+}
+
+// Photos taken on a month and day ("MM-DD") in years before the given one.
+func (q *Queries) ListOnThisDayPhotos(ctx context.Context, arg ListOnThisDayPhotosParams) ([]ListOnThisDayPhotosRow, error) {
+	rows, err := q.db.QueryContext(ctx, listOnThisDayPhotos, arg.MonthDay, arg.Year, arg.Limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []ListOnThisDayPhotosRow{}
+	for rows.Next() {
+		var i ListOnThisDayPhotosRow
+		if err := rows.Scan(
+			&i.ID,
+			&i.AlbumID,
+			&i.Filename,
+			&i.Width,
+			&i.Height,
+			&i.SizeBytes,
+			&i.Format,
+			&i.CreatedAt,
+			&i.Caption,
+			&i.Position,
+			&i.TakenAt,
+			&i.DeletedAt,
+			&i.OriginalFormat,
+			&i.Edits,
+			&i.AnimationFormat,
+			&i.Latitude,
+			&i.Longitude,
 			&i.AlbumTitle,
+			&i.SortKey,
 		); err != nil {
 			return nil, err
 		}
@@ -202,8 +544,35 @@ func (q *Queries) ListAllPhotosWithAlbum(ctx context.Context, arg ListAllPhotosW
 	return items, nil
 }
 
+const listPhotoIDsByAlbumPosition = `-- name: ListPhotoIDsByAlbumPosition :many
+SELECT id FROM photos WHERE album_id = ? AND deleted_at IS NULL ORDER BY position ASC, id ASC
+`
+
+func (q *Queries) ListPhotoIDsByAlbumPosition(ctx context.Context, albumID int64) ([]int64, error) {
+	rows, err := q.db.QueryContext(ctx, listPhotoIDsByAlbumPosition, albumID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []int64{}
+	for rows.Next() {
+		var id int64
+		if err := rows.Scan(&id); err != nil {
+			return nil, err
+		}
+		items = append(items, id)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
 const listPhotosByAlbum = `-- name: ListPhotosByAlbum :many
-SELECT id, album_id, filename, width, height, size_bytes, format, created_at FROM photos WHERE album_id = ? ORDER BY created_at DESC LIMIT ? OFFSET ?
+SELECT id, album_id, filename, width, height, size_bytes, format, created_at, caption, position, taken_at, deleted_at, original_format, edits, animation_format, latitude, longitude FROM photos WHERE album_id = ? AND deleted_at IS NULL ORDER BY created_at DESC LIMIT ? OFFSET ?
 `
 
 type ListPhotosByAlbumParams struct {
@@ -230,6 +599,15 @@ func (q *Queries) ListPhotosByAlbum(ctx context.Context, arg ListPhotosByAlbumPa
 			&i.SizeBytes,
 			&i.Format,
 			&i.CreatedAt,
+			&i.Caption,
+			&i.Position,
+			&i.TakenAt,
+			&i.DeletedAt,
+			&i.OriginalFormat,
+			&i.Edits,
+			&i.AnimationFormat,
+			&i.Latitude,
+			&i.Longitude,
 		); err != nil {
 			return nil, err
 		}
@@ -244,25 +622,689 @@ func (q *Queries) ListPhotosByAlbum(ctx context.Context, arg ListPhotosByAlbumPa
 	return items, nil
 }
 
-const updatePhotoDimensions = `-- name: UpdatePhotoDimensions :exec
-UPDATE photos
-SET width = ?, height = ?, size_bytes = ?
-WHERE id = ?
+const listPhotosByAlbumPosition = `-- name: ListPhotosByAlbumPosition :many
+SELECT id, album_id, filename, width, height, size_bytes, format, created_at, caption, position, taken_at, deleted_at, original_format, edits, animation_format, latitude, longitude FROM photos WHERE album_id = ? AND deleted_at IS NULL ORDER BY position ASC, id ASC LIMIT ? OFFSET ?
 `
 
-type UpdatePhotoDimensionsParams struct {
-	Width     int64 `json:"width"`
-	Height    int64 `json:"height"`
-	SizeBytes int64 `json:"size_bytes"`
-	ID        int64 `json:"id"`
+type ListPhotosByAlbumPositionParams struct {
+	AlbumID int64 `json:"album_id"`
+	Limit   int64 `json:"limit"`
+	Offset  int64 `json:"offset"`
 }
 
-func (q *Queries) UpdatePhotoDimensions(ctx context.Context, arg UpdatePhotoDimensionsParams) error {
-	_, err := q.db.ExecContext(ctx, updatePhotoDimensions,
-		arg.Width,
-		arg.Height,
-		arg.SizeBytes,
-		arg.ID,
-	)
+func (q *Queries) ListPhotosByAlbumPosition(ctx context.Context, arg ListPhotosByAlbumPositionParams) ([]Photo, error) {
+	rows, err := q.db.QueryContext(ctx, listPhotosByAlbumPosition, arg.AlbumID, arg.Limit, arg.Offset)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []Photo{}
+	for rows.Next() {
+		var i Photo
+		if err := rows.Scan(
+			&i.ID,
+			&i.AlbumID,
+			&i.Filename,
+			&i.Width,
+			&i.Height,
+			&i.SizeBytes,
+			&i.Format,
+			&i.CreatedAt,
+			&i.Caption,
+			&i.Position,
+			&i.TakenAt,
+			&i.DeletedAt,
+			&i.OriginalFormat,
+			&i.Edits,
+			&i.AnimationFormat,
+			&i.Latitude,
+			&i.Longitude,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listPhotosByAlbumTakenAt = `-- name: ListPhotosByAlbumTakenAt :many
+SELECT id, album_id, filename, width, height, size_bytes, format, created_at, caption, position, taken_at, deleted_at, original_format, edits, animation_format, latitude, longitude FROM photos WHERE album_id = ? AND deleted_at IS NULL
+ORDER BY COALESCE(taken_at, created_at) ASC, id ASC
+LIMIT ? OFFSET ?
+`
+
+type ListPhotosByAlbumTakenAtParams struct {
+	AlbumID int64 `json:"album_id"`
+	Limit   int64 `json:"limit"`
+	Offset  int64 `json:"offset"`
+}
+
+func (q *Queries) ListPhotosByAlbumTakenAt(ctx context.Context, arg ListPhotosByAlbumTakenAtParams) ([]Photo, error) {
+	rows, err := q.db.QueryContext(ctx, listPhotosByAlbumTakenAt, arg.AlbumID, arg.Limit, arg.Offset)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []Photo{}
+	for rows.Next() {
+		var i Photo
+		if err := rows.Scan(
+			&i.ID,
+			&i.AlbumID,
+			&i.Filename,
+			&i.Width,
+			&i.Height,
+			&i.SizeBytes,
+			&i.Format,
+			&i.CreatedAt,
+			&i.Caption,
+			&i.Position,
+			&i.TakenAt,
+			&i.DeletedAt,
+			&i.OriginalFormat,
+			&i.Edits,
+			&i.AnimationFormat,
+			&i.Latitude,
+			&i.Longitude,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listPhotosForAlbumIncludingDeleted = `-- name: ListPhotosForAlbumIncludingDeleted :many
+SELECT id, album_id, filename, width, height, size_bytes, format, created_at, caption, position, taken_at, deleted_at, original_format, edits, animation_format, latitude, longitude FROM photos WHERE album_id = ?
+`
+
+func (q *Queries) ListPhotosForAlbumIncludingDeleted(ctx context.Context, albumID int64) ([]Photo, error) {
+	rows, err := q.db.QueryContext(ctx, listPhotosForAlbumIncludingDeleted, albumID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []Photo{}
+	for rows.Next() {
+		var i Photo
+		if err := rows.Scan(
+			&i.ID,
+			&i.AlbumID,
+			&i.Filename,
+			&i.Width,
+			&i.Height,
+			&i.SizeBytes,
+			&i.Format,
+			&i.CreatedAt,
+			&i.Caption,
+			&i.Position,
+			&i.TakenAt,
+			&i.DeletedAt,
+			&i.OriginalFormat,
+			&i.Edits,
+			&i.AnimationFormat,
+			&i.Latitude,
+			&i.Longitude,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listReprocessCandidates = `-- name: ListReprocessCandidates :many
+SELECT p.id, p.album_id, p.format,
+    CAST(COALESCE((
+        SELECT j.original_filename FROM processing_queue j
+        WHERE j.photo_id = p.id AND j.kind = 'upload'
+        ORDER BY j.id LIMIT 1
+    ), p.filename) AS TEXT) AS filename
+FROM photos p
+JOIN albums a ON a.id = p.album_id
+WHERE p.deleted_at IS NULL AND a.deleted_at IS NULL
+  AND NOT EXISTS (
+    SELECT 1 FROM processing_queue j
+    WHERE j.photo_id = p.id AND j.kind = 'reprocess' AND j.status IN ('pending', 'processing')
+  )
+ORDER BY p.id
+`
+
+type ListReprocessCandidatesRow struct {
+	ID       int64  `json:"id"`
+	AlbumID  int64  `json:"album_id"`
+	Format   string `json:"format"`
+	Filename string `json:"filename"`
+}
+
+// Live photos without a pending reprocess job, named by their upload filename.
+func (q *Queries) ListReprocessCandidates(ctx context.Context) ([]ListReprocessCandidatesRow, error) {
+	rows, err := q.db.QueryContext(ctx, listReprocessCandidates)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []ListReprocessCandidatesRow{}
+	for rows.Next() {
+		var i ListReprocessCandidatesRow
+		if err := rows.Scan(
+			&i.ID,
+			&i.AlbumID,
+			&i.Format,
+			&i.Filename,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listTimelineMonths = `-- name: ListTimelineMonths :many
+SELECT
+    substr(COALESCE(p.taken_at, p.created_at), 1, 7) as month,
+    COUNT(*) as count
+FROM photos p
+JOIN albums a ON p.album_id = a.id
+WHERE p.deleted_at IS NULL AND a.deleted_at IS NULL
+GROUP BY month
+ORDER BY month DESC
+`
+
+type ListTimelineMonthsRow struct {
+	Month string `json:"month"`
+	Count int64  `json:"count"`
+}
+
+func (q *Queries) ListTimelineMonths(ctx context.Context) ([]ListTimelineMonthsRow, error) {
+	rows, err := q.db.QueryContext(ctx, listTimelineMonths)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []ListTimelineMonthsRow{}
+	for rows.Next() {
+		var i ListTimelineMonthsRow
+		if err := rows.Scan(&i.Month, &i.Count); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listTimelinePhotos = `-- name: ListTimelinePhotos :many
+SELECT
+    p.id, p.album_id, p.filename, p.width, p.height, p.size_bytes, p.format, p.created_at, p.caption, p.position, p.taken_at, p.deleted_at, p.original_format, p.edits, p.animation_format, p.latitude, p.longitude,
+    a.title as album_title,
+    substr(COALESCE(p.taken_at, p.created_at), 1, 19) as sort_key
+FROM photos p INDEXED BY idx_photos_timeline
+JOIN albums a ON p.album_id = a.id
+WHERE p.deleted_at IS NULL AND a.deleted_at IS NULL
+  AND substr(COALESCE(p.taken_at, p.created_at), 1, 19) <= ?1
+  AND (substr(COALESCE(p.taken_at, p.created_at), 1, 19), p.id) < (?1, ?2)
+ORDER BY substr(COALESCE(p.taken_at, p.created_at), 1, 19) DESC, p.id DESC
+LIMIT ?
+`
+
+type ListTimelinePhotosParams struct {
+	BeforeKey string `json:"before_key"`
+	BeforeID  int64  `json:"before_id"`
+	Limit     int64  `json:"limit"`
+}
+
+type ListTimelinePhotosRow struct {
+	ID              int64           `json:"id"`
+	AlbumID         int64           `json:"album_id"`
+	Filename        string          `json:"filename"`
+	Width           int64           `json:"width"`
+	Height          int64           `json:"height"`
+	SizeBytes       int64           `json:"size_bytes"`
+	Format          string          `json:"format"`
+	CreatedAt       sql.NullTime    `json:"created_at"`
+	Caption         sql.NullString  `json:"caption"`
+	Position        int64           `json:"position"`
+	TakenAt         sql.NullTime    `json:"taken_at"`
+	DeletedAt       sql.NullTime    `json:"deleted_at"`
+	OriginalFormat  sql.NullString  `json:"original_format"`
+	Edits           sql.NullString  `json:"edits"`
+	AnimationFormat sql.NullString  `json:"animation_format"`
+	Latitude        sql.NullFloat64 `json:"latitude"`
+	Longitude       sql.NullFloat64 `json:"longitude"`
+	AlbumTitle      string          `json:"album_title"`
+	SortKey         string          `json:"sort_key"`
+}
+
+// Keyset page of ListAllPhotosWithAlbum in timeline order: newest capture
+// time first, falling back to the upload time. Pass the sort key and id of
+// the last photo of the previous page. The range on the sort key alone lets
+// the timeline index seek to the page instead of scanning up to it.
+func (q *Queries) ListTimelinePhotos(ctx context.Context, arg ListTimelinePhotosParams) ([]ListTimelinePhotosRow, error) {
+	rows, err := q.db.QueryContext(ctx, listTimelinePhotos, arg.BeforeKey, arg.BeforeID, arg.Limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []ListTimelinePhotosRow{}
+	for rows.Next() {
+		var i ListTimelinePhotosRow
+		if err := rows.Scan(
+			&i.ID,
+			&i.AlbumID,
+			&i.Filename,
+			&i.Width,
+			&i.Height,
+			&i.SizeBytes,
+			&i.Format,
+			&i.CreatedAt,
+			&i.Caption,
+			&i.Position,
+			&i.TakenAt,
+			&i.DeletedAt,
+			&i.OriginalFormat,
+			&i.Edits,
+			&i.AnimationFormat,
+			&i.Latitude,
+			&i.Longitude,
+			&i.AlbumTitle,
+			&i.SortKey,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listTrashedPhotos = `-- name: ListTrashedPhotos :many
+SELECT
+    p.id, p.album_id, p.filename, p.width, p.height, p.size_bytes, p.format, p.created_at, p.caption, p.position, p.taken_at, p.deleted_at, p.original_format, p.edits, p.animation_format, p.latitude, p.longitude,
+    a.title as album_title
+FROM photos p
+JOIN albums a ON p.album_id = a.id
+WHERE p.deleted_at IS NOT NULL AND a.deleted_at IS NULL
+ORDER BY p.deleted_at DESC
+`
+
+type ListTrashedPhotosRow struct {
+	ID              int64           `json:"id"`
+	AlbumID         int64           `json:"album_id"`
+	Filename        string          `json:"filename"`
+	Width           int64           `json:"width"`
+	Height          int64           `json:"height"`
+	SizeBytes       int64           `json:"size_bytes"`
+	Format          string          `json:"format"`
+	CreatedAt       sql.NullTime    `json:"created_at"`
+	Caption         sql.NullString  `json:"caption"`
+	Position        int64           `json:"position"`
+	TakenAt         sql.NullTime    `json:"taken_at"`
+	DeletedAt       sql.NullTime    `json:"deleted_at"`
+	OriginalFormat  sql.NullString  `json:"original_format"`
+	Edits           sql.NullString  `json:"edits"`
+	AnimationFormat sql.NullString  `json:"animation_format"`
+	Latitude        sql.NullFloat64 `json:"latitude"`
+	Longitude       sql.NullFloat64 `json:"longitude"`
+	AlbumTitle      string          `json:"album_title"`
+}
+
+// Photos trashed on their own; photos of a trashed album go with the album.
+func (q *Queries) ListTrashedPhotos(ctx context.Context) ([]ListTrashedPhotosRow, error) {
+	rows, err := q.db.QueryContext(ctx, listTrashedPhotos)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []ListTrashedPhotosRow{}
+	for rows.Next() {
+		var i ListTrashedPhotosRow
+		if err := rows.Scan(
+			&i.ID,
+			&i.AlbumID,
+			&i.Filename,
+			&i.Width,
+			&i.Height,
+			&i.SizeBytes,
+			&i.Format,
+			&i.CreatedAt,
+			&i.Caption,
+			&i.Position,
+			&i.TakenAt,
+			&i.DeletedAt,
+			&i.OriginalFormat,
+			&i.Edits,
+			&i.AnimationFormat,
+			&i.Latitude,
+			&i.Longitude,
+			&i.AlbumTitle,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const movePhotoToAlbum = `-- name: MovePhotoToAlbum :exec
+UPDATE photos SET album_id = ?, position = ? WHERE id = ?
+`
+
+type MovePhotoToAlbumParams struct {
+	AlbumID  int64 `json:"album_id"`
+	Position int64 `json:"position"`
+	ID       int64 `json:"id"`
+}
+
+func (q *Queries) MovePhotoToAlbum(ctx context.Context, arg MovePhotoToAlbumParams) error {
+	_, err := q.db.ExecContext(ctx, movePhotoToAlbum, arg.AlbumID, arg.Position, arg.ID)
+	return err
+}
+
+const purgePhotosOfTrashedAlbums = `-- name: PurgePhotosOfTrashedAlbums :many
+DELETE FROM photos
+WHERE album_id IN (SELECT id FROM albums WHERE deleted_at < ?)
+RETURNING id, album_id, format, created_at, original_format, animation_format
+`
+
+type PurgePhotosOfTrashedAlbumsRow struct {
+	ID              int64          `json:"id"`
+	AlbumID         int64          `json:"album_id"`
+	Format          string         `json:"format"`
+	CreatedAt       sql.NullTime   `json:"created_at"`
+	OriginalFormat  sql.NullString `json:"original_format"`
+	AnimationFormat sql.NullString `json:"animation_format"`
+}
+
+func (q *Queries) PurgePhotosOfTrashedAlbums(ctx context.Context, deletedAt sql.NullTime) ([]PurgePhotosOfTrashedAlbumsRow, error) {
+	rows, err := q.db.QueryContext(ctx, purgePhotosOfTrashedAlbums, deletedAt)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []PurgePhotosOfTrashedAlbumsRow{}
+	for rows.Next() {
+		var i PurgePhotosOfTrashedAlbumsRow
+		if err := rows.Scan(
+			&i.ID,
+			&i.AlbumID,
+			&i.Format,
+			&i.CreatedAt,
+			&i.OriginalFormat,
+			&i.AnimationFormat,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const purgeTrashedPhotos = `-- name: PurgeTrashedPhotos :many
+DELETE FROM photos
+WHERE deleted_at < ?
+RETURNING id, album_id, format, created_at, original_format, animation_format
+`
+
+type PurgeTrashedPhotosRow struct {
+	ID              int64          `json:"id"`
+	AlbumID         int64          `json:"album_id"`
+	Format          string         `json:"format"`
+	CreatedAt       sql.NullTime   `json:"created_at"`
+	OriginalFormat  sql.NullString `json:"original_format"`
+	AnimationFormat sql.NullString `json:"animation_format"`
+}
+
+func (q *Queries) PurgeTrashedPhotos(ctx context.Context, deletedAt sql.NullTime) ([]PurgeTrashedPhotosRow, error) {
+	rows, err := q.db.QueryContext(ctx, purgeTrashedPhotos, deletedAt)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []PurgeTrashedPhotosRow{}
+	for rows.Next() {
+		var i PurgeTrashedPhotosRow
+		if err := rows.Scan(
+			&i.ID,
+			&i.AlbumID,
+			&i.Format,
+			&i.CreatedAt,
+			&i.OriginalFormat,
+			&i.AnimationFormat,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const restorePhoto = `-- name: RestorePhoto :exec
+UPDATE photos SET deleted_at = NULL WHERE id = ?
+`
+
+func (q *Queries) RestorePhoto(ctx context.Context, id int64) error {
+	_, err := q.db.ExecContext(ctx, restorePhoto, id)
+	return err
+}
+
+const setPhotoAnimationFormat = `-- name: SetPhotoAnimationFormat :exec
+UPDATE photos SET animation_format = ? WHERE id = ?
+`
+
+type SetPhotoAnimationFormatParams struct {
+	AnimationFormat sql.NullString `json:"animation_format"`
+	ID              int64          `json:"id"`
+}
+
+func (q *Queries) SetPhotoAnimationFormat(ctx context.Context, arg SetPhotoAnimationFormatParams) error {
+	_, err := q.db.ExecContext(ctx, setPhotoAnimationFormat, arg.AnimationFormat, arg.ID)
+	return err
+}
+
+const setPhotoEdits = `-- name: SetPhotoEdits :exec
+UPDATE photos SET edits = ? WHERE id = ?
+`
+
+type SetPhotoEditsParams struct {
+	Edits sql.NullString `json:"edits"`
+	ID    int64          `json:"id"`
+}
+
+func (q *Queries) SetPhotoEdits(ctx context.Context, arg SetPhotoEditsParams) error {
+	_, err := q.db.ExecContext(ctx, setPhotoEdits, arg.Edits, arg.ID)
+	return err
+}
+
+const setPhotoOriginalFormat = `-- name: SetPhotoOriginalFormat :exec
+UPDATE photos SET original_format = ? WHERE id = ?
+`
+
+type SetPhotoOriginalFormatParams struct {
+	OriginalFormat sql.NullString `json:"original_format"`
+	ID             int64          `json:"id"`
+}
+
+func (q *Queries) SetPhotoOriginalFormat(ctx context.Context, arg SetPhotoOriginalFormatParams) error {
+	_, err := q.db.ExecContext(ctx, setPhotoOriginalFormat, arg.OriginalFormat, arg.ID)
+	return err
+}
+
+const trashPhoto = `-- name: TrashPhoto :exec
+UPDATE photos SET deleted_at = CURRENT_TIMESTAMP WHERE id = ? AND deleted_at IS NULL
+`
+
+func (q *Queries) TrashPhoto(ctx context.Context, id int64) error {
+	_, err := q.db.ExecContext(ctx, trashPhoto, id)
+	return err
+}
+
+const updatePhotoCaption = `-- name: UpdatePhotoCaption :exec
+UPDATE photos
+SET caption = ?
+WHERE id = ?
+`
+
+type UpdatePhotoCaptionParams struct {
+	Caption sql.NullString `json:"caption"`
+	ID      int64          `json:"id"`
+}
+
+func (q *Queries) UpdatePhotoCaption(ctx context.Context, arg UpdatePhotoCaptionParams) error {
+	_, err := q.db.ExecContext(ctx, updatePhotoCaption, arg.Caption, arg.ID)
+	return err
+}
+
+const updatePhotoDimensions = `-- name: UpdatePhotoDimensions :exec
+UPDATE photos
+SET width = ?, height = ?, size_bytes = ?
+WHERE id = ?
+`
+
+type UpdatePhotoDimensionsParams struct {
+	Width     int64 `json:"width"`
+	Height    int64 `json:"height"`
+	SizeBytes int64 `json:"size_bytes"`
+	ID        int64 `json:"id"`
+}
+
+func (q *Queries) UpdatePhotoDimensions(ctx context.Context, arg UpdatePhotoDimensionsParams) error {
+	_, err := q.db.ExecContext(ctx, updatePhotoDimensions,
+		arg.Width,
+		arg.Height,
+		arg.SizeBytes,
+		arg.ID,
+	)
+	return err
+}
+
+const updatePhotoLocation = `-- name: UpdatePhotoLocation :exec
+UPDATE photos
+SET latitude = ?, longitude = ?
+WHERE id = ?
+`
+
+type UpdatePhotoLocationParams struct {
+	Latitude  sql.NullFloat64 `json:"latitude"`
+	Longitude sql.NullFloat64 `json:"longitude"`
+	ID        int64           `json:"id"`
+}
+
+func (q *Queries) UpdatePhotoLocation(ctx context.Context, arg UpdatePhotoLocationParams) error {
+	_, err := q.db.ExecContext(ctx, updatePhotoLocation, arg.Latitude, arg.Longitude, arg.ID)
+	return err
+}
+
+const updatePhotoPosition = `-- name: UpdatePhotoPosition :exec
+UPDATE photos
+SET position = ?
+WHERE id = ? AND album_id = ?
+`
+
+type UpdatePhotoPositionParams struct {
+	Position int64 `json:"position"`
+	ID       int64 `json:"id"`
+	AlbumID  int64 `json:"album_id"`
+}
+
+func (q *Queries) UpdatePhotoPosition(ctx context.Context, arg UpdatePhotoPositionParams) error {
+	_, err := q.db.ExecContext(ctx, updatePhotoPosition, arg.Position, arg.ID, arg.AlbumID)
+	return err
+}
+
+const updatePhotoRendition = `-- name: UpdatePhotoRendition :exec
+UPDATE photos
+SET filename = ?, format = ?, width = ?, height = ?, size_bytes = ?, edits = ?
+WHERE id = ?
+`
+
+type UpdatePhotoRenditionParams struct {
+	Filename  string         `json:"filename"`
+	Format    string         `json:"format"`
+	Width     int64          `json:"width"`
+	Height    int64          `json:"height"`
+	SizeBytes int64          `json:"size_bytes"`
+	Edits     sql.NullString `json:"edits"`
+	ID        int64          `json:"id"`
+}
+
+func (q *Queries) UpdatePhotoRendition(ctx context.Context, arg UpdatePhotoRenditionParams) error {
+	_, err := q.db.ExecContext(ctx, updatePhotoRendition,
+		arg.Filename,
+		arg.Format,
+		arg.Width,
+		arg.Height,
+		arg.SizeBytes,
+		arg.Edits,
+		arg.ID,
+	)
+	return err
+}
+
+const updatePhotoTakenAt = `-- name: UpdatePhotoTakenAt :exec
+UPDATE photos
+SET taken_at = ?
+WHERE id = ?
+`
+
+type UpdatePhotoTakenAtParams struct {
+	TakenAt sql.NullTime `json:"taken_at"`
+	ID      int64        `json:"id"`
+}
+
+func (q *Queries) UpdatePhotoTakenAt(ctx context.Context, arg UpdatePhotoTakenAtParams) error {
+	_, err := q.db.ExecContext(ctx, updatePhotoTakenAt, arg.TakenAt, arg.ID)
 	return err
 }
@@ -32,6 +32,45 @@ func (q *Queries) CountActiveJobs(ctx context.Context, albumID int64) (int64, er
 	return count, err
 }
 
+const deleteExpiredFailedJobs = `-- name: DeleteExpiredFailedJobs :many
+DELETE FROM processing_queue
+WHERE status = 'failed' AND updated_at < ?
+RETURNING temp_filepath
+`
+
+func (q *Queries) DeleteExpiredFailedJobs(ctx context.Context, updatedAt sql.NullTime) ([]string, error) {
+	rows, err := q.db.QueryContext(ctx, deleteExpiredFailedJobs, updatedAt)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []string{}
+	for rows.Next() {
+		var temp_filepath string
+		if err := rows.Scan(&temp_filepath); err != nil {
+			return nil, err
+		}
+		items = append(items, temp_filepath)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const deleteFinishedReprocessJobs = `-- name: DeleteFinishedReprocessJobs :exec
+DELETE FROM processing_queue
+WHERE kind = 'reprocess' AND status IN ('completed', 'failed')
+`
+
+func (q *Queries) DeleteFinishedReprocessJobs(ctx context.Context) error {
+	_, err := q.db.ExecContext(ctx, deleteFinishedReprocessJobs)
+	return err
+}
+
 const deleteJob = `-- name: DeleteJob :exec
 DELETE FROM processing_queue
 WHERE id = ?
@@ -48,7 +87,7 @@ INSERT INTO processing_queue (
 ) VALUES (
     ?, ?, ?, 'pending'
 )
-RETURNING id, album_id, original_filename, temp_filepath, status, error_message, created_at, updated_at
+RETURNING id, album_id, original_filename, temp_filepath, status, error_message, created_at, updated_at, photo_id, attempts, next_attempt_at, lease_expires_at, heartbeat_at, kind
 `
 
 type EnqueueJobParams struct {
@@ -69,24 +108,96 @@ func (q *Queries) EnqueueJob(ctx context.Context, arg EnqueueJobParams) (Process
 		&i.ErrorMessage,
 		&i.CreatedAt,
 		&i.UpdatedAt,
+		&i.PhotoID,
+		&i.Attempts,
+		&i.NextAttemptAt,
+		&i.LeaseExpiresAt,
+		&i.HeartbeatAt,
+		&i.Kind,
+	)
+	return i, err
+}
+
+const enqueueReprocessJob = `-- name: EnqueueReprocessJob :one
+INSERT INTO processing_queue (
+    album_id, original_filename, temp_filepath, status, kind, photo_id
+) VALUES (
+    ?, ?, '', 'pending', 'reprocess', ?
+)
+RETURNING id, album_id, original_filename, temp_filepath, status, error_message, created_at, updated_at, photo_id, attempts, next_attempt_at, lease_expires_at, heartbeat_at, kind
+`
+
+type EnqueueReprocessJobParams struct {
+	AlbumID          int64         `json:"album_id"`
+	OriginalFilename string        `json:"original_filename"`
+	PhotoID          sql.NullInt64 `json:"photo_id"`
+}
+
+func (q *Queries) EnqueueReprocessJob(ctx context.Context, arg EnqueueReprocessJobParams) (ProcessingQueue, error) {
+	row := q.db.QueryRowContext(ctx, enqueueReprocessJob, arg.AlbumID, arg.OriginalFilename, arg.PhotoID)
+	var i ProcessingQueue
+	err := row.Scan(
+		&i.ID,
+		&i.AlbumID,
+		&i.OriginalFilename,
+		&i.TempFilepath,
+		&i.Status,
+		&i.ErrorMessage,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.PhotoID,
+		&i.Attempts,
+		&i.NextAttemptAt,
+		&i.LeaseExpiresAt,
+		&i.HeartbeatAt,
+		&i.Kind,
+	)
+	return i, err
+}
+
+const getJob = `-- name: GetJob :one
+SELECT id, album_id, original_filename, temp_filepath, status, error_message, created_at, updated_at, photo_id, attempts, next_attempt_at, lease_expires_at, heartbeat_at, kind FROM processing_queue
+WHERE id = ?
+`
+
+func (q *Queries) GetJob(ctx context.Context, id int64) (ProcessingQueue, error) {
+	row := q.db.QueryRowContext(ctx, getJob, id)
+	var i ProcessingQueue
+	err := row.Scan(
+		&i.ID,
+		&i.AlbumID,
+		&i.OriginalFilename,
+		&i.TempFilepath,
+		&i.Status,
+		&i.ErrorMessage,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.PhotoID,
+		&i.Attempts,
+		&i.NextAttemptAt,
+		&i.LeaseExpiresAt,
+		&i.HeartbeatAt,
+		&i.Kind,
 	)
 	return i, err
 }
 
 const getNextPendingJob = `-- name: GetNextPendingJob :one
 UPDATE processing_queue
-SET status = 'processing', updated_at = CURRENT_TIMESTAMP
+SET status = 'processing', heartbeat_at = CURRENT_TIMESTAMP,
+    lease_expires_at = datetime('now', ?), updated_at = CURRENT_TIMESTAMP
 WHERE id = (
   SELECT id FROM processing_queue
   WHERE status = 'pending'
+    AND (next_attempt_at IS NULL OR next_attempt_at <= CURRENT_TIMESTAMP)
   ORDER BY created_at ASC
   LIMIT 1
 )
-RETURNING id, album_id, original_filename, temp_filepath, status, error_message, created_at, updated_at
+RETURNING id, album_id, original_filename, temp_filepath, status, error_message, created_at, updated_at, photo_id, attempts, next_attempt_at, lease_expires_at, heartbeat_at, kind
 `
 
-func (q *Queries) GetNextPendingJob(ctx context.Context) (ProcessingQueue, error) {
-	row := q.db.QueryRowContext(ctx, getNextPendingJob)
+func (q *Queries) GetNextPendingJob(ctx context.Context, lease string) (ProcessingQueue, error) {
+	row := q.db.QueryRowContext(ctx, getNextPendingJob, lease)
 	var i ProcessingQueue
 	err := row.Scan(
 		&i.ID,
@@ -97,6 +208,12 @@ func (q *Queries) GetNextPendingJob(ctx context.Context) (ProcessingQueue, error
 		&i.ErrorMessage,
 		&i.CreatedAt,
 		&i.UpdatedAt,
+		&i.PhotoID,
+		&i.Attempts,
+		&i.NextAttemptAt,
+		&i.LeaseExpiresAt,
+		&i.HeartbeatAt,
+		&i.Kind,
 	)
 	return i, err
 }
@@ -130,8 +247,53 @@ func (q *Queries) GetQueueStatus(ctx context.Context, albumID int64) (GetQueueSt
 	return i, err
 }
 
+const getReprocessStatus = `-- name: GetReprocessStatus :one
+SELECT
+    COUNT(CASE WHEN status = 'pending' THEN 1 END) AS pending_count,
+    COUNT(CASE WHEN status = 'processing' THEN 1 END) AS processing_count,
+    COUNT(CASE WHEN status = 'failed' THEN 1 END) AS failed_count,
+    COUNT(CASE WHEN status = 'completed' THEN 1 END) AS completed_count
+FROM processing_queue
+WHERE kind = 'reprocess' AND id >= ?
+`
+
+type GetReprocessStatusRow struct {
+	PendingCount    int64 `json:"pending_count"`
+	ProcessingCount int64 `json:"processing_count"`
+	FailedCount     int64 `json:"failed_count"`
+	CompletedCount  int64 `json:"completed_count"`
+}
+
+func (q *Queries) GetReprocessStatus(ctx context.Context, id int64) (GetReprocessStatusRow, error) {
+	row := q.db.QueryRowContext(ctx, getReprocessStatus, id)
+	var i GetReprocessStatusRow
+	err := row.Scan(
+		&i.PendingCount,
+		&i.ProcessingCount,
+		&i.FailedCount,
+		&i.CompletedCount,
+	)
+	return i, err
+}
+
+const heartbeatJob = `-- name: HeartbeatJob :exec
+UPDATE processing_queue
+SET heartbeat_at = CURRENT_TIMESTAMP, lease_expires_at = datetime('now', ?)
+WHERE id = ? AND status = 'processing'
+`
+
+type HeartbeatJobParams struct {
+	Lease string `json:"lease"`
+	ID    int64  `json:"id"`
+}
+
+func (q *Queries) HeartbeatJob(ctx context.Context, arg HeartbeatJobParams) error {
+	_, err := q.db.ExecContext(ctx, heartbeatJob, arg.Lease, arg.ID)
+	return err
+}
+
 const listFailedJobs = `-- name: ListFailedJobs :many
-SELECT id, album_id, original_filename, temp_filepath, status, error_message, created_at, updated_at FROM processing_queue
+SELECT id, album_id, original_filename, temp_filepath, status, error_message, created_at, updated_at, photo_id, attempts, next_attempt_at, lease_expires_at, heartbeat_at, kind FROM processing_queue
 WHERE album_id = ? AND status = 'failed'
 ORDER BY created_at ASC
 `
@@ -154,6 +316,66 @@ func (q *Queries) ListFailedJobs(ctx context.Context, albumID int64) ([]Processi
 			&i.ErrorMessage,
 			&i.CreatedAt,
 			&i.UpdatedAt,
+			&i.PhotoID,
+			&i.Attempts,
+			&i.NextAttemptAt,
+			&i.LeaseExpiresAt,
+			&i.HeartbeatAt,
+			&i.Kind,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listFailedJobsWithAlbum = `-- name: ListFailedJobsWithAlbum :many
+SELECT q.id, q.album_id, q.original_filename, q.temp_filepath, q.error_message,
+    q.attempts, q.updated_at, q.kind, a.title AS album_title
+FROM processing_queue q
+JOIN albums a ON a.id = q.album_id
+WHERE q.status = 'failed' AND a.deleted_at IS NULL
+ORDER BY q.updated_at DESC, q.id DESC
+`
+
+type ListFailedJobsWithAlbumRow struct {
+	ID               int64          `json:"id"`
+	AlbumID          int64          `json:"album_id"`
+	OriginalFilename string         `json:"original_filename"`
+	TempFilepath     string         `json:"temp_filepath"`
+	ErrorMessage     sql.NullString `json:"error_message"`
+	Attempts         int64          `json:"attempts"`
+	UpdatedAt        sql.NullTime   `json:"updated_at"`
+	Kind             string         `json:"kind"`
+	AlbumTitle       string         `json:"album_title"`
+}
+
+func (q *Queries) ListFailedJobsWithAlbum(ctx context.Context) ([]ListFailedJobsWithAlbumRow, error) {
+	rows, err := q.db.QueryContext(ctx, listFailedJobsWithAlbum)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []ListFailedJobsWithAlbumRow{}
+	for rows.Next() {
+		var i ListFailedJobsWithAlbumRow
+		if err := rows.Scan(
+			&i.ID,
+			&i.AlbumID,
+			&i.OriginalFilename,
+			&i.TempFilepath,
+			&i.ErrorMessage,
+			&i.Attempts,
+			&i.UpdatedAt,
+			&i.Kind,
+			&i.AlbumTitle,
 		); err != nil {
 			return nil, err
 		}
@@ -168,6 +390,173 @@ func (q *Queries) ListFailedJobs(ctx context.Context, albumID int64) ([]Processi
 	return items, nil
 }
 
+const listJobStatusesByAlbum = `-- name: ListJobStatusesByAlbum :many
+SELECT id, status FROM processing_queue
+WHERE album_id = ?
+ORDER BY id
+`
+
+type ListJobStatusesByAlbumRow struct {
+	ID     int64  `json:"id"`
+	Status string `json:"status"`
+}
+
+func (q *Queries) ListJobStatusesByAlbum(ctx context.Context, albumID int64) ([]ListJobStatusesByAlbumRow, error) {
+	rows, err := q.db.QueryContext(ctx, listJobStatusesByAlbum, albumID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []ListJobStatusesByAlbumRow{}
+	for rows.Next() {
+		var i ListJobStatusesByAlbumRow
+		if err := rows.Scan(&i.ID, &i.Status); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listJobTempFiles = `-- name: ListJobTempFiles :many
+SELECT temp_filepath FROM processing_queue
+WHERE status IN ('pending', 'processing', 'failed')
+`
+
+// Temp inputs still needed by a queued, running or failed job.
+func (q *Queries) ListJobTempFiles(ctx context.Context) ([]string, error) {
+	rows, err := q.db.QueryContext(ctx, listJobTempFiles)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []string{}
+	for rows.Next() {
+		var temp_filepath string
+		if err := rows.Scan(&temp_filepath); err != nil {
+			return nil, err
+		}
+		items = append(items, temp_filepath)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const moveJobsToAlbum = `-- name: MoveJobsToAlbum :exec
+UPDATE processing_queue
+SET album_id = ?, updated_at = CURRENT_TIMESTAMP
+WHERE photo_id = ?
+`
+
+type MoveJobsToAlbumParams struct {
+	AlbumID int64         `json:"album_id"`
+	PhotoID sql.NullInt64 `json:"photo_id"`
+}
+
+func (q *Queries) MoveJobsToAlbum(ctx context.Context, arg MoveJobsToAlbumParams) error {
+	_, err := q.db.ExecContext(ctx, moveJobsToAlbum, arg.AlbumID, arg.PhotoID)
+	return err
+}
+
+const reclaimExpiredJobs = `-- name: ReclaimExpiredJobs :many
+UPDATE processing_queue
+SET status = 'pending', lease_expires_at = NULL, heartbeat_at = NULL, updated_at = CURRENT_TIMESTAMP
+WHERE status = 'processing'
+  AND (lease_expires_at IS NULL OR lease_expires_at < CURRENT_TIMESTAMP)
+RETURNING id
+`
+
+func (q *Queries) ReclaimExpiredJobs(ctx context.Context) ([]int64, error) {
+	rows, err := q.db.QueryContext(ctx, reclaimExpiredJobs)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []int64{}
+	for rows.Next() {
+		var id int64
+		if err := rows.Scan(&id); err != nil {
+			return nil, err
+		}
+		items = append(items, id)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const requeueFailedJob = `-- name: RequeueFailedJob :exec
+UPDATE processing_queue
+SET status = 'pending', error_message = NULL, attempts = 0, next_attempt_at = NULL,
+    updated_at = CURRENT_TIMESTAMP
+WHERE id = ? AND status = 'failed'
+`
+
+func (q *Queries) RequeueFailedJob(ctx context.Context, id int64) error {
+	_, err := q.db.ExecContext(ctx, requeueFailedJob, id)
+	return err
+}
+
+const requeueJob = `-- name: RequeueJob :exec
+UPDATE processing_queue
+SET status = 'pending', lease_expires_at = NULL, heartbeat_at = NULL, updated_at = CURRENT_TIMESTAMP
+WHERE id = ? AND status = 'processing'
+`
+
+func (q *Queries) RequeueJob(ctx context.Context, id int64) error {
+	_, err := q.db.ExecContext(ctx, requeueJob, id)
+	return err
+}
+
+const retryJobLater = `-- name: RetryJobLater :exec
+UPDATE processing_queue
+SET status = 'pending', attempts = attempts + 1, error_message = ?,
+    next_attempt_at = datetime('now', ?), updated_at = CURRENT_TIMESTAMP
+WHERE id = ?
+`
+
+type RetryJobLaterParams struct {
+	ErrorMessage sql.NullString `json:"error_message"`
+	Delay        string         `json:"delay"`
+	ID           int64          `json:"id"`
+}
+
+func (q *Queries) RetryJobLater(ctx context.Context, arg RetryJobLaterParams) error {
+	_, err := q.db.ExecContext(ctx, retryJobLater, arg.ErrorMessage, arg.Delay, arg.ID)
+	return err
+}
+
+const setJobPhoto = `-- name: SetJobPhoto :exec
+UPDATE processing_queue
+SET photo_id = ?, updated_at = CURRENT_TIMESTAMP
+WHERE id = ?
+`
+
+type SetJobPhotoParams struct {
+	PhotoID sql.NullInt64 `json:"photo_id"`
+	ID      int64         `json:"id"`
+}
+
+func (q *Queries) SetJobPhoto(ctx context.Context, arg SetJobPhotoParams) error {
+	_, err := q.db.ExecContext(ctx, setJobPhoto, arg.PhotoID, arg.ID)
+	return err
+}
+
 const updateJobStatus = `-- name: UpdateJobStatus :exec
 UPDATE processing_queue
 SET status = ?, error_message = ?, updated_at = CURRENT_TIMESTAMP
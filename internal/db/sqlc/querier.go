@@ -10,14 +10,26 @@ import (
 )
 
 type Querier interface {
+	AddPhotoTag(ctx context.Context, arg AddPhotoTagParams) error
 	ClearAlbumCoverIfPhoto(ctx context.Context, coverPhotoID sql.NullInt64) error
+	ClearAlbumProcessingProfile(ctx context.Context, processingProfileID sql.NullInt64) error
 	ClearFailedJobs(ctx context.Context, albumID int64) error
+	// Geotagged photos within a bounding box, grouped into a grid of cells of
+	// cell degrees. Each cluster is placed at the mean location of its photos
+	// and represented by its newest photo. album_id limits it to one album.
+	ClusterPhotoLocations(ctx context.Context, arg ClusterPhotoLocationsParams) ([]ClusterPhotoLocationsRow, error)
 	CountActiveJobs(ctx context.Context, albumID int64) (int64, error)
 	CountActivityByTypeSince(ctx context.Context, createdAt sql.NullTime) ([]CountActivityByTypeSinceRow, error)
+	CountAlbumPhotos(ctx context.Context, albumID int64) (int64, error)
 	CountAlbumViewsSince(ctx context.Context, createdAt sql.NullTime) (int64, error)
 	CountAlbums(ctx context.Context) (int64, error)
+	CountAlbumsByProcessingProfile(ctx context.Context) ([]CountAlbumsByProcessingProfileRow, error)
+	CountPhotoTag(ctx context.Context, arg CountPhotoTagParams) (int64, error)
 	CountPhotoViewsSince(ctx context.Context, createdAt sql.NullTime) (int64, error)
 	CountPhotos(ctx context.Context) (int64, error)
+	CountPhotosByTag(ctx context.Context, tagID int64) (int64, error)
+	CountReactionsSince(ctx context.Context, createdAt sql.NullTime) (int64, error)
+	CountShareLinkRecipients(ctx context.Context, shareLinkID int64) (int64, error)
 	CountShareLinks(ctx context.Context) (int64, error)
 	CountShareViewsSince(ctx context.Context, createdAt sql.NullTime) (int64, error)
 	CountUniqueShareLinkViews(ctx context.Context, shareLinkID int64) (int64, error)
@@ -25,43 +37,140 @@ type Querier interface {
 	CreateActivityEvent(ctx context.Context, arg CreateActivityEventParams) error
 	CreateAlbum(ctx context.Context, arg CreateAlbumParams) (Album, error)
 	CreatePhoto(ctx context.Context, arg CreatePhotoParams) (Photo, error)
+	CreatePhotoComment(ctx context.Context, arg CreatePhotoCommentParams) (PhotoComment, error)
+	CreateProcessingProfile(ctx context.Context, arg CreateProcessingProfileParams) (ProcessingProfile, error)
 	CreateSession(ctx context.Context, arg CreateSessionParams) (Session, error)
 	CreateShareLink(ctx context.Context, arg CreateShareLinkParams) (ShareLink, error)
+	CreateShareLinkRecipient(ctx context.Context, arg CreateShareLinkRecipientParams) (ShareLinkRecipient, error)
+	CreateTag(ctx context.Context, name string) (Tag, error)
 	DeleteAlbum(ctx context.Context, id int64) error
+	DeleteExpiredFailedJobs(ctx context.Context, updatedAt sql.NullTime) ([]string, error)
 	DeleteExpiredSessions(ctx context.Context) error
-	DeleteExpiredShareLinks(ctx context.Context) ([]DeleteExpiredShareLinksRow, error)
+	DeleteExpiredShareLinks(ctx context.Context, cutoff sql.NullTime) ([]DeleteExpiredShareLinksRow, error)
+	DeleteFinishedReprocessJobs(ctx context.Context) error
 	DeleteJob(ctx context.Context, id int64) error
 	DeleteOldActivityEvents(ctx context.Context, createdAt sql.NullTime) error
 	DeleteOrphanedPhotos(ctx context.Context) ([]DeleteOrphanedPhotosRow, error)
 	DeletePhoto(ctx context.Context, id int64) error
+	DeletePhotoComment(ctx context.Context, id int64) error
+	DeletePhotoReaction(ctx context.Context, arg DeletePhotoReactionParams) error
+	DeleteProcessingProfile(ctx context.Context, id int64) error
 	DeleteSession(ctx context.Context, id string) error
+	DeleteTag(ctx context.Context, id int64) error
 	DeleteUserSessions(ctx context.Context, userID string) error
 	EnqueueJob(ctx context.Context, arg EnqueueJobParams) (ProcessingQueue, error)
+	EnqueueReprocessJob(ctx context.Context, arg EnqueueReprocessJobParams) (ProcessingQueue, error)
 	GetAlbum(ctx context.Context, id int64) (Album, error)
+	GetAlbumIncludingDeleted(ctx context.Context, id int64) (Album, error)
+	GetAlbumProcessingProfile(ctx context.Context, id int64) (ProcessingProfile, error)
 	GetAlbumWithPhotoCount(ctx context.Context, id int64) (GetAlbumWithPhotoCountRow, error)
-	GetNextPendingJob(ctx context.Context) (ProcessingQueue, error)
+	GetJob(ctx context.Context, id int64) (ProcessingQueue, error)
+	GetMaxPhotoPosition(ctx context.Context, albumID int64) (int64, error)
+	GetNextPendingJob(ctx context.Context, lease string) (ProcessingQueue, error)
 	GetPhoto(ctx context.Context, id int64) (Photo, error)
+	GetPhotoComment(ctx context.Context, id int64) (PhotoComment, error)
+	GetPhotoIncludingDeleted(ctx context.Context, id int64) (Photo, error)
 	GetPhotosForAlbum(ctx context.Context, albumID int64) ([]Photo, error)
+	GetProcessingProfile(ctx context.Context, id int64) (ProcessingProfile, error)
 	GetQueueStatus(ctx context.Context, albumID int64) (GetQueueStatusRow, error)
+	GetReprocessStatus(ctx context.Context, id int64) (GetReprocessStatusRow, error)
 	GetSession(ctx context.Context, id string) (Session, error)
 	GetShareLink(ctx context.Context, id int64) (ShareLink, error)
+	GetShareLinkBySlug(ctx context.Context, slug sql.NullString) (ShareLink, error)
 	GetShareLinkByToken(ctx context.Context, token string) (ShareLink, error)
+	GetShareLinkRecipientByToken(ctx context.Context, token string) (ShareLinkRecipient, error)
+	GetTag(ctx context.Context, id int64) (Tag, error)
+	GetTagByName(ctx context.Context, name string) (Tag, error)
 	GetTotalStorageBytes(ctx context.Context) (interface{}, error)
+	GetViewerReaction(ctx context.Context, arg GetViewerReactionParams) (string, error)
+	HeartbeatJob(ctx context.Context, arg HeartbeatJobParams) error
 	IncrementShareLinkView(ctx context.Context, arg IncrementShareLinkViewParams) error
 	ListActiveShareLinks(ctx context.Context, arg ListActiveShareLinksParams) ([]ShareLink, error)
 	ListAlbums(ctx context.Context, arg ListAlbumsParams) ([]Album, error)
 	ListAlbumsWithPhotoCount(ctx context.Context, arg ListAlbumsWithPhotoCountParams) ([]ListAlbumsWithPhotoCountRow, error)
 	ListAllPhotosWithAlbum(ctx context.Context, arg ListAllPhotosWithAlbumParams) ([]ListAllPhotosWithAlbumRow, error)
+	ListAllPhotosWithAlbumByTag(ctx context.Context, arg ListAllPhotosWithAlbumByTagParams) ([]ListAllPhotosWithAlbumByTagRow, error)
 	ListFailedJobs(ctx context.Context, albumID int64) ([]ProcessingQueue, error)
+	ListFailedJobsWithAlbum(ctx context.Context) ([]ListFailedJobsWithAlbumRow, error)
+	ListJobStatusesByAlbum(ctx context.Context, albumID int64) ([]ListJobStatusesByAlbumRow, error)
+	// Temp inputs still needed by a queued, running or failed job.
+	ListJobTempFiles(ctx context.Context) ([]string, error)
+	// Photos taken on a month and day ("MM-DD") in years before the given one.
+	ListOnThisDayPhotos(ctx context.Context, arg ListOnThisDayPhotosParams) ([]ListOnThisDayPhotosRow, error)
+	ListPhotoCommentsWithDetails(ctx context.Context, arg ListPhotoCommentsWithDetailsParams) ([]ListPhotoCommentsWithDetailsRow, error)
+	ListPhotoIDsByAlbumPosition(ctx context.Context, albumID int64) ([]int64, error)
 	ListPhotosByAlbum(ctx context.Context, arg ListPhotosByAlbumParams) ([]Photo, error)
+	ListPhotosByAlbumPosition(ctx context.Context, arg ListPhotosByAlbumPositionParams) ([]Photo, error)
+	ListPhotosByAlbumTakenAt(ctx context.Context, arg ListPhotosByAlbumTakenAtParams) ([]Photo, error)
+	ListPhotosByTag(ctx context.Context, arg ListPhotosByTagParams) ([]Photo, error)
+	ListPhotosForAlbumIncludingDeleted(ctx context.Context, albumID int64) ([]Photo, error)
+	ListProcessingProfiles(ctx context.Context) ([]ProcessingProfile, error)
+	ListReactionCountsForAlbum(ctx context.Context, albumID int64) ([]ListReactionCountsForAlbumRow, error)
+	ListReactionCountsForPhoto(ctx context.Context, photoID int64) ([]ListReactionCountsForPhotoRow, error)
+	ListReactionCountsForTag(ctx context.Context, tagID int64) ([]ListReactionCountsForTagRow, error)
 	ListRecentActivity(ctx context.Context, arg ListRecentActivityParams) ([]ActivityEvent, error)
+	ListRecipientsForShareLinks(ctx context.Context, arg ListRecipientsForShareLinksParams) ([]ListRecipientsForShareLinksRow, error)
+	// Live photos without a pending reprocess job, named by their upload filename.
+	ListReprocessCandidates(ctx context.Context) ([]ListReprocessCandidatesRow, error)
+	ListSettings(ctx context.Context) ([]Setting, error)
+	ListShareLinkRecipients(ctx context.Context, shareLinkID int64) ([]ListShareLinkRecipientsRow, error)
 	ListShareLinks(ctx context.Context, arg ListShareLinksParams) ([]ShareLink, error)
 	ListShareLinksWithDetails(ctx context.Context, arg ListShareLinksWithDetailsParams) ([]ListShareLinksWithDetailsRow, error)
+	ListTags(ctx context.Context) ([]Tag, error)
+	ListTagsForAlbumPhotos(ctx context.Context, albumID int64) ([]ListTagsForAlbumPhotosRow, error)
+	ListTagsForPhoto(ctx context.Context, photoID int64) ([]Tag, error)
+	ListTagsWithPhotoCount(ctx context.Context) ([]ListTagsWithPhotoCountRow, error)
+	ListTimelineMonths(ctx context.Context) ([]ListTimelineMonthsRow, error)
+	// Keyset page of ListAllPhotosWithAlbum in timeline order: newest capture
+	// time first, falling back to the upload time. Pass the sort key and id of
+	// the last photo of the previous page. The range on the sort key alone lets
+	// the timeline index seek to the page instead of scanning up to it.
+	ListTimelinePhotos(ctx context.Context, arg ListTimelinePhotosParams) ([]ListTimelinePhotosRow, error)
+	ListTrashedAlbums(ctx context.Context) ([]ListTrashedAlbumsRow, error)
+	// Photos trashed on their own; photos of a trashed album go with the album.
+	ListTrashedPhotos(ctx context.Context) ([]ListTrashedPhotosRow, error)
+	ListViewerReactions(ctx context.Context, viewerHash string) ([]ListViewerReactionsRow, error)
+	ListVisiblePhotoComments(ctx context.Context, arg ListVisiblePhotoCommentsParams) ([]PhotoComment, error)
+	MoveJobsToAlbum(ctx context.Context, arg MoveJobsToAlbumParams) error
+	MovePhotoToAlbum(ctx context.Context, arg MovePhotoToAlbumParams) error
+	PurgePhotosOfTrashedAlbums(ctx context.Context, deletedAt sql.NullTime) ([]PurgePhotosOfTrashedAlbumsRow, error)
+	PurgeTrashedAlbums(ctx context.Context, deletedAt sql.NullTime) ([]int64, error)
+	PurgeTrashedPhotos(ctx context.Context, deletedAt sql.NullTime) ([]PurgeTrashedPhotosRow, error)
+	ReactivateShareLink(ctx context.Context, id int64) (int64, error)
+	ReclaimExpiredJobs(ctx context.Context) ([]int64, error)
+	RemovePhotoTag(ctx context.Context, arg RemovePhotoTagParams) error
+	RequeueFailedJob(ctx context.Context, id int64) error
+	RequeueJob(ctx context.Context, id int64) error
+	ResetShareLinkViews(ctx context.Context, shareLinkID int64) error
+	RestoreAlbum(ctx context.Context, id int64) error
+	RestorePhoto(ctx context.Context, id int64) error
+	RetryJobLater(ctx context.Context, arg RetryJobLaterParams) error
 	RevokeShareLink(ctx context.Context, id int64) error
+	RevokeShareLinkRecipient(ctx context.Context, arg RevokeShareLinkRecipientParams) (int64, error)
+	Search(ctx context.Context, arg SearchParams) ([]SearchRow, error)
 	SetAlbumCover(ctx context.Context, arg SetAlbumCoverParams) error
+	SetAlbumCoverHeader(ctx context.Context, arg SetAlbumCoverHeaderParams) error
+	SetAlbumProcessingProfile(ctx context.Context, arg SetAlbumProcessingProfileParams) error
+	SetAlbumSortMode(ctx context.Context, arg SetAlbumSortModeParams) error
+	SetJobPhoto(ctx context.Context, arg SetJobPhotoParams) error
+	SetPhotoAnimationFormat(ctx context.Context, arg SetPhotoAnimationFormatParams) error
+	SetPhotoCommentHidden(ctx context.Context, arg SetPhotoCommentHiddenParams) error
+	SetPhotoEdits(ctx context.Context, arg SetPhotoEditsParams) error
+	SetPhotoOriginalFormat(ctx context.Context, arg SetPhotoOriginalFormatParams) error
+	TrashAlbum(ctx context.Context, id int64) error
+	TrashPhoto(ctx context.Context, id int64) error
 	UpdateAlbum(ctx context.Context, arg UpdateAlbumParams) error
 	UpdateJobStatus(ctx context.Context, arg UpdateJobStatusParams) error
+	UpdatePhotoCaption(ctx context.Context, arg UpdatePhotoCaptionParams) error
 	UpdatePhotoDimensions(ctx context.Context, arg UpdatePhotoDimensionsParams) error
+	UpdatePhotoLocation(ctx context.Context, arg UpdatePhotoLocationParams) error
+	UpdatePhotoPosition(ctx context.Context, arg UpdatePhotoPositionParams) error
+	UpdatePhotoRendition(ctx context.Context, arg UpdatePhotoRenditionParams) error
+	UpdatePhotoTakenAt(ctx context.Context, arg UpdatePhotoTakenAtParams) error
+	UpdateProcessingProfile(ctx context.Context, arg UpdateProcessingProfileParams) error
+	UpdateShareLink(ctx context.Context, arg UpdateShareLinkParams) (int64, error)
+	UpsertPhotoReaction(ctx context.Context, arg UpsertPhotoReactionParams) error
+	UpsertSetting(ctx context.Context, arg UpsertSettingParams) error
 }
 
 var _ Querier = (*Queries)(nil)
@@ -33,18 +33,23 @@ func (q *Queries) CountUniqueShareLinkViews(ctx context.Context, shareLinkID int
 }
 
 const createShareLink = `-- name: CreateShareLink :one
-INSERT INTO share_links (token, target_type, target_id, max_views, expires_at, message)
-VALUES (?, ?, ?, ?, ?, ?)
-RETURNING id, token, target_type, target_id, max_views, expires_at, created_at, revoked_at, message
+INSERT INTO share_links (token, target_type, target_id, max_views, expires_at, message, allow_comments, show_map, open_graph, slug, locale)
+VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+RETURNING id, token, target_type, target_id, max_views, expires_at, created_at, revoked_at, message, allow_comments, show_map, open_graph, slug, locale
 `
 
 type CreateShareLinkParams struct {
-	Token      string         `json:"token"`
-	TargetType string         `json:"target_type"`
-	TargetID   int64          `json:"target_id"`
-	MaxViews   sql.NullInt64  `json:"max_views"`
-	ExpiresAt  sql.NullTime   `json:"expires_at"`
-	Message    sql.NullString `json:"message"`
+	Token         string         `json:"token"`
+	TargetType    string         `json:"target_type"`
+	TargetID      int64          `json:"target_id"`
+	MaxViews      sql.NullInt64  `json:"max_views"`
+	ExpiresAt     sql.NullTime   `json:"expires_at"`
+	Message       sql.NullString `json:"message"`
+	AllowComments bool           `json:"allow_comments"`
+	ShowMap       bool           `json:"show_map"`
+	OpenGraph     bool           `json:"open_graph"`
+	Slug          sql.NullString `json:"slug"`
+	Locale        sql.NullString `json:"locale"`
 }
 
 func (q *Queries) CreateShareLink(ctx context.Context, arg CreateShareLinkParams) (ShareLink, error) {
@@ -55,6 +60,11 @@ func (q *Queries) CreateShareLink(ctx context.Context, arg CreateShareLinkParams
 		arg.MaxViews,
 		arg.ExpiresAt,
 		arg.Message,
+		arg.AllowComments,
+		arg.ShowMap,
+		arg.OpenGraph,
+		arg.Slug,
+		arg.Locale,
 	)
 	var i ShareLink
 	err := row.Scan(
@@ -67,14 +77,19 @@ func (q *Queries) CreateShareLink(ctx context.Context, arg CreateShareLinkParams
 		&i.CreatedAt,
 		&i.RevokedAt,
 		&i.Message,
+		&i.AllowComments,
+		&i.ShowMap,
+		&i.OpenGraph,
+		&i.Slug,
+		&i.Locale,
 	)
 	return i, err
 }
 
 const deleteExpiredShareLinks = `-- name: DeleteExpiredShareLinks :many
-DELETE FROM share_links 
-WHERE expires_at IS NOT NULL AND expires_at < CURRENT_TIMESTAMP
-   OR revoked_at IS NOT NULL
+DELETE FROM share_links
+WHERE expires_at IS NOT NULL AND expires_at < ?1
+   OR revoked_at IS NOT NULL AND revoked_at < ?1
 RETURNING id, target_type, target_id
 `
 
@@ -84,8 +99,8 @@ type DeleteExpiredShareLinksRow struct {
 	TargetID   int64  `json:"target_id"`
 }
 
-func (q *Queries) DeleteExpiredShareLinks(ctx context.Context) ([]DeleteExpiredShareLinksRow, error) {
-	rows, err := q.db.QueryContext(ctx, deleteExpiredShareLinks)
+func (q *Queries) DeleteExpiredShareLinks(ctx context.Context, cutoff sql.NullTime) ([]DeleteExpiredShareLinksRow, error) {
+	rows, err := q.db.QueryContext(ctx, deleteExpiredShareLinks, cutoff)
 	if err != nil {
 		return nil, err
 	}
@@ -108,7 +123,7 @@ func (q *Queries) DeleteExpiredShareLinks(ctx context.Context) ([]DeleteExpiredS
 }
 
 const getShareLink = `-- name: GetShareLink :one
-SELECT id, token, target_type, target_id, max_views, expires_at, created_at, revoked_at, message FROM share_links WHERE id = ?
+SELECT id, token, target_type, target_id, max_views, expires_at, created_at, revoked_at, message, allow_comments, show_map, open_graph, slug, locale FROM share_links WHERE id = ?
 `
 
 func (q *Queries) GetShareLink(ctx context.Context, id int64) (ShareLink, error) {
@@ -124,12 +139,43 @@ func (q *Queries) GetShareLink(ctx context.Context, id int64) (ShareLink, error)
 		&i.CreatedAt,
 		&i.RevokedAt,
 		&i.Message,
+		&i.AllowComments,
+		&i.ShowMap,
+		&i.OpenGraph,
+		&i.Slug,
+		&i.Locale,
+	)
+	return i, err
+}
+
+const getShareLinkBySlug = `-- name: GetShareLinkBySlug :one
+SELECT id, token, target_type, target_id, max_views, expires_at, created_at, revoked_at, message, allow_comments, show_map, open_graph, slug, locale FROM share_links WHERE slug = ?
+`
+
+func (q *Queries) GetShareLinkBySlug(ctx context.Context, slug sql.NullString) (ShareLink, error) {
+	row := q.db.QueryRowContext(ctx, getShareLinkBySlug, slug)
+	var i ShareLink
+	err := row.Scan(
+		&i.ID,
+		&i.Token,
+		&i.TargetType,
+		&i.TargetID,
+		&i.MaxViews,
+		&i.ExpiresAt,
+		&i.CreatedAt,
+		&i.RevokedAt,
+		&i.Message,
+		&i.AllowComments,
+		&i.ShowMap,
+		&i.OpenGraph,
+		&i.Slug,
+		&i.Locale,
 	)
 	return i, err
 }
 
 const getShareLinkByToken = `-- name: GetShareLinkByToken :one
-SELECT id, token, target_type, target_id, max_views, expires_at, created_at, revoked_at, message FROM share_links WHERE token = ?
+SELECT id, token, target_type, target_id, max_views, expires_at, created_at, revoked_at, message, allow_comments, show_map, open_graph, slug, locale FROM share_links WHERE token = ?
 `
 
 func (q *Queries) GetShareLinkByToken(ctx context.Context, token string) (ShareLink, error) {
@@ -145,26 +191,32 @@ func (q *Queries) GetShareLinkByToken(ctx context.Context, token string) (ShareL
 		&i.CreatedAt,
 		&i.RevokedAt,
 		&i.Message,
+		&i.AllowComments,
+		&i.ShowMap,
+		&i.OpenGraph,
+		&i.Slug,
+		&i.Locale,
 	)
 	return i, err
 }
 
 const incrementShareLinkView = `-- name: IncrementShareLinkView :exec
-INSERT OR IGNORE INTO share_link_views (share_link_id, viewer_hash) VALUES (?, ?)
+INSERT OR IGNORE INTO share_link_views (share_link_id, viewer_hash, recipient_id) VALUES (?, ?, ?)
 `
 
 type IncrementShareLinkViewParams struct {
-	ShareLinkID int64  `json:"share_link_id"`
-	ViewerHash  string `json:"viewer_hash"`
+	ShareLinkID int64         `json:"share_link_id"`
+	ViewerHash  string        `json:"viewer_hash"`
+	RecipientID sql.NullInt64 `json:"recipient_id"`
 }
 
 func (q *Queries) IncrementShareLinkView(ctx context.Context, arg IncrementShareLinkViewParams) error {
-	_, err := q.db.ExecContext(ctx, incrementShareLinkView, arg.ShareLinkID, arg.ViewerHash)
+	_, err := q.db.ExecContext(ctx, incrementShareLinkView, arg.ShareLinkID, arg.ViewerHash, arg.RecipientID)
 	return err
 }
 
 const listActiveShareLinks = `-- name: ListActiveShareLinks :many
-SELECT id, token, target_type, target_id, max_views, expires_at, created_at, revoked_at, message FROM share_links
+SELECT id, token, target_type, target_id, max_views, expires_at, created_at, revoked_at, message, allow_comments, show_map, open_graph, slug, locale FROM share_links
 WHERE revoked_at IS NULL
   AND (expires_at IS NULL OR expires_at > CURRENT_TIMESTAMP)
 ORDER BY created_at DESC
@@ -195,6 +247,11 @@ func (q *Queries) ListActiveShareLinks(ctx context.Context, arg ListActiveShareL
 			&i.CreatedAt,
 			&i.RevokedAt,
 			&i.Message,
+			&i.AllowComments,
+			&i.ShowMap,
+			&i.OpenGraph,
+			&i.Slug,
+			&i.Locale,
 		); err != nil {
 			return nil, err
 		}
@@ -210,7 +267,7 @@ func (q *Queries) ListActiveShareLinks(ctx context.Context, arg ListActiveShareL
 }
 
 const listShareLinks = `-- name: ListShareLinks :many
-SELECT id, token, target_type, target_id, max_views, expires_at, created_at, revoked_at, message FROM share_links
+SELECT id, token, target_type, target_id, max_views, expires_at, created_at, revoked_at, message, allow_comments, show_map, open_graph, slug, locale FROM share_links
 ORDER BY created_at DESC
 LIMIT ? OFFSET ?
 `
@@ -239,6 +296,11 @@ func (q *Queries) ListShareLinks(ctx context.Context, arg ListShareLinksParams)
 			&i.CreatedAt,
 			&i.RevokedAt,
 			&i.Message,
+			&i.AllowComments,
+			&i.ShowMap,
+			&i.OpenGraph,
+			&i.Slug,
+			&i.Locale,
 		); err != nil {
 			return nil, err
 		}
@@ -255,20 +317,23 @@ func (q *Queries) ListShareLinks(ctx context.Context, arg ListShareLinksParams)
 
 const listShareLinksWithDetails = `-- name: ListShareLinksWithDetails :many
 SELECT 
-    sl.id, sl.token, sl.target_type, sl.target_id, sl.max_views, sl.expires_at, sl.created_at, sl.revoked_at, sl.message,
+    sl.id, sl.token, sl.target_type, sl.target_id, sl.max_views, sl.expires_at, sl.created_at, sl.revoked_at, sl.message, sl.allow_comments, sl.show_map, sl.open_graph, sl.slug, sl.locale,
     CASE 
         WHEN sl.target_type = 'album' THEN a.title
         WHEN sl.target_type = 'photo' THEN (SELECT title FROM albums WHERE id = p.album_id)
+        WHEN sl.target_type = 'tag' THEN (SELECT name FROM tags WHERE id = sl.target_id)
     END as target_title,
     CASE
         WHEN sl.target_type = 'photo' THEN p.album_id
         ELSE NULL
     END as photo_album_id,
-    (SELECT COUNT(DISTINCT viewer_hash) FROM share_link_views WHERE share_link_id = sl.id) as current_views
+    (SELECT COUNT(DISTINCT viewer_hash) FROM share_link_views WHERE share_link_id = sl.id) as current_views,
+    (SELECT COUNT(*) FROM activity_events WHERE share_link_id = sl.id AND event_type = 'share_bot_view') as bot_views,
+    (SELECT COUNT(*) FROM share_link_recipients WHERE share_link_id = sl.id) as recipients
 FROM share_links sl
 LEFT JOIN albums a ON sl.target_type = 'album' AND sl.target_id = a.id
 LEFT JOIN photos p ON sl.target_type = 'photo' AND sl.target_id = p.id
-ORDER BY sl.created_at DESC
+ORDER BY sl.created_at DESC, sl.id DESC
 LIMIT ? OFFSET ?
 `
 
@@ -278,18 +343,25 @@ type ListShareLinksWithDetailsParams struct {
 }
 
 type ListShareLinksWithDetailsRow struct {
-	ID           int64          `json:"id"`
-	Token        string         `json:"token"`
-	TargetType   string         `json:"target_type"`
-	TargetID     int64          `json:"target_id"`
-	MaxViews     sql.NullInt64  `json:"max_views"`
-	ExpiresAt    sql.NullTime   `json:"expires_at"`
-	CreatedAt    sql.NullTime   `json:"created_at"`
-	RevokedAt    sql.NullTime   `json:"revoked_at"`
-	Message      sql.NullString `json:"message"`
-	TargetTitle  interface{}    `json:"target_title"`
-	PhotoAlbumID interface{}    `json:"photo_album_id"`
-	CurrentViews int64          `json:"current_views"`
+	ID            int64          `json:"id"`
+	Token         string         `json:"token"`
+	TargetType    string         `json:"target_type"`
+	TargetID      int64          `json:"target_id"`
+	MaxViews      sql.NullInt64  `json:"max_views"`
+	ExpiresAt     sql.NullTime   `json:"expires_at"`
+	CreatedAt     sql.NullTime   `json:"created_at"`
+	RevokedAt     sql.NullTime   `json:"revoked_at"`
+	Message       sql.NullString `json:"message"`
+	AllowComments bool           `json:"allow_comments"`
+	ShowMap       bool           `json:"show_map"`
+	OpenGraph     bool           `json:"open_graph"`
+	Slug          sql.NullString `json:"slug"`
+	Locale        sql.NullString `json:"locale"`
+	TargetTitle   interface{}    `json:"target_title"`
+	PhotoAlbumID  interface{}    `json:"photo_album_id"`
+	CurrentViews  int64          `json:"current_views"`
+	BotViews      int64          `json:"bot_views"`
+	Recipients    int64          `json:"recipients"`
 }
 
 func (q *Queries) ListShareLinksWithDetails(ctx context.Context, arg ListShareLinksWithDetailsParams) ([]ListShareLinksWithDetailsRow, error) {
@@ -311,9 +383,16 @@ func (q *Queries) ListShareLinksWithDetails(ctx context.Context, arg ListShareLi
 			&i.CreatedAt,
 			&i.RevokedAt,
 			&i.Message,
+			&i.AllowComments,
+			&i.ShowMap,
+			&i.OpenGraph,
+			&i.Slug,
+			&i.Locale,
 			&i.TargetTitle,
 			&i.PhotoAlbumID,
 			&i.CurrentViews,
+			&i.BotViews,
+			&i.Recipients,
 		); err != nil {
 			return nil, err
 		}
@@ -328,6 +407,29 @@ func (q *Queries) ListShareLinksWithDetails(ctx context.Context, arg ListShareLi
 	return items, nil
 }
 
+const reactivateShareLink = `-- name: ReactivateShareLink :execrows
+UPDATE share_links
+SET revoked_at = NULL
+WHERE id = ? AND revoked_at IS NOT NULL
+`
+
+func (q *Queries) ReactivateShareLink(ctx context.Context, id int64) (int64, error) {
+	result, err := q.db.ExecContext(ctx, reactivateShareLink, id)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+const resetShareLinkViews = `-- name: ResetShareLinkViews :exec
+DELETE FROM share_link_views WHERE share_link_id = ?
+`
+
+func (q *Queries) ResetShareLinkViews(ctx context.Context, shareLinkID int64) error {
+	_, err := q.db.ExecContext(ctx, resetShareLinkViews, shareLinkID)
+	return err
+}
+
 const revokeShareLink = `-- name: RevokeShareLink :exec
 UPDATE share_links
 SET revoked_at = CURRENT_TIMESTAMP
@@ -338,3 +440,33 @@ func (q *Queries) RevokeShareLink(ctx context.Context, id int64) error {
 	_, err := q.db.ExecContext(ctx, revokeShareLink, id)
 	return err
 }
+
+const updateShareLink = `-- name: UpdateShareLink :execrows
+UPDATE share_links
+SET max_views = ?, expires_at = ?, message = ?, slug = ?, locale = ?
+WHERE id = ?
+`
+
+type UpdateShareLinkParams struct {
+	MaxViews  sql.NullInt64  `json:"max_views"`
+	ExpiresAt sql.NullTime   `json:"expires_at"`
+	Message   sql.NullString `json:"message"`
+	Slug      sql.NullString `json:"slug"`
+	Locale    sql.NullString `json:"locale"`
+	ID        int64          `json:"id"`
+}
+
+func (q *Queries) UpdateShareLink(ctx context.Context, arg UpdateShareLinkParams) (int64, error) {
+	result, err := q.db.ExecContext(ctx, updateShareLink,
+		arg.MaxViews,
+		arg.ExpiresAt,
+		arg.Message,
+		arg.Slug,
+		arg.Locale,
+		arg.ID,
+	)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
@@ -4,14 +4,11 @@ import (
 	"database/sql"
 	"log"
 	"net/http"
-	"os"
 	"strconv"
-	"time"
 
 	"github.com/go-chi/chi/v5"
 
 	"familyshare/internal/db/sqlc"
-	"familyshare/internal/storage"
 )
 
 // CreateAlbum handles POST /admin/albums
@@ -66,8 +63,21 @@ func (h *Handler) EditAlbumForm(w http.ResponseWriter, r *http.Request) {
 		templateName = "album_edit_form_detail.html"
 	}
 
+	profiles, err := q.ListProcessingProfiles(r.Context())
+	if err != nil {
+		log.Printf("failed to list processing profiles: %v", err)
+	}
+
+	data := struct {
+		sqlc.Album
+		Profiles []sqlc.ProcessingProfile
+	}{
+		Album:    alb,
+		Profiles: profiles,
+	}
+
 	// Render the album edit form partial with album data
-	if err := h.RenderTemplate(w, templateName, alb); err != nil {
+	if err := h.RenderTemplate(w, templateName, data); err != nil {
 		http.Error(w, "template render error", http.StatusInternalServerError)
 	}
 }
@@ -89,17 +99,28 @@ func (h *Handler) ViewAlbum(w http.ResponseWriter, r *http.Request) {
 	}
 
 	// fetch photos for album
-	photos, _ := q.ListPhotosByAlbum(r.Context(), sqlc.ListPhotosByAlbumParams{AlbumID: id, Limit: 100, Offset: 0})
+	photos, _ := listAlbumPhotos(r.Context(), q, alb, 100, 0)
+	cards := albumPhotoCards(r.Context(), q, id, photos)
 
-	// Check queue status for processing batch indicator and stats
-	type uploadStats struct {
-		PendingCount    int64
-		ProcessingCount int64
-		CompletedCount  int64
-		FailedCount     int64
-		Percent         int
+	// Existing tags feed the bulk tag autocomplete
+	tags, err := q.ListTags(r.Context())
+	if err != nil {
+		log.Printf("failed to list tags: %v", err)
+	}
+
+	// Other albums are targets for moving or copying selected photos
+	allAlbums, err := q.ListAlbums(r.Context(), sqlc.ListAlbumsParams{Limit: 1000, Offset: 0})
+	if err != nil {
+		log.Printf("failed to list albums: %v", err)
+	}
+	var targets []sqlc.Album
+	for _, a := range allAlbums {
+		if a.ID != id {
+			targets = append(targets, a)
+		}
 	}
 
+	// Check queue status for processing batch indicator and stats
 	var stats uploadStats
 	var statsLoaded bool
 	activeCount := int64(0)
@@ -113,55 +134,30 @@ func (h *Handler) ViewAlbum(w http.ResponseWriter, r *http.Request) {
 		}
 	} else {
 		statsLoaded = true
-		pending := int64(0)
-		if status.PendingCount.Valid {
-			pending = int64(status.PendingCount.Float64)
-		}
-		processing := int64(0)
-		if status.ProcessingCount.Valid {
-			processing = int64(status.ProcessingCount.Float64)
-		}
-		completed := int64(0)
-		if status.CompletedCount.Valid {
-			completed = int64(status.CompletedCount.Float64)
-		}
-		failed := int64(0)
-		if status.FailedCount.Valid {
-			failed = int64(status.FailedCount.Float64)
-		}
-
-		total := float64(pending + processing + completed + failed)
-		processed := float64(completed + failed)
-		percent := 0
-		if total > 0 {
-			percent = int((processed / total) * 100)
-		}
-
-		stats = uploadStats{
-			PendingCount:    pending,
-			ProcessingCount: processing,
-			CompletedCount:  completed,
-			FailedCount:     failed,
-			Percent:         percent,
-		}
-		activeCount = pending + processing
+		stats = queueStats(status)
+		activeCount = stats.PendingCount + stats.ProcessingCount
 	}
-	polling := activeCount > 0
 
 	data := struct {
 		Album           sqlc.Album
-		Photos          []sqlc.Photo
+		Photos          []photoCard
+		MostLoved       []photoCard
+		Tags            []sqlc.Tag
+		TargetAlbums    []sqlc.Album
 		ProcessingBatch bool
 		Stats           uploadStats
 		StatsLoaded     bool
-		Polling         bool
+		Live            bool
 	}{
 		Album:           alb,
-		Photos:          photos,
+		Photos:          cards,
+		MostLoved:       mostLovedPhotos(cards, 6),
+		Tags:            tags,
+		TargetAlbums:    targets,
 		ProcessingBatch: activeCount > 0,
 		Stats:           stats,
 		StatsLoaded:     statsLoaded,
-		Polling:         polling,
+		Live:            activeCount > 0,
 	}
 
 	if err := h.RenderTemplate(w, "album_detail.html", data); err != nil {
@@ -187,6 +183,27 @@ func (h *Handler) UpdateAlbum(w http.ResponseWriter, r *http.Request) {
 		http.Error(w, "title required", http.StatusBadRequest)
 		return
 	}
+	// sort_mode is optional so older forms keep working
+	sortMode := r.PostFormValue("sort_mode")
+	if sortMode != "" && !validSortMode(sortMode) {
+		http.Error(w, "invalid sort_mode", http.StatusBadRequest)
+		return
+	}
+	// processing_profile_id is optional too; an empty value selects the defaults
+	_, setProfile := r.PostForm["processing_profile_id"]
+	var profileID sql.NullInt64
+	if v := r.PostFormValue("processing_profile_id"); v != "" {
+		pid, err := strconv.ParseInt(v, 10, 64)
+		if err != nil {
+			http.Error(w, "invalid processing_profile_id", http.StatusBadRequest)
+			return
+		}
+		profileID = sql.NullInt64{Int64: pid, Valid: true}
+	}
+	// cover_header is optional as well. Its form sends a hidden 0 before the
+	// checkbox, so the last value is the checkbox's when it is checked.
+	coverHeaderValues, setCoverHeader := r.PostForm["cover_header"]
+	coverHeader := setCoverHeader && coverHeaderValues[len(coverHeaderValues)-1] == "1"
 
 	q := sqlc.New(h.db)
 
@@ -197,6 +214,13 @@ func (h *Handler) UpdateAlbum(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	if profileID.Valid {
+		if _, err := q.GetProcessingProfile(r.Context(), profileID.Int64); err != nil {
+			http.Error(w, "processing profile not found", http.StatusBadRequest)
+			return
+		}
+	}
+
 	err = q.UpdateAlbum(r.Context(), sqlc.UpdateAlbumParams{
 		Title:        title,
 		Description:  sql.NullString{String: desc, Valid: desc != ""},
@@ -207,6 +231,24 @@ func (h *Handler) UpdateAlbum(w http.ResponseWriter, r *http.Request) {
 		http.Error(w, "failed to update", http.StatusInternalServerError)
 		return
 	}
+	if sortMode != "" {
+		if err := q.SetAlbumSortMode(r.Context(), sqlc.SetAlbumSortModeParams{SortMode: sortMode, ID: id}); err != nil {
+			http.Error(w, "failed to update", http.StatusInternalServerError)
+			return
+		}
+	}
+	if setProfile {
+		if err := q.SetAlbumProcessingProfile(r.Context(), sqlc.SetAlbumProcessingProfileParams{ProcessingProfileID: profileID, ID: id}); err != nil {
+			http.Error(w, "failed to update", http.StatusInternalServerError)
+			return
+		}
+	}
+	if setCoverHeader {
+		if err := q.SetAlbumCoverHeader(r.Context(), sqlc.SetAlbumCoverHeaderParams{CoverHeader: coverHeader, ID: id}); err != nil {
+			http.Error(w, "failed to update", http.StatusInternalServerError)
+			return
+		}
+	}
 
 	if IsHTMX(r) {
 		// Get album with photo count for proper rendering
@@ -236,6 +278,7 @@ func (h *Handler) UpdateAlbum(w http.ResponseWriter, r *http.Request) {
 }
 
 // DeleteAlbum handles DELETE /admin/albums/{id}
+// The album and its photos go to the trash and can be restored from there.
 func (h *Handler) DeleteAlbum(w http.ResponseWriter, r *http.Request) {
 	idstr := chi.URLParam(r, "id")
 	id, err := strconv.ParseInt(idstr, 10, 64)
@@ -247,30 +290,13 @@ func (h *Handler) DeleteAlbum(w http.ResponseWriter, r *http.Request) {
 	ctx := r.Context()
 	q := sqlc.New(h.db)
 
-	// First, get all photos in this album
-	photos, err := q.ListPhotosByAlbum(ctx, sqlc.ListPhotosByAlbumParams{
-		AlbumID: id,
-		Limit:   1000, // Get all photos
-		Offset:  0,
-	})
-	if err != nil {
-		http.Error(w, "failed to list photos", http.StatusInternalServerError)
+	if _, err := q.GetAlbum(ctx, id); err != nil {
+		http.Error(w, "album not found", http.StatusNotFound)
 		return
 	}
 
-	// Delete all photo files from disk
-	for _, photo := range photos {
-		createdAt := time.Now().UTC()
-		if photo.CreatedAt.Valid {
-			createdAt = photo.CreatedAt.Time.UTC()
-		}
-		photoPath := storage.PhotoPathAt(h.storage.BaseDir, photo.AlbumID, photo.ID, photo.Format, createdAt)
-		// Ignore errors if file doesn't exist
-		_ = os.Remove(photoPath)
-	}
-
-	// Delete the album (cascade will delete photos from DB via foreign key)
-	if err := q.DeleteAlbum(ctx, id); err != nil {
+	// Move the album to the trash; its photos are hidden along with it
+	if err := q.TrashAlbum(ctx, id); err != nil {
 		http.Error(w, "failed to delete", http.StatusInternalServerError)
 		return
 	}
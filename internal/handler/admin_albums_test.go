@@ -74,7 +74,7 @@ func TestCreateAndListAndUpdateAndDeleteAlbum(t *testing.T) {
 	}
 
 	// Add a photo to the album
-	_, err = q.CreatePhoto(context.Background(), sqlc.CreatePhotoParams{AlbumID: id, Filename: "p.webp", Width: 100, Height: 100, SizeBytes: 1234, Format: "webp"})
+	photo, err := q.CreatePhoto(context.Background(), sqlc.CreatePhotoParams{AlbumID: id, Filename: "p.webp", Width: 100, Height: 100, SizeBytes: 1234, Format: "webp"})
 	if err != nil {
 		t.Fatalf("CreatePhoto: %v", err)
 	}
@@ -92,13 +92,9 @@ func TestCreateAndListAndUpdateAndDeleteAlbum(t *testing.T) {
 		t.Fatalf("expected album to be deleted")
 	}
 
-	// Verify photos cascaded
-	photos, err := q.ListPhotosByAlbum(context.Background(), sqlc.ListPhotosByAlbumParams{AlbumID: id, Limit: 10, Offset: 0})
-	if err != nil {
-		t.Fatalf("ListPhotosByAlbum: %v", err)
-	}
-	if len(photos) != 0 {
-		t.Fatalf("expected photos to be deleted with album, found %d", len(photos))
+	// Verify photos hidden with the album
+	if _, err := q.GetPhoto(context.Background(), photo.ID); err == nil {
+		t.Fatalf("expected photos to be hidden with album")
 	}
 }
 
@@ -0,0 +1,357 @@
+package handler
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"os"
+	"strconv"
+	"time"
+
+	"familyshare/internal/db/sqlc"
+	"familyshare/internal/storage"
+)
+
+// errNoPhotosSelected is returned by parsePhotoIDs for an empty selection.
+var errNoPhotosSelected = errors.New("no photos selected")
+
+// parsePhotoIDs reads the repeated photo_ids form field of bulk actions.
+func parsePhotoIDs(r *http.Request) ([]int64, error) {
+	var ids []int64
+	for _, s := range r.PostForm["photo_ids"] {
+		id, err := strconv.ParseInt(s, 10, 64)
+		if err != nil || id <= 0 {
+			return nil, errors.New("invalid photo id")
+		}
+		ids = append(ids, id)
+	}
+	if len(ids) == 0 {
+		return nil, errNoPhotosSelected
+	}
+	return ids, nil
+}
+
+// photoFilePath resolves the stored file of a photo.
+func photoFilePath(baseDir string, photo sqlc.Photo) string {
+	createdAt := time.Now().UTC()
+	if photo.CreatedAt.Valid {
+		createdAt = photo.CreatedAt.Time.UTC()
+	}
+	return storage.PhotoPathAt(baseDir, photo.AlbumID, photo.ID, photo.Format, createdAt)
+}
+
+// bulkResult tallies a bulk action that continues past individual failures.
+type bulkResult struct {
+	Action string
+	Done   int
+	Failed []int64
+}
+
+// write refreshes the page and, when some photos failed, reports them in the body.
+func (b bulkResult) write(w http.ResponseWriter) {
+	w.Header().Set("HX-Refresh", "true")
+	if len(b.Failed) == 0 {
+		w.WriteHeader(http.StatusNoContent)
+		return
+	}
+	w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+	w.WriteHeader(http.StatusOK)
+	fmt.Fprintf(w, "%s %d of %d photos; failed: %v\n", b.Action, b.Done, b.Done+len(b.Failed), b.Failed)
+}
+
+// movePhotoToAlbum moves a photo and its file to another album. The row update
+// and the file rename succeed or fail together.
+func (h *Handler) movePhotoToAlbum(ctx context.Context, photo sqlc.Photo, albumID int64) error {
+	if photo.AlbumID == albumID {
+		return nil
+	}
+
+	tx, err := h.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("begin tx: %w", err)
+	}
+	defer tx.Rollback()
+
+	q := sqlc.New(tx)
+
+	last, err := q.GetMaxPhotoPosition(ctx, albumID)
+	if err != nil {
+		return fmt.Errorf("get max position: %w", err)
+	}
+	if err := q.MovePhotoToAlbum(ctx, sqlc.MovePhotoToAlbumParams{AlbumID: albumID, Position: last + 1, ID: photo.ID}); err != nil {
+		return fmt.Errorf("move photo row: %w", err)
+	}
+	// The photo can no longer be the cover of the album it left
+	if err := q.ClearAlbumCoverIfPhoto(ctx, sql.NullInt64{Int64: photo.ID, Valid: true}); err != nil {
+		return fmt.Errorf("clear album cover: %w", err)
+	}
+	// Keep upload jobs (and the filename search entries built from them) with the photo
+	if err := q.MoveJobsToAlbum(ctx, sqlc.MoveJobsToAlbumParams{AlbumID: albumID, PhotoID: sql.NullInt64{Int64: photo.ID, Valid: true}}); err != nil {
+		return fmt.Errorf("move upload jobs: %w", err)
+	}
+
+	src := photoFilePath(h.storage.BaseDir, photo)
+	moved := photo
+	moved.AlbumID = albumID
+	dst := photoFilePath(h.storage.BaseDir, moved)
+	if err := storage.MoveFile(src, dst); err != nil {
+		return fmt.Errorf("move file: %w", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		// put the file back where the unchanged row expects it
+		if rerr := storage.MoveFile(dst, src); rerr != nil {
+			log.Printf("failed to restore file of photo %d to %s: %v", photo.ID, src, rerr)
+		}
+		return fmt.Errorf("commit tx: %w", err)
+	}
+	return nil
+}
+
+// copyPhotoToAlbum duplicates a photo, with its caption, capture date,
+// location and tags, into another album. The new row is only committed once its file exists.
+func (h *Handler) copyPhotoToAlbum(ctx context.Context, photo sqlc.Photo, albumID int64) error {
+	tx, err := h.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("begin tx: %w", err)
+	}
+	defer tx.Rollback()
+
+	q := sqlc.New(tx)
+
+	dup, err := q.CreatePhoto(ctx, sqlc.CreatePhotoParams{
+		AlbumID:   albumID,
+		Filename:  photo.Filename,
+		Width:     photo.Width,
+		Height:    photo.Height,
+		SizeBytes: photo.SizeBytes,
+		Format:    photo.Format,
+	})
+	if err != nil {
+		return fmt.Errorf("create photo row: %w", err)
+	}
+	if photo.Caption.Valid {
+		if err := q.UpdatePhotoCaption(ctx, sqlc.UpdatePhotoCaptionParams{Caption: photo.Caption, ID: dup.ID}); err != nil {
+			return fmt.Errorf("copy caption: %w", err)
+		}
+	}
+	if photo.TakenAt.Valid {
+		if err := q.UpdatePhotoTakenAt(ctx, sqlc.UpdatePhotoTakenAtParams{TakenAt: photo.TakenAt, ID: dup.ID}); err != nil {
+			return fmt.Errorf("copy capture date: %w", err)
+		}
+	}
+	if photo.Latitude.Valid {
+		if err := q.UpdatePhotoLocation(ctx, sqlc.UpdatePhotoLocationParams{Latitude: photo.Latitude, Longitude: photo.Longitude, ID: dup.ID}); err != nil {
+			return fmt.Errorf("copy location: %w", err)
+		}
+	}
+	tags, err := q.ListTagsForPhoto(ctx, photo.ID)
+	if err != nil {
+		return fmt.Errorf("list tags: %w", err)
+	}
+	for _, tag := range tags {
+		if err := q.AddPhotoTag(ctx, sqlc.AddPhotoTagParams{PhotoID: dup.ID, TagID: tag.ID}); err != nil {
+			return fmt.Errorf("copy tag %d: %w", tag.ID, err)
+		}
+	}
+
+	dst := photoFilePath(h.storage.BaseDir, dup)
+	if err := storage.CopyFile(photoFilePath(h.storage.BaseDir, photo), dst); err != nil {
+		return fmt.Errorf("copy file: %w", err)
+	}
+	copied := []string{dst}
+	removeCopies := func() {
+		for _, path := range copied {
+			_ = os.Remove(path)
+		}
+	}
+
+	// The copy keeps its own archived original so it can be reprocessed
+	if photo.OriginalFormat.Valid {
+		origDst := storage.OriginalPath(h.storage.BaseDir, dup.ID, photo.OriginalFormat.String)
+		if err := storage.CopyFile(storage.OriginalPath(h.storage.BaseDir, photo.ID, photo.OriginalFormat.String), origDst); err != nil {
+			removeCopies()
+			return fmt.Errorf("copy original: %w", err)
+		}
+		copied = append(copied, origDst)
+		if err := q.SetPhotoOriginalFormat(ctx, sqlc.SetPhotoOriginalFormatParams{OriginalFormat: photo.OriginalFormat, ID: dup.ID}); err != nil {
+			removeCopies()
+			return fmt.Errorf("copy original format: %w", err)
+		}
+		if err := q.SetPhotoEdits(ctx, sqlc.SetPhotoEditsParams{Edits: photo.Edits, ID: dup.ID}); err != nil {
+			removeCopies()
+			return fmt.Errorf("copy edits: %w", err)
+		}
+	}
+	// and its own animation
+	if photo.AnimationFormat.Valid {
+		animDst := storage.AnimationPath(h.storage.BaseDir, dup.ID, photo.AnimationFormat.String)
+		if err := storage.CopyFile(storage.AnimationPath(h.storage.BaseDir, photo.ID, photo.AnimationFormat.String), animDst); err != nil {
+			removeCopies()
+			return fmt.Errorf("copy animation: %w", err)
+		}
+		copied = append(copied, animDst)
+		if err := q.SetPhotoAnimationFormat(ctx, sqlc.SetPhotoAnimationFormatParams{AnimationFormat: photo.AnimationFormat, ID: dup.ID}); err != nil {
+			removeCopies()
+			return fmt.Errorf("copy animation format: %w", err)
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		removeCopies()
+		return fmt.Errorf("commit tx: %w", err)
+	}
+	return nil
+}
+
+// MovePhotos handles POST /admin/photos/move
+// Form fields: photo_ids (repeated), album_id (target album).
+func (h *Handler) MovePhotos(w http.ResponseWriter, r *http.Request) {
+	h.relocatePhotos(w, r, "moved", h.movePhotoToAlbum)
+}
+
+// CopyPhotos handles POST /admin/photos/copy
+// Form fields: photo_ids (repeated), album_id (target album).
+func (h *Handler) CopyPhotos(w http.ResponseWriter, r *http.Request) {
+	h.relocatePhotos(w, r, "copied", h.copyPhotoToAlbum)
+}
+
+// relocatePhotos applies a move or copy to each selected photo. Each photo is
+// handled in its own transaction, so one failure does not undo the others.
+func (h *Handler) relocatePhotos(w http.ResponseWriter, r *http.Request, action string, apply func(context.Context, sqlc.Photo, int64) error) {
+	if err := r.ParseForm(); err != nil {
+		http.Error(w, "invalid form", http.StatusBadRequest)
+		return
+	}
+
+	photoIDs, err := parsePhotoIDs(r)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	albumID, err := strconv.ParseInt(r.PostFormValue("album_id"), 10, 64)
+	if err != nil {
+		http.Error(w, "invalid album id", http.StatusBadRequest)
+		return
+	}
+
+	q := sqlc.New(h.db)
+	if _, err := q.GetAlbum(r.Context(), albumID); err != nil {
+		http.Error(w, "album not found", http.StatusNotFound)
+		return
+	}
+
+	result := bulkResult{Action: action}
+	for _, id := range photoIDs {
+		photo, err := q.GetPhoto(r.Context(), id)
+		if err == nil {
+			err = apply(r.Context(), photo, albumID)
+		}
+		if err != nil {
+			log.Printf("photo %d not %s to album %d: %v", id, action, albumID, err)
+			result.Failed = append(result.Failed, id)
+			continue
+		}
+		result.Done++
+	}
+
+	result.write(w)
+}
+
+// BulkDeletePhotos handles POST /admin/photos/delete
+// The selected photos are moved to the trash in one transaction. Photos that
+// are already gone are skipped.
+func (h *Handler) BulkDeletePhotos(w http.ResponseWriter, r *http.Request) {
+	if err := r.ParseForm(); err != nil {
+		http.Error(w, "invalid form", http.StatusBadRequest)
+		return
+	}
+
+	photoIDs, err := parsePhotoIDs(r)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	tx, err := h.db.BeginTx(r.Context(), nil)
+	if err != nil {
+		log.Printf("failed to begin delete transaction: %v", err)
+		http.Error(w, "failed to delete photos", http.StatusInternalServerError)
+		return
+	}
+	defer tx.Rollback()
+
+	q := sqlc.New(tx)
+
+	for _, id := range photoIDs {
+		if _, err := q.GetPhoto(r.Context(), id); err == sql.ErrNoRows {
+			continue
+		} else if err != nil {
+			log.Printf("failed to load photo %d: %v", id, err)
+			http.Error(w, "failed to delete photos", http.StatusInternalServerError)
+			return
+		}
+		if err := q.ClearAlbumCoverIfPhoto(r.Context(), sql.NullInt64{Int64: id, Valid: true}); err != nil {
+			log.Printf("failed to clear album cover: %v", err)
+			http.Error(w, "failed to delete photos", http.StatusInternalServerError)
+			return
+		}
+		if err := q.TrashPhoto(r.Context(), id); err != nil {
+			log.Printf("failed to move photo %d to trash: %v", id, err)
+			http.Error(w, "failed to delete photos", http.StatusInternalServerError)
+			return
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		log.Printf("failed to commit delete transaction: %v", err)
+		http.Error(w, "failed to delete photos", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("HX-Refresh", "true")
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// BulkRotatePhotos handles POST /admin/photos/rotate
+// Form fields: photo_ids (repeated), angle.
+func (h *Handler) BulkRotatePhotos(w http.ResponseWriter, r *http.Request) {
+	if err := r.ParseForm(); err != nil {
+		http.Error(w, "invalid form", http.StatusBadRequest)
+		return
+	}
+
+	photoIDs, err := parsePhotoIDs(r)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	angle, err := strconv.Atoi(r.PostFormValue("angle"))
+	if err != nil || !validRotation(angle) {
+		http.Error(w, "Angle must be 90, 180, or 270 (-90)", http.StatusBadRequest)
+		return
+	}
+
+	q := sqlc.New(h.db)
+
+	result := bulkResult{Action: "rotated"}
+	for _, id := range photoIDs {
+		photo, err := q.GetPhoto(r.Context(), id)
+		if err == nil {
+			err = h.rotatePhoto(r.Context(), q, photo, angle)
+		}
+		if err != nil {
+			log.Printf("failed to rotate photo %d: %v", id, err)
+			result.Failed = append(result.Failed, id)
+			continue
+		}
+		result.Done++
+	}
+
+	result.write(w)
+}
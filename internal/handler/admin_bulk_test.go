@@ -0,0 +1,274 @@
+package handler_test
+
+import (
+	"context"
+	"database/sql"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"strconv"
+	"strings"
+	"testing"
+
+	"familyshare/internal/config"
+	"familyshare/internal/db/sqlc"
+	"familyshare/internal/handler"
+	"familyshare/internal/storage"
+	"familyshare/internal/testutil"
+	"familyshare/web"
+)
+
+func setupBulkTest(t *testing.T) (*handler.Handler, *sqlc.Queries, string) {
+	t.Helper()
+	dbConn, q, dbCleanup := testutil.SetupTestDB(t)
+	t.Cleanup(dbCleanup)
+
+	storageDir := t.TempDir()
+	cfg := &config.Config{RateLimitShare: 60, RateLimitAdmin: 10}
+	h := handler.New(dbConn, storage.New(storageDir), web.EmbedFS, cfg, nil)
+	return h, q, storageDir
+}
+
+// storedPhoto creates a photo row and writes its file where the row expects it.
+func storedPhoto(t *testing.T, q *sqlc.Queries, baseDir string, albumID int64, filename string) (sqlc.Photo, string) {
+	t.Helper()
+	p := testutil.CreateTestPhoto(t, q, albumID, filename)
+	path := storage.PhotoPathAt(baseDir, p.AlbumID, p.ID, p.Format, p.CreatedAt.Time)
+	if err := storage.AtomicWrite(path, strings.NewReader(filename)); err != nil {
+		t.Fatalf("write photo file: %v", err)
+	}
+	return *p, path
+}
+
+func postBulk(handle http.HandlerFunc, path string, vals url.Values) *httptest.ResponseRecorder {
+	req := httptest.NewRequest("POST", path, strings.NewReader(vals.Encode()))
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.Header.Set("HX-Request", "true")
+	w := httptest.NewRecorder()
+	handle(w, req)
+	return w
+}
+
+func photoIDValues(ids ...int64) url.Values {
+	vals := url.Values{}
+	for _, id := range ids {
+		vals.Add("photo_ids", strconv.FormatInt(id, 10))
+	}
+	return vals
+}
+
+func TestMovePhotos(t *testing.T) {
+	h, q, baseDir := setupBulkTest(t)
+	ctx := context.Background()
+
+	from := testutil.CreateTestAlbum(t, q, "From", "")
+	to := testutil.CreateTestAlbum(t, q, "To", "")
+	existing, _ := storedPhoto(t, q, baseDir, to.ID, "existing.webp")
+	photo, oldPath := storedPhoto(t, q, baseDir, from.ID, "beach.webp")
+	if err := q.SetAlbumCover(ctx, sqlc.SetAlbumCoverParams{CoverPhotoID: sql.NullInt64{Int64: photo.ID, Valid: true}, ID: from.ID}); err != nil {
+		t.Fatalf("SetAlbumCover: %v", err)
+	}
+
+	vals := photoIDValues(photo.ID)
+	vals.Set("album_id", strconv.FormatInt(to.ID, 10))
+	w := postBulk(h.MovePhotos, "/admin/photos/move", vals)
+	if w.Code != http.StatusNoContent {
+		t.Fatalf("expected 204, got %d: %s", w.Code, w.Body.String())
+	}
+	if w.Header().Get("HX-Refresh") != "true" {
+		t.Errorf("expected HX-Refresh header")
+	}
+
+	moved, err := q.GetPhoto(ctx, photo.ID)
+	if err != nil {
+		t.Fatalf("GetPhoto: %v", err)
+	}
+	if moved.AlbumID != to.ID {
+		t.Fatalf("expected photo in album %d, got %d", to.ID, moved.AlbumID)
+	}
+	if moved.Position <= existing.Position {
+		t.Errorf("expected moved photo appended after existing photos, got position %d", moved.Position)
+	}
+
+	newPath := storage.PhotoPathAt(baseDir, moved.AlbumID, moved.ID, moved.Format, moved.CreatedAt.Time)
+	if b, err := os.ReadFile(newPath); err != nil || string(b) != "beach.webp" {
+		t.Fatalf("expected file at new path %s: %v", newPath, err)
+	}
+	if _, err := os.Stat(oldPath); !os.IsNotExist(err) {
+		t.Fatalf("expected old file removed, got: %v", err)
+	}
+
+	album, _ := q.GetAlbum(ctx, from.ID)
+	if album.CoverPhotoID.Valid {
+		t.Errorf("expected source album cover cleared")
+	}
+}
+
+func TestMovePhotos_MissingFileKeepsRow(t *testing.T) {
+	h, q, baseDir := setupBulkTest(t)
+	ctx := context.Background()
+
+	from := testutil.CreateTestAlbum(t, q, "From", "")
+	to := testutil.CreateTestAlbum(t, q, "To", "")
+	good, _ := storedPhoto(t, q, baseDir, from.ID, "good.webp")
+	broken := testutil.CreateTestPhoto(t, q, from.ID, "missing.webp")
+
+	vals := photoIDValues(broken.ID, good.ID)
+	vals.Set("album_id", strconv.FormatInt(to.ID, 10))
+	w := postBulk(h.MovePhotos, "/admin/photos/move", vals)
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected 200 with partial failure report, got %d", w.Code)
+	}
+	if !strings.Contains(w.Body.String(), "moved 1 of 2") {
+		t.Errorf("expected summary in body, got %q", w.Body.String())
+	}
+
+	// The failed file move rolls back its row
+	if p, _ := q.GetPhoto(ctx, broken.ID); p.AlbumID != from.ID {
+		t.Errorf("expected photo without file to stay in source album, got %d", p.AlbumID)
+	}
+	if p, _ := q.GetPhoto(ctx, good.ID); p.AlbumID != to.ID {
+		t.Errorf("expected other photo moved, got album %d", p.AlbumID)
+	}
+}
+
+func TestCopyPhotos(t *testing.T) {
+	h, q, baseDir := setupBulkTest(t)
+	ctx := context.Background()
+
+	from := testutil.CreateTestAlbum(t, q, "From", "")
+	to := testutil.CreateTestAlbum(t, q, "To", "")
+	photo, srcPath := storedPhoto(t, q, baseDir, from.ID, "beach.webp")
+	if err := q.UpdatePhotoCaption(ctx, sqlc.UpdatePhotoCaptionParams{Caption: sql.NullString{String: "At the beach", Valid: true}, ID: photo.ID}); err != nil {
+		t.Fatalf("UpdatePhotoCaption: %v", err)
+	}
+	location := sqlc.UpdatePhotoLocationParams{Latitude: sql.NullFloat64{Float64: 38.72, Valid: true}, Longitude: sql.NullFloat64{Float64: -9.14, Valid: true}, ID: photo.ID}
+	if err := q.UpdatePhotoLocation(ctx, location); err != nil {
+		t.Fatalf("UpdatePhotoLocation: %v", err)
+	}
+	tag, err := q.CreateTag(ctx, "summer")
+	if err != nil {
+		t.Fatalf("CreateTag: %v", err)
+	}
+	if err := q.AddPhotoTag(ctx, sqlc.AddPhotoTagParams{PhotoID: photo.ID, TagID: tag.ID}); err != nil {
+		t.Fatalf("AddPhotoTag: %v", err)
+	}
+
+	vals := photoIDValues(photo.ID)
+	vals.Set("album_id", strconv.FormatInt(to.ID, 10))
+	if w := postBulk(h.CopyPhotos, "/admin/photos/copy", vals); w.Code != http.StatusNoContent {
+		t.Fatalf("expected 204, got %d: %s", w.Code, w.Body.String())
+	}
+
+	copies, err := q.ListPhotosByAlbum(ctx, sqlc.ListPhotosByAlbumParams{AlbumID: to.ID, Limit: 10})
+	if err != nil || len(copies) != 1 {
+		t.Fatalf("expected one copy in target album, got %d: %v", len(copies), err)
+	}
+	dup := copies[0]
+	if dup.ID == photo.ID || dup.Caption.String != "At the beach" {
+		t.Errorf("expected a new photo carrying the caption, got %+v", dup)
+	}
+	if dup.Latitude != location.Latitude || dup.Longitude != location.Longitude {
+		t.Errorf("expected the location copied, got %v, %v", dup.Latitude, dup.Longitude)
+	}
+	if tags, _ := q.ListTagsForPhoto(ctx, dup.ID); len(tags) != 1 || tags[0].ID != tag.ID {
+		t.Errorf("expected tags copied, got %v", tags)
+	}
+
+	dupPath := storage.PhotoPathAt(baseDir, dup.AlbumID, dup.ID, dup.Format, dup.CreatedAt.Time)
+	if b, err := os.ReadFile(dupPath); err != nil || string(b) != "beach.webp" {
+		t.Fatalf("expected copied file at %s: %v", dupPath, err)
+	}
+	if _, err := os.Stat(srcPath); err != nil {
+		t.Fatalf("expected original file kept: %v", err)
+	}
+}
+
+func TestRelocatePhotos_Invalid(t *testing.T) {
+	h, q, _ := setupBulkTest(t)
+	album := testutil.CreateTestAlbum(t, q, "From", "")
+	photo := testutil.CreateTestPhoto(t, q, album.ID, "beach.webp")
+
+	tests := []struct {
+		name string
+		vals url.Values
+		want int
+	}{
+		{"no photos", url.Values{"album_id": {strconv.FormatInt(album.ID, 10)}}, http.StatusBadRequest},
+		{"no album", photoIDValues(photo.ID), http.StatusBadRequest},
+		{"unknown album", url.Values{"photo_ids": {strconv.FormatInt(photo.ID, 10)}, "album_id": {"9999"}}, http.StatusNotFound},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if w := postBulk(h.MovePhotos, "/admin/photos/move", tt.vals); w.Code != tt.want {
+				t.Errorf("expected %d, got %d", tt.want, w.Code)
+			}
+		})
+	}
+}
+
+func TestBulkDeletePhotos(t *testing.T) {
+	h, q, baseDir := setupBulkTest(t)
+	ctx := context.Background()
+
+	album := testutil.CreateTestAlbum(t, q, "Trip", "")
+	p1, path1 := storedPhoto(t, q, baseDir, album.ID, "1.webp")
+	p2, path2 := storedPhoto(t, q, baseDir, album.ID, "2.webp")
+	// A photo without a file is trashed like the others
+	p3 := testutil.CreateTestPhoto(t, q, album.ID, "3.webp")
+	keep, keepPath := storedPhoto(t, q, baseDir, album.ID, "keep.webp")
+
+	w := postBulk(h.BulkDeletePhotos, "/admin/photos/delete", photoIDValues(p1.ID, p2.ID, p3.ID, 9999))
+	if w.Code != http.StatusNoContent {
+		t.Fatalf("expected 204, got %d: %s", w.Code, w.Body.String())
+	}
+
+	for _, id := range []int64{p1.ID, p2.ID, p3.ID} {
+		if _, err := q.GetPhoto(ctx, id); err == nil {
+			t.Errorf("expected photo %d deleted", id)
+		}
+		if p, err := q.GetPhotoIncludingDeleted(ctx, id); err != nil || !p.DeletedAt.Valid {
+			t.Errorf("expected photo %d in the trash: %v", id, err)
+		}
+	}
+	// Files stay until the trash is purged
+	for _, path := range []string{path1, path2} {
+		if _, err := os.Stat(path); err != nil {
+			t.Errorf("expected file %s kept in the trash: %v", path, err)
+		}
+	}
+	if _, err := q.GetPhoto(ctx, keep.ID); err != nil {
+		t.Errorf("expected unselected photo kept: %v", err)
+	}
+	if _, err := os.Stat(keepPath); err != nil {
+		t.Errorf("expected unselected file kept: %v", err)
+	}
+}
+
+func TestBulkRotatePhotos(t *testing.T) {
+	h, q, baseDir := setupBulkTest(t)
+	ctx := context.Background()
+
+	album := testutil.CreateTestAlbum(t, q, "Trip", "")
+	photo := testutil.CreateTestPhoto(t, q, album.ID, "wide.webp")
+	createTestWebP(t, storage.PhotoPathAt(baseDir, album.ID, photo.ID, "webp", photo.CreatedAt.Time), 100, 50)
+	missing := testutil.CreateTestPhoto(t, q, album.ID, "missing.webp")
+
+	vals := photoIDValues(photo.ID, missing.ID)
+	vals.Set("angle", "90")
+	w := postBulk(h.BulkRotatePhotos, "/admin/photos/rotate", vals)
+	if w.Code != http.StatusOK || !strings.Contains(w.Body.String(), "rotated 1 of 2") {
+		t.Fatalf("expected partial success report, got %d: %s", w.Code, w.Body.String())
+	}
+
+	rotated, _ := q.GetPhoto(ctx, photo.ID)
+	if rotated.Width != 50 || rotated.Height != 100 {
+		t.Errorf("expected 50x100 after rotation, got %dx%d", rotated.Width, rotated.Height)
+	}
+
+	vals.Set("angle", "45")
+	if w := postBulk(h.BulkRotatePhotos, "/admin/photos/rotate", vals); w.Code != http.StatusBadRequest {
+		t.Errorf("expected 400 for invalid angle, got %d", w.Code)
+	}
+}
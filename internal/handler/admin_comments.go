@@ -0,0 +1,106 @@
+package handler
+
+import (
+	"database/sql"
+	"log"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+
+	"familyshare/internal/db/sqlc"
+)
+
+// commentPageSize bounds the moderation list; older comments are rarely revisited.
+const commentPageSize = 200
+
+// ListComments handles GET /admin/comments
+func (h *Handler) ListComments(w http.ResponseWriter, r *http.Request) {
+	rows, err := h.queries.ListPhotoCommentsWithDetails(r.Context(), sqlc.ListPhotoCommentsWithDetailsParams{
+		Limit:  commentPageSize,
+		Offset: 0,
+	})
+	if err != nil {
+		log.Printf("failed to list comments: %v", err)
+		http.Error(w, "failed to list comments", http.StatusInternalServerError)
+		return
+	}
+
+	data := struct {
+		Comments []sqlc.ListPhotoCommentsWithDetailsRow
+	}{
+		Comments: rows,
+	}
+
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	if err := h.RenderTemplate(w, "comments.html", data); err != nil {
+		log.Printf("template render error for comments: %v", err)
+		http.Error(w, "template render error", http.StatusInternalServerError)
+	}
+}
+
+// HideComment handles POST /admin/comments/{id}/hide
+func (h *Handler) HideComment(w http.ResponseWriter, r *http.Request) {
+	h.setCommentHidden(w, r, true)
+}
+
+// UnhideComment handles POST /admin/comments/{id}/unhide
+func (h *Handler) UnhideComment(w http.ResponseWriter, r *http.Request) {
+	h.setCommentHidden(w, r, false)
+}
+
+// setCommentHidden toggles whether a comment is shown on the share page.
+func (h *Handler) setCommentHidden(w http.ResponseWriter, r *http.Request, hidden bool) {
+	idStr := chi.URLParam(r, "id")
+	id, err := strconv.ParseInt(idStr, 10, 64)
+	if err != nil {
+		http.Error(w, "invalid id", http.StatusBadRequest)
+		return
+	}
+
+	q := sqlc.New(h.db)
+
+	if _, err := q.GetPhotoComment(r.Context(), id); err != nil {
+		http.Error(w, "comment not found", http.StatusNotFound)
+		return
+	}
+
+	hiddenAt := sql.NullTime{}
+	if hidden {
+		hiddenAt = sql.NullTime{Time: time.Now().UTC(), Valid: true}
+	}
+	if err := q.SetPhotoCommentHidden(r.Context(), sqlc.SetPhotoCommentHiddenParams{HiddenAt: hiddenAt, ID: id}); err != nil {
+		log.Printf("failed to update comment %d: %v", id, err)
+		http.Error(w, "failed to update comment", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("HX-Refresh", "true")
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// DeleteComment handles DELETE /admin/comments/{id}
+func (h *Handler) DeleteComment(w http.ResponseWriter, r *http.Request) {
+	idStr := chi.URLParam(r, "id")
+	id, err := strconv.ParseInt(idStr, 10, 64)
+	if err != nil {
+		http.Error(w, "invalid id", http.StatusBadRequest)
+		return
+	}
+
+	q := sqlc.New(h.db)
+
+	if _, err := q.GetPhotoComment(r.Context(), id); err != nil {
+		http.Error(w, "comment not found", http.StatusNotFound)
+		return
+	}
+
+	if err := q.DeletePhotoComment(r.Context(), id); err != nil {
+		log.Printf("failed to delete comment %d: %v", id, err)
+		http.Error(w, "failed to delete comment", http.StatusInternalServerError)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
@@ -0,0 +1,290 @@
+package handler_test
+
+import (
+	"context"
+	"database/sql"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"regexp"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/go-chi/chi/v5"
+
+	"familyshare/internal/config"
+	"familyshare/internal/db/sqlc"
+	"familyshare/internal/handler"
+	"familyshare/internal/storage"
+	"familyshare/internal/testutil"
+	"familyshare/web"
+)
+
+var commentCSRFPattern = regexp.MustCompile(`name="csrf_token" value="([^"]+)"`)
+
+func setupCommentTest(t *testing.T) (*handler.Handler, *sqlc.Queries) {
+	t.Helper()
+	dbConn, q, dbCleanup := testutil.SetupTestDB(t)
+	t.Cleanup(dbCleanup)
+
+	storageDir, storageCleanup := testutil.SetupTestStorage(t)
+	t.Cleanup(storageCleanup)
+
+	cfg := &config.Config{RateLimitShare: 100000, RateLimitAdmin: 10, CSRFSecret: "comment-test-secret"}
+	h := handler.New(dbConn, storage.New(storageDir), web.EmbedFS, cfg, nil)
+	return h, q
+}
+
+func createCommentShare(t *testing.T, q *sqlc.Queries, token string, photoID int64, allowComments bool) sqlc.ShareLink {
+	t.Helper()
+	link, err := q.CreateShareLink(context.Background(), sqlc.CreateShareLinkParams{
+		Token:         token,
+		TargetType:    "photo",
+		TargetID:      photoID,
+		AllowComments: allowComments,
+	})
+	if err != nil {
+		t.Fatalf("CreateShareLink: %v", err)
+	}
+	return link
+}
+
+// visitShare renders the share page and returns its cookies and comment CSRF token.
+func visitShare(t *testing.T, h *handler.Handler, token string) ([]*http.Cookie, string) {
+	t.Helper()
+	req := httptest.NewRequest("GET", "/s/"+token, nil)
+	rctx := chi.NewRouteContext()
+	rctx.URLParams.Add("token", token)
+	req = req.WithContext(context.WithValue(req.Context(), chi.RouteCtxKey, rctx))
+	w := httptest.NewRecorder()
+	h.ViewShareLink(w, req)
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected 200 from share page, got %d", w.Code)
+	}
+
+	var csrf string
+	if m := commentCSRFPattern.FindStringSubmatch(w.Body.String()); m != nil {
+		csrf = m[1]
+	}
+	return w.Result().Cookies(), csrf
+}
+
+func postComment(h *handler.Handler, token string, photoID int64, cookies []*http.Cookie, csrf, name, body string) *httptest.ResponseRecorder {
+	vals := url.Values{}
+	vals.Set("csrf_token", csrf)
+	vals.Set("display_name", name)
+	vals.Set("body", body)
+	idStr := strconv.FormatInt(photoID, 10)
+	req := httptest.NewRequest("POST", "/s/"+token+"/photos/"+idStr+"/comments", strings.NewReader(vals.Encode()))
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.Header.Set("HX-Request", "true")
+	for _, c := range cookies {
+		req.AddCookie(c)
+	}
+	rctx := chi.NewRouteContext()
+	rctx.URLParams.Add("token", token)
+	rctx.URLParams.Add("id", idStr)
+	req = req.WithContext(context.WithValue(req.Context(), chi.RouteCtxKey, rctx))
+	w := httptest.NewRecorder()
+	h.PostShareComment(w, req)
+	return w
+}
+
+func TestPostShareComment_Success(t *testing.T) {
+	h, q := setupCommentTest(t)
+	ctx := context.Background()
+
+	album := testutil.CreateTestAlbum(t, q, "Birthday", "")
+	photo := testutil.CreateTestPhoto(t, q, album.ID, "cake.webp")
+	link := createCommentShare(t, q, "comment-token", photo.ID, true)
+
+	cookies, csrf := visitShare(t, h, "comment-token")
+	if csrf == "" {
+		t.Fatal("expected comment form with CSRF token on share page")
+	}
+
+	w := postComment(h, "comment-token", photo.ID, cookies, csrf, "Aunt May", "Lovely cake!")
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d: %s", w.Code, w.Body.String())
+	}
+	if !strings.Contains(w.Body.String(), "Lovely cake!") {
+		t.Errorf("expected rendered comment in response, got %s", w.Body.String())
+	}
+
+	comments, err := q.ListVisiblePhotoComments(ctx, sqlc.ListVisiblePhotoCommentsParams{PhotoID: photo.ID, ShareLinkID: link.ID})
+	if err != nil {
+		t.Fatalf("ListVisiblePhotoComments: %v", err)
+	}
+	if len(comments) != 1 || comments[0].DisplayName != "Aunt May" {
+		t.Fatalf("expected one comment from Aunt May, got %+v", comments)
+	}
+}
+
+func TestPostShareComment_Rejected(t *testing.T) {
+	h, q := setupCommentTest(t)
+
+	album := testutil.CreateTestAlbum(t, q, "Birthday", "")
+	photo := testutil.CreateTestPhoto(t, q, album.ID, "cake.webp")
+	other := testutil.CreateTestPhoto(t, q, album.ID, "other.webp")
+	createCommentShare(t, q, "open-token", photo.ID, true)
+	createCommentShare(t, q, "closed-token", photo.ID, false)
+
+	cookies, csrf := visitShare(t, h, "open-token")
+
+	tests := []struct {
+		name    string
+		token   string
+		photoID int64
+		csrf    string
+		author  string
+		body    string
+		want    int
+	}{
+		{"missing csrf", "open-token", photo.ID, "", "Bob", "Hi", http.StatusForbidden},
+		{"forged csrf", "open-token", photo.ID, "not-a-token", "Bob", "Hi", http.StatusForbidden},
+		{"comments disabled", "closed-token", photo.ID, csrf, "Bob", "Hi", http.StatusForbidden},
+		{"photo outside link", "open-token", other.ID, csrf, "Bob", "Hi", http.StatusNotFound},
+		{"empty body", "open-token", photo.ID, csrf, "Bob", "  ", http.StatusBadRequest},
+		{"name too long", "open-token", photo.ID, csrf, strings.Repeat("a", 51), "Hi", http.StatusBadRequest},
+		{"unknown link", "missing-token", photo.ID, csrf, "Bob", "Hi", http.StatusNotFound},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := postComment(h, tt.token, tt.photoID, cookies, tt.csrf, tt.author, tt.body)
+			if w.Code != tt.want {
+				t.Errorf("expected %d, got %d: %s", tt.want, w.Code, w.Body.String())
+			}
+		})
+	}
+}
+
+func TestPostShareComment_TokenBoundToLink(t *testing.T) {
+	h, q := setupCommentTest(t)
+
+	album := testutil.CreateTestAlbum(t, q, "Birthday", "")
+	photo := testutil.CreateTestPhoto(t, q, album.ID, "cake.webp")
+	createCommentShare(t, q, "first-token", photo.ID, true)
+	createCommentShare(t, q, "second-token", photo.ID, true)
+
+	cookies, csrf := visitShare(t, h, "first-token")
+
+	w := postComment(h, "second-token", photo.ID, cookies, csrf, "Bob", "Hi")
+	if w.Code != http.StatusForbidden {
+		t.Fatalf("expected token from another link to be rejected, got %d", w.Code)
+	}
+}
+
+func TestCommentModeration(t *testing.T) {
+	h, q := setupCommentTest(t)
+	ctx := context.Background()
+
+	album := testutil.CreateTestAlbum(t, q, "Birthday", "")
+	photo := testutil.CreateTestPhoto(t, q, album.ID, "cake.webp")
+	link := createCommentShare(t, q, "moderate-token", photo.ID, true)
+
+	comment, err := q.CreatePhotoComment(ctx, sqlc.CreatePhotoCommentParams{
+		PhotoID:     photo.ID,
+		ShareLinkID: link.ID,
+		DisplayName: "Spammer",
+		Body:        "buy now",
+	})
+	if err != nil {
+		t.Fatalf("CreatePhotoComment: %v", err)
+	}
+
+	moderate := func(method, path string, fn http.HandlerFunc) int {
+		req := httptest.NewRequest(method, path, nil)
+		rctx := chi.NewRouteContext()
+		rctx.URLParams.Add("id", strconv.FormatInt(comment.ID, 10))
+		req = req.WithContext(context.WithValue(req.Context(), chi.RouteCtxKey, rctx))
+		w := httptest.NewRecorder()
+		fn(w, req)
+		return w.Code
+	}
+	visible := func() int {
+		comments, err := q.ListVisiblePhotoComments(ctx, sqlc.ListVisiblePhotoCommentsParams{PhotoID: photo.ID, ShareLinkID: link.ID})
+		if err != nil {
+			t.Fatalf("ListVisiblePhotoComments: %v", err)
+		}
+		return len(comments)
+	}
+
+	// Listing shows the comment with its album
+	req := httptest.NewRequest("GET", "/admin/comments", nil)
+	w := httptest.NewRecorder()
+	h.ListComments(w, req)
+	if w.Code != http.StatusOK || !strings.Contains(w.Body.String(), "buy now") || !strings.Contains(w.Body.String(), "Birthday") {
+		t.Fatalf("expected comment listed, got %d: %s", w.Code, w.Body.String())
+	}
+
+	if code := moderate("POST", "/admin/comments/1/hide", h.HideComment); code != http.StatusNoContent {
+		t.Fatalf("hide: expected 204, got %d", code)
+	}
+	if n := visible(); n != 0 {
+		t.Fatalf("expected hidden comment to disappear from share page, got %d", n)
+	}
+
+	if code := moderate("POST", "/admin/comments/1/unhide", h.UnhideComment); code != http.StatusNoContent {
+		t.Fatalf("unhide: expected 204, got %d", code)
+	}
+	if n := visible(); n != 1 {
+		t.Fatalf("expected comment visible again, got %d", n)
+	}
+
+	if code := moderate("DELETE", "/admin/comments/1", h.DeleteComment); code != http.StatusNoContent {
+		t.Fatalf("delete: expected 204, got %d", code)
+	}
+	if _, err := q.GetPhotoComment(ctx, comment.ID); err != sql.ErrNoRows {
+		t.Fatalf("expected comment deleted, got %v", err)
+	}
+	if code := moderate("DELETE", "/admin/comments/1", h.DeleteComment); code != http.StatusNotFound {
+		t.Fatalf("expected 404 deleting twice, got %d", code)
+	}
+}
+
+func TestUpdatePhotoCaption(t *testing.T) {
+	h, q := setupCommentTest(t)
+	ctx := context.Background()
+
+	album := testutil.CreateTestAlbum(t, q, "Beach", "")
+	photo := testutil.CreateTestPhoto(t, q, album.ID, "waves.webp")
+
+	post := func(caption string) *httptest.ResponseRecorder {
+		vals := url.Values{}
+		vals.Set("caption", caption)
+		req := httptest.NewRequest("POST", "/admin/photos/1/caption", strings.NewReader(vals.Encode()))
+		req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+		rctx := chi.NewRouteContext()
+		rctx.URLParams.Add("id", strconv.FormatInt(photo.ID, 10))
+		req = req.WithContext(context.WithValue(req.Context(), chi.RouteCtxKey, rctx))
+		w := httptest.NewRecorder()
+		h.UpdatePhotoCaption(w, req)
+		return w
+	}
+
+	w := post("  Sunset at the pier  ")
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d: %s", w.Code, w.Body.String())
+	}
+	if !strings.Contains(w.Body.String(), "Sunset at the pier") {
+		t.Errorf("expected caption in rendered card")
+	}
+	got, _ := q.GetPhoto(ctx, photo.ID)
+	if !got.Caption.Valid || got.Caption.String != "Sunset at the pier" {
+		t.Fatalf("expected trimmed caption saved, got %+v", got.Caption)
+	}
+
+	if w := post(strings.Repeat("x", 501)); w.Code != http.StatusBadRequest {
+		t.Fatalf("expected 400 for long caption, got %d", w.Code)
+	}
+
+	if w := post(""); w.Code != http.StatusOK {
+		t.Fatalf("expected 200 clearing caption, got %d", w.Code)
+	}
+	got, _ = q.GetPhoto(ctx, photo.ID)
+	if got.Caption.Valid {
+		t.Fatalf("expected caption cleared, got %q", got.Caption.String)
+	}
+}
@@ -0,0 +1,204 @@
+package handler
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"math"
+	"net/http"
+	"strconv"
+
+	"github.com/go-chi/chi/v5"
+
+	"familyshare/internal/db/sqlc"
+	"familyshare/internal/pipeline"
+)
+
+// imageFormat is the configured format of albums without a processing profile.
+func (h *Handler) imageFormat() string {
+	if h.config != nil && h.config.ImageFormat != "" {
+		return h.config.ImageFormat
+	}
+	return "webp"
+}
+
+// errAnimatedEdit is returned for edits of animated photos: the edits would
+// only change the poster, not the kept animation.
+var errAnimatedEdit = errors.New("animated photos can't be edited")
+
+// editPhoto changes the edits of a photo with change and renders it again
+// with the settings of its album.
+func (h *Handler) editPhoto(ctx context.Context, q *sqlc.Queries, photo sqlc.Photo, change func(*pipeline.Edits) error) error {
+	if photo.AnimationFormat.Valid {
+		return errAnimatedEdit
+	}
+	edits, err := pipeline.ParseEdits(photo.Edits)
+	if err != nil {
+		return err
+	}
+	if err := change(&edits); err != nil {
+		return err
+	}
+	profile, err := pipeline.AlbumProfile(ctx, q, photo.AlbumID, h.imageFormat())
+	if err != nil {
+		return fmt.Errorf("load profile: %w", err)
+	}
+	_, err = pipeline.Edit(ctx, h.db, h.storage.BaseDir, photo, edits, profile)
+	return err
+}
+
+// parseEditOp reads an edit form into a change of a photo's edits.
+// Form fields: op (rotate, flip, crop, uncrop or adjust) and its values.
+func parseEditOp(r *http.Request) (func(*pipeline.Edits) error, error) {
+	switch r.PostFormValue("op") {
+	case "rotate":
+		angle, err := strconv.Atoi(r.PostFormValue("angle"))
+		if err != nil || !validRotation(angle) {
+			return nil, errors.New("angle must be 90, 180, or 270 (-90)")
+		}
+		return func(e *pipeline.Edits) error { e.RotateBy(angle); return nil }, nil
+	case "flip":
+		vertical := r.PostFormValue("direction") == "vertical"
+		if !vertical && r.PostFormValue("direction") != "horizontal" {
+			return nil, errors.New("direction must be horizontal or vertical")
+		}
+		return func(e *pipeline.Edits) error { e.Flip(vertical); return nil }, nil
+	case "crop":
+		var c pipeline.Crop
+		for _, f := range []struct {
+			name string
+			v    *float64
+		}{{"x", &c.X}, {"y", &c.Y}, {"w", &c.W}, {"h", &c.H}} {
+			v, err := strconv.ParseFloat(r.PostFormValue(f.name), 64)
+			if err != nil || math.IsNaN(v) {
+				return nil, fmt.Errorf("crop %s must be a number", f.name)
+			}
+			*f.v = v
+		}
+		if !c.Valid() {
+			return nil, errors.New("crop must lie within the photo")
+		}
+		return func(e *pipeline.Edits) error { return e.CropTo(c) }, nil
+	case "uncrop":
+		return func(e *pipeline.Edits) error { e.Crop = nil; return nil }, nil
+	case "adjust":
+		var values [3]float64
+		for i, name := range []string{"brightness", "contrast", "saturation"} {
+			v, err := strconv.Atoi(r.PostFormValue(name))
+			if err != nil || v < -100 || v > 100 {
+				return nil, fmt.Errorf("%s must be between -100 and 100", name)
+			}
+			values[i] = float64(v)
+		}
+		return func(e *pipeline.Edits) error {
+			e.Brightness, e.Contrast, e.Saturation = values[0], values[1], values[2]
+			return nil
+		}, nil
+	}
+	return nil, errors.New("unknown operation")
+}
+
+// photoEditPage is the data of the photo editor.
+type photoEditPage struct {
+	Photo      sqlc.Photo
+	AlbumTitle string
+	Edits      pipeline.Edits
+	Brightness int
+	Contrast   int
+	Saturation int
+}
+
+// EditPhotoPage handles GET /admin/photos/{id}/edit
+func (h *Handler) EditPhotoPage(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.ParseInt(chi.URLParam(r, "id"), 10, 64)
+	if err != nil {
+		http.Error(w, "invalid id", http.StatusBadRequest)
+		return
+	}
+
+	q := sqlc.New(h.db)
+	photo, err := q.GetPhoto(r.Context(), id)
+	if err != nil {
+		http.Error(w, "photo not found", http.StatusNotFound)
+		return
+	}
+	edits, err := pipeline.ParseEdits(photo.Edits)
+	if err != nil {
+		log.Printf("photo %d has unreadable edits: %v", id, err)
+	}
+
+	data := photoEditPage{
+		Photo:      photo,
+		Edits:      edits,
+		Brightness: int(edits.Brightness),
+		Contrast:   int(edits.Contrast),
+		Saturation: int(edits.Saturation),
+	}
+	if album, err := q.GetAlbum(r.Context(), photo.AlbumID); err == nil {
+		data.AlbumTitle = album.Title
+	}
+
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	if err := h.RenderTemplate(w, "photo_edit.html", data); err != nil {
+		log.Printf("template render error for photo edit: %v", err)
+		http.Error(w, "template render error", http.StatusInternalServerError)
+	}
+}
+
+// EditPhoto handles POST /admin/photos/{id}/edit
+// Form fields: op and the values of the operation (see parseEditOp).
+func (h *Handler) EditPhoto(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.ParseInt(chi.URLParam(r, "id"), 10, 64)
+	if err != nil {
+		http.Error(w, "invalid id", http.StatusBadRequest)
+		return
+	}
+	if err := r.ParseForm(); err != nil {
+		http.Error(w, "invalid form", http.StatusBadRequest)
+		return
+	}
+	change, err := parseEditOp(r)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	h.applyPhotoEdit(w, r, id, change)
+}
+
+// RevertPhoto handles POST /admin/photos/{id}/revert
+func (h *Handler) RevertPhoto(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.ParseInt(chi.URLParam(r, "id"), 10, 64)
+	if err != nil {
+		http.Error(w, "invalid id", http.StatusBadRequest)
+		return
+	}
+	h.applyPhotoEdit(w, r, id, func(e *pipeline.Edits) error { *e = pipeline.Edits{}; return nil })
+}
+
+// applyPhotoEdit renders a photo with changed edits and refreshes the page.
+func (h *Handler) applyPhotoEdit(w http.ResponseWriter, r *http.Request, id int64, change func(*pipeline.Edits) error) {
+	q := sqlc.New(h.db)
+	photo, err := q.GetPhoto(r.Context(), id)
+	if err != nil {
+		http.Error(w, "photo not found", http.StatusNotFound)
+		return
+	}
+
+	if err := h.editPhoto(r.Context(), q, photo, change); err != nil {
+		if errors.Is(err, errAnimatedEdit) {
+			http.Error(w, err.Error(), http.StatusConflict)
+			return
+		}
+		if errors.Is(err, pipeline.ErrCropTooSmall) {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		log.Printf("failed to edit photo %d: %v", id, err)
+		http.Error(w, "failed to edit photo", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("HX-Refresh", "true")
+	w.WriteHeader(http.StatusNoContent)
+}
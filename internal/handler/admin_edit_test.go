@@ -0,0 +1,150 @@
+package handler_test
+
+import (
+	"context"
+	"database/sql"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"strconv"
+	"strings"
+	"testing"
+
+	"familyshare/internal/db/sqlc"
+	"familyshare/internal/pipeline"
+	"familyshare/internal/storage"
+	"familyshare/internal/testutil"
+)
+
+func TestEditPhoto(t *testing.T) {
+	h, q, baseDir := setupBulkTest(t)
+	ctx := context.Background()
+
+	album := testutil.CreateTestAlbum(t, q, "Trip", "")
+	photo := testutil.CreateTestPhoto(t, q, album.ID, "wide.webp")
+	createTestWebP(t, storage.PhotoPathAt(baseDir, album.ID, photo.ID, "webp", photo.CreatedAt.Time), 100, 50)
+
+	edit := func(vals url.Values) *httptest.ResponseRecorder {
+		return postIDForm(h.EditPhoto, photo.ID, vals)
+	}
+	current := func() (sqlc.Photo, pipeline.Edits) {
+		t.Helper()
+		p, err := q.GetPhoto(ctx, photo.ID)
+		if err != nil {
+			t.Fatalf("GetPhoto: %v", err)
+		}
+		e, err := pipeline.ParseEdits(p.Edits)
+		if err != nil {
+			t.Fatalf("ParseEdits: %v", err)
+		}
+		return p, e
+	}
+
+	if w := edit(url.Values{"op": {"rotate"}, "angle": {"-90"}}); w.Code != http.StatusNoContent || w.Header().Get("HX-Refresh") != "true" {
+		t.Fatalf("expected 204 with refresh, got %d: %s", w.Code, w.Body.String())
+	}
+	p, e := current()
+	if p.Width != 50 || p.Height != 100 || e.Rotate != 270 {
+		t.Fatalf("expected 50x100 turned right, got %dx%d %+v", p.Width, p.Height, e)
+	}
+	if !p.OriginalFormat.Valid {
+		t.Fatalf("expected the stored file kept as original")
+	}
+
+	// Crop the top half of the rotated photo, then flip and adjust it
+	if w := edit(url.Values{"op": {"crop"}, "x": {"0"}, "y": {"0"}, "w": {"1"}, "h": {"0.5"}}); w.Code != http.StatusNoContent {
+		t.Fatalf("crop: expected 204, got %d: %s", w.Code, w.Body.String())
+	}
+	if w := edit(url.Values{"op": {"flip"}, "direction": {"horizontal"}}); w.Code != http.StatusNoContent {
+		t.Fatalf("flip: expected 204, got %d: %s", w.Code, w.Body.String())
+	}
+	if w := edit(url.Values{"op": {"adjust"}, "brightness": {"20"}, "contrast": {"0"}, "saturation": {"-10"}}); w.Code != http.StatusNoContent {
+		t.Fatalf("adjust: expected 204, got %d: %s", w.Code, w.Body.String())
+	}
+	p, e = current()
+	if p.Width != 50 || p.Height != 50 || e.Crop == nil || !e.Mirror || e.Brightness != 20 || e.Saturation != -10 {
+		t.Fatalf("expected cropped, mirrored and adjusted 50x50, got %dx%d %+v", p.Width, p.Height, e)
+	}
+
+	if w := edit(url.Values{"op": {"uncrop"}}); w.Code != http.StatusNoContent {
+		t.Fatalf("uncrop: expected 204, got %d", w.Code)
+	}
+	if p, e = current(); p.Height != 100 || e.Crop != nil {
+		t.Errorf("expected crop removed, got %dx%d %+v", p.Width, p.Height, e)
+	}
+
+	// The editor shows the photo and its adjustments
+	w := postIDForm(h.EditPhotoPage, photo.ID, url.Values{})
+	if w.Code != http.StatusOK || !strings.Contains(w.Body.String(), "Revert to original") || !strings.Contains(w.Body.String(), `value="20"`) {
+		t.Errorf("expected editor with revert action, got %d", w.Code)
+	}
+
+	// A copy keeps the edits and its own original
+	other := testutil.CreateTestAlbum(t, q, "Other", "")
+	vals := photoIDValues(photo.ID)
+	vals.Set("album_id", strconv.FormatInt(other.ID, 10))
+	if w := postBulk(h.CopyPhotos, "/admin/photos/copy", vals); w.Code != http.StatusNoContent {
+		t.Fatalf("copy: expected 204, got %d: %s", w.Code, w.Body.String())
+	}
+	copies, _ := q.ListPhotosByAlbum(ctx, sqlc.ListPhotosByAlbumParams{AlbumID: other.ID, Limit: 10})
+	if len(copies) != 1 || copies[0].Edits != p.Edits || !copies[0].OriginalFormat.Valid {
+		t.Errorf("expected copy with edits %q, got %+v", p.Edits.String, copies)
+	}
+
+	if w := postIDForm(h.RevertPhoto, photo.ID, url.Values{}); w.Code != http.StatusNoContent {
+		t.Fatalf("revert: expected 204, got %d: %s", w.Code, w.Body.String())
+	}
+	p, _ = current()
+	if p.Width != 100 || p.Height != 50 || p.Edits.Valid {
+		t.Errorf("expected the original 100x50 without edits, got %dx%d %q", p.Width, p.Height, p.Edits.String)
+	}
+	if _, err := os.Stat(storage.OriginalPath(baseDir, photo.ID, p.OriginalFormat.String)); err != nil {
+		t.Errorf("expected original kept after revert: %v", err)
+	}
+}
+
+func TestEditPhoto_Invalid(t *testing.T) {
+	h, q, _ := setupBulkTest(t)
+
+	album := testutil.CreateTestAlbum(t, q, "Trip", "")
+	photo := testutil.CreateTestPhoto(t, q, album.ID, "wide.webp")
+
+	for _, vals := range []url.Values{
+		{"op": {"sharpen"}},
+		{"op": {"rotate"}, "angle": {"45"}},
+		{"op": {"flip"}, "direction": {"diagonal"}},
+		{"op": {"crop"}, "x": {"0.5"}, "y": {"0"}, "w": {"0.6"}, "h": {"1"}},
+		{"op": {"crop"}, "x": {"NaN"}, "y": {"0"}, "w": {"0.5"}, "h": {"1"}},
+		{"op": {"adjust"}, "brightness": {"150"}, "contrast": {"0"}, "saturation": {"0"}},
+	} {
+		if w := postIDForm(h.EditPhoto, photo.ID, vals); w.Code != http.StatusBadRequest {
+			t.Errorf("%v: expected 400, got %d", vals, w.Code)
+		}
+	}
+
+	// A crop of a crop can't end up smaller than the smallest crop
+	cropped := pipeline.Edits{Crop: &pipeline.Crop{X: 0, Y: 0, W: 0.05, H: 1}}
+	if err := q.SetPhotoEdits(context.Background(), sqlc.SetPhotoEditsParams{Edits: cropped.Value(), ID: photo.ID}); err != nil {
+		t.Fatalf("SetPhotoEdits: %v", err)
+	}
+	if w := postIDForm(h.EditPhoto, photo.ID, url.Values{"op": {"crop"}, "x": {"0"}, "y": {"0"}, "w": {"0.1"}, "h": {"1"}}); w.Code != http.StatusBadRequest {
+		t.Errorf("expected 400 for a crop narrowed below the smallest crop, got %d", w.Code)
+	}
+
+	if w := postIDForm(h.EditPhoto, photo.ID+100, url.Values{"op": {"uncrop"}}); w.Code != http.StatusNotFound {
+		t.Errorf("expected 404 for unknown photo, got %d", w.Code)
+	}
+	if w := postIDForm(h.EditPhotoPage, photo.ID+100, url.Values{}); w.Code != http.StatusNotFound {
+		t.Errorf("expected 404 editor for unknown photo, got %d", w.Code)
+	}
+
+	// Edits would only change the poster of an animated photo
+	animated := sql.NullString{String: "gif", Valid: true}
+	if err := q.SetPhotoAnimationFormat(context.Background(), sqlc.SetPhotoAnimationFormatParams{AnimationFormat: animated, ID: photo.ID}); err != nil {
+		t.Fatalf("SetPhotoAnimationFormat: %v", err)
+	}
+	if w := postIDForm(h.EditPhoto, photo.ID, url.Values{"op": {"uncrop"}}); w.Code != http.StatusConflict {
+		t.Errorf("expected 409 for an animated photo, got %d", w.Code)
+	}
+}
@@ -0,0 +1,200 @@
+package handler
+
+import (
+	"errors"
+	"log"
+	"net/http"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+
+	"familyshare/internal/db/sqlc"
+	"familyshare/internal/pipeline"
+)
+
+// jobError turns the error message stored on a failed job back into an error
+// friendlyUploadError can classify.
+func jobError(msg string) error {
+	for _, known := range []error{pipeline.ErrTooLarge, pipeline.ErrNotAnImage, pipeline.ErrInvalidDimensions, pipeline.ErrDecodeFailed} {
+		if strings.Contains(msg, known.Error()) {
+			return known
+		}
+	}
+	return errors.New(msg)
+}
+
+// failedUpload is a failed processing job as shown on the failed uploads page.
+type failedUpload struct {
+	ID       int64
+	AlbumID  int64
+	Album    string
+	Filename string
+	Reason   string
+	Detail   string
+	Attempts int64
+	FailedAt time.Time
+	CanRetry bool
+	// Reprocess marks a failed re-encode of a stored photo.
+	Reprocess bool
+}
+
+// canRetry reports whether a failed job can run again: uploads need their
+// temp file, reprocess jobs work from the stored photo.
+func canRetry(kind, tempFilepath string) bool {
+	if kind == "reprocess" {
+		return true
+	}
+	_, err := os.Stat(tempFilepath)
+	return err == nil
+}
+
+// ListFailedUploads handles GET /admin/uploads/failed
+func (h *Handler) ListFailedUploads(w http.ResponseWriter, r *http.Request) {
+	q := sqlc.New(h.db)
+
+	jobs, err := q.ListFailedJobsWithAlbum(r.Context())
+	if err != nil {
+		log.Printf("failed to list failed jobs: %v", err)
+		http.Error(w, "failed to load failed uploads", http.StatusInternalServerError)
+		return
+	}
+
+	uploads := make([]failedUpload, 0, len(jobs))
+	for _, job := range jobs {
+		uploads = append(uploads, failedUpload{
+			ID:        job.ID,
+			AlbumID:   job.AlbumID,
+			Album:     job.AlbumTitle,
+			Filename:  job.OriginalFilename,
+			Reason:    friendlyUploadError(h.locale, jobError(job.ErrorMessage.String), maxUploadFileSize),
+			Detail:    job.ErrorMessage.String,
+			Attempts:  job.Attempts,
+			FailedAt:  job.UpdatedAt.Time,
+			CanRetry:  canRetry(job.Kind, job.TempFilepath),
+			Reprocess: job.Kind == "reprocess",
+		})
+	}
+
+	retentionDays := 0
+	if h.config != nil {
+		retentionDays = int(h.config.FailedJobRetention / (24 * time.Hour))
+	}
+
+	data := struct {
+		Uploads       []failedUpload
+		RetentionDays int
+	}{
+		Uploads:       uploads,
+		RetentionDays: retentionDays,
+	}
+
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	if err := h.RenderTemplate(w, "failed_uploads.html", data); err != nil {
+		log.Printf("template render error for failed uploads: %v", err)
+		http.Error(w, "template render error", http.StatusInternalServerError)
+	}
+}
+
+// RetryFailedUpload handles POST /admin/uploads/failed/{id}/retry
+func (h *Handler) RetryFailedUpload(w http.ResponseWriter, r *http.Request) {
+	idStr := chi.URLParam(r, "id")
+	id, err := strconv.ParseInt(idStr, 10, 64)
+	if err != nil {
+		http.Error(w, "invalid id", http.StatusBadRequest)
+		return
+	}
+
+	q := sqlc.New(h.db)
+
+	job, err := q.GetJob(r.Context(), id)
+	if err != nil || job.Status != "failed" {
+		http.Error(w, "failed upload not found", http.StatusNotFound)
+		return
+	}
+	if !canRetry(job.Kind, job.TempFilepath) {
+		http.Error(w, "the original upload is no longer available", http.StatusConflict)
+		return
+	}
+
+	if err := q.RequeueFailedJob(r.Context(), id); err != nil {
+		log.Printf("failed to requeue job %d: %v", id, err)
+		http.Error(w, "failed to retry upload", http.StatusInternalServerError)
+		return
+	}
+	h.publishJob(job, "pending")
+	if h.worker != nil {
+		h.worker.TriggerSignal()
+	}
+
+	w.Header().Set("HX-Refresh", "true")
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// RetryAllFailedUploads handles POST /admin/uploads/failed/retry
+// Failed uploads whose original file is gone are left in the list.
+func (h *Handler) RetryAllFailedUploads(w http.ResponseWriter, r *http.Request) {
+	q := sqlc.New(h.db)
+
+	jobs, err := q.ListFailedJobsWithAlbum(r.Context())
+	if err != nil {
+		log.Printf("failed to list failed jobs: %v", err)
+		http.Error(w, "failed to retry uploads", http.StatusInternalServerError)
+		return
+	}
+
+	queued := 0
+	for _, job := range jobs {
+		if !canRetry(job.Kind, job.TempFilepath) {
+			continue
+		}
+		if err := q.RequeueFailedJob(r.Context(), job.ID); err != nil {
+			log.Printf("failed to requeue job %d: %v", job.ID, err)
+			continue
+		}
+		h.publishJob(sqlc.ProcessingQueue{ID: job.ID, AlbumID: job.AlbumID, OriginalFilename: job.OriginalFilename}, "pending")
+		queued++
+	}
+	if h.worker != nil && queued > 0 {
+		h.worker.TriggerSignal()
+	}
+
+	w.Header().Set("HX-Refresh", "true")
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// DiscardFailedUpload handles DELETE /admin/uploads/failed/{id}
+func (h *Handler) DiscardFailedUpload(w http.ResponseWriter, r *http.Request) {
+	idStr := chi.URLParam(r, "id")
+	id, err := strconv.ParseInt(idStr, 10, 64)
+	if err != nil {
+		http.Error(w, "invalid id", http.StatusBadRequest)
+		return
+	}
+
+	q := sqlc.New(h.db)
+
+	job, err := q.GetJob(r.Context(), id)
+	if err != nil || job.Status != "failed" {
+		http.Error(w, "failed upload not found", http.StatusNotFound)
+		return
+	}
+
+	if err := q.DeleteJob(r.Context(), id); err != nil {
+		log.Printf("failed to delete job %d: %v", id, err)
+		http.Error(w, "failed to discard upload", http.StatusInternalServerError)
+		return
+	}
+	h.publishJob(job, "")
+	// Reprocess jobs have no temp file
+	if job.TempFilepath != "" {
+		if err := os.Remove(job.TempFilepath); err != nil && !os.IsNotExist(err) {
+			// Don't return error - the janitor sweeps unreferenced temp files
+			log.Printf("failed to delete temp file %s: %v", job.TempFilepath, err)
+		}
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
@@ -0,0 +1,131 @@
+package handler_test
+
+import (
+	"context"
+	"database/sql"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"familyshare/internal/db/sqlc"
+	"familyshare/internal/testutil"
+)
+
+// failedJob enqueues a job with a temp input and marks it failed.
+func failedJob(t *testing.T, q *sqlc.Queries, albumID int64, filename, msg string) (sqlc.ProcessingQueue, string) {
+	t.Helper()
+	ctx := context.Background()
+	path := filepath.Join(t.TempDir(), "upload-1.tmp")
+	if err := os.WriteFile(path, []byte(filename), 0o600); err != nil {
+		t.Fatalf("write temp file: %v", err)
+	}
+	job, err := q.EnqueueJob(ctx, sqlc.EnqueueJobParams{AlbumID: albumID, OriginalFilename: filename, TempFilepath: path})
+	if err != nil {
+		t.Fatalf("EnqueueJob: %v", err)
+	}
+	if err := q.UpdateJobStatus(ctx, sqlc.UpdateJobStatusParams{
+		Status:       "failed",
+		ErrorMessage: sql.NullString{String: msg, Valid: true},
+		ID:           job.ID,
+	}); err != nil {
+		t.Fatalf("UpdateJobStatus: %v", err)
+	}
+	return job, path
+}
+
+func TestListFailedUploads(t *testing.T) {
+	h, q, _ := setupBulkTest(t)
+
+	album := testutil.CreateTestAlbum(t, q, "Trip", "")
+	failedJob(t, q, album.ID, "IMG_2041.HEIC", "validate decode: uploaded file is not an image")
+
+	w := httptest.NewRecorder()
+	h.ListFailedUploads(w, httptest.NewRequest("GET", "/admin/uploads/failed", nil))
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d", w.Code)
+	}
+	body := w.Body.String()
+	for _, want := range []string{"IMG_2041.HEIC", "Unsupported file type", "Trip", "/admin/uploads/failed/"} {
+		if !strings.Contains(body, want) {
+			t.Errorf("expected %q on the page", want)
+		}
+	}
+}
+
+func TestRetryFailedUpload(t *testing.T) {
+	h, q, _ := setupBulkTest(t)
+	ctx := context.Background()
+
+	album := testutil.CreateTestAlbum(t, q, "Trip", "")
+	job, _ := failedJob(t, q, album.ID, "beach.jpg", "database is locked")
+
+	if w := idRequest(h.RetryFailedUpload, "POST", job.ID); w.Code != http.StatusNoContent {
+		t.Fatalf("expected 204, got %d: %s", w.Code, w.Body.String())
+	}
+	retried, _ := q.GetJob(ctx, job.ID)
+	if retried.Status != "pending" || retried.ErrorMessage.Valid {
+		t.Errorf("expected job back in the queue, got %s (%v)", retried.Status, retried.ErrorMessage)
+	}
+
+	// Only failed jobs can be retried
+	if w := idRequest(h.RetryFailedUpload, "POST", job.ID); w.Code != http.StatusNotFound {
+		t.Errorf("expected 404 for a pending job, got %d", w.Code)
+	}
+
+	gone, gonePath := failedJob(t, q, album.ID, "gone.jpg", "boom")
+	if err := os.Remove(gonePath); err != nil {
+		t.Fatalf("remove: %v", err)
+	}
+	if w := idRequest(h.RetryFailedUpload, "POST", gone.ID); w.Code != http.StatusConflict {
+		t.Errorf("expected 409 without the original file, got %d", w.Code)
+	}
+}
+
+func TestRetryAllFailedUploads(t *testing.T) {
+	h, q, _ := setupBulkTest(t)
+	ctx := context.Background()
+
+	album := testutil.CreateTestAlbum(t, q, "Trip", "")
+	first, _ := failedJob(t, q, album.ID, "1.jpg", "boom")
+	second, _ := failedJob(t, q, album.ID, "2.jpg", "boom")
+	gone, gonePath := failedJob(t, q, album.ID, "gone.jpg", "boom")
+	if err := os.Remove(gonePath); err != nil {
+		t.Fatalf("remove: %v", err)
+	}
+
+	w := httptest.NewRecorder()
+	h.RetryAllFailedUploads(w, httptest.NewRequest("POST", "/admin/uploads/failed/retry", nil))
+	if w.Code != http.StatusNoContent {
+		t.Fatalf("expected 204, got %d", w.Code)
+	}
+
+	for _, id := range []int64{first.ID, second.ID} {
+		if job, _ := q.GetJob(ctx, id); job.Status != "pending" {
+			t.Errorf("expected job %d pending, got %s", id, job.Status)
+		}
+	}
+	if job, _ := q.GetJob(ctx, gone.ID); job.Status != "failed" {
+		t.Errorf("expected job without input to stay failed, got %s", job.Status)
+	}
+}
+
+func TestDiscardFailedUpload(t *testing.T) {
+	h, q, _ := setupBulkTest(t)
+	ctx := context.Background()
+
+	album := testutil.CreateTestAlbum(t, q, "Trip", "")
+	job, path := failedJob(t, q, album.ID, "beach.jpg", "boom")
+
+	if w := idRequest(h.DiscardFailedUpload, "DELETE", job.ID); w.Code != http.StatusNoContent {
+		t.Fatalf("expected 204, got %d", w.Code)
+	}
+	if _, err := q.GetJob(ctx, job.ID); err == nil {
+		t.Errorf("expected job deleted")
+	}
+	if _, err := os.Stat(path); !os.IsNotExist(err) {
+		t.Errorf("expected temp file removed, got: %v", err)
+	}
+}
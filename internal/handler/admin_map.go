@@ -0,0 +1,222 @@
+package handler
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"log"
+	"math"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"familyshare/internal/db/sqlc"
+)
+
+const (
+	// mapMaxClusters bounds the clusters returned for one view
+	mapMaxClusters = 500
+	// mapCellsPerTile is the clusters across one 256px map tile
+	mapCellsPerTile = 4
+	// mapMaxZoom is the deepest zoom level of the map
+	mapMaxZoom = 20
+	// mapPlaceCell groups photos within about a kilometre into one place
+	// in the list shown without map tiles
+	mapPlaceCell = 0.01
+	// mapMaxPlaces bounds the places listed without map tiles
+	mapMaxPlaces = 200
+)
+
+// mapPlace is a cluster of geotagged photos.
+type mapPlace struct {
+	Latitude  float64
+	Longitude float64
+	Count     int64
+	PhotoID   int64
+	PhotoURL  string
+}
+
+// photoMap is the data of the photo map component: the map when tiles are
+// configured, and the list of places it falls back to without them.
+type photoMap struct {
+	TileURL     string
+	Attribution string
+	ClustersURL string
+	Places      []mapPlace
+}
+
+// geoJSON types of the cluster endpoints.
+type geoJSONFeatureCollection struct {
+	Type     string           `json:"type"`
+	Features []geoJSONFeature `json:"features"`
+}
+
+type geoJSONFeature struct {
+	Type       string             `json:"type"`
+	Geometry   geoJSONPoint       `json:"geometry"`
+	Properties mapClusterProperty `json:"properties"`
+}
+
+type geoJSONPoint struct {
+	Type        string     `json:"type"`
+	Coordinates [2]float64 `json:"coordinates"`
+}
+
+type mapClusterProperty struct {
+	Count    int64  `json:"count"`
+	PhotoID  int64  `json:"photo_id"`
+	PhotoURL string `json:"photo_url"`
+}
+
+// parseBBox reads a bounding box as "west,south,east,north" in degrees, the
+// order of GeoJSON and of Leaflet's toBBoxString. Longitudes may run past
+// ±180 when the map is panned across the antimeridian.
+func parseBBox(s string) (west, south, east, north float64, err error) {
+	parts := strings.Split(s, ",")
+	if len(parts) != 4 {
+		return 0, 0, 0, 0, errors.New("bbox must be west,south,east,north")
+	}
+	var v [4]float64
+	for i, p := range parts {
+		v[i], err = strconv.ParseFloat(strings.TrimSpace(p), 64)
+		if err != nil || math.IsNaN(v[i]) || math.IsInf(v[i], 0) {
+			return 0, 0, 0, 0, errors.New("bbox must be four numbers")
+		}
+	}
+	west, south, east, north = v[0], max(v[1], -90), v[2], min(v[3], 90)
+	if west > east || south > north {
+		return 0, 0, 0, 0, errors.New("bbox is empty")
+	}
+	return west, south, east, north, nil
+}
+
+// lonRanges splits the longitudes from west to east into ranges within
+// -180 to 180, two of them when they cross the antimeridian.
+func lonRanges(west, east float64) [][2]float64 {
+	if east-west >= 360 {
+		return [][2]float64{{-180, 180}}
+	}
+	span := east - west
+	west = math.Mod(west+180, 360)
+	if west < 0 {
+		west += 360
+	}
+	west -= 180
+	east = west + span
+	if east <= 180 {
+		return [][2]float64{{west, east}}
+	}
+	return [][2]float64{{west, 180}, {-180, east - 360}}
+}
+
+// mapCell returns the cluster size in degrees at a zoom level.
+func mapCell(zoom int) float64 {
+	return 360 / (math.Exp2(float64(zoom)) * mapCellsPerTile)
+}
+
+// mapClusters clusters the geotagged photos within a bounding box, of one
+// album when albumID is set.
+func (h *Handler) mapClusters(ctx context.Context, albumID sql.NullInt64, cell, west, south, east, north float64, limit int64, photoURL func(int64) string) ([]mapPlace, error) {
+	q := sqlc.New(h.db)
+	var places []mapPlace
+	for _, lon := range lonRanges(west, east) {
+		rows, err := q.ClusterPhotoLocations(ctx, sqlc.ClusterPhotoLocationsParams{
+			Cell:        cell,
+			South:       south,
+			North:       north,
+			West:        lon[0],
+			East:        lon[1],
+			AlbumID:     albumID,
+			MaxClusters: limit,
+		})
+		if err != nil {
+			return nil, err
+		}
+		for _, row := range rows {
+			places = append(places, mapPlace{
+				Latitude:  row.Latitude,
+				Longitude: row.Longitude,
+				Count:     row.Count,
+				PhotoID:   row.PhotoID,
+				PhotoURL:  photoURL(row.PhotoID),
+			})
+		}
+	}
+	return places, nil
+}
+
+// photoMapFor builds the map component of the geotagged photos, of one
+// album when albumID is set.
+func (h *Handler) photoMapFor(ctx context.Context, albumID sql.NullInt64, clustersURL string, photoURL func(int64) string) photoMap {
+	m := photoMap{ClustersURL: clustersURL}
+	if h.config != nil {
+		m.TileURL, m.Attribution = h.config.MapTileURL, h.config.MapTileAttribution
+	}
+	places, err := h.mapClusters(ctx, albumID, mapPlaceCell, -180, -90, 180, 90, mapMaxPlaces, photoURL)
+	if err != nil {
+		log.Printf("failed to load photo places: %v", err)
+	}
+	m.Places = places
+	return m
+}
+
+// writeMapClusters answers a cluster request for the bounding box and zoom
+// of the request with a GeoJSON feature collection.
+// Query: bbox (west,south,east,north) and zoom (0-20).
+func (h *Handler) writeMapClusters(w http.ResponseWriter, r *http.Request, albumID sql.NullInt64, photoURL func(int64) string) {
+	west, south, east, north, err := parseBBox(r.URL.Query().Get("bbox"))
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	zoom, err := strconv.Atoi(r.URL.Query().Get("zoom"))
+	if err != nil || zoom < 0 || zoom > mapMaxZoom {
+		http.Error(w, "zoom must be between 0 and 20", http.StatusBadRequest)
+		return
+	}
+
+	places, err := h.mapClusters(r.Context(), albumID, mapCell(zoom), west, south, east, north, mapMaxClusters, photoURL)
+	if err != nil {
+		log.Printf("failed to cluster photo locations: %v", err)
+		http.Error(w, "failed to load map", http.StatusInternalServerError)
+		return
+	}
+
+	out := geoJSONFeatureCollection{Type: "FeatureCollection", Features: make([]geoJSONFeature, 0, len(places))}
+	for _, p := range places {
+		out.Features = append(out.Features, geoJSONFeature{
+			Type:     "Feature",
+			Geometry: geoJSONPoint{Type: "Point", Coordinates: [2]float64{p.Longitude, p.Latitude}},
+			Properties: mapClusterProperty{
+				Count:    p.Count,
+				PhotoID:  p.PhotoID,
+				PhotoURL: p.PhotoURL,
+			},
+		})
+	}
+	w.Header().Set("Content-Type", "application/geo+json")
+	_ = json.NewEncoder(w).Encode(out)
+}
+
+// adminPhotoURL is the admin URL of a photo.
+func adminPhotoURL(id int64) string {
+	return "/admin/photos/" + strconv.FormatInt(id, 10) + ".webp"
+}
+
+// AdminMap handles GET /admin/map
+func (h *Handler) AdminMap(w http.ResponseWriter, r *http.Request) {
+	data := h.photoMapFor(r.Context(), sql.NullInt64{}, "/admin/map/clusters", adminPhotoURL)
+
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	if err := h.RenderTemplate(w, "map.html", data); err != nil {
+		log.Printf("template render error for map: %v", err)
+		http.Error(w, "template render error", http.StatusInternalServerError)
+	}
+}
+
+// AdminMapClusters handles GET /admin/map/clusters
+// Query: bbox (west,south,east,north) and zoom.
+func (h *Handler) AdminMapClusters(w http.ResponseWriter, r *http.Request) {
+	h.writeMapClusters(w, r, sql.NullInt64{}, adminPhotoURL)
+}
@@ -0,0 +1,169 @@
+package handler_test
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+
+	"familyshare/internal/db/sqlc"
+	"familyshare/internal/testutil"
+)
+
+type mapFeatures struct {
+	Type     string `json:"type"`
+	Features []struct {
+		Geometry struct {
+			Coordinates [2]float64 `json:"coordinates"`
+		} `json:"geometry"`
+		Properties struct {
+			Count    int64  `json:"count"`
+			PhotoURL string `json:"photo_url"`
+		} `json:"properties"`
+	} `json:"features"`
+}
+
+func locatedAt(t *testing.T, q *sqlc.Queries, photoID int64, lat, lon float64) {
+	t.Helper()
+	err := q.UpdatePhotoLocation(context.Background(), sqlc.UpdatePhotoLocationParams{
+		Latitude:  sql.NullFloat64{Float64: lat, Valid: true},
+		Longitude: sql.NullFloat64{Float64: lon, Valid: true},
+		ID:        photoID,
+	})
+	if err != nil {
+		t.Fatalf("UpdatePhotoLocation: %v", err)
+	}
+}
+
+func getMapClusters(t *testing.T, handle http.HandlerFunc, token, query string) (*httptest.ResponseRecorder, mapFeatures) {
+	t.Helper()
+	req := httptest.NewRequest("GET", "/map/clusters?"+query, nil)
+	rctx := chi.NewRouteContext()
+	rctx.URLParams.Add("token", token)
+	req = req.WithContext(context.WithValue(req.Context(), chi.RouteCtxKey, rctx))
+	w := httptest.NewRecorder()
+	handle(w, req)
+
+	var fc mapFeatures
+	if w.Code == http.StatusOK {
+		if err := json.Unmarshal(w.Body.Bytes(), &fc); err != nil {
+			t.Fatalf("decode GeoJSON: %v", err)
+		}
+	}
+	return w, fc
+}
+
+// clusterCounts returns the total photos of the clusters.
+func clusterCounts(fc mapFeatures) int64 {
+	var n int64
+	for _, f := range fc.Features {
+		n += f.Properties.Count
+	}
+	return n
+}
+
+func TestAdminMapClusters(t *testing.T) {
+	h, q, _ := setupBulkTest(t)
+
+	album := testutil.CreateTestAlbum(t, q, "Travels", "")
+	for _, loc := range [][2]float64{{48.8584, 2.2945}, {48.8606, 2.3376}, {-33.8568, 151.2153}, {64.1466, -21.9426}} {
+		p := testutil.CreateTestPhoto(t, q, album.ID, "photo.webp")
+		locatedAt(t, q, p.ID, loc[0], loc[1])
+	}
+	testutil.CreateTestPhoto(t, q, album.ID, "nowhere.webp")
+
+	w, fc := getMapClusters(t, h.AdminMapClusters, "", "bbox=-180,-90,180,90&zoom=0")
+	if w.Code != http.StatusOK || w.Header().Get("Content-Type") != "application/geo+json" {
+		t.Fatalf("expected GeoJSON, got %d %q", w.Code, w.Header().Get("Content-Type"))
+	}
+	if fc.Type != "FeatureCollection" || clusterCounts(fc) != 4 || len(fc.Features) != 3 {
+		t.Errorf("expected the two Paris photos clustered at zoom 0, got %d clusters of %d photos", len(fc.Features), clusterCounts(fc))
+	}
+
+	// Zoomed in, only Paris is in view and the photos separate
+	_, fc = getMapClusters(t, h.AdminMapClusters, "", "bbox=2.2,48.8,2.4,48.9&zoom=14")
+	if len(fc.Features) != 2 || clusterCounts(fc) != 2 {
+		t.Errorf("expected two Paris photos in their own clusters, got %d clusters", len(fc.Features))
+	}
+	for _, f := range fc.Features {
+		if !strings.HasPrefix(f.Properties.PhotoURL, "/admin/photos/") {
+			t.Errorf("expected admin photo URLs, got %q", f.Properties.PhotoURL)
+		}
+	}
+
+	// A view panned across the antimeridian still finds Sydney
+	_, fc = getMapClusters(t, h.AdminMapClusters, "", "bbox=140,-40,200,-30&zoom=3")
+	if clusterCounts(fc) != 1 {
+		t.Errorf("expected Sydney across the antimeridian, got %d photos", clusterCounts(fc))
+	}
+
+	for _, query := range []string{"", "bbox=1,2,3&zoom=1", "bbox=10,0,0,10&zoom=1", "bbox=0,0,1,1&zoom=21", "bbox=a,0,1,1&zoom=1"} {
+		if w, _ := getMapClusters(t, h.AdminMapClusters, "", query); w.Code != http.StatusBadRequest {
+			t.Errorf("%q: expected 400, got %d", query, w.Code)
+		}
+	}
+}
+
+func TestShareMapClusters(t *testing.T) {
+	h, q, cleanup := setupTestHandlerForShare(t)
+	defer cleanup()
+	ctx := context.Background()
+
+	album := testutil.CreateTestAlbum(t, q, "Travels", "")
+	other := testutil.CreateTestAlbum(t, q, "Elsewhere", "")
+	p := testutil.CreateTestPhoto(t, q, album.ID, "paris.webp")
+	locatedAt(t, q, p.ID, 48.8584, 2.2945)
+	o := testutil.CreateTestPhoto(t, q, other.ID, "rome.webp")
+	locatedAt(t, q, o.ID, 41.8902, 12.4922)
+
+	hidden := testutil.CreateTestShareLink(t, q, album.ID, "no-map-token", 0, time.Time{})
+	shown, err := q.CreateShareLink(ctx, sqlc.CreateShareLinkParams{
+		Token:      "map-token",
+		TargetType: "album",
+		TargetID:   album.ID,
+		ShowMap:    true,
+	})
+	if err != nil {
+		t.Fatalf("CreateShareLink: %v", err)
+	}
+
+	if w, _ := getMapClusters(t, h.ShareMapClusters, hidden.Token, "bbox=-180,-90,180,90&zoom=0"); w.Code != http.StatusNotFound {
+		t.Errorf("expected 404 without the map, got %d", w.Code)
+	}
+
+	w, fc := getMapClusters(t, h.ShareMapClusters, shown.Token, "bbox=-180,-90,180,90&zoom=0")
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d", w.Code)
+	}
+	if len(fc.Features) != 1 || fc.Features[0].Properties.PhotoURL != "/s/map-token/photos/"+fmt.Sprint(p.ID)+".webp" {
+		t.Errorf("expected only the album's photo under the link, got %+v", fc.Features)
+	}
+	if views, _ := q.CountUniqueShareLinkViews(ctx, shown.ID); views != 0 {
+		t.Errorf("expected the map not to count views, got %d", views)
+	}
+
+	// The gallery lists the album's places
+	req := httptest.NewRequest("GET", "/s/map-token", nil)
+	rctx := chi.NewRouteContext()
+	rctx.URLParams.Add("token", shown.Token)
+	req = req.WithContext(context.WithValue(req.Context(), chi.RouteCtxKey, rctx))
+	rec := httptest.NewRecorder()
+	h.ViewShareLink(rec, req)
+	if body := rec.Body.String(); !strings.Contains(body, "Where these were taken") || !strings.Contains(body, "48.8584") {
+		t.Errorf("expected the album's places on the gallery")
+	}
+
+	if err := q.RevokeShareLink(ctx, shown.ID); err != nil {
+		t.Fatalf("RevokeShareLink: %v", err)
+	}
+	if w, _ := getMapClusters(t, h.ShareMapClusters, shown.Token, "bbox=-180,-90,180,90&zoom=0"); w.Code != http.StatusNotFound {
+		t.Errorf("expected 404 for a revoked link, got %d", w.Code)
+	}
+}
@@ -0,0 +1,185 @@
+package handler
+
+import (
+	"context"
+	"log"
+	"net/http"
+	"strconv"
+
+	"github.com/go-chi/chi/v5"
+
+	"familyshare/internal/db/sqlc"
+)
+
+// Album sort modes, stored in albums.sort_mode.
+const (
+	sortModeManual  = "manual"  // curated order (photos.position)
+	sortModeCapture = "capture" // EXIF capture date, oldest first
+	sortModeUpload  = "upload"  // upload date, newest first
+)
+
+// validSortMode reports whether mode is one of the album sort modes.
+func validSortMode(mode string) bool {
+	return mode == sortModeManual || mode == sortModeCapture || mode == sortModeUpload
+}
+
+// listAlbumPhotos returns a page of an album's photos in the album's sort mode.
+func listAlbumPhotos(ctx context.Context, q *sqlc.Queries, album sqlc.Album, limit, offset int64) ([]sqlc.Photo, error) {
+	switch album.SortMode {
+	case sortModeManual:
+		return q.ListPhotosByAlbumPosition(ctx, sqlc.ListPhotosByAlbumPositionParams{AlbumID: album.ID, Limit: limit, Offset: offset})
+	case sortModeCapture:
+		return q.ListPhotosByAlbumTakenAt(ctx, sqlc.ListPhotosByAlbumTakenAtParams{AlbumID: album.ID, Limit: limit, Offset: offset})
+	default:
+		return q.ListPhotosByAlbum(ctx, sqlc.ListPhotosByAlbumParams{AlbumID: album.ID, Limit: limit, Offset: offset})
+	}
+}
+
+// movePhoto returns ids with photoID moved to the 1-based position pos.
+// Positions past either end are clamped.
+func movePhoto(ids []int64, photoID int64, pos int) ([]int64, bool) {
+	from := -1
+	for i, id := range ids {
+		if id == photoID {
+			from = i
+			break
+		}
+	}
+	if from < 0 {
+		return nil, false
+	}
+
+	rest := make([]int64, 0, len(ids)-1)
+	rest = append(rest, ids[:from]...)
+	rest = append(rest, ids[from+1:]...)
+
+	to := pos - 1
+	if to < 0 {
+		to = 0
+	}
+	if to > len(rest) {
+		to = len(rest)
+	}
+
+	out := make([]int64, 0, len(ids))
+	out = append(out, rest[:to]...)
+	out = append(out, photoID)
+	out = append(out, rest[to:]...)
+	return out, true
+}
+
+// samePhotoSet reports whether order is a permutation of ids.
+func samePhotoSet(ids, order []int64) bool {
+	if len(ids) != len(order) {
+		return false
+	}
+	seen := make(map[int64]bool, len(ids))
+	for _, id := range ids {
+		seen[id] = true
+	}
+	for _, id := range order {
+		if !seen[id] {
+			return false
+		}
+		delete(seen, id)
+	}
+	return true
+}
+
+// ReorderAlbumPhotos handles POST /admin/albums/{id}/order
+// Accepts either the full ordered list (photo_ids, repeated) or a single move
+// (photo_id and 1-based position). Reordering switches the album to manual sort.
+func (h *Handler) ReorderAlbumPhotos(w http.ResponseWriter, r *http.Request) {
+	idStr := chi.URLParam(r, "id")
+	albumID, err := strconv.ParseInt(idStr, 10, 64)
+	if err != nil {
+		http.Error(w, "invalid id", http.StatusBadRequest)
+		return
+	}
+
+	if err := r.ParseForm(); err != nil {
+		http.Error(w, "invalid form", http.StatusBadRequest)
+		return
+	}
+
+	tx, err := h.db.BeginTx(r.Context(), nil)
+	if err != nil {
+		log.Printf("failed to begin reorder transaction: %v", err)
+		http.Error(w, "failed to reorder photos", http.StatusInternalServerError)
+		return
+	}
+	defer tx.Rollback()
+
+	q := sqlc.New(tx)
+
+	if _, err := q.GetAlbum(r.Context(), albumID); err != nil {
+		http.Error(w, "album not found", http.StatusNotFound)
+		return
+	}
+
+	current, err := q.ListPhotoIDsByAlbumPosition(r.Context(), albumID)
+	if err != nil {
+		log.Printf("failed to list photo order for album %d: %v", albumID, err)
+		http.Error(w, "failed to reorder photos", http.StatusInternalServerError)
+		return
+	}
+
+	var order []int64
+	if ids := r.PostForm["photo_ids"]; len(ids) > 0 {
+		for _, s := range ids {
+			id, err := strconv.ParseInt(s, 10, 64)
+			if err != nil {
+				http.Error(w, "invalid photo id", http.StatusBadRequest)
+				return
+			}
+			order = append(order, id)
+		}
+		if !samePhotoSet(current, order) {
+			http.Error(w, "photo_ids must list every photo in the album exactly once", http.StatusBadRequest)
+			return
+		}
+	} else {
+		photoID, err := strconv.ParseInt(r.PostFormValue("photo_id"), 10, 64)
+		if err != nil {
+			http.Error(w, "photo_ids or photo_id is required", http.StatusBadRequest)
+			return
+		}
+		pos, err := strconv.Atoi(r.PostFormValue("position"))
+		if err != nil {
+			http.Error(w, "invalid position", http.StatusBadRequest)
+			return
+		}
+		var ok bool
+		order, ok = movePhoto(current, photoID, pos)
+		if !ok {
+			http.Error(w, "photo not found in album", http.StatusNotFound)
+			return
+		}
+	}
+
+	for i, id := range order {
+		if err := q.UpdatePhotoPosition(r.Context(), sqlc.UpdatePhotoPositionParams{
+			Position: int64(i + 1),
+			ID:       id,
+			AlbumID:  albumID,
+		}); err != nil {
+			log.Printf("failed to update position of photo %d: %v", id, err)
+			http.Error(w, "failed to reorder photos", http.StatusInternalServerError)
+			return
+		}
+	}
+
+	if err := q.SetAlbumSortMode(r.Context(), sqlc.SetAlbumSortModeParams{SortMode: sortModeManual, ID: albumID}); err != nil {
+		log.Printf("failed to set manual sort for album %d: %v", albumID, err)
+		http.Error(w, "failed to reorder photos", http.StatusInternalServerError)
+		return
+	}
+
+	if err := tx.Commit(); err != nil {
+		log.Printf("failed to commit reorder transaction: %v", err)
+		http.Error(w, "failed to reorder photos", http.StatusInternalServerError)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
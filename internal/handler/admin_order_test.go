@@ -0,0 +1,219 @@
+package handler_test
+
+import (
+	"context"
+	"database/sql"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+
+	"familyshare/internal/db/sqlc"
+	"familyshare/internal/handler"
+	"familyshare/internal/testutil"
+)
+
+func postOrder(h *handler.Handler, albumID int64, vals url.Values) *httptest.ResponseRecorder {
+	req := httptest.NewRequest("POST", "/admin/albums/1/order", strings.NewReader(vals.Encode()))
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	rctx := chi.NewRouteContext()
+	rctx.URLParams.Add("id", strconv.FormatInt(albumID, 10))
+	req = req.WithContext(context.WithValue(req.Context(), chi.RouteCtxKey, rctx))
+	w := httptest.NewRecorder()
+	h.ReorderAlbumPhotos(w, req)
+	return w
+}
+
+func photoOrder(t *testing.T, q *sqlc.Queries, albumID int64) []int64 {
+	t.Helper()
+	ids, err := q.ListPhotoIDsByAlbumPosition(context.Background(), albumID)
+	if err != nil {
+		t.Fatalf("ListPhotoIDsByAlbumPosition: %v", err)
+	}
+	return ids
+}
+
+func equalIDs(a, b []int64) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
+
+func TestReorderAlbumPhotos(t *testing.T) {
+	h, q := setupCommentTest(t)
+	ctx := context.Background()
+
+	album := testutil.CreateTestAlbum(t, q, "Trip", "")
+	p1 := testutil.CreateTestPhoto(t, q, album.ID, "1.webp")
+	p2 := testutil.CreateTestPhoto(t, q, album.ID, "2.webp")
+	p3 := testutil.CreateTestPhoto(t, q, album.ID, "3.webp")
+
+	// New photos are appended in upload order
+	if got := photoOrder(t, q, album.ID); !equalIDs(got, []int64{p1.ID, p2.ID, p3.ID}) {
+		t.Fatalf("expected upload order, got %v", got)
+	}
+
+	// Full list
+	vals := url.Values{}
+	for _, id := range []int64{p3.ID, p1.ID, p2.ID} {
+		vals.Add("photo_ids", strconv.FormatInt(id, 10))
+	}
+	if w := postOrder(h, album.ID, vals); w.Code != http.StatusNoContent {
+		t.Fatalf("expected 204, got %d: %s", w.Code, w.Body.String())
+	}
+	if got := photoOrder(t, q, album.ID); !equalIDs(got, []int64{p3.ID, p1.ID, p2.ID}) {
+		t.Fatalf("expected full list order applied, got %v", got)
+	}
+
+	updated, _ := q.GetAlbum(ctx, album.ID)
+	if updated.SortMode != "manual" {
+		t.Errorf("expected reorder to switch album to manual sort, got %q", updated.SortMode)
+	}
+
+	// Move operation: p2 to the front
+	vals = url.Values{"photo_id": {strconv.FormatInt(p2.ID, 10)}, "position": {"1"}}
+	if w := postOrder(h, album.ID, vals); w.Code != http.StatusNoContent {
+		t.Fatalf("expected 204, got %d: %s", w.Code, w.Body.String())
+	}
+	if got := photoOrder(t, q, album.ID); !equalIDs(got, []int64{p2.ID, p3.ID, p1.ID}) {
+		t.Fatalf("expected p2 moved to front, got %v", got)
+	}
+
+	// Positions past the end are clamped
+	vals = url.Values{"photo_id": {strconv.FormatInt(p2.ID, 10)}, "position": {"99"}}
+	postOrder(h, album.ID, vals)
+	if got := photoOrder(t, q, album.ID); !equalIDs(got, []int64{p3.ID, p1.ID, p2.ID}) {
+		t.Fatalf("expected p2 moved to end, got %v", got)
+	}
+
+	// A later upload still lands at the end
+	p4 := testutil.CreateTestPhoto(t, q, album.ID, "4.webp")
+	if got := photoOrder(t, q, album.ID); got[len(got)-1] != p4.ID {
+		t.Fatalf("expected new photo appended, got %v", got)
+	}
+}
+
+func TestReorderAlbumPhotos_Invalid(t *testing.T) {
+	h, q := setupCommentTest(t)
+
+	album := testutil.CreateTestAlbum(t, q, "Trip", "")
+	p1 := testutil.CreateTestPhoto(t, q, album.ID, "1.webp")
+	p2 := testutil.CreateTestPhoto(t, q, album.ID, "2.webp")
+	other := testutil.CreateTestAlbum(t, q, "Other", "")
+	foreign := testutil.CreateTestPhoto(t, q, other.ID, "x.webp")
+
+	id := func(p *sqlc.Photo) string { return strconv.FormatInt(p.ID, 10) }
+
+	tests := []struct {
+		name    string
+		albumID int64
+		vals    url.Values
+		want    int
+	}{
+		{"partial list", album.ID, url.Values{"photo_ids": {id(p1)}}, http.StatusBadRequest},
+		{"duplicate in list", album.ID, url.Values{"photo_ids": {id(p1), id(p1)}}, http.StatusBadRequest},
+		{"foreign photo in list", album.ID, url.Values{"photo_ids": {id(p1), id(foreign)}}, http.StatusBadRequest},
+		{"move foreign photo", album.ID, url.Values{"photo_id": {id(foreign)}, "position": {"1"}}, http.StatusNotFound},
+		{"move without position", album.ID, url.Values{"photo_id": {id(p2)}}, http.StatusBadRequest},
+		{"nothing to do", album.ID, url.Values{}, http.StatusBadRequest},
+		{"unknown album", 9999, url.Values{"photo_id": {id(p2)}, "position": {"1"}}, http.StatusNotFound},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if w := postOrder(h, tt.albumID, tt.vals); w.Code != tt.want {
+				t.Errorf("expected %d, got %d: %s", tt.want, w.Code, w.Body.String())
+			}
+		})
+	}
+
+	if got := photoOrder(t, q, album.ID); !equalIDs(got, []int64{p1.ID, p2.ID}) {
+		t.Fatalf("expected order untouched after rejected requests, got %v", got)
+	}
+}
+
+func TestShareAlbum_HonorsSortMode(t *testing.T) {
+	h, q := setupCommentTest(t)
+	ctx := context.Background()
+
+	album := testutil.CreateTestAlbum(t, q, "Trip", "")
+	testutil.CreateTestPhoto(t, q, album.ID, "first-upload.webp")
+	second := testutil.CreateTestPhoto(t, q, album.ID, "second-upload.webp")
+	// The later upload was taken earlier
+	if err := q.UpdatePhotoTakenAt(ctx, sqlc.UpdatePhotoTakenAtParams{
+		TakenAt: sql.NullTime{Time: time.Date(2001, 1, 1, 0, 0, 0, 0, time.UTC), Valid: true},
+		ID:      second.ID,
+	}); err != nil {
+		t.Fatalf("UpdatePhotoTakenAt: %v", err)
+	}
+	if _, err := q.CreateShareLink(ctx, sqlc.CreateShareLinkParams{Token: "order-token", TargetType: "album", TargetID: album.ID}); err != nil {
+		t.Fatalf("CreateShareLink: %v", err)
+	}
+
+	order := func() (firstIdx, secondIdx int) {
+		req := httptest.NewRequest("GET", "/s/order-token", nil)
+		rctx := chi.NewRouteContext()
+		rctx.URLParams.Add("token", "order-token")
+		req = req.WithContext(context.WithValue(req.Context(), chi.RouteCtxKey, rctx))
+		w := httptest.NewRecorder()
+		h.ViewShareLink(w, req)
+		body := w.Body.String()
+		return strings.Index(body, "first-upload.webp"), strings.Index(body, "second-upload.webp")
+	}
+
+	tests := []struct {
+		mode        string
+		firstBefore bool
+	}{
+		{"capture", false},
+		{"manual", true}, // positions follow upload order
+	}
+	for _, tt := range tests {
+		if err := q.SetAlbumSortMode(ctx, sqlc.SetAlbumSortModeParams{SortMode: tt.mode, ID: album.ID}); err != nil {
+			t.Fatalf("SetAlbumSortMode: %v", err)
+		}
+		a, b := order()
+		if a < 0 || b < 0 {
+			t.Fatalf("%s: expected both photos on the page", tt.mode)
+		}
+		if (a < b) != tt.firstBefore {
+			t.Errorf("%s: unexpected order (first at %d, second at %d)", tt.mode, a, b)
+		}
+	}
+}
+
+func TestUpdateAlbum_SortMode(t *testing.T) {
+	h, q := setupCommentTest(t)
+	album := testutil.CreateTestAlbum(t, q, "Trip", "")
+
+	update := func(mode string) int {
+		vals := url.Values{"title": {"Trip"}, "sort_mode": {mode}}
+		req := httptest.NewRequest("POST", "/admin/albums/1", strings.NewReader(vals.Encode()))
+		req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+		rctx := chi.NewRouteContext()
+		rctx.URLParams.Add("id", strconv.FormatInt(album.ID, 10))
+		req = req.WithContext(context.WithValue(req.Context(), chi.RouteCtxKey, rctx))
+		w := httptest.NewRecorder()
+		h.UpdateAlbum(w, req)
+		return w.Code
+	}
+
+	if code := update("sideways"); code != http.StatusBadRequest {
+		t.Fatalf("expected 400 for unknown sort mode, got %d", code)
+	}
+	update("capture")
+	got, _ := q.GetAlbum(context.Background(), album.ID)
+	if got.SortMode != "capture" {
+		t.Fatalf("expected capture sort mode, got %q", got.SortMode)
+	}
+}
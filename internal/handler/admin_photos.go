@@ -4,17 +4,16 @@ import (
 	"database/sql"
 	"log"
 	"net/http"
-	"os"
 	"strconv"
-	"time"
+	"strings"
 
 	"github.com/go-chi/chi/v5"
 
 	"familyshare/internal/db/sqlc"
-	"familyshare/internal/storage"
 )
 
 // DeletePhoto handles DELETE /admin/photos/{id}
+// The photo is moved to the trash; its file stays until the trash is purged.
 func (h *Handler) DeletePhoto(w http.ResponseWriter, r *http.Request) {
 	idstr := chi.URLParam(r, "id")
 	id, err := strconv.ParseInt(idstr, 10, 64)
@@ -25,9 +24,7 @@ func (h *Handler) DeletePhoto(w http.ResponseWriter, r *http.Request) {
 
 	q := sqlc.New(h.db)
 
-	// Get photo details before deleting
-	photo, err := q.GetPhoto(r.Context(), id)
-	if err != nil {
+	if _, err := q.GetPhoto(r.Context(), id); err != nil {
 		http.Error(w, "photo not found", http.StatusNotFound)
 		return
 	}
@@ -38,23 +35,64 @@ func (h *Handler) DeletePhoto(w http.ResponseWriter, r *http.Request) {
 		// Continue with deletion even if this fails
 	}
 
-	// Delete photo from database
-	if err := q.DeletePhoto(r.Context(), id); err != nil {
-		log.Printf("failed to delete photo from database: %v", err)
+	if err := q.TrashPhoto(r.Context(), id); err != nil {
+		log.Printf("failed to move photo %d to trash: %v", id, err)
 		http.Error(w, "failed to delete photo", http.StatusInternalServerError)
 		return
 	}
 
-	// Delete photo file from disk using the hierarchical path
-	createdAt := time.Now().UTC()
-	if photo.CreatedAt.Valid {
-		createdAt = photo.CreatedAt.Time.UTC()
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// maxCaptionLength bounds captions so they fit under a thumbnail.
+const maxCaptionLength = 500
+
+// UpdatePhotoCaption handles POST /admin/photos/{id}/caption
+// An empty caption clears it. Responds with the refreshed photo card.
+func (h *Handler) UpdatePhotoCaption(w http.ResponseWriter, r *http.Request) {
+	idStr := chi.URLParam(r, "id")
+	id, err := strconv.ParseInt(idStr, 10, 64)
+	if err != nil {
+		http.Error(w, "invalid id", http.StatusBadRequest)
+		return
 	}
-	photoPath := storage.PhotoPathAt(h.storage.BaseDir, photo.AlbumID, id, photo.Format, createdAt)
-	if err := os.Remove(photoPath); err != nil {
-		log.Printf("failed to delete photo file %s: %v", photoPath, err)
-		// Don't return error - photo already deleted from DB
+
+	if err := r.ParseForm(); err != nil {
+		http.Error(w, "invalid form", http.StatusBadRequest)
+		return
 	}
 
-	w.WriteHeader(http.StatusNoContent)
+	caption := strings.TrimSpace(r.PostFormValue("caption"))
+	if len([]rune(caption)) > maxCaptionLength {
+		http.Error(w, "caption is too long", http.StatusBadRequest)
+		return
+	}
+
+	q := sqlc.New(h.db)
+
+	if _, err := q.GetPhoto(r.Context(), id); err != nil {
+		http.Error(w, "photo not found", http.StatusNotFound)
+		return
+	}
+
+	if err := q.UpdatePhotoCaption(r.Context(), sqlc.UpdatePhotoCaptionParams{
+		Caption: sql.NullString{String: caption, Valid: caption != ""},
+		ID:      id,
+	}); err != nil {
+		log.Printf("failed to update caption for photo %d: %v", id, err)
+		http.Error(w, "failed to update caption", http.StatusInternalServerError)
+		return
+	}
+
+	photo, err := q.GetPhoto(r.Context(), id)
+	if err != nil {
+		log.Printf("failed to reload photo %d: %v", id, err)
+		http.Error(w, "failed to update caption", http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	if err := h.RenderTemplate(w, "photo_card", singlePhotoCard(r.Context(), q, photo)); err != nil {
+		log.Printf("template render error for photo_card: %v", err)
+		http.Error(w, "template render error", http.StatusInternalServerError)
+	}
 }
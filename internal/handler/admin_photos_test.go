@@ -21,7 +21,7 @@ import (
 	"familyshare/web"
 )
 
-func TestDeletePhoto_RemovesFileAndDBRecord(t *testing.T) {
+func TestDeletePhoto_MovesToTrash(t *testing.T) {
 	dbConn, err := db.InitDB(":memory:")
 	if err != nil {
 		t.Fatalf("InitDB: %v", err)
@@ -87,14 +87,18 @@ func TestDeletePhoto_RemovesFileAndDBRecord(t *testing.T) {
 		t.Fatalf("expected status 204, got %d", w.Code)
 	}
 
-	// Verify photo removed from DB
+	// Verify photo hidden but kept in the trash
 	if _, err := q.GetPhoto(context.Background(), photo.ID); err == nil {
-		t.Fatalf("expected photo to be deleted from DB")
+		t.Fatalf("expected photo to be hidden after delete")
+	}
+	trashed, err := q.GetPhotoIncludingDeleted(context.Background(), photo.ID)
+	if err != nil || !trashed.DeletedAt.Valid {
+		t.Fatalf("expected photo in the trash, got %+v: %v", trashed, err)
 	}
 
-	// Verify file removed from disk
-	if _, err := os.Stat(photoPath); !os.IsNotExist(err) {
-		t.Fatalf("expected photo file to be deleted, but it still exists")
+	// The file stays until the trash is purged
+	if _, err := os.Stat(photoPath); err != nil {
+		t.Fatalf("expected photo file kept while in the trash: %v", err)
 	}
 }
 
@@ -0,0 +1,277 @@
+package handler
+
+import (
+	"database/sql"
+	"errors"
+	"log"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/go-chi/chi/v5"
+
+	"familyshare/internal/db/sqlc"
+	"familyshare/internal/pipeline"
+)
+
+// profileCard is a processing profile as shown on the profiles page.
+type profileCard struct {
+	sqlc.ProcessingProfile
+	Albums int64
+	Form   profileForm
+}
+
+// profileForm prefills the fields of a profile form. Prefix keeps the input
+// ids of the forms on the page apart.
+type profileForm struct {
+	Prefix        string
+	Name          string
+	MaxDimension  int64
+	Format        string
+	Quality       int64
+	AvifSpeed     int64
+	KeepOriginals bool
+	StripMetadata bool
+}
+
+func newProfileForm(prefix string, p sqlc.ProcessingProfile) profileForm {
+	return profileForm{
+		Prefix:        prefix,
+		Name:          p.Name,
+		MaxDimension:  p.MaxDimension,
+		Format:        p.Format,
+		Quality:       p.Quality,
+		AvifSpeed:     p.AvifSpeed,
+		KeepOriginals: p.KeepOriginals,
+		StripMetadata: p.StripMetadata,
+	}
+}
+
+// parseProfileForm reads and validates the fields of a processing profile form.
+func parseProfileForm(r *http.Request) (sqlc.CreateProcessingProfileParams, error) {
+	var p sqlc.CreateProcessingProfileParams
+	if err := r.ParseForm(); err != nil {
+		return p, errors.New("invalid form")
+	}
+
+	p.Name = strings.TrimSpace(r.PostFormValue("name"))
+	if p.Name == "" {
+		return p, errors.New("name required")
+	}
+
+	maxDimension, err := strconv.ParseInt(r.PostFormValue("max_dimension"), 10, 64)
+	if err != nil || maxDimension < 320 || maxDimension > pipeline.MaxDimension {
+		return p, errors.New("max dimension must be between 320 and 10000 pixels")
+	}
+	p.MaxDimension = maxDimension
+
+	p.Format = r.PostFormValue("format")
+	if p.Format != "webp" && p.Format != "avif" {
+		return p, errors.New("format must be webp or avif")
+	}
+
+	quality, err := strconv.ParseInt(r.PostFormValue("quality"), 10, 64)
+	if err != nil || quality < 1 || quality > 100 {
+		return p, errors.New("quality must be between 1 and 100")
+	}
+	p.Quality = quality
+
+	p.AvifSpeed = pipeline.DefaultAVIFSpeed
+	if v := r.PostFormValue("avif_speed"); v != "" {
+		speed, err := strconv.ParseInt(v, 10, 64)
+		if err != nil || speed < 0 || speed > 10 {
+			return p, errors.New("AVIF speed must be between 0 and 10")
+		}
+		p.AvifSpeed = speed
+	}
+
+	p.KeepOriginals = r.PostFormValue("keep_originals") != ""
+	p.StripMetadata = r.PostFormValue("strip_metadata") != ""
+	return p, nil
+}
+
+// isUniqueViolation reports whether err comes from a UNIQUE constraint.
+func isUniqueViolation(err error) bool {
+	return err != nil && strings.Contains(err.Error(), "UNIQUE constraint failed")
+}
+
+// ListProfiles handles GET /admin/profiles
+func (h *Handler) ListProfiles(w http.ResponseWriter, r *http.Request) {
+	q := sqlc.New(h.db)
+
+	profiles, err := q.ListProcessingProfiles(r.Context())
+	if err != nil {
+		log.Printf("failed to list processing profiles: %v", err)
+		http.Error(w, "failed to load profiles", http.StatusInternalServerError)
+		return
+	}
+	counts, err := q.CountAlbumsByProcessingProfile(r.Context())
+	if err != nil {
+		log.Printf("failed to count albums by profile: %v", err)
+	}
+	albums := make(map[int64]int64, len(counts))
+	for _, c := range counts {
+		albums[c.ProcessingProfileID.Int64] = c.AlbumCount
+	}
+
+	cards := make([]profileCard, 0, len(profiles))
+	for _, p := range profiles {
+		cards = append(cards, profileCard{
+			ProcessingProfile: p,
+			Albums:            albums[p.ID],
+			Form:              newProfileForm("profile-"+strconv.FormatInt(p.ID, 10), p),
+		})
+	}
+
+	albumList, err := q.ListAlbums(r.Context(), sqlc.ListAlbumsParams{Limit: 1000, Offset: 0})
+	if err != nil {
+		log.Printf("failed to list albums: %v", err)
+	}
+	progress, err := h.reprocessProgress(r)
+	if err != nil {
+		log.Printf("failed to load reprocess progress: %v", err)
+	}
+
+	def := pipeline.DefaultProfile(h.imageFormat())
+	data := struct {
+		Profiles  []profileCard
+		Default   pipeline.Profile
+		New       profileForm
+		Albums    []sqlc.Album
+		Reprocess reprocessProgress
+	}{
+		Profiles:  cards,
+		Default:   def,
+		Albums:    albumList,
+		Reprocess: progress,
+		New: newProfileForm("new-profile", sqlc.ProcessingProfile{
+			MaxDimension:  int64(def.MaxDimension),
+			Format:        def.Format,
+			Quality:       int64(def.Quality),
+			AvifSpeed:     int64(def.AVIFSpeed),
+			StripMetadata: def.StripMetadata,
+		}),
+	}
+
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	if err := h.RenderTemplate(w, "profiles.html", data); err != nil {
+		log.Printf("template render error for profiles: %v", err)
+		http.Error(w, "template render error", http.StatusInternalServerError)
+	}
+}
+
+// CreateProfile handles POST /admin/profiles
+func (h *Handler) CreateProfile(w http.ResponseWriter, r *http.Request) {
+	params, err := parseProfileForm(r)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	q := sqlc.New(h.db)
+	if _, err := q.CreateProcessingProfile(r.Context(), params); err != nil {
+		if isUniqueViolation(err) {
+			http.Error(w, "a profile with this name already exists", http.StatusConflict)
+			return
+		}
+		log.Printf("failed to create processing profile: %v", err)
+		http.Error(w, "failed to create profile", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("HX-Refresh", "true")
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// UpdateProfile handles POST /admin/profiles/{id}
+// Albums using the profile pick up the change with their next upload.
+func (h *Handler) UpdateProfile(w http.ResponseWriter, r *http.Request) {
+	idStr := chi.URLParam(r, "id")
+	id, err := strconv.ParseInt(idStr, 10, 64)
+	if err != nil {
+		http.Error(w, "invalid id", http.StatusBadRequest)
+		return
+	}
+	params, err := parseProfileForm(r)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	q := sqlc.New(h.db)
+	if _, err := q.GetProcessingProfile(r.Context(), id); err != nil {
+		http.Error(w, "profile not found", http.StatusNotFound)
+		return
+	}
+
+	err = q.UpdateProcessingProfile(r.Context(), sqlc.UpdateProcessingProfileParams{
+		Name:          params.Name,
+		MaxDimension:  params.MaxDimension,
+		Format:        params.Format,
+		Quality:       params.Quality,
+		AvifSpeed:     params.AvifSpeed,
+		KeepOriginals: params.KeepOriginals,
+		StripMetadata: params.StripMetadata,
+		ID:            id,
+	})
+	if err != nil {
+		if isUniqueViolation(err) {
+			http.Error(w, "a profile with this name already exists", http.StatusConflict)
+			return
+		}
+		log.Printf("failed to update processing profile %d: %v", id, err)
+		http.Error(w, "failed to update profile", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("HX-Refresh", "true")
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// DeleteProfile handles DELETE /admin/profiles/{id}
+// Albums using the profile fall back to the default settings.
+func (h *Handler) DeleteProfile(w http.ResponseWriter, r *http.Request) {
+	idStr := chi.URLParam(r, "id")
+	id, err := strconv.ParseInt(idStr, 10, 64)
+	if err != nil {
+		http.Error(w, "invalid id", http.StatusBadRequest)
+		return
+	}
+
+	q := sqlc.New(h.db)
+	if _, err := q.GetProcessingProfile(r.Context(), id); err != nil {
+		http.Error(w, "profile not found", http.StatusNotFound)
+		return
+	}
+
+	// Albums are detached and the profile deleted together, so a failure
+	// leaves both as they were
+	tx, err := h.db.BeginTx(r.Context(), nil)
+	if err != nil {
+		log.Printf("failed to begin profile transaction: %v", err)
+		http.Error(w, "failed to delete profile", http.StatusInternalServerError)
+		return
+	}
+	defer tx.Rollback()
+	q = sqlc.New(tx)
+
+	profileID := sql.NullInt64{Int64: id, Valid: true}
+	if err := q.ClearAlbumProcessingProfile(r.Context(), profileID); err != nil {
+		log.Printf("failed to detach processing profile %d from albums: %v", id, err)
+		http.Error(w, "failed to delete profile", http.StatusInternalServerError)
+		return
+	}
+	if err := q.DeleteProcessingProfile(r.Context(), id); err != nil {
+		log.Printf("failed to delete processing profile %d: %v", id, err)
+		http.Error(w, "failed to delete profile", http.StatusInternalServerError)
+		return
+	}
+	if err := tx.Commit(); err != nil {
+		log.Printf("failed to commit profile deletion %d: %v", id, err)
+		http.Error(w, "failed to delete profile", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("HX-Refresh", "true")
+	w.WriteHeader(http.StatusNoContent)
+}
@@ -0,0 +1,157 @@
+package handler_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/go-chi/chi/v5"
+
+	"familyshare/internal/db/sqlc"
+	"familyshare/internal/testutil"
+)
+
+func postIDForm(handle http.HandlerFunc, id int64, vals url.Values) *httptest.ResponseRecorder {
+	req := httptest.NewRequest("POST", "/admin", strings.NewReader(vals.Encode()))
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	rctx := chi.NewRouteContext()
+	rctx.URLParams.Add("id", strconv.FormatInt(id, 10))
+	req = req.WithContext(context.WithValue(req.Context(), chi.RouteCtxKey, rctx))
+	w := httptest.NewRecorder()
+	handle(w, req)
+	return w
+}
+
+func profileValues(name string) url.Values {
+	return url.Values{
+		"name":           {name},
+		"max_dimension":  {"2048"},
+		"format":         {"avif"},
+		"quality":        {"55"},
+		"avif_speed":     {"4"},
+		"keep_originals": {"on"},
+	}
+}
+
+func findProfile(t *testing.T, q *sqlc.Queries, name string) sqlc.ProcessingProfile {
+	t.Helper()
+	profiles, err := q.ListProcessingProfiles(context.Background())
+	if err != nil {
+		t.Fatalf("ListProcessingProfiles: %v", err)
+	}
+	for _, p := range profiles {
+		if p.Name == name {
+			return p
+		}
+	}
+	t.Fatalf("profile %q not found", name)
+	return sqlc.ProcessingProfile{}
+}
+
+func TestProfiles_CreateAndUpdate(t *testing.T) {
+	h, q, _ := setupBulkTest(t)
+
+	w := postBulk(h.CreateProfile, "/admin/profiles", profileValues("Archive"))
+	if w.Code != http.StatusNoContent {
+		t.Fatalf("expected 204, got %d: %s", w.Code, w.Body.String())
+	}
+	p := findProfile(t, q, "Archive")
+	if p.MaxDimension != 2048 || p.Format != "avif" || p.Quality != 55 || p.AvifSpeed != 4 || !p.KeepOriginals || p.StripMetadata {
+		t.Errorf("unexpected profile %+v", p)
+	}
+
+	// Names are unique
+	if w := postBulk(h.CreateProfile, "/admin/profiles", profileValues("Archive")); w.Code != http.StatusConflict {
+		t.Errorf("expected 409 for duplicate name, got %d", w.Code)
+	}
+
+	invalid := profileValues("Tiny")
+	invalid.Set("max_dimension", "100")
+	if w := postBulk(h.CreateProfile, "/admin/profiles", invalid); w.Code != http.StatusBadRequest {
+		t.Errorf("expected 400 for invalid size, got %d", w.Code)
+	}
+	invalid = profileValues("Gif")
+	invalid.Set("format", "gif")
+	if w := postBulk(h.CreateProfile, "/admin/profiles", invalid); w.Code != http.StatusBadRequest {
+		t.Errorf("expected 400 for invalid format, got %d", w.Code)
+	}
+
+	update := profileValues("Archive")
+	update.Set("format", "webp")
+	update.Set("quality", "95")
+	update.Del("keep_originals")
+	update.Set("strip_metadata", "on")
+	if w := postIDForm(h.UpdateProfile, p.ID, update); w.Code != http.StatusNoContent {
+		t.Fatalf("expected 204, got %d: %s", w.Code, w.Body.String())
+	}
+	p = findProfile(t, q, "Archive")
+	if p.Format != "webp" || p.Quality != 95 || p.KeepOriginals || !p.StripMetadata {
+		t.Errorf("unexpected updated profile %+v", p)
+	}
+
+	// Renaming onto a seeded profile conflicts
+	if w := postIDForm(h.UpdateProfile, p.ID, profileValues("Compact")); w.Code != http.StatusConflict {
+		t.Errorf("expected 409 renaming onto an existing profile, got %d", w.Code)
+	}
+	if w := postIDForm(h.UpdateProfile, 9999, profileValues("Missing")); w.Code != http.StatusNotFound {
+		t.Errorf("expected 404 for unknown profile, got %d", w.Code)
+	}
+}
+
+func TestProfiles_AlbumSelectionAndDelete(t *testing.T) {
+	h, q, _ := setupBulkTest(t)
+	ctx := context.Background()
+
+	album := testutil.CreateTestAlbum(t, q, "Trip", "")
+	compact := findProfile(t, q, "Compact")
+
+	vals := url.Values{"title": {"Trip"}, "processing_profile_id": {strconv.FormatInt(compact.ID, 10)}}
+	if w := postIDForm(h.UpdateAlbum, album.ID, vals); w.Code >= 400 {
+		t.Fatalf("update album failed: %d %s", w.Code, w.Body.String())
+	}
+	got, err := q.GetAlbum(ctx, album.ID)
+	if err != nil {
+		t.Fatalf("GetAlbum: %v", err)
+	}
+	if got.ProcessingProfileID.Int64 != compact.ID {
+		t.Fatalf("expected album to use profile %d, got %v", compact.ID, got.ProcessingProfileID)
+	}
+
+	vals.Set("processing_profile_id", "9999")
+	if w := postIDForm(h.UpdateAlbum, album.ID, vals); w.Code != http.StatusBadRequest {
+		t.Errorf("expected 400 for unknown profile, got %d", w.Code)
+	}
+
+	// The profiles page lists the profile with its album count
+	req := httptest.NewRequest("GET", "/admin/profiles", nil)
+	w := httptest.NewRecorder()
+	h.ListProfiles(w, req)
+	if w.Code != http.StatusOK || !strings.Contains(w.Body.String(), "used by 1 album") {
+		t.Errorf("expected profile listed with one album, got %d", w.Code)
+	}
+
+	// Deleting the profile sends the album back to the defaults
+	if w := idRequest(h.DeleteProfile, "DELETE", compact.ID); w.Code != http.StatusNoContent {
+		t.Fatalf("expected 204, got %d: %s", w.Code, w.Body.String())
+	}
+	if got, _ := q.GetAlbum(ctx, album.ID); got.ProcessingProfileID.Valid {
+		t.Errorf("expected album detached from deleted profile")
+	}
+	if _, err := q.GetProcessingProfile(ctx, compact.ID); err == nil {
+		t.Errorf("expected profile deleted")
+	}
+
+	// An empty selection clears the profile
+	high := findProfile(t, q, "High quality")
+	vals.Set("processing_profile_id", strconv.FormatInt(high.ID, 10))
+	postIDForm(h.UpdateAlbum, album.ID, vals)
+	vals.Set("processing_profile_id", "")
+	postIDForm(h.UpdateAlbum, album.ID, vals)
+	if got, _ := q.GetAlbum(ctx, album.ID); got.ProcessingProfileID.Valid {
+		t.Errorf("expected empty selection to clear the profile")
+	}
+}
@@ -0,0 +1,118 @@
+package handler
+
+import (
+	"log"
+	"net/http"
+	"strconv"
+
+	"familyshare/internal/db/sqlc"
+	"familyshare/internal/worker"
+)
+
+// reprocessProgress is the state of the reprocess jobs of the current run.
+type reprocessProgress struct {
+	Pending    int64
+	Processing int64
+	Failed     int64
+	Completed  int64
+	// Requested is set when rendered for a reprocess request, which queued
+	// Queued photos.
+	Requested bool
+	Queued    int
+}
+
+// Total is the number of photos in the run.
+func (p reprocessProgress) Total() int64 {
+	return p.Pending + p.Processing + p.Failed + p.Completed
+}
+
+// Active reports whether photos are still waiting or being reprocessed.
+func (p reprocessProgress) Active() bool {
+	return p.Pending+p.Processing > 0
+}
+
+// Percent is the share of the run that is finished.
+func (p reprocessProgress) Percent() int64 {
+	if p.Total() == 0 {
+		return 0
+	}
+	return (p.Completed + p.Failed) * 100 / p.Total()
+}
+
+func (h *Handler) reprocessProgress(r *http.Request) (reprocessProgress, error) {
+	s, err := sqlc.New(h.db).GetReprocessStatus(r.Context(), 0)
+	if err != nil {
+		return reprocessProgress{}, err
+	}
+	return reprocessProgress{
+		Pending:    s.PendingCount,
+		Processing: s.ProcessingCount,
+		Failed:     s.FailedCount,
+		Completed:  s.CompletedCount,
+	}, nil
+}
+
+func (h *Handler) renderReprocessStatus(w http.ResponseWriter, p reprocessProgress) {
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	if err := h.RenderTemplate(w, "reprocess_status", p); err != nil {
+		log.Printf("template render error for reprocess status: %v", err)
+		http.Error(w, "template render error", http.StatusInternalServerError)
+	}
+}
+
+// ReprocessPhotos handles POST /admin/reprocess
+// Form fields: album_id and format, both optional. Queues the matching photos
+// to be re-encoded with the current settings of their album.
+func (h *Handler) ReprocessPhotos(w http.ResponseWriter, r *http.Request) {
+	if err := r.ParseForm(); err != nil {
+		http.Error(w, "invalid form", http.StatusBadRequest)
+		return
+	}
+
+	var filter worker.ReprocessFilter
+	if v := r.PostFormValue("album_id"); v != "" {
+		id, err := strconv.ParseInt(v, 10, 64)
+		if err != nil {
+			http.Error(w, "invalid album_id", http.StatusBadRequest)
+			return
+		}
+		filter.AlbumID = id
+	}
+	filter.Format = r.PostFormValue("format")
+	if filter.Format != "" && filter.Format != "webp" && filter.Format != "avif" {
+		http.Error(w, "format must be webp or avif", http.StatusBadRequest)
+		return
+	}
+
+	jobs, err := worker.EnqueueReprocess(r.Context(), h.db, filter)
+	if err != nil {
+		log.Printf("failed to queue photos for reprocessing: %v", err)
+		http.Error(w, "failed to queue photos", http.StatusInternalServerError)
+		return
+	}
+	for _, job := range jobs {
+		h.publishJob(job, "pending")
+	}
+	if h.worker != nil && len(jobs) > 0 {
+		h.worker.TriggerSignal()
+	}
+
+	progress, err := h.reprocessProgress(r)
+	if err != nil {
+		log.Printf("failed to load reprocess progress: %v", err)
+	}
+	progress.Requested = true
+	progress.Queued = len(jobs)
+	h.renderReprocessStatus(w, progress)
+}
+
+// ReprocessStatus handles GET /admin/reprocess/status
+func (h *Handler) ReprocessStatus(w http.ResponseWriter, r *http.Request) {
+	progress, err := h.reprocessProgress(r)
+	if err != nil {
+		log.Printf("failed to load reprocess progress: %v", err)
+		http.Error(w, "failed to load progress", http.StatusInternalServerError)
+		return
+	}
+	h.renderReprocessStatus(w, progress)
+}
@@ -0,0 +1,75 @@
+package handler_test
+
+import (
+	"context"
+	"database/sql"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strconv"
+	"strings"
+	"testing"
+
+	"familyshare/internal/db/sqlc"
+	"familyshare/internal/testutil"
+)
+
+func TestReprocessPhotos(t *testing.T) {
+	h, q, _ := setupBulkTest(t)
+	ctx := context.Background()
+
+	album := testutil.CreateTestAlbum(t, q, "Trip", "")
+	other := testutil.CreateTestAlbum(t, q, "Other", "")
+	photo := testutil.CreateTestPhoto(t, q, album.ID, "beach.webp")
+	testutil.CreateTestPhoto(t, q, other.ID, "hills.webp")
+
+	vals := url.Values{"album_id": {strconv.FormatInt(album.ID, 10)}, "format": {"webp"}}
+	w := postBulk(h.ReprocessPhotos, "/admin/reprocess", vals)
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d: %s", w.Code, w.Body.String())
+	}
+	body := w.Body.String()
+	if !strings.Contains(body, "Queued 1 photo for") || !strings.Contains(body, `hx-trigger="every 2s"`) {
+		t.Errorf("expected one photo queued with polling progress, got %s", body)
+	}
+
+	// The status fragment reports the run
+	req := httptest.NewRequest("GET", "/admin/reprocess/status", nil)
+	w = httptest.NewRecorder()
+	h.ReprocessStatus(w, req)
+	if w.Code != http.StatusOK || !strings.Contains(w.Body.String(), "Pending: <strong>1</strong>") {
+		t.Errorf("expected one pending photo, got %d: %s", w.Code, w.Body.String())
+	}
+
+	if w := postBulk(h.ReprocessPhotos, "/admin/reprocess", url.Values{"format": {"gif"}}); w.Code != http.StatusBadRequest {
+		t.Errorf("expected 400 for invalid format, got %d", w.Code)
+	}
+	if w := postBulk(h.ReprocessPhotos, "/admin/reprocess", url.Values{"album_id": {"x"}}); w.Code != http.StatusBadRequest {
+		t.Errorf("expected 400 for invalid album, got %d", w.Code)
+	}
+
+	// A failed reprocess job can be retried without a temp file
+	jobs, err := q.ListJobStatusesByAlbum(ctx, album.ID)
+	if err != nil || len(jobs) != 1 {
+		t.Fatalf("expected one job: %v", err)
+	}
+	if err := q.UpdateJobStatus(ctx, sqlc.UpdateJobStatusParams{
+		Status:       "failed",
+		ErrorMessage: sql.NullString{String: "decode failed", Valid: true},
+		ID:           jobs[0].ID,
+	}); err != nil {
+		t.Fatalf("fail job: %v", err)
+	}
+	req = httptest.NewRequest("GET", "/admin/uploads/failed", nil)
+	w = httptest.NewRecorder()
+	h.ListFailedUploads(w, req)
+	if !strings.Contains(w.Body.String(), "reprocessing") {
+		t.Errorf("expected failed reprocess job listed")
+	}
+	if w := idRequest(h.RetryFailedUpload, "POST", jobs[0].ID); w.Code != http.StatusNoContent {
+		t.Errorf("expected retry accepted, got %d: %s", w.Code, w.Body.String())
+	}
+	if job, _ := q.GetJob(ctx, jobs[0].ID); job.Status != "pending" || job.PhotoID.Int64 != photo.ID {
+		t.Errorf("expected job requeued for photo %d, got %+v", photo.ID, job)
+	}
+}
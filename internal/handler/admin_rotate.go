@@ -1,6 +1,9 @@
 package handler
 
 import (
+	"context"
+	"errors"
+	"fmt"
 	"log"
 	"net/http"
 	"strconv"
@@ -9,9 +12,23 @@ import (
 
 	"familyshare/internal/db/sqlc"
 	"familyshare/internal/pipeline"
-	"familyshare/internal/storage"
 )
 
+// validRotation reports whether angle is 90, 180 or 270 degrees (-90 is an alias for 270).
+func validRotation(angle int) bool {
+	return angle == 90 || angle == -90 || angle == 180 || angle == 270
+}
+
+// rotatePhoto turns a photo by angle degrees counter-clockwise (90 is left,
+// -90 right). The rotation is kept with the photo's edits and rendered from
+// its original, so turning it back and forth loses no quality.
+func (h *Handler) rotatePhoto(ctx context.Context, q *sqlc.Queries, photo sqlc.Photo, angle int) error {
+	if err := h.editPhoto(ctx, q, photo, func(e *pipeline.Edits) error { e.RotateBy(angle); return nil }); err != nil {
+		return fmt.Errorf("rotate: %w", err)
+	}
+	return nil
+}
+
 // AdminRotatePhoto handles POST /admin/photos/{id}/rotate
 func (h *Handler) AdminRotatePhoto(w http.ResponseWriter, r *http.Request) {
 	idStr := chi.URLParam(r, "id")
@@ -35,7 +52,7 @@ func (h *Handler) AdminRotatePhoto(w http.ResponseWriter, r *http.Request) {
 
 	// Validate angle (90, -90, 180, 270)
 	// We allow 270 as alias for -90
-	if angle != 90 && angle != -90 && angle != 180 && angle != 270 {
+	if !validRotation(angle) {
 		http.Error(w, "Angle must be 90, 180, or 270 (-90)", http.StatusBadRequest)
 		return
 	}
@@ -48,42 +65,16 @@ func (h *Handler) AdminRotatePhoto(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	// Construct full file path
-	// Look up createdAt for path resolution
-	createdAt := photo.CreatedAt.Time
-	if !photo.CreatedAt.Valid {
-		// Fallback should not happen in legitimate cases but handle defensively
-		// Maybe default to now or zero time? Path resolution relies on it.
-		// If data is old/corrupt, this might fail to find the file.
-		log.Printf("photo %d has no created_at, using zero time", id)
-	}
-
-	photoPath := storage.PhotoPathAt(h.storage.BaseDir, photo.AlbumID, photo.ID, "webp", createdAt)
-
-	// Perform rotation
-	// Note: angle in pipeline.Rotate is counter-clockwise.
-	// 90 -> Left
-	// -90 -> Right
-	newWidth, newHeight, newSize, err := pipeline.Rotate(photoPath, angle)
-	if err != nil {
+	if err := h.rotatePhoto(r.Context(), q, photo, angle); err != nil {
+		if errors.Is(err, errAnimatedEdit) {
+			http.Error(w, err.Error(), http.StatusConflict)
+			return
+		}
 		log.Printf("failed to rotate photo %d: %v", id, err)
 		http.Error(w, "Failed to process image rotation", http.StatusInternalServerError)
 		return
 	}
 
-	// Update DB
-	err = q.UpdatePhotoDimensions(r.Context(), sqlc.UpdatePhotoDimensionsParams{
-		Width:     int64(newWidth),
-		Height:    int64(newHeight),
-		SizeBytes: newSize,
-		ID:        id,
-	})
-	if err != nil {
-		log.Printf("failed to update photo dimensions %d: %v", id, err)
-		http.Error(w, "Failed to update database", http.StatusInternalServerError)
-		return
-	}
-
 	// Fetch updated photo for rendering
 	updatedPhoto, err := q.GetPhoto(r.Context(), id)
 	if err != nil {
@@ -96,7 +87,7 @@ func (h *Handler) AdminRotatePhoto(w http.ResponseWriter, r *http.Request) {
 
 	// Render the single photo card
 	w.Header().Set("Content-Type", "text/html; charset=utf-8")
-	if err := h.RenderTemplate(w, "photo_card", updatedPhoto); err != nil {
+	if err := h.RenderTemplate(w, "photo_card", singlePhotoCard(r.Context(), q, updatedPhoto)); err != nil {
 		log.Printf("template render error: %v", err)
 		// Fallback to refresh if template fails
 		w.Header().Set("HX-Refresh", "true")
@@ -21,6 +21,7 @@ import (
 	"familyshare/internal/db"
 	"familyshare/internal/db/sqlc"
 	"familyshare/internal/handler"
+	"familyshare/internal/pipeline"
 	"familyshare/internal/storage"
 	"familyshare/web"
 )
@@ -134,6 +135,9 @@ func TestAdminRotatePhoto_Success(t *testing.T) {
 	if updatedPhoto.Width != 50 || updatedPhoto.Height != 100 {
 		t.Errorf("expected dimensions 50x100, got %dx%d", updatedPhoto.Width, updatedPhoto.Height)
 	}
+	if edits, err := pipeline.ParseEdits(updatedPhoto.Edits); err != nil || edits.Rotate != 90 {
+		t.Errorf("expected rotation 90 recorded in edits, got %q", updatedPhoto.Edits.String)
+	}
 
 	// Verify File on Disk
 	f, err := os.Open(photoPath)
@@ -0,0 +1,98 @@
+package handler
+
+import (
+	"fmt"
+	"log"
+	"net/http"
+	"strings"
+
+	"familyshare/internal/db/sqlc"
+)
+
+// searchResultLimit caps the number of hits rendered per query.
+const searchResultLimit = 50
+
+// searchResult is a search hit resolved to an admin URL.
+type searchResult struct {
+	Kind  string
+	Title string
+	Body  string
+	URL   string
+}
+
+// ftsQuery turns free text into an FTS5 MATCH expression. Every word is
+// quoted (so punctuation cannot break the query syntax) and matched as a
+// prefix; words are ANDed together.
+func ftsQuery(input string) string {
+	var terms []string
+	for _, word := range strings.Fields(input) {
+		terms = append(terms, `"`+strings.ReplaceAll(word, `"`, `""`)+`"*`)
+	}
+	return strings.Join(terms, " ")
+}
+
+// searchResultURL links a hit to the admin page that shows it.
+func searchResultURL(row sqlc.SearchRow) string {
+	switch row.Kind {
+	case "album":
+		return fmt.Sprintf("/admin/albums/%d", row.AlbumID)
+	case "photo", "upload":
+		if row.PhotoID > 0 {
+			return fmt.Sprintf("/admin/albums/%d#photo-%d", row.AlbumID, row.PhotoID)
+		}
+		return fmt.Sprintf("/admin/albums/%d", row.AlbumID)
+	case "tag":
+		return fmt.Sprintf("/admin/photos?tag=%d", row.RefID)
+	}
+	return "/admin"
+}
+
+// AdminSearch handles GET /admin/search?q=...
+// HTMX requests receive only the results fragment for live search.
+func (h *Handler) AdminSearch(w http.ResponseWriter, r *http.Request) {
+	query := strings.TrimSpace(r.URL.Query().Get("q"))
+
+	results := []searchResult{}
+	if match := ftsQuery(query); match != "" {
+		rows, err := h.queries.Search(r.Context(), sqlc.SearchParams{
+			Query: match,
+			Limit: searchResultLimit,
+		})
+		if err != nil {
+			log.Printf("search failed for %q: %v", query, err)
+			http.Error(w, "search failed", http.StatusInternalServerError)
+			return
+		}
+		for _, row := range rows {
+			// Photos without a caption are indexed but have nothing to show
+			if row.Title == "" && row.Body == "" {
+				continue
+			}
+			results = append(results, searchResult{
+				Kind:  row.Kind,
+				Title: row.Title,
+				Body:  row.Body,
+				URL:   searchResultURL(row),
+			})
+		}
+	}
+
+	data := struct {
+		Query   string
+		Results []searchResult
+	}{
+		Query:   query,
+		Results: results,
+	}
+
+	templateName := "search.html"
+	if IsHTMX(r) {
+		templateName = "search_results"
+	}
+
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	if err := h.RenderTemplate(w, templateName, data); err != nil {
+		log.Printf("template render error for %s: %v", templateName, err)
+		http.Error(w, "template render error", http.StatusInternalServerError)
+	}
+}
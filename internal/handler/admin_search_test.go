@@ -0,0 +1,137 @@
+package handler_test
+
+import (
+	"context"
+	"database/sql"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+
+	"familyshare/internal/config"
+	"familyshare/internal/db/sqlc"
+	"familyshare/internal/handler"
+	"familyshare/internal/storage"
+	"familyshare/internal/testutil"
+	"familyshare/web"
+)
+
+func setupSearchTest(t *testing.T) (*handler.Handler, *sql.DB, *sqlc.Queries) {
+	t.Helper()
+	dbConn, q, dbCleanup := testutil.SetupTestDB(t)
+	t.Cleanup(dbCleanup)
+	h := handler.New(dbConn, storage.New(t.TempDir()), web.EmbedFS, &config.Config{RateLimitShare: 60, RateLimitAdmin: 10}, nil)
+	return h, dbConn, q
+}
+
+func doSearch(h *handler.Handler, query string, htmx bool) *httptest.ResponseRecorder {
+	req := httptest.NewRequest("GET", "/admin/search?q="+url.QueryEscape(query), nil)
+	if htmx {
+		req.Header.Set("HX-Request", "true")
+	}
+	w := httptest.NewRecorder()
+	h.AdminSearch(w, req)
+	return w
+}
+
+func TestAdminSearch_CoversAllSources(t *testing.T) {
+	h, dbConn, q := setupSearchTest(t)
+	ctx := context.Background()
+
+	album := testutil.CreateTestAlbum(t, q, "Lake Holiday", "Canoeing with the cousins")
+	photo := testutil.CreateTestPhoto(t, q, album.ID, "stored.webp")
+	if _, err := dbConn.Exec(`UPDATE photos SET caption = ? WHERE id = ?`, "Grandpa catching a trout", photo.ID); err != nil {
+		t.Fatalf("set caption: %v", err)
+	}
+	if _, err := q.EnqueueJob(ctx, sqlc.EnqueueJobParams{
+		AlbumID:          album.ID,
+		OriginalFilename: "IMG_2041.jpg",
+		TempFilepath:     "/tmp/IMG_2041.jpg",
+	}); err != nil {
+		t.Fatalf("EnqueueJob: %v", err)
+	}
+	if _, err := q.CreateTag(ctx, "Grandma"); err != nil {
+		t.Fatalf("CreateTag: %v", err)
+	}
+
+	cases := map[string]string{
+		"lake":      "Lake Holiday",
+		"canoe":     "Lake Holiday", // prefix match on description
+		"trout":     "Grandpa catching a trout",
+		"IMG_2041":  "IMG_2041.jpg",
+		"grand":     "Grandma",
+		"café lake": "",
+	}
+	for query, want := range cases {
+		w := doSearch(h, query, true)
+		if w.Code != http.StatusOK {
+			t.Fatalf("search %q: expected 200, got %d: %s", query, w.Code, w.Body.String())
+		}
+		body := w.Body.String()
+		if want == "" {
+			if !strings.Contains(body, "No Results") {
+				t.Fatalf("search %q: expected no results", query)
+			}
+			continue
+		}
+		if !strings.Contains(body, want) {
+			t.Fatalf("search %q: expected %q in results, got %s", query, want, body)
+		}
+	}
+}
+
+func TestAdminSearch_TriggersKeepIndexInSync(t *testing.T) {
+	h, _, q := setupSearchTest(t)
+	ctx := context.Background()
+
+	album := testutil.CreateTestAlbum(t, q, "Old Title", "")
+	if !strings.Contains(doSearch(h, "old", true).Body.String(), "Old Title") {
+		t.Fatalf("expected new album to be indexed on insert")
+	}
+
+	if err := q.UpdateAlbum(ctx, sqlc.UpdateAlbumParams{Title: "Fresh Title", ID: album.ID}); err != nil {
+		t.Fatalf("UpdateAlbum: %v", err)
+	}
+	if strings.Contains(doSearch(h, "old", true).Body.String(), "Old Title") {
+		t.Fatalf("expected old title to be removed from index on update")
+	}
+	if !strings.Contains(doSearch(h, "fresh", true).Body.String(), "Fresh Title") {
+		t.Fatalf("expected updated title in index")
+	}
+
+	if err := q.DeleteAlbum(ctx, album.ID); err != nil {
+		t.Fatalf("DeleteAlbum: %v", err)
+	}
+	if strings.Contains(doSearch(h, "fresh", true).Body.String(), "Fresh Title") {
+		t.Fatalf("expected deleted album to be removed from index")
+	}
+}
+
+func TestAdminSearch_QuerySyntaxIsEscaped(t *testing.T) {
+	h, _, q := setupSearchTest(t)
+	testutil.CreateTestAlbum(t, q, "Mom's 60th", "")
+
+	for _, query := range []string{`"unbalanced`, `a - b`, `title:foo`, `NEAR(x y)`, `*`, `mom's`} {
+		w := doSearch(h, query, true)
+		if w.Code != http.StatusOK {
+			t.Fatalf("search %q: expected 200, got %d", query, w.Code)
+		}
+	}
+}
+
+func TestAdminSearch_FullPageAndEmptyQuery(t *testing.T) {
+	h, _, _ := setupSearchTest(t)
+
+	w := doSearch(h, "", false)
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d", w.Code)
+	}
+	body := w.Body.String()
+	if !strings.Contains(body, "<!DOCTYPE html>") || !strings.Contains(body, `id="search-results"`) {
+		t.Fatalf("expected full search page for non-HTMX request")
+	}
+	if strings.Contains(body, "No Results") {
+		t.Fatalf("did not expect a no-results message for an empty query")
+	}
+}
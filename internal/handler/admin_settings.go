@@ -0,0 +1,149 @@
+package handler
+
+import (
+	"errors"
+	"log"
+	"net/http"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"familyshare/internal/pipeline"
+	"familyshare/internal/settings"
+	"familyshare/internal/storage"
+)
+
+// maxLogoFileSize bounds an uploaded logo. Logos are stored small, so this
+// is well below the limit of photos.
+const maxLogoFileSize = int64(5 << 20)
+
+// AdminSettings handles GET /admin/settings
+func (h *Handler) AdminSettings(w http.ResponseWriter, r *http.Request) {
+	branding, err := h.settings.Branding(r.Context())
+	if err != nil {
+		log.Printf("failed to load settings: %v", err)
+		http.Error(w, "failed to load settings", http.StatusInternalServerError)
+		return
+	}
+
+	data := struct {
+		Branding      settings.Branding
+		MaxFamilyName int
+		MaxFooterText int
+		MaxLogoMB     int64
+		LogoSize      int
+	}{
+		Branding:      branding,
+		MaxFamilyName: settings.MaxFamilyNameLength,
+		MaxFooterText: settings.MaxFooterTextLength,
+		MaxLogoMB:     maxLogoFileSize >> 20,
+		LogoSize:      pipeline.LogoMaxDimension,
+	}
+
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	if err := h.RenderTemplate(w, "settings.html", data); err != nil {
+		log.Printf("template render error for settings: %v", err)
+		http.Error(w, "template render error", http.StatusInternalServerError)
+	}
+}
+
+// UpdateSettings handles POST /admin/settings
+// An empty field goes back to the FamilyShare default.
+func (h *Handler) UpdateSettings(w http.ResponseWriter, r *http.Request) {
+	if err := r.ParseForm(); err != nil {
+		http.Error(w, "invalid form", http.StatusBadRequest)
+		return
+	}
+	branding := settings.Branding{
+		FamilyName:  strings.TrimSpace(r.PostFormValue("family_name")),
+		AccentColor: settings.NormalizeAccentColor(r.PostFormValue("accent_color")),
+		FooterText:  strings.TrimSpace(r.PostFormValue("footer_text")),
+	}
+	if err := branding.Validate(); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	if err := h.settings.SetBranding(r.Context(), branding); err != nil {
+		log.Printf("failed to save settings: %v", err)
+		http.Error(w, "failed to save settings", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("HX-Refresh", "true")
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// UploadLogo handles POST /admin/settings/logo
+// The logo goes through the image pipeline like photos, which checks it is
+// an image and stores it small, as WebP.
+func (h *Handler) UploadLogo(w http.ResponseWriter, r *http.Request) {
+	r.Body = http.MaxBytesReader(w, r.Body, maxLogoFileSize+(1<<20))
+	if err := r.ParseMultipartForm(maxLogoFileSize); err != nil {
+		var tooLarge *http.MaxBytesError
+		if errors.As(err, &tooLarge) {
+			http.Error(w, friendlyUploadError(h.locale, errUploadTooLarge, maxLogoFileSize), http.StatusRequestEntityTooLarge)
+			return
+		}
+		http.Error(w, "invalid form", http.StatusBadRequest)
+		return
+	}
+	file, _, err := r.FormFile("logo")
+	if err != nil {
+		http.Error(w, "logo required", http.StatusBadRequest)
+		return
+	}
+	defer file.Close()
+
+	if err := pipeline.WriteLogo(file, maxLogoFileSize, storage.LogoPath(h.storage.BaseDir)); err != nil {
+		log.Printf("failed to process logo: %v", err)
+		http.Error(w, friendlyUploadError(h.locale, err, maxLogoFileSize), http.StatusBadRequest)
+		return
+	}
+	// A new version makes browsers fetch the new logo
+	version := strconv.FormatInt(time.Now().UnixNano(), 36)
+	if err := h.settings.SetLogo(r.Context(), version); err != nil {
+		log.Printf("failed to save logo setting: %v", err)
+		http.Error(w, "failed to save logo", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("HX-Refresh", "true")
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// DeleteLogo handles DELETE /admin/settings/logo
+func (h *Handler) DeleteLogo(w http.ResponseWriter, r *http.Request) {
+	if err := h.settings.SetLogo(r.Context(), ""); err != nil {
+		log.Printf("failed to clear logo setting: %v", err)
+		http.Error(w, "failed to remove logo", http.StatusInternalServerError)
+		return
+	}
+	// Public pages no longer link to the file, so failing to remove it
+	// leaves nothing broken
+	if err := os.Remove(storage.LogoPath(h.storage.BaseDir)); err != nil && !errors.Is(err, os.ErrNotExist) {
+		log.Printf("failed to remove logo file: %v", err)
+	}
+
+	w.Header().Set("HX-Refresh", "true")
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// ServeLogo handles GET /branding/logo.webp
+// Its URL changes with every upload, so it can be cached for long.
+func (h *Handler) ServeLogo(w http.ResponseWriter, r *http.Request) {
+	version, err := h.settings.Get(r.Context(), settings.KeyLogo)
+	if err != nil {
+		log.Printf("failed to load logo setting: %v", err)
+		http.Error(w, "Internal server error", http.StatusInternalServerError)
+		return
+	}
+	if version == "" {
+		http.NotFound(w, r)
+		return
+	}
+
+	w.Header().Set("Content-Type", "image/webp")
+	w.Header().Set("Cache-Control", "public, max-age=31536000, immutable")
+	http.ServeFile(w, r, storage.LogoPath(h.storage.BaseDir))
+}
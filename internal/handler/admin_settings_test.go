@@ -0,0 +1,179 @@
+package handler_test
+
+import (
+	"bytes"
+	"context"
+	"database/sql"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"familyshare/internal/db/sqlc"
+	"familyshare/internal/testutil"
+)
+
+func logoRequest(t *testing.T, image io.Reader) *http.Request {
+	t.Helper()
+	var body bytes.Buffer
+	mw := multipart.NewWriter(&body)
+	part, err := mw.CreateFormFile("logo", "logo.png")
+	if err != nil {
+		t.Fatalf("create form file: %v", err)
+	}
+	if _, err := io.Copy(part, image); err != nil {
+		t.Fatalf("write form file: %v", err)
+	}
+	mw.Close()
+	req := httptest.NewRequest("POST", "/admin/settings/logo", &body)
+	req.Header.Set("Content-Type", mw.FormDataContentType())
+	return req
+}
+
+func TestSettings_Branding(t *testing.T) {
+	h, q, cleanup := setupTestHandlerForShare(t)
+	defer cleanup()
+
+	album := testutil.CreateTestAlbum(t, q, "Summer", "")
+	testutil.CreateTestPhoto(t, q, album.ID, "beach.webp")
+	link := testutil.CreateTestShareLink(t, q, album.ID, "branding-token", 0, time.Time{})
+
+	// Unbranded pages keep the FamilyShare defaults
+	body := getShare(h.ViewShareLink, link.Token, "").Body.String()
+	if !strings.Contains(body, "Shared via FamilyShare") || strings.Contains(body, "--color-primary:") {
+		t.Errorf("expected the default branding")
+	}
+
+	form := url.Values{"family_name": {"The Silvas"}, "accent_color": {" #AA3300"}, "footer_text": {"Made with love in Lisbon"}}
+	w := httptest.NewRecorder()
+	h.UpdateSettings(w, shareAdminRequest("POST", "/admin/settings", "", form))
+	if w.Code != http.StatusNoContent {
+		t.Fatalf("expected status 204, got %d: %s", w.Code, w.Body.String())
+	}
+
+	body = getShare(h.ViewShareLink, link.Token, "").Body.String()
+	for _, want := range []string{"<title>Summer - The Silvas</title>", `<h1 class="brand-name">The Silvas</h1>`, "--color-primary: #aa3300", "Made with love in Lisbon"} {
+		if !strings.Contains(body, want) {
+			t.Errorf("expected the share page to contain %q", want)
+		}
+	}
+	if strings.Contains(body, "Shared via FamilyShare") {
+		t.Errorf("expected the footer text to replace the default footer")
+	}
+
+	w = httptest.NewRecorder()
+	h.AdminSettings(w, httptest.NewRequest("GET", "/admin/settings", nil))
+	if w.Code != http.StatusOK || !strings.Contains(w.Body.String(), `value="The Silvas"`) {
+		t.Errorf("expected the settings page with the family name, got %d", w.Code)
+	}
+
+	for _, color := range []string{"red", "#abc", "#aa3300; background: red"} {
+		w = httptest.NewRecorder()
+		h.UpdateSettings(w, shareAdminRequest("POST", "/admin/settings", "", url.Values{"accent_color": {color}}))
+		if w.Code != http.StatusBadRequest {
+			t.Errorf("%q: expected status 400, got %d", color, w.Code)
+		}
+	}
+}
+
+func TestSettings_Logo(t *testing.T) {
+	h, q, cleanup := setupTestHandlerForShare(t)
+	defer cleanup()
+	ctx := context.Background()
+
+	album := testutil.CreateTestAlbum(t, q, "Summer", "")
+	link := testutil.CreateTestShareLink(t, q, album.ID, "logo-token", 0, time.Time{})
+
+	w := httptest.NewRecorder()
+	h.ServeLogo(w, httptest.NewRequest("GET", "/branding/logo.webp", nil))
+	if w.Code != http.StatusNotFound {
+		t.Errorf("expected status 404 without a logo, got %d", w.Code)
+	}
+
+	w = httptest.NewRecorder()
+	h.UploadLogo(w, logoRequest(t, testutil.GenerateTestImage(t, "png", 800, 400)))
+	if w.Code != http.StatusNoContent {
+		t.Fatalf("expected status 204, got %d: %s", w.Code, w.Body.String())
+	}
+	settings, err := q.ListSettings(ctx)
+	if err != nil || len(settings) != 1 {
+		t.Fatalf("expected the logo setting, got %+v (%v)", settings, err)
+	}
+	logoURL := "/branding/logo.webp?v=" + settings[0].Value
+
+	if body := getShare(h.ViewShareLink, link.Token, "").Body.String(); !strings.Contains(body, logoURL) {
+		t.Errorf("expected the share page to show the logo %s", logoURL)
+	}
+	w = httptest.NewRecorder()
+	h.ServeLogo(w, httptest.NewRequest("GET", logoURL, nil))
+	if w.Code != http.StatusOK || w.Header().Get("Content-Type") != "image/webp" || !bytes.HasPrefix(w.Body.Bytes(), []byte("RIFF")) {
+		t.Errorf("expected the WebP logo, got %d %q", w.Code, w.Header().Get("Content-Type"))
+	}
+
+	w = httptest.NewRecorder()
+	h.UploadLogo(w, logoRequest(t, strings.NewReader("not an image")))
+	if w.Code != http.StatusBadRequest {
+		t.Errorf("expected status 400 for a file that isn't an image, got %d", w.Code)
+	}
+
+	w = httptest.NewRecorder()
+	h.DeleteLogo(w, httptest.NewRequest("DELETE", "/admin/settings/logo", nil))
+	if w.Code != http.StatusNoContent {
+		t.Fatalf("expected status 204, got %d", w.Code)
+	}
+	if body := getShare(h.ViewShareLink, link.Token, "").Body.String(); strings.Contains(body, "/branding/logo.webp") {
+		t.Errorf("expected the share page without a logo")
+	}
+	w = httptest.NewRecorder()
+	h.ServeLogo(w, httptest.NewRequest("GET", logoURL, nil))
+	if w.Code != http.StatusNotFound {
+		t.Errorf("expected status 404 after removing the logo, got %d", w.Code)
+	}
+}
+
+func TestShareAlbum_CoverHeader(t *testing.T) {
+	h, q, cleanup := setupTestHandlerForShare(t)
+	defer cleanup()
+	ctx := context.Background()
+
+	album := testutil.CreateTestAlbum(t, q, "Summer", "")
+	photo := testutil.CreateTestPhoto(t, q, album.ID, "beach.webp")
+	link := testutil.CreateTestShareLink(t, q, album.ID, "cover-token", 0, time.Time{})
+	if err := q.SetAlbumCover(ctx, sqlc.SetAlbumCoverParams{CoverPhotoID: sql.NullInt64{Int64: photo.ID, Valid: true}, ID: album.ID}); err != nil {
+		t.Fatalf("set cover: %v", err)
+	}
+	id := strconv.FormatInt(album.ID, 10)
+	coverURL := "/s/" + link.Token + "/photos/" + strconv.FormatInt(photo.ID, 10) + ".webp"
+
+	if body := getShare(h.ViewShareLink, link.Token, "").Body.String(); strings.Contains(body, `class="album-cover"`) {
+		t.Errorf("expected no cover header until the album turns it on")
+	}
+
+	w := httptest.NewRecorder()
+	h.UpdateAlbum(w, shareAdminRequest("PUT", "/admin/albums/"+id, id, url.Values{"title": {"Summer"}, "cover_header": {"0", "1"}}))
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected status 200, got %d: %s", w.Code, w.Body.String())
+	}
+	body := getShare(h.ViewShareLink, link.Token, "").Body.String()
+	if !strings.Contains(body, `class="album-cover"`) || !strings.Contains(body, `<img src="`+coverURL+`" alt="" class="album-cover-image">`) {
+		t.Errorf("expected a cover header with %s", coverURL)
+	}
+
+	// Forms without the field leave it alone
+	w = httptest.NewRecorder()
+	h.UpdateAlbum(w, shareAdminRequest("PUT", "/admin/albums/"+id, id, url.Values{"title": {"Summer"}}))
+	if updated, _ := q.GetAlbum(ctx, album.ID); !updated.CoverHeader {
+		t.Errorf("expected the cover header to stay on")
+	}
+
+	w = httptest.NewRecorder()
+	h.UpdateAlbum(w, shareAdminRequest("PUT", "/admin/albums/"+id, id, url.Values{"title": {"Summer"}, "cover_header": {"0"}}))
+	if body := getShare(h.ViewShareLink, link.Token, "").Body.String(); strings.Contains(body, `class="album-cover"`) {
+		t.Errorf("expected the cover header turned off")
+	}
+}
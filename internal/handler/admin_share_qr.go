@@ -0,0 +1,91 @@
+package handler
+
+import (
+	"fmt"
+	"log"
+	"net/http"
+	"strconv"
+
+	"github.com/go-chi/chi/v5"
+
+	"familyshare/internal/db/sqlc"
+	"familyshare/internal/qrcode"
+)
+
+// qrModulePixels is the width of one QR code module in PNG downloads, enough
+// for a sharp print of a card.
+const qrModulePixels = 10
+
+// ShareLinkQRCode handles GET /admin/shares/{id}/qr
+// It draws the QR code of a share link's URL as a PNG, or as an SVG with
+// ?format=svg. The URL uses the link's slug when it has one. Links with
+// recipients have a QR code per recipient, picked with ?recipient=.
+func (h *Handler) ShareLinkQRCode(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.ParseInt(chi.URLParam(r, "id"), 10, 64)
+	if err != nil {
+		http.Error(w, "invalid id", http.StatusBadRequest)
+		return
+	}
+	format := r.URL.Query().Get("format")
+	if format == "" {
+		format = "png"
+	}
+	if format != "png" && format != "svg" {
+		http.Error(w, "invalid format", http.StatusBadRequest)
+		return
+	}
+
+	q := sqlc.New(h.db)
+	share, err := q.GetShareLink(r.Context(), id)
+	if err != nil {
+		http.Error(w, "share link not found", http.StatusNotFound)
+		return
+	}
+
+	path := share.Token
+	if share.Slug.Valid {
+		path = share.Slug.String
+	}
+	recipients, err := q.ListShareLinkRecipients(r.Context(), id)
+	if err != nil {
+		log.Printf("failed to list recipients of share link %d: %v", id, err)
+		http.Error(w, "failed to load share link", http.StatusInternalServerError)
+		return
+	}
+	if len(recipients) > 0 {
+		recipientID, err := strconv.ParseInt(r.URL.Query().Get("recipient"), 10, 64)
+		if err != nil {
+			http.Error(w, "recipient required", http.StatusBadRequest)
+			return
+		}
+		path = ""
+		for _, recipient := range recipients {
+			if recipient.ID == recipientID {
+				path = recipient.Token
+			}
+		}
+		if path == "" {
+			http.Error(w, "recipient not found", http.StatusNotFound)
+			return
+		}
+	}
+
+	code, err := qrcode.Encode(getBaseURL(r) + "/s/" + path)
+	if err != nil {
+		log.Printf("failed to encode QR code of share link %d: %v", id, err)
+		http.Error(w, "failed to draw QR code", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Disposition", fmt.Sprintf(`inline; filename="share-%d.%s"`, id, format))
+	if format == "svg" {
+		w.Header().Set("Content-Type", "image/svg+xml")
+		err = code.WriteSVG(w)
+	} else {
+		w.Header().Set("Content-Type", "image/png")
+		err = code.WritePNG(w, qrModulePixels)
+	}
+	if err != nil {
+		log.Printf("failed to write QR code of share link %d: %v", id, err)
+	}
+}
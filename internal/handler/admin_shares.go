@@ -1,18 +1,31 @@
 package handler
 
 import (
+	"context"
 	"database/sql"
+	"errors"
+	"fmt"
 	"log"
 	"net/http"
 	"strconv"
+	"strings"
 	"time"
+	"unicode/utf8"
 
 	"github.com/go-chi/chi/v5"
 
 	"familyshare/internal/db/sqlc"
+	"familyshare/internal/i18n"
 	"familyshare/internal/security"
+	"familyshare/internal/timefmt"
 )
 
+// shareLinkDetails is a share link on the shares page with its recipients.
+type shareLinkDetails struct {
+	sqlc.ListShareLinksWithDetailsRow
+	RecipientList []sqlc.ListRecipientsForShareLinksRow
+}
+
 // ListShareLinks handles GET /admin/shares
 func (h *Handler) ListShareLinks(w http.ResponseWriter, r *http.Request) {
 	w.Header().Set("Content-Type", "text/html; charset=utf-8")
@@ -33,32 +46,45 @@ func (h *Handler) ListShareLinks(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	// Recipients of the same links, grouped by link
+	recipients := map[int64][]sqlc.ListRecipientsForShareLinksRow{}
+	allRecipients, err := q.ListRecipientsForShareLinks(r.Context(), sqlc.ListRecipientsForShareLinksParams{
+		Limit:  100,
+		Offset: 0,
+	})
+	if err != nil {
+		log.Printf("failed to list share link recipients: %v", err)
+	}
+	for _, recipient := range allRecipients {
+		recipients[recipient.ShareLinkID] = append(recipients[recipient.ShareLinkID], recipient)
+	}
+
 	// Filter out revoked shares unless explicitly requested
-	var shares []sqlc.ListShareLinksWithDetailsRow
-	if showRevoked {
-		shares = allShares
-	} else {
-		for _, share := range allShares {
-			if !share.RevokedAt.Valid {
-				shares = append(shares, share)
-			}
+	var shares []shareLinkDetails
+	for _, share := range allShares {
+		if share.RevokedAt.Valid && !showRevoked {
+			continue
 		}
+		shares = append(shares, shareLinkDetails{ListShareLinksWithDetailsRow: share, RecipientList: recipients[share.ID]})
 	}
 
 	// Get albums and photos for the form dropdown
 	albums, _ := q.ListAlbums(r.Context(), sqlc.ListAlbumsParams{Limit: 100, Offset: 0})
 	photos, _ := q.ListAllPhotosWithAlbum(r.Context(), sqlc.ListAllPhotosWithAlbumParams{Limit: 100, Offset: 0})
+	tags, _ := q.ListTags(r.Context())
 
 	data := struct {
-		Shares      []sqlc.ListShareLinksWithDetailsRow
+		Shares      []shareLinkDetails
 		Albums      []sqlc.Album
 		Photos      []sqlc.ListAllPhotosWithAlbumRow
+		Tags        []sqlc.Tag
 		BaseURL     string
 		ShowRevoked bool
 	}{
 		Shares:      shares,
 		Albums:      albums,
 		Photos:      photos,
+		Tags:        tags,
 		BaseURL:     getBaseURL(r),
 		ShowRevoked: showRevoked,
 	}
@@ -88,7 +114,7 @@ func (h *Handler) CreateShareLink(w http.ResponseWriter, r *http.Request) {
 		targetType, targetIDStr, maxViewsStr, expiresAtStr)
 
 	// Validate target type
-	if targetType != "album" && targetType != "photo" {
+	if targetType != "album" && targetType != "photo" && targetType != "tag" {
 		log.Printf("invalid target_type: %s", targetType)
 		http.Error(w, "invalid target_type", http.StatusBadRequest)
 		return
@@ -101,34 +127,31 @@ func (h *Handler) CreateShareLink(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	// Parse max views (optional)
-	var maxViews sql.NullInt64
-	if maxViewsStr != "" {
-		mv, err := strconv.ParseInt(maxViewsStr, 10, 64)
-		if err != nil || mv <= 0 {
-			http.Error(w, "invalid max_views", http.StatusBadRequest)
-			return
-		}
-		maxViews = sql.NullInt64{Int64: mv, Valid: true}
+	// Parse max views, expires_at and message (optional)
+	settings, err := parseShareLinkSettings(r, h.tz)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
 	}
 
-	// Parse expires_at (optional)
-	var expiresAt sql.NullTime
-	if expiresAtStr != "" {
-		// Parse in UTC timezone
-		t, err := time.ParseInLocation("2006-01-02T15:04", expiresAtStr, time.UTC)
-		if err != nil {
-			http.Error(w, "invalid expires_at format", http.StatusBadRequest)
-			return
-		}
-		expiresAt = sql.NullTime{Time: t, Valid: true}
+	// Guest comments are opt-in per link
+	allowComments := r.PostFormValue("allow_comments") == "on" || r.PostFormValue("allow_comments") == "true"
+	// The map is only offered for albums
+	showMap := targetType == "album" && (r.PostFormValue("show_map") == "on" || r.PostFormValue("show_map") == "true")
+	// Link previews show the title, message and a photo to anyone the link is pasted to
+	openGraph := r.PostFormValue("open_graph") == "on" || r.PostFormValue("open_graph") == "true"
+
+	// Named recipients each get their own link
+	recipients, err := parseRecipients(r.PostFormValue("recipients"))
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
 	}
 
-	// Parse message (optional)
-	message := r.PostFormValue("message")
-	var messageSQL sql.NullString
-	if message != "" {
-		messageSQL = sql.NullString{String: message, Valid: true}
+	// A slug would open the link for anyone, not just its recipients
+	if settings.Slug.Valid && len(recipients) > 0 {
+		http.Error(w, "a link with recipients can't have a slug", http.StatusBadRequest)
+		return
 	}
 
 	q := sqlc.New(h.db)
@@ -145,6 +168,20 @@ func (h *Handler) CreateShareLink(w http.ResponseWriter, r *http.Request) {
 			http.Error(w, "photo not found", http.StatusNotFound)
 			return
 		}
+	case "tag":
+		if _, err := q.GetTag(r.Context(), targetID); err != nil {
+			http.Error(w, "tag not found", http.StatusNotFound)
+			return
+		}
+	}
+
+	if taken, err := slugTaken(r.Context(), q, settings.Slug, 0); err != nil {
+		log.Printf("failed to check slug: %v", err)
+		http.Error(w, "failed to create share link", http.StatusInternalServerError)
+		return
+	} else if taken {
+		http.Error(w, "slug already in use", http.StatusConflict)
+		return
 	}
 
 	// Generate secure token with retry logic for uniqueness
@@ -159,23 +196,37 @@ func (h *Handler) CreateShareLink(w http.ResponseWriter, r *http.Request) {
 		}
 
 		// Try to create share link
-		share, err := q.CreateShareLink(r.Context(), sqlc.CreateShareLinkParams{
-			Token:      token,
-			TargetType: targetType,
-			TargetID:   targetID,
-			MaxViews:   maxViews,
-			ExpiresAt:  expiresAt,
-			Message:    messageSQL,
+		share, err := h.createShareLink(r.Context(), recipients, sqlc.CreateShareLinkParams{
+			Token:         token,
+			TargetType:    targetType,
+			TargetID:      targetID,
+			MaxViews:      settings.MaxViews,
+			ExpiresAt:     settings.ExpiresAt,
+			Message:       settings.Message,
+			AllowComments: allowComments,
+			ShowMap:       showMap,
+			OpenGraph:     openGraph,
+			Slug:          settings.Slug,
+			Locale:        settings.Locale,
 		})
 
 		if err == nil {
 			// Success - return the share link row
+			var shareRecipients []sqlc.ListShareLinkRecipientsRow
+			if len(recipients) > 0 {
+				shareRecipients, err = q.ListShareLinkRecipients(r.Context(), share.ID)
+				if err != nil {
+					log.Printf("failed to list recipients of share link %d: %v", share.ID, err)
+				}
+			}
 			data := struct {
-				Share   sqlc.ShareLink
-				BaseURL string
+				Share      sqlc.ShareLink
+				Recipients []sqlc.ListShareLinkRecipientsRow
+				BaseURL    string
 			}{
-				Share:   share,
-				BaseURL: getBaseURL(r),
+				Share:      share,
+				Recipients: shareRecipients,
+				BaseURL:    getBaseURL(r),
 			}
 
 			w.Header().Set("Content-Type", "text/html; charset=utf-8")
@@ -200,6 +251,305 @@ func (h *Handler) CreateShareLink(w http.ResponseWriter, r *http.Request) {
 	}
 }
 
+// parseShareLinkSettings reads the settings of a share link that can be
+// changed after it's created: the view limit, the expiry, the message, the
+// slug and the language.
+func parseShareLinkSettings(r *http.Request, loc *time.Location) (sqlc.UpdateShareLinkParams, error) {
+	var settings sqlc.UpdateShareLinkParams
+
+	if v := r.PostFormValue("max_views"); v != "" {
+		mv, err := strconv.ParseInt(v, 10, 64)
+		if err != nil || mv <= 0 {
+			return settings, errors.New("invalid max_views")
+		}
+		settings.MaxViews = sql.NullInt64{Int64: mv, Valid: true}
+	}
+
+	if v := r.PostFormValue("expires_at"); v != "" {
+		// The browser sends the wall clock time of the instance's timezone
+		t, err := timefmt.ParseInput(v, loc)
+		if err != nil {
+			return settings, errors.New("invalid expires_at format")
+		}
+		settings.ExpiresAt = sql.NullTime{Time: t, Valid: true}
+	}
+
+	if v := r.PostFormValue("message"); v != "" {
+		settings.Message = sql.NullString{String: v, Valid: true}
+	}
+
+	if v := security.NormalizeSlug(r.PostFormValue("slug")); v != "" {
+		if err := security.ValidateSlug(v); err != nil {
+			return settings, err
+		}
+		settings.Slug = sql.NullString{String: v, Valid: true}
+	}
+
+	// Without a language the pages follow the visitor's browser
+	if v := r.PostFormValue("locale"); v != "" {
+		if !i18n.Supported(v) {
+			return settings, errors.New("invalid locale")
+		}
+		settings.Locale = sql.NullString{String: v, Valid: true}
+	}
+	return settings, nil
+}
+
+// slugTaken reports whether a share link other than the one with the given
+// id already uses the slug.
+func slugTaken(ctx context.Context, q *sqlc.Queries, slug sql.NullString, id int64) (bool, error) {
+	if !slug.Valid {
+		return false, nil
+	}
+	other, err := q.GetShareLinkBySlug(ctx, slug)
+	if err == sql.ErrNoRows {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+	return other.ID != id, nil
+}
+
+// EditShareLinkForm handles GET /admin/shares/{id}/edit
+func (h *Handler) EditShareLinkForm(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.ParseInt(chi.URLParam(r, "id"), 10, 64)
+	if err != nil {
+		http.Error(w, "invalid id", http.StatusBadRequest)
+		return
+	}
+
+	q := sqlc.New(h.db)
+	share, err := q.GetShareLink(r.Context(), id)
+	if err != nil {
+		http.Error(w, "share link not found", http.StatusNotFound)
+		return
+	}
+
+	recipients, err := q.CountShareLinkRecipients(r.Context(), id)
+	if err != nil {
+		log.Printf("failed to count recipients of share link %d: %v", id, err)
+	}
+
+	data := struct {
+		sqlc.ShareLink
+		Recipients int64
+	}{
+		ShareLink:  share,
+		Recipients: recipients,
+	}
+
+	if err := h.RenderTemplate(w, "share_edit_form.html", data); err != nil {
+		log.Printf("template render error: %v", err)
+		http.Error(w, "template render error", http.StatusInternalServerError)
+	}
+}
+
+// UpdateShareLink handles PUT /admin/shares/{id}
+// It changes the view limit, expiry, message, slug and language of a link,
+// for example to extend a link that expired.
+func (h *Handler) UpdateShareLink(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.ParseInt(chi.URLParam(r, "id"), 10, 64)
+	if err != nil {
+		http.Error(w, "invalid id", http.StatusBadRequest)
+		return
+	}
+	if err := r.ParseForm(); err != nil {
+		http.Error(w, "invalid form", http.StatusBadRequest)
+		return
+	}
+	settings, err := parseShareLinkSettings(r, h.tz)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	settings.ID = id
+
+	q := sqlc.New(h.db)
+	if settings.Slug.Valid {
+		recipients, err := q.CountShareLinkRecipients(r.Context(), id)
+		if err != nil {
+			log.Printf("failed to count recipients of share link %d: %v", id, err)
+			http.Error(w, "failed to update share link", http.StatusInternalServerError)
+			return
+		}
+		if recipients > 0 {
+			http.Error(w, "a link with recipients can't have a slug", http.StatusBadRequest)
+			return
+		}
+		if taken, err := slugTaken(r.Context(), q, settings.Slug, id); err != nil {
+			log.Printf("failed to check slug: %v", err)
+			http.Error(w, "failed to update share link", http.StatusInternalServerError)
+			return
+		} else if taken {
+			http.Error(w, "slug already in use", http.StatusConflict)
+			return
+		}
+	}
+
+	updated, err := q.UpdateShareLink(r.Context(), settings)
+	if err != nil {
+		// Another link can take the slug after it was checked
+		if isUniqueViolation(err) {
+			http.Error(w, "slug already in use", http.StatusConflict)
+			return
+		}
+		log.Printf("failed to update share link %d: %v", id, err)
+		http.Error(w, "failed to update share link", http.StatusInternalServerError)
+		return
+	}
+	if updated == 0 {
+		http.Error(w, "share link not found", http.StatusNotFound)
+		return
+	}
+
+	if IsHTMX(r) {
+		w.Header().Set("HX-Refresh", "true")
+		w.WriteHeader(http.StatusNoContent)
+		return
+	}
+	http.Redirect(w, r, "/admin/shares", http.StatusSeeOther)
+}
+
+// ReactivateShareLink handles POST /admin/shares/{id}/reactivate
+// A revoked link works again until the janitor has deleted it.
+func (h *Handler) ReactivateShareLink(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.ParseInt(chi.URLParam(r, "id"), 10, 64)
+	if err != nil {
+		http.Error(w, "invalid id", http.StatusBadRequest)
+		return
+	}
+
+	q := sqlc.New(h.db)
+	reactivated, err := q.ReactivateShareLink(r.Context(), id)
+	if err != nil {
+		log.Printf("failed to reactivate share link %d: %v", id, err)
+		http.Error(w, "failed to reactivate share link", http.StatusInternalServerError)
+		return
+	}
+	if reactivated == 0 {
+		http.Error(w, "share link not revoked", http.StatusNotFound)
+		return
+	}
+
+	w.Header().Set("HX-Refresh", "true")
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// ResetShareLinkViews handles POST /admin/shares/{id}/reset-views
+// It forgets the link's viewers, giving it a fresh view limit.
+func (h *Handler) ResetShareLinkViews(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.ParseInt(chi.URLParam(r, "id"), 10, 64)
+	if err != nil {
+		http.Error(w, "invalid id", http.StatusBadRequest)
+		return
+	}
+
+	q := sqlc.New(h.db)
+	if _, err := q.GetShareLink(r.Context(), id); err != nil {
+		http.Error(w, "share link not found", http.StatusNotFound)
+		return
+	}
+	if err := q.ResetShareLinkViews(r.Context(), id); err != nil {
+		log.Printf("failed to reset views of share link %d: %v", id, err)
+		http.Error(w, "failed to reset views", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("HX-Refresh", "true")
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// Recipient limits of a share link.
+const (
+	maxShareRecipients     = 50
+	maxRecipientNameLength = 100
+)
+
+// parseRecipients reads the recipients of a share link, one name per line.
+// Blank lines and repeated names are skipped.
+func parseRecipients(s string) ([]string, error) {
+	var names []string
+	seen := map[string]bool{}
+	for _, line := range strings.Split(s, "\n") {
+		name := strings.TrimSpace(line)
+		if name == "" || seen[strings.ToLower(name)] {
+			continue
+		}
+		if utf8.RuneCountInString(name) > maxRecipientNameLength {
+			return nil, fmt.Errorf("recipient names can be at most %d characters", maxRecipientNameLength)
+		}
+		seen[strings.ToLower(name)] = true
+		names = append(names, name)
+	}
+	if len(names) > maxShareRecipients {
+		return nil, fmt.Errorf("a link can have at most %d recipients", maxShareRecipients)
+	}
+	return names, nil
+}
+
+// createShareLink creates a share link together with its recipients, each
+// with a token derived from the link's.
+func (h *Handler) createShareLink(ctx context.Context, recipients []string, params sqlc.CreateShareLinkParams) (sqlc.ShareLink, error) {
+	tx, err := h.db.BeginTx(ctx, nil)
+	if err != nil {
+		return sqlc.ShareLink{}, err
+	}
+	defer tx.Rollback()
+
+	q := sqlc.New(tx)
+	share, err := q.CreateShareLink(ctx, params)
+	if err != nil {
+		return sqlc.ShareLink{}, err
+	}
+	for _, name := range recipients {
+		token, err := security.GenerateSubToken(share.Token)
+		if err != nil {
+			return sqlc.ShareLink{}, err
+		}
+		if _, err := q.CreateShareLinkRecipient(ctx, sqlc.CreateShareLinkRecipientParams{
+			ShareLinkID: share.ID,
+			Name:        name,
+			Token:       token,
+		}); err != nil {
+			return sqlc.ShareLink{}, err
+		}
+	}
+	return share, tx.Commit()
+}
+
+// RevokeShareLinkRecipient handles DELETE /admin/shares/{id}/recipients/{recipientID}
+// It cuts off one recipient of a link; the others keep their access.
+func (h *Handler) RevokeShareLinkRecipient(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.ParseInt(chi.URLParam(r, "id"), 10, 64)
+	if err != nil {
+		http.Error(w, "invalid id", http.StatusBadRequest)
+		return
+	}
+	recipientID, err := strconv.ParseInt(chi.URLParam(r, "recipientID"), 10, 64)
+	if err != nil {
+		http.Error(w, "invalid recipient id", http.StatusBadRequest)
+		return
+	}
+
+	q := sqlc.New(h.db)
+	revoked, err := q.RevokeShareLinkRecipient(r.Context(), sqlc.RevokeShareLinkRecipientParams{
+		ID:          recipientID,
+		ShareLinkID: id,
+	})
+	if err != nil {
+		log.Printf("failed to revoke share link recipient: %v", err)
+		http.Error(w, "failed to revoke recipient", http.StatusInternalServerError)
+		return
+	}
+	if revoked == 0 {
+		http.Error(w, "recipient not found", http.StatusNotFound)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
 // RevokeShareLink handles DELETE /admin/shares/{id}
 func (h *Handler) RevokeShareLink(w http.ResponseWriter, r *http.Request) {
 	idStr := chi.URLParam(r, "id")
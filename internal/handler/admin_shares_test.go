@@ -4,9 +4,12 @@ import (
 	"context"
 	"database/sql"
 	"familyshare/internal/config"
+	"fmt"
+	"image/png"
 	"net/http"
 	"net/http/httptest"
 	"net/url"
+	"strconv"
 	"strings"
 	"testing"
 	"time"
@@ -17,6 +20,7 @@ import (
 	"familyshare/internal/db/sqlc"
 	"familyshare/internal/handler"
 	"familyshare/internal/storage"
+	"familyshare/internal/testutil"
 	"familyshare/web"
 )
 
@@ -453,3 +457,408 @@ func TestCreateShareLink_WithMessage(t *testing.T) {
 		t.Fatalf("expected message 'For Grandma', got %v", share.Message)
 	}
 }
+
+func TestCreateShareLink_WithRecipients(t *testing.T) {
+	h, q, cleanup := setupTestHandlerForShare(t)
+	defer cleanup()
+	ctx := context.Background()
+
+	album := testutil.CreateTestAlbum(t, q, "Reunion", "")
+
+	vals := url.Values{}
+	vals.Set("target_type", "album")
+	vals.Set("target_id", strconv.FormatInt(album.ID, 10))
+	vals.Set("recipients", "Aunt Maria\n\n  Uncle Joe \naunt maria\n")
+	req := httptest.NewRequest("POST", "/admin/shares", strings.NewReader(vals.Encode()))
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	w := httptest.NewRecorder()
+	h.CreateShareLink(w, req)
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected status 200, got %d: %s", w.Code, w.Body.String())
+	}
+
+	shares, err := q.ListShareLinks(ctx, sqlc.ListShareLinksParams{Limit: 10})
+	if err != nil || len(shares) != 1 {
+		t.Fatalf("expected 1 share link, got %d (%v)", len(shares), err)
+	}
+	link := shares[0]
+	recipients, err := q.ListShareLinkRecipients(ctx, link.ID)
+	if err != nil {
+		t.Fatalf("ListShareLinkRecipients: %v", err)
+	}
+	if len(recipients) != 2 || recipients[0].Name != "Aunt Maria" || recipients[1].Name != "Uncle Joe" {
+		t.Fatalf("expected Aunt Maria and Uncle Joe, got %+v", recipients)
+	}
+	maria, joe := recipients[0], recipients[1]
+	if !strings.Contains(w.Body.String(), "/s/"+maria.Token) || strings.Contains(w.Body.String(), "/s/"+link.Token) {
+		t.Errorf("expected the new row to list the recipients' links instead of the link's own")
+	}
+
+	// Only the recipients' tokens open the link
+	if w := getShare(h.ViewShareLink, link.Token, "", withHeader("User-Agent", browserUA), fromIP("203.0.113.1")); w.Code != http.StatusNotFound {
+		t.Errorf("expected 404 for the link's own token, got %d", w.Code)
+	}
+	if w := getShare(h.ViewShareLink, maria.Token, "", withHeader("User-Agent", browserUA), fromIP("203.0.113.2")); w.Code != http.StatusOK || !strings.Contains(w.Body.String(), "Reunion") {
+		t.Fatalf("expected Maria to see the album, got %d", w.Code)
+	}
+
+	// Revoking Maria leaves Joe's link working
+	del := httptest.NewRequest("DELETE", "/admin/shares/1/recipients/1", nil)
+	rc := chi.NewRouteContext()
+	rc.URLParams.Add("id", strconv.FormatInt(link.ID, 10))
+	rc.URLParams.Add("recipientID", strconv.FormatInt(maria.ID, 10))
+	del = del.WithContext(context.WithValue(del.Context(), chi.RouteCtxKey, rc))
+	w = httptest.NewRecorder()
+	h.RevokeShareLinkRecipient(w, del)
+	if w.Code != http.StatusNoContent {
+		t.Fatalf("expected status 204, got %d", w.Code)
+	}
+	w = httptest.NewRecorder()
+	h.RevokeShareLinkRecipient(w, del)
+	if w.Code != http.StatusNotFound {
+		t.Errorf("expected 404 revoking twice, got %d", w.Code)
+	}
+
+	if w := getShare(h.ViewShareLink, maria.Token, "", withHeader("User-Agent", browserUA), fromIP("203.0.113.2")); w.Code != http.StatusGone {
+		t.Errorf("expected 410 for the revoked recipient, got %d", w.Code)
+	}
+	if w := getShare(h.ViewShareLink, joe.Token, "", withHeader("User-Agent", browserUA), fromIP("203.0.113.3")); w.Code != http.StatusOK {
+		t.Errorf("expected Joe to still see the album, got %d", w.Code)
+	}
+
+	// Views are tracked per recipient and listed on the shares page
+	recipients, _ = q.ListShareLinkRecipients(ctx, link.ID)
+	if recipients[0].Views != 1 || !recipients[0].RevokedAt.Valid || recipients[1].Views != 1 {
+		t.Errorf("expected one view each and Maria revoked, got %+v", recipients)
+	}
+	w = httptest.NewRecorder()
+	h.ListShareLinks(w, httptest.NewRequest("GET", "/admin/shares", nil))
+	body := w.Body.String()
+	for _, want := range []string{"Aunt Maria", "Uncle Joe", "/s/" + joe.Token, "Revoked"} {
+		if !strings.Contains(body, want) {
+			t.Errorf("expected %q on the shares page", want)
+		}
+	}
+}
+
+func TestCreateShareLink_TooManyRecipients(t *testing.T) {
+	h, q, cleanup := setupTestHandlerForShare(t)
+	defer cleanup()
+
+	album := testutil.CreateTestAlbum(t, q, "Reunion", "")
+	var names []string
+	for i := 0; i < 51; i++ {
+		names = append(names, fmt.Sprintf("Cousin %d", i))
+	}
+
+	vals := url.Values{}
+	vals.Set("target_type", "album")
+	vals.Set("target_id", strconv.FormatInt(album.ID, 10))
+	vals.Set("recipients", strings.Join(names, "\n"))
+	req := httptest.NewRequest("POST", "/admin/shares", strings.NewReader(vals.Encode()))
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	w := httptest.NewRecorder()
+	h.CreateShareLink(w, req)
+	if w.Code != http.StatusBadRequest {
+		t.Errorf("expected status 400, got %d", w.Code)
+	}
+}
+
+func shareAdminRequest(method, path, id string, form url.Values) *http.Request {
+	req := httptest.NewRequest(method, path, strings.NewReader(form.Encode()))
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.Header.Set("HX-Request", "true")
+	rc := chi.NewRouteContext()
+	rc.URLParams.Add("id", id)
+	return req.WithContext(context.WithValue(req.Context(), chi.RouteCtxKey, rc))
+}
+
+func TestUpdateShareLink_ExtendsExpiredLink(t *testing.T) {
+	h, q, cleanup := setupTestHandlerForShare(t)
+	defer cleanup()
+	ctx := context.Background()
+
+	album := testutil.CreateTestAlbum(t, q, "Wedding", "")
+	link := testutil.CreateTestShareLink(t, q, album.ID, "expired-token", 0, time.Now().UTC().Add(-time.Hour))
+	id := strconv.FormatInt(link.ID, 10)
+
+	if w := getShare(h.ViewShareLink, link.Token, "", withHeader("User-Agent", browserUA), fromIP("203.0.113.1")); w.Code != http.StatusGone {
+		t.Fatalf("expected 410 for the expired link, got %d", w.Code)
+	}
+
+	w := httptest.NewRecorder()
+	h.EditShareLinkForm(w, shareAdminRequest("GET", "/admin/shares/"+id+"/edit", id, nil))
+	if w.Code != http.StatusOK || !strings.Contains(w.Body.String(), `name="expires_at"`) {
+		t.Fatalf("expected the edit form, got %d", w.Code)
+	}
+
+	expires := time.Now().UTC().Add(48 * time.Hour).Format("2006-01-02T15:04")
+	w = httptest.NewRecorder()
+	h.UpdateShareLink(w, shareAdminRequest("PUT", "/admin/shares/"+id, id, url.Values{
+		"max_views":  {"5"},
+		"expires_at": {expires},
+		"message":    {"For the cousins"},
+	}))
+	if w.Code != http.StatusNoContent || w.Header().Get("HX-Refresh") != "true" {
+		t.Fatalf("expected 204 with a refresh, got %d", w.Code)
+	}
+
+	updated, err := q.GetShareLink(ctx, link.ID)
+	if err != nil {
+		t.Fatalf("GetShareLink: %v", err)
+	}
+	if updated.MaxViews.Int64 != 5 || updated.ExpiresAt.Time.UTC().Format("2006-01-02T15:04") != expires || updated.Message.String != "For the cousins" {
+		t.Errorf("expected the new settings, got %+v", updated)
+	}
+	if w := getShare(h.ViewShareLink, link.Token, "", withHeader("User-Agent", browserUA), fromIP("203.0.113.1")); w.Code != http.StatusOK {
+		t.Errorf("expected the extended link to work, got %d", w.Code)
+	}
+
+	// Clearing the fields removes the limits
+	w = httptest.NewRecorder()
+	h.UpdateShareLink(w, shareAdminRequest("PUT", "/admin/shares/"+id, id, url.Values{}))
+	if updated, _ := q.GetShareLink(ctx, link.ID); updated.MaxViews.Valid || updated.ExpiresAt.Valid || updated.Message.Valid {
+		t.Errorf("expected no limits, got %+v", updated)
+	}
+
+	w = httptest.NewRecorder()
+	h.UpdateShareLink(w, shareAdminRequest("PUT", "/admin/shares/"+id, id, url.Values{"max_views": {"0"}}))
+	if w.Code != http.StatusBadRequest {
+		t.Errorf("expected 400 for an invalid view limit, got %d", w.Code)
+	}
+	w = httptest.NewRecorder()
+	h.UpdateShareLink(w, shareAdminRequest("PUT", "/admin/shares/9999", "9999", url.Values{}))
+	if w.Code != http.StatusNotFound {
+		t.Errorf("expected 404 for a missing link, got %d", w.Code)
+	}
+}
+
+func TestReactivateShareLink(t *testing.T) {
+	h, q, cleanup := setupTestHandlerForShare(t)
+	defer cleanup()
+	ctx := context.Background()
+
+	album := testutil.CreateTestAlbum(t, q, "Wedding", "")
+	link := testutil.CreateTestShareLink(t, q, album.ID, "revoked-token", 0, time.Time{})
+	id := strconv.FormatInt(link.ID, 10)
+
+	w := httptest.NewRecorder()
+	h.ReactivateShareLink(w, shareAdminRequest("POST", "/admin/shares/"+id+"/reactivate", id, nil))
+	if w.Code != http.StatusNotFound {
+		t.Errorf("expected 404 for a link that isn't revoked, got %d", w.Code)
+	}
+
+	if err := q.RevokeShareLink(ctx, link.ID); err != nil {
+		t.Fatalf("RevokeShareLink: %v", err)
+	}
+	w = httptest.NewRecorder()
+	h.ReactivateShareLink(w, shareAdminRequest("POST", "/admin/shares/"+id+"/reactivate", id, nil))
+	if w.Code != http.StatusNoContent {
+		t.Fatalf("expected 204, got %d", w.Code)
+	}
+	if w := getShare(h.ViewShareLink, link.Token, "", withHeader("User-Agent", browserUA), fromIP("203.0.113.1")); w.Code != http.StatusOK {
+		t.Errorf("expected the reactivated link to work, got %d", w.Code)
+	}
+}
+
+func TestResetShareLinkViews(t *testing.T) {
+	h, q, cleanup := setupTestHandlerForShare(t)
+	defer cleanup()
+	ctx := context.Background()
+
+	album := testutil.CreateTestAlbum(t, q, "Wedding", "")
+	link := testutil.CreateTestShareLink(t, q, album.ID, "one-view-token", 1, time.Time{})
+	id := strconv.FormatInt(link.ID, 10)
+
+	getShare(h.ViewShareLink, link.Token, "", withHeader("User-Agent", browserUA), fromIP("203.0.113.1"))
+	if w := getShare(h.ViewShareLink, link.Token, "", withHeader("User-Agent", browserUA), fromIP("203.0.113.2")); w.Code != http.StatusGone {
+		t.Fatalf("expected the view limit to be reached, got %d", w.Code)
+	}
+
+	w := httptest.NewRecorder()
+	h.ResetShareLinkViews(w, shareAdminRequest("POST", "/admin/shares/"+id+"/reset-views", id, nil))
+	if w.Code != http.StatusNoContent {
+		t.Fatalf("expected 204, got %d", w.Code)
+	}
+	if views, _ := q.CountUniqueShareLinkViews(ctx, link.ID); views != 0 {
+		t.Errorf("expected no views after the reset, got %d", views)
+	}
+	if w := getShare(h.ViewShareLink, link.Token, "", withHeader("User-Agent", browserUA), fromIP("203.0.113.2")); w.Code != http.StatusOK {
+		t.Errorf("expected a new viewer to get in after the reset, got %d", w.Code)
+	}
+
+	w = httptest.NewRecorder()
+	h.ResetShareLinkViews(w, shareAdminRequest("POST", "/admin/shares/9999/reset-views", "9999", nil))
+	if w.Code != http.StatusNotFound {
+		t.Errorf("expected 404 for a missing link, got %d", w.Code)
+	}
+}
+
+func TestCreateShareLink_WithSlug(t *testing.T) {
+	h, q, cleanup := setupTestHandlerForShare(t)
+	defer cleanup()
+	ctx := context.Background()
+
+	album := testutil.CreateTestAlbum(t, q, "Reunion", "")
+	photo := testutil.CreateTestPhoto(t, q, album.ID, "group.webp")
+	create := func(form url.Values) *httptest.ResponseRecorder {
+		form.Set("target_type", "album")
+		form.Set("target_id", strconv.FormatInt(album.ID, 10))
+		req := httptest.NewRequest("POST", "/admin/shares", strings.NewReader(form.Encode()))
+		req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+		w := httptest.NewRecorder()
+		h.CreateShareLink(w, req)
+		return w
+	}
+
+	if w := create(url.Values{"slug": {" Reunion-2025 "}}); w.Code != http.StatusOK {
+		t.Fatalf("expected status 200, got %d: %s", w.Code, w.Body.String())
+	}
+	link, err := q.GetShareLinkBySlug(ctx, sql.NullString{String: "reunion-2025", Valid: true})
+	if err != nil {
+		t.Fatalf("GetShareLinkBySlug: %v", err)
+	}
+
+	// The slug opens the link and its pages link back through it
+	w := getShare(h.ViewShareLink, "reunion-2025", "", withHeader("User-Agent", browserUA), fromIP("203.0.113.1"))
+	if w.Code != http.StatusOK || !strings.Contains(w.Body.String(), fmt.Sprintf("/s/reunion-2025/photos/%d.webp", photo.ID)) {
+		t.Fatalf("expected the album under the slug, got %d", w.Code)
+	}
+	if w := getShare(h.ViewShareLink, link.Token, "", withHeader("User-Agent", browserUA), fromIP("203.0.113.2")); w.Code != http.StatusOK {
+		t.Errorf("expected the token to keep working, got %d", w.Code)
+	}
+	if views, _ := q.CountUniqueShareLinkViews(ctx, link.ID); views != 2 {
+		t.Errorf("expected both views to count for the link, got %d", views)
+	}
+	// A viewer opening the link by its slug and its token counts once
+	if w := getShare(h.ViewShareLink, link.Token, "", withHeader("User-Agent", browserUA), fromIP("203.0.113.1")); w.Code != http.StatusOK {
+		t.Errorf("expected the token to open the link, got %d", w.Code)
+	}
+	if views, _ := q.CountUniqueShareLinkViews(ctx, link.ID); views != 2 {
+		t.Errorf("expected the same viewer to count once, got %d", views)
+	}
+
+	for _, tc := range []struct {
+		form url.Values
+		code int
+	}{
+		{url.Values{"slug": {"reunion-2025"}}, http.StatusConflict},
+		{url.Values{"slug": {"admin"}}, http.StatusBadRequest},
+		{url.Values{"slug": {"family reunion"}}, http.StatusBadRequest},
+		{url.Values{"slug": {"cousins"}, "recipients": {"Aunt Maria"}}, http.StatusBadRequest},
+	} {
+		if w := create(tc.form); w.Code != tc.code {
+			t.Errorf("%v: expected %d, got %d", tc.form, tc.code, w.Code)
+		}
+	}
+
+	// The slug can be changed and removed
+	id := strconv.FormatInt(link.ID, 10)
+	w = httptest.NewRecorder()
+	h.UpdateShareLink(w, shareAdminRequest("PUT", "/admin/shares/"+id, id, url.Values{"slug": {"reunion"}}))
+	if w.Code != http.StatusNoContent {
+		t.Fatalf("expected 204, got %d: %s", w.Code, w.Body.String())
+	}
+	if w := getShare(h.ViewShareLink, "reunion-2025", "", withHeader("User-Agent", browserUA), fromIP("203.0.113.3")); w.Code != http.StatusNotFound {
+		t.Errorf("expected the old slug to stop working, got %d", w.Code)
+	}
+	if w := getShare(h.ViewShareLink, "reunion", "", withHeader("User-Agent", browserUA), fromIP("203.0.113.3")); w.Code != http.StatusOK {
+		t.Errorf("expected the new slug to work, got %d", w.Code)
+	}
+	w = httptest.NewRecorder()
+	h.UpdateShareLink(w, shareAdminRequest("PUT", "/admin/shares/"+id, id, url.Values{}))
+	if updated, _ := q.GetShareLink(ctx, link.ID); updated.Slug.Valid {
+		t.Errorf("expected the slug removed, got %q", updated.Slug.String)
+	}
+}
+
+func TestShareLinkQRCode(t *testing.T) {
+	h, q, cleanup := setupTestHandlerForShare(t)
+	defer cleanup()
+	ctx := context.Background()
+
+	album := testutil.CreateTestAlbum(t, q, "Reunion", "")
+	link := testutil.CreateTestShareLink(t, q, album.ID, "qr-token", 0, time.Time{})
+	id := strconv.FormatInt(link.ID, 10)
+	qr := func(id, query string) *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		h.ShareLinkQRCode(w, shareAdminRequest("GET", "/admin/shares/"+id+"/qr?"+query, id, nil))
+		return w
+	}
+
+	w := qr(id, "")
+	if w.Code != http.StatusOK || w.Header().Get("Content-Type") != "image/png" {
+		t.Fatalf("expected a PNG, got %d %q", w.Code, w.Header().Get("Content-Type"))
+	}
+	if _, err := png.Decode(w.Body); err != nil {
+		t.Errorf("expected a valid PNG: %v", err)
+	}
+	w = qr(id, "format=svg")
+	if w.Header().Get("Content-Type") != "image/svg+xml" || !strings.HasPrefix(w.Body.String(), "<svg ") {
+		t.Errorf("expected an SVG, got %q", w.Header().Get("Content-Type"))
+	}
+	if w := qr(id, "format=gif"); w.Code != http.StatusBadRequest {
+		t.Errorf("expected 400 for an unknown format, got %d", w.Code)
+	}
+	if w := qr("9999", ""); w.Code != http.StatusNotFound {
+		t.Errorf("expected 404 for a missing link, got %d", w.Code)
+	}
+
+	// Links with recipients have a code per recipient
+	recipient, err := q.CreateShareLinkRecipient(ctx, sqlc.CreateShareLinkRecipientParams{ShareLinkID: link.ID, Name: "Aunt Maria", Token: "maria-token"})
+	if err != nil {
+		t.Fatalf("CreateShareLinkRecipient: %v", err)
+	}
+	if w := qr(id, ""); w.Code != http.StatusBadRequest {
+		t.Errorf("expected 400 without a recipient, got %d", w.Code)
+	}
+	if w := qr(id, "recipient="+strconv.FormatInt(recipient.ID, 10)); w.Code != http.StatusOK {
+		t.Errorf("expected the recipient's code, got %d", w.Code)
+	}
+	if w := qr(id, "recipient=9999"); w.Code != http.StatusNotFound {
+		t.Errorf("expected 404 for another link's recipient, got %d", w.Code)
+	}
+}
+
+func TestShareLinks_InstanceTimezone(t *testing.T) {
+	dbConn, q, cleanup := testutil.SetupTestDB(t)
+	defer cleanup()
+	newYork, err := time.LoadLocation("America/New_York")
+	if err != nil {
+		t.Fatalf("LoadLocation: %v", err)
+	}
+	cfg := &config.Config{RateLimitShare: 60, RateLimitAdmin: 10, Timezone: newYork}
+	h := handler.New(dbConn, storage.New(t.TempDir()), web.EmbedFS, cfg, nil)
+	album := testutil.CreateTestAlbum(t, q, "Fourth of July", "")
+
+	// The admin enters the expiry in the instance's timezone
+	expires := time.Now().In(newYork).AddDate(0, 0, 3).Add(time.Hour)
+	form := url.Values{}
+	form.Set("target_type", "album")
+	form.Set("target_id", strconv.FormatInt(album.ID, 10))
+	form.Set("expires_at", expires.Format("2006-01-02T15:04"))
+	req := httptest.NewRequest("POST", "/admin/shares", strings.NewReader(form.Encode()))
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	w := httptest.NewRecorder()
+	h.CreateShareLink(w, req)
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected status 200, got %d: %s", w.Code, w.Body.String())
+	}
+
+	links, err := q.ListShareLinks(context.Background(), sqlc.ListShareLinksParams{Limit: 10})
+	if err != nil || len(links) != 1 {
+		t.Fatalf("expected 1 share link, got %d (%v)", len(links), err)
+	}
+	if want := expires.Truncate(time.Minute); !links[0].ExpiresAt.Time.Equal(want) {
+		t.Errorf("expected expiry %v, got %v", want.UTC(), links[0].ExpiresAt.Time.UTC())
+	}
+
+	w = httptest.NewRecorder()
+	h.ListShareLinks(w, httptest.NewRequest("GET", "/admin/shares", nil))
+	body := w.Body.String()
+	for _, want := range []string{expires.Format("Jan 02, 2006 15:04") + " America/New_York", "(in 3 days)"} {
+		if !strings.Contains(body, want) {
+			t.Errorf("expected %q on the shares page", want)
+		}
+	}
+}
@@ -0,0 +1,289 @@
+package handler
+
+import (
+	"context"
+	"database/sql"
+	"log"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/go-chi/chi/v5"
+
+	"familyshare/internal/db/sqlc"
+)
+
+// maxTagNameLength bounds tag names so they stay readable in chips and links.
+const maxTagNameLength = 50
+
+// photoCard is the data rendered by the admin "photo_card" template.
+type photoCard struct {
+	sqlc.Photo
+	Tags          []sqlc.Tag
+	Reactions     []reactionCount
+	ReactionTotal int64
+}
+
+// albumPhotoCards attaches tags and reaction counts to each photo of an album,
+// with one query for each.
+func albumPhotoCards(ctx context.Context, q *sqlc.Queries, albumID int64, photos []sqlc.Photo) []photoCard {
+	tagsByPhoto := map[int64][]sqlc.Tag{}
+	rows, err := q.ListTagsForAlbumPhotos(ctx, albumID)
+	if err != nil {
+		log.Printf("failed to load tags for album %d: %v", albumID, err)
+	}
+	for _, row := range rows {
+		tagsByPhoto[row.PhotoID] = append(tagsByPhoto[row.PhotoID], sqlc.Tag{ID: row.TagID, Name: row.Name})
+	}
+
+	tally := reactionTally{}
+	counts, err := q.ListReactionCountsForAlbum(ctx, albumID)
+	if err != nil {
+		log.Printf("failed to load reactions for album %d: %v", albumID, err)
+	}
+	for _, c := range counts {
+		tally.add(c.PhotoID, c.Reaction, c.Count)
+	}
+
+	cards := make([]photoCard, 0, len(photos))
+	for _, p := range photos {
+		cards = append(cards, photoCard{
+			Photo:         p,
+			Tags:          tagsByPhoto[p.ID],
+			Reactions:     tally.summary(p.ID),
+			ReactionTotal: tally.total(p.ID),
+		})
+	}
+	return cards
+}
+
+// singlePhotoCard builds the card of one photo, e.g. after an inline edit.
+func singlePhotoCard(ctx context.Context, q *sqlc.Queries, photo sqlc.Photo) photoCard {
+	tags, err := q.ListTagsForPhoto(ctx, photo.ID)
+	if err != nil {
+		log.Printf("failed to load tags for photo %d: %v", photo.ID, err)
+	}
+	tally := photoReactionTally(ctx, q, photo.ID)
+	return photoCard{
+		Photo:         photo,
+		Tags:          tags,
+		Reactions:     tally.summary(photo.ID),
+		ReactionTotal: tally.total(photo.ID),
+	}
+}
+
+// mostLovedPhotos returns up to limit photos with reactions, most reacted first.
+func mostLovedPhotos(cards []photoCard, limit int) []photoCard {
+	var loved []photoCard
+	for _, c := range cards {
+		if c.ReactionTotal > 0 {
+			loved = append(loved, c)
+		}
+	}
+	sort.SliceStable(loved, func(i, j int) bool { return loved[i].ReactionTotal > loved[j].ReactionTotal })
+	if len(loved) > limit {
+		loved = loved[:limit]
+	}
+	return loved
+}
+
+// parseTagNames splits a comma separated list of tag names, trimming blanks
+// and dropping duplicates (case-insensitive, matching the tags.name collation).
+func parseTagNames(raw string) []string {
+	var names []string
+	seen := map[string]bool{}
+	for _, part := range strings.Split(raw, ",") {
+		name := strings.Join(strings.Fields(part), " ")
+		if name == "" {
+			continue
+		}
+		key := strings.ToLower(name)
+		if seen[key] {
+			continue
+		}
+		seen[key] = true
+		names = append(names, name)
+	}
+	return names
+}
+
+// ListPhotos handles GET /admin/photos with an optional ?tag={id} filter
+func (h *Handler) ListPhotos(w http.ResponseWriter, r *http.Request) {
+	q := sqlc.New(h.db)
+
+	var selectedTag sqlc.Tag
+	if tagStr := r.URL.Query().Get("tag"); tagStr != "" {
+		tagID, err := strconv.ParseInt(tagStr, 10, 64)
+		if err != nil {
+			http.Error(w, "invalid tag", http.StatusBadRequest)
+			return
+		}
+		selectedTag, err = q.GetTag(r.Context(), tagID)
+		if err != nil {
+			http.Error(w, "tag not found", http.StatusNotFound)
+			return
+		}
+	}
+
+	var photos []sqlc.ListAllPhotosWithAlbumRow
+	var err error
+	if selectedTag.ID != 0 {
+		var tagged []sqlc.ListAllPhotosWithAlbumByTagRow
+		tagged, err = q.ListAllPhotosWithAlbumByTag(r.Context(), sqlc.ListAllPhotosWithAlbumByTagParams{
+			TagID:  selectedTag.ID,
+			Limit:  200,
+			Offset: 0,
+		})
+		for _, p := range tagged {
+			photos = append(photos, sqlc.ListAllPhotosWithAlbumRow(p))
+		}
+	} else {
+		photos, err = q.ListAllPhotosWithAlbum(r.Context(), sqlc.ListAllPhotosWithAlbumParams{Limit: 200, Offset: 0})
+	}
+	if err != nil {
+		log.Printf("failed to list photos: %v", err)
+		http.Error(w, "failed to list photos", http.StatusInternalServerError)
+		return
+	}
+
+	tags, err := q.ListTagsWithPhotoCount(r.Context())
+	if err != nil {
+		log.Printf("failed to list tags: %v", err)
+		tags = []sqlc.ListTagsWithPhotoCountRow{}
+	}
+
+	data := struct {
+		Photos      []sqlc.ListAllPhotosWithAlbumRow
+		Tags        []sqlc.ListTagsWithPhotoCountRow
+		SelectedTag sqlc.Tag
+	}{
+		Photos:      photos,
+		Tags:        tags,
+		SelectedTag: selectedTag,
+	}
+
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	if err := h.RenderTemplate(w, "photos_list.html", data); err != nil {
+		log.Printf("template render error for photos_list: %v", err)
+		http.Error(w, "template render error", http.StatusInternalServerError)
+	}
+}
+
+// BulkTagPhotos handles POST /admin/photos/tags
+// Form fields: photo_ids (repeated), tags (comma separated names), action (add|remove).
+func (h *Handler) BulkTagPhotos(w http.ResponseWriter, r *http.Request) {
+	if err := r.ParseForm(); err != nil {
+		http.Error(w, "invalid form", http.StatusBadRequest)
+		return
+	}
+
+	action := r.PostFormValue("action")
+	if action == "" {
+		action = "add"
+	}
+	if action != "add" && action != "remove" {
+		http.Error(w, "invalid action", http.StatusBadRequest)
+		return
+	}
+
+	photoIDs, err := parsePhotoIDs(r)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	names := parseTagNames(r.PostFormValue("tags"))
+	if len(names) == 0 {
+		http.Error(w, "tag name is required", http.StatusBadRequest)
+		return
+	}
+	for _, name := range names {
+		if utf8.RuneCountInString(name) > maxTagNameLength {
+			http.Error(w, "tag name is too long", http.StatusBadRequest)
+			return
+		}
+	}
+
+	tx, err := h.db.BeginTx(r.Context(), nil)
+	if err != nil {
+		log.Printf("failed to begin tag transaction: %v", err)
+		http.Error(w, "failed to tag photos", http.StatusInternalServerError)
+		return
+	}
+	defer tx.Rollback()
+
+	q := sqlc.New(tx)
+
+	for _, id := range photoIDs {
+		if _, err := q.GetPhoto(r.Context(), id); err != nil {
+			http.Error(w, "photo not found", http.StatusNotFound)
+			return
+		}
+	}
+
+	for _, name := range names {
+		tag, err := q.GetTagByName(r.Context(), name)
+		if err == sql.ErrNoRows {
+			if action == "remove" {
+				continue
+			}
+			tag, err = q.CreateTag(r.Context(), name)
+		}
+		if err != nil {
+			log.Printf("failed to resolve tag %q: %v", name, err)
+			http.Error(w, "failed to tag photos", http.StatusInternalServerError)
+			return
+		}
+
+		for _, id := range photoIDs {
+			if action == "add" {
+				err = q.AddPhotoTag(r.Context(), sqlc.AddPhotoTagParams{PhotoID: id, TagID: tag.ID})
+			} else {
+				err = q.RemovePhotoTag(r.Context(), sqlc.RemovePhotoTagParams{PhotoID: id, TagID: tag.ID})
+			}
+			if err != nil {
+				log.Printf("failed to %s tag %d on photo %d: %v", action, tag.ID, id, err)
+				http.Error(w, "failed to tag photos", http.StatusInternalServerError)
+				return
+			}
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		log.Printf("failed to commit tag transaction: %v", err)
+		http.Error(w, "failed to tag photos", http.StatusInternalServerError)
+		return
+	}
+
+	// Tag chips are rendered on several cards; a refresh keeps them consistent
+	w.Header().Set("HX-Refresh", "true")
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// DeleteTag handles DELETE /admin/tags/{id}
+func (h *Handler) DeleteTag(w http.ResponseWriter, r *http.Request) {
+	idStr := chi.URLParam(r, "id")
+	id, err := strconv.ParseInt(idStr, 10, 64)
+	if err != nil {
+		http.Error(w, "invalid id", http.StatusBadRequest)
+		return
+	}
+
+	q := sqlc.New(h.db)
+
+	if _, err := q.GetTag(r.Context(), id); err != nil {
+		http.Error(w, "tag not found", http.StatusNotFound)
+		return
+	}
+
+	// photo_tags rows are removed by ON DELETE CASCADE
+	if err := q.DeleteTag(r.Context(), id); err != nil {
+		log.Printf("failed to delete tag: %v", err)
+		http.Error(w, "failed to delete tag", http.StatusInternalServerError)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
@@ -0,0 +1,337 @@
+package handler_test
+
+import (
+	"context"
+	"database/sql"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+
+	"familyshare/internal/config"
+	"familyshare/internal/db/sqlc"
+	"familyshare/internal/handler"
+	"familyshare/internal/storage"
+	"familyshare/internal/testutil"
+	"familyshare/web"
+)
+
+func setupTagTest(t *testing.T) (*handler.Handler, *sqlc.Queries, string) {
+	t.Helper()
+	dbConn, q, dbCleanup := testutil.SetupTestDB(t)
+	t.Cleanup(dbCleanup)
+
+	storageDir, storageCleanup := testutil.SetupTestStorage(t)
+	t.Cleanup(storageCleanup)
+
+	h := handler.New(dbConn, storage.New(storageDir), web.EmbedFS, &config.Config{RateLimitShare: 100000, RateLimitAdmin: 10}, nil)
+	return h, q, storageDir
+}
+
+func postBulkTag(h *handler.Handler, action, tags string, photoIDs ...int64) *httptest.ResponseRecorder {
+	vals := url.Values{}
+	vals.Set("action", action)
+	vals.Set("tags", tags)
+	for _, id := range photoIDs {
+		vals.Add("photo_ids", strconv.FormatInt(id, 10))
+	}
+	req := httptest.NewRequest("POST", "/admin/photos/tags", strings.NewReader(vals.Encode()))
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	w := httptest.NewRecorder()
+	h.BulkTagPhotos(w, req)
+	return w
+}
+
+func TestBulkTagPhotos_AddAndRemove(t *testing.T) {
+	h, q, _ := setupTagTest(t)
+	ctx := context.Background()
+
+	album := testutil.CreateTestAlbum(t, q, "Birthday", "")
+	p1 := testutil.CreateTestPhoto(t, q, album.ID, "one.webp")
+	p2 := testutil.CreateTestPhoto(t, q, album.ID, "two.webp")
+
+	w := postBulkTag(h, "add", "Grandma, beach, grandma", p1.ID, p2.ID)
+	if w.Code != http.StatusNoContent {
+		t.Fatalf("expected 204, got %d: %s", w.Code, w.Body.String())
+	}
+
+	tags, err := q.ListTags(ctx)
+	if err != nil {
+		t.Fatalf("ListTags: %v", err)
+	}
+	if len(tags) != 2 {
+		t.Fatalf("expected 2 tags (duplicates folded), got %d", len(tags))
+	}
+
+	for _, p := range []int64{p1.ID, p2.ID} {
+		photoTags, _ := q.ListTagsForPhoto(ctx, p)
+		if len(photoTags) != 2 {
+			t.Fatalf("expected photo %d to have 2 tags, got %d", p, len(photoTags))
+		}
+	}
+
+	// Re-adding is idempotent and matching is case-insensitive
+	if w := postBulkTag(h, "add", "GRANDMA", p1.ID); w.Code != http.StatusNoContent {
+		t.Fatalf("expected 204 on re-add, got %d", w.Code)
+	}
+	if tags, _ := q.ListTags(ctx); len(tags) != 2 {
+		t.Fatalf("expected no new tag on case-insensitive re-add, got %d tags", len(tags))
+	}
+
+	if w := postBulkTag(h, "remove", "beach", p1.ID); w.Code != http.StatusNoContent {
+		t.Fatalf("expected 204 on remove, got %d", w.Code)
+	}
+	photoTags, _ := q.ListTagsForPhoto(ctx, p1.ID)
+	if len(photoTags) != 1 || photoTags[0].Name != "Grandma" {
+		t.Fatalf("expected only Grandma on photo 1, got %+v", photoTags)
+	}
+}
+
+func TestBulkTagPhotos_Validation(t *testing.T) {
+	h, q, _ := setupTagTest(t)
+
+	album := testutil.CreateTestAlbum(t, q, "Album", "")
+	photo := testutil.CreateTestPhoto(t, q, album.ID, "a.webp")
+
+	if w := postBulkTag(h, "add", "Grandma"); w.Code != http.StatusBadRequest {
+		t.Fatalf("expected 400 with no photos, got %d", w.Code)
+	}
+	if w := postBulkTag(h, "add", " , ", photo.ID); w.Code != http.StatusBadRequest {
+		t.Fatalf("expected 400 with blank tag, got %d", w.Code)
+	}
+	if w := postBulkTag(h, "rename", "Grandma", photo.ID); w.Code != http.StatusBadRequest {
+		t.Fatalf("expected 400 with invalid action, got %d", w.Code)
+	}
+	if w := postBulkTag(h, "add", "Grandma", photo.ID, 9999); w.Code != http.StatusNotFound {
+		t.Fatalf("expected 404 for unknown photo, got %d", w.Code)
+	}
+
+	// Nothing should have been written by the failed request
+	if tags, _ := q.ListTags(context.Background()); len(tags) != 0 {
+		t.Fatalf("expected rollback to leave no tags, got %d", len(tags))
+	}
+
+	// Names are limited in characters, not bytes
+	if w := postBulkTag(h, "add", strings.Repeat("a", 51), photo.ID); w.Code != http.StatusBadRequest {
+		t.Errorf("expected 400 for a tag name over 50 characters, got %d", w.Code)
+	}
+	if w := postBulkTag(h, "add", strings.Repeat("ã", 50), photo.ID); w.Code != http.StatusNoContent {
+		t.Errorf("expected 204 for a tag name of 50 accented characters, got %d", w.Code)
+	}
+}
+
+func TestListPhotos_FilterByTag(t *testing.T) {
+	h, q, _ := setupTagTest(t)
+	ctx := context.Background()
+
+	a1 := testutil.CreateTestAlbum(t, q, "Summer", "")
+	a2 := testutil.CreateTestAlbum(t, q, "Winter", "")
+	tagged := testutil.CreateTestPhoto(t, q, a1.ID, "tagged-summer.webp")
+	taggedToo := testutil.CreateTestPhoto(t, q, a2.ID, "tagged-winter.webp")
+	testutil.CreateTestPhoto(t, q, a1.ID, "untagged.webp")
+
+	tag, err := q.CreateTag(ctx, "Grandma")
+	if err != nil {
+		t.Fatalf("CreateTag: %v", err)
+	}
+	for _, id := range []int64{tagged.ID, taggedToo.ID} {
+		if err := q.AddPhotoTag(ctx, sqlc.AddPhotoTagParams{PhotoID: id, TagID: tag.ID}); err != nil {
+			t.Fatalf("AddPhotoTag: %v", err)
+		}
+	}
+
+	req := httptest.NewRequest("GET", "/admin/photos?tag="+strconv.FormatInt(tag.ID, 10), nil)
+	w := httptest.NewRecorder()
+	h.ListPhotos(w, req)
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d: %s", w.Code, w.Body.String())
+	}
+	body := w.Body.String()
+	if !strings.Contains(body, "tagged-summer.webp") || !strings.Contains(body, "tagged-winter.webp") {
+		t.Fatalf("expected tagged photos from both albums in body")
+	}
+	if strings.Contains(body, "untagged.webp") {
+		t.Fatalf("did not expect untagged photo in filtered list")
+	}
+
+	// Unfiltered list shows everything
+	req = httptest.NewRequest("GET", "/admin/photos", nil)
+	w = httptest.NewRecorder()
+	h.ListPhotos(w, req)
+	if !strings.Contains(w.Body.String(), "untagged.webp") {
+		t.Fatalf("expected untagged photo in unfiltered list")
+	}
+
+	// Unknown tag
+	req = httptest.NewRequest("GET", "/admin/photos?tag=9999", nil)
+	w = httptest.NewRecorder()
+	h.ListPhotos(w, req)
+	if w.Code != http.StatusNotFound {
+		t.Fatalf("expected 404 for unknown tag, got %d", w.Code)
+	}
+}
+
+func TestListTagsWithPhotoCount_SkipsTrash(t *testing.T) {
+	_, q, _ := setupTagTest(t)
+	ctx := context.Background()
+
+	kept := testutil.CreateTestAlbum(t, q, "Kept", "")
+	trashed := testutil.CreateTestAlbum(t, q, "Trashed", "")
+	tag, _ := q.CreateTag(ctx, "Grandma")
+	for _, album := range []*sqlc.Album{kept, trashed} {
+		photo := testutil.CreateTestPhoto(t, q, album.ID, "a.webp")
+		_ = q.AddPhotoTag(ctx, sqlc.AddPhotoTagParams{PhotoID: photo.ID, TagID: tag.ID})
+	}
+	if err := q.TrashAlbum(ctx, trashed.ID); err != nil {
+		t.Fatalf("TrashAlbum: %v", err)
+	}
+
+	tags, err := q.ListTagsWithPhotoCount(ctx)
+	if err != nil || len(tags) != 1 {
+		t.Fatalf("expected 1 tag, got %d (%v)", len(tags), err)
+	}
+	if tags[0].PhotoCount != 1 {
+		t.Errorf("expected photos of trashed albums not to count, got %d", tags[0].PhotoCount)
+	}
+}
+
+func TestDeleteTag_RemovesPhotoTags(t *testing.T) {
+	h, q, _ := setupTagTest(t)
+	ctx := context.Background()
+
+	album := testutil.CreateTestAlbum(t, q, "Album", "")
+	photo := testutil.CreateTestPhoto(t, q, album.ID, "a.webp")
+	tag, _ := q.CreateTag(ctx, "Grandpa")
+	_ = q.AddPhotoTag(ctx, sqlc.AddPhotoTagParams{PhotoID: photo.ID, TagID: tag.ID})
+
+	req := httptest.NewRequest("DELETE", "/admin/tags/"+strconv.FormatInt(tag.ID, 10), nil)
+	rctx := chi.NewRouteContext()
+	rctx.URLParams.Add("id", strconv.FormatInt(tag.ID, 10))
+	req = req.WithContext(context.WithValue(req.Context(), chi.RouteCtxKey, rctx))
+	w := httptest.NewRecorder()
+	h.DeleteTag(w, req)
+
+	if w.Code != http.StatusNoContent {
+		t.Fatalf("expected 204, got %d", w.Code)
+	}
+	if _, err := q.GetTag(ctx, tag.ID); err != sql.ErrNoRows {
+		t.Fatalf("expected tag to be deleted, got %v", err)
+	}
+	if n, _ := q.CountPhotoTag(ctx, sqlc.CountPhotoTagParams{PhotoID: photo.ID, TagID: tag.ID}); n != 0 {
+		t.Fatalf("expected photo_tags row to cascade, got %d", n)
+	}
+}
+
+func TestTagShareLink_ServesTaggedPhotosOnly(t *testing.T) {
+	h, q, storageDir := setupTagTest(t)
+	ctx := context.Background()
+
+	a1 := testutil.CreateTestAlbum(t, q, "Summer", "")
+	a2 := testutil.CreateTestAlbum(t, q, "Winter", "")
+	tagged := testutil.CreateTestPhoto(t, q, a1.ID, "grandma-summer.webp")
+	taggedToo := testutil.CreateTestPhoto(t, q, a2.ID, "grandma-winter.webp")
+	other := testutil.CreateTestPhoto(t, q, a1.ID, "someone-else.webp")
+
+	tag, _ := q.CreateTag(ctx, "Grandma")
+	for _, id := range []int64{tagged.ID, taggedToo.ID} {
+		_ = q.AddPhotoTag(ctx, sqlc.AddPhotoTagParams{PhotoID: id, TagID: tag.ID})
+	}
+
+	for _, p := range []*sqlc.Photo{tagged, other} {
+		path := storage.PhotoPathAt(storageDir, p.AlbumID, p.ID, "webp", p.CreatedAt.Time.UTC())
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			t.Fatalf("MkdirAll: %v", err)
+		}
+		if err := os.WriteFile(path, []byte("testdata"), 0o644); err != nil {
+			t.Fatalf("WriteFile: %v", err)
+		}
+	}
+
+	_, err := q.CreateShareLink(ctx, sqlc.CreateShareLinkParams{
+		Token:      "grandma-token",
+		TargetType: "tag",
+		TargetID:   tag.ID,
+		ExpiresAt:  sql.NullTime{Time: time.Now().UTC().Add(time.Hour), Valid: true},
+	})
+	if err != nil {
+		t.Fatalf("CreateShareLink with tag target: %v", err)
+	}
+
+	r := chi.NewRouter()
+	h.RegisterRoutes(r)
+
+	req := httptest.NewRequest("GET", "/s/grandma-token", nil)
+	req.Header.Set("X-Forwarded-For", "203.0.113.40")
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected 200 for tag share, got %d", w.Code)
+	}
+	body := w.Body.String()
+	if !strings.Contains(body, "grandma-summer.webp") || !strings.Contains(body, "grandma-winter.webp") {
+		t.Fatalf("expected tagged photos across albums in share page")
+	}
+	if strings.Contains(body, "someone-else.webp") {
+		t.Fatalf("did not expect untagged photo in share page")
+	}
+
+	req = httptest.NewRequest("GET", "/s/grandma-token/photos/"+strconv.FormatInt(tagged.ID, 10)+".webp", nil)
+	req.Header.Set("X-Forwarded-For", "203.0.113.40")
+	w = httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected 200 for tagged photo, got %d", w.Code)
+	}
+
+	req = httptest.NewRequest("GET", "/s/grandma-token/photos/"+strconv.FormatInt(other.ID, 10)+".webp", nil)
+	req.Header.Set("X-Forwarded-For", "203.0.113.40")
+	w = httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	if w.Code != http.StatusNotFound {
+		t.Fatalf("expected 404 for untagged photo, got %d", w.Code)
+	}
+}
+
+func TestCreateShareLink_TagTarget(t *testing.T) {
+	h, q, _ := setupTagTest(t)
+	ctx := context.Background()
+
+	tag, _ := q.CreateTag(ctx, "Cousins")
+
+	vals := url.Values{}
+	vals.Set("target_type", "tag")
+	vals.Set("target_id", strconv.FormatInt(tag.ID, 10))
+	req := httptest.NewRequest("POST", "/admin/shares", strings.NewReader(vals.Encode()))
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	w := httptest.NewRecorder()
+	h.CreateShareLink(w, req)
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d: %s", w.Code, w.Body.String())
+	}
+
+	shares, _ := q.ListShareLinksWithDetails(ctx, sqlc.ListShareLinksWithDetailsParams{Limit: 10, Offset: 0})
+	if len(shares) != 1 || shares[0].TargetType != "tag" {
+		t.Fatalf("expected one tag share, got %+v", shares)
+	}
+	if title, _ := shares[0].TargetTitle.(string); title != "Cousins" {
+		t.Fatalf("expected target title Cousins, got %v", shares[0].TargetTitle)
+	}
+
+	// Unknown tag is rejected
+	vals.Set("target_id", "9999")
+	req = httptest.NewRequest("POST", "/admin/shares", strings.NewReader(vals.Encode()))
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	w = httptest.NewRecorder()
+	h.CreateShareLink(w, req)
+	if w.Code != http.StatusNotFound {
+		t.Fatalf("expected 404 for unknown tag, got %d", w.Code)
+	}
+}
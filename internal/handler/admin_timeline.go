@@ -0,0 +1,213 @@
+package handler
+
+import (
+	"log"
+	"math"
+	"net/http"
+	"net/url"
+	"strconv"
+	"time"
+
+	"familyshare/internal/db/sqlc"
+)
+
+const (
+	// timelinePageSize is the photos loaded per timeline page
+	timelinePageSize = 60
+	// onThisDayLimit bounds the photos of the "on this day" panel
+	onThisDayLimit = 24
+	// timelineKeyLayout is the layout of timeline sort keys and cursors
+	timelineKeyLayout = "2006-01-02 15:04:05"
+)
+
+// timelineDay is the photos of one day on the timeline. A day split across
+// pages continues the previous page without repeating its heading.
+type timelineDay struct {
+	Date      time.Time
+	Continued bool
+	Photos    []sqlc.ListTimelinePhotosRow
+}
+
+// timelineMonth is a month of the jump navigation. Before is the cursor that
+// starts the timeline at the end of the month.
+type timelineMonth struct {
+	Month  time.Time
+	Count  int64
+	Before string
+}
+
+// timelineYear is a year of the jump navigation.
+type timelineYear struct {
+	Year   int
+	Count  int64
+	Before string
+	Months []timelineMonth
+}
+
+// onThisDayYear is the photos of today's date in an earlier year.
+type onThisDayYear struct {
+	Year     int
+	YearsAgo int
+	Photos   []sqlc.ListTimelinePhotosRow
+}
+
+// timelinePage is a page of the timeline and the URL of the next one.
+type timelinePage struct {
+	Days    []timelineDay
+	NextURL string
+}
+
+// timelineDays groups timeline photos by the day of their sort key. prevDay
+// is the last day of the previous page.
+func timelineDays(photos []sqlc.ListTimelinePhotosRow, prevDay string) []timelineDay {
+	var days []timelineDay
+	var current string
+	for _, p := range photos {
+		day := p.SortKey[:min(len(p.SortKey), 10)]
+		if len(days) == 0 || day != current {
+			date, err := time.Parse(time.DateOnly, day)
+			if err != nil {
+				log.Printf("photo %d has an unreadable timeline date %q", p.ID, p.SortKey)
+			}
+			days = append(days, timelineDay{Date: date, Continued: len(days) == 0 && day == prevDay})
+			current = day
+		}
+		days[len(days)-1].Photos = append(days[len(days)-1].Photos, p)
+	}
+	return days
+}
+
+// timelineYears builds the jump navigation from the photo counts per month
+// ("YYYY-MM"), newest first.
+func timelineYears(months []sqlc.ListTimelineMonthsRow) []timelineYear {
+	var years []timelineYear
+	for _, m := range months {
+		month, err := time.Parse("2006-01", m.Month)
+		if err != nil {
+			continue
+		}
+		if len(years) == 0 || years[len(years)-1].Year != month.Year() {
+			years = append(years, timelineYear{
+				Year:   month.Year(),
+				Before: time.Date(month.Year()+1, 1, 1, 0, 0, 0, 0, time.UTC).Format(timelineKeyLayout),
+			})
+		}
+		y := &years[len(years)-1]
+		y.Count += m.Count
+		y.Months = append(y.Months, timelineMonth{
+			Month:  month,
+			Count:  m.Count,
+			Before: month.AddDate(0, 1, 0).Format(timelineKeyLayout),
+		})
+	}
+	return years
+}
+
+// onThisDay groups the photos taken on today's date in earlier years.
+func onThisDay(photos []sqlc.ListTimelinePhotosRow, today time.Time) []onThisDayYear {
+	var years []onThisDayYear
+	for _, p := range photos {
+		year, err := strconv.Atoi(p.SortKey[:min(len(p.SortKey), 4)])
+		if err != nil {
+			continue
+		}
+		if len(years) == 0 || years[len(years)-1].Year != year {
+			years = append(years, onThisDayYear{Year: year, YearsAgo: today.Year() - year})
+		}
+		years[len(years)-1].Photos = append(years[len(years)-1].Photos, p)
+	}
+	return years
+}
+
+// AdminTimeline handles GET /admin/timeline
+// Query: before and before_id, the keyset cursor of the page (the sort key
+// and id of the last photo shown, or a date to jump to), and day, the last
+// day shown. HTMX requests for the next page get only its days.
+func (h *Handler) AdminTimeline(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+	before, beforeID := "9999-12-31 23:59:59", int64(math.MaxInt64)
+	if s := query.Get("before"); s != "" {
+		if _, err := time.Parse(timelineKeyLayout, s); err != nil {
+			http.Error(w, "invalid before", http.StatusBadRequest)
+			return
+		}
+		before, beforeID = s, 0
+	}
+	if s := query.Get("before_id"); s != "" {
+		id, err := strconv.ParseInt(s, 10, 64)
+		if err != nil {
+			http.Error(w, "invalid before_id", http.StatusBadRequest)
+			return
+		}
+		beforeID = id
+	}
+
+	q := sqlc.New(h.db)
+	photos, err := q.ListTimelinePhotos(r.Context(), sqlc.ListTimelinePhotosParams{
+		BeforeKey: before,
+		BeforeID:  beforeID,
+		Limit:     timelinePageSize + 1,
+	})
+	if err != nil {
+		log.Printf("failed to load timeline: %v", err)
+		http.Error(w, "failed to load timeline", http.StatusInternalServerError)
+		return
+	}
+
+	page := timelinePage{}
+	if len(photos) > timelinePageSize {
+		photos = photos[:timelinePageSize]
+		last := photos[len(photos)-1]
+		next := url.Values{}
+		next.Set("before", last.SortKey)
+		next.Set("before_id", strconv.FormatInt(last.ID, 10))
+		next.Set("day", last.SortKey[:min(len(last.SortKey), 10)])
+		page.NextURL = "/admin/timeline?" + next.Encode()
+	}
+	page.Days = timelineDays(photos, query.Get("day"))
+
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	if r.Header.Get("HX-Request") == "true" {
+		if err := h.RenderTemplate(w, "timeline_days.html", page); err != nil {
+			log.Printf("template render error for timeline days: %v", err)
+			http.Error(w, "template render error", http.StatusInternalServerError)
+		}
+		return
+	}
+
+	months, err := q.ListTimelineMonths(r.Context())
+	if err != nil {
+		log.Printf("failed to load timeline months: %v", err)
+	}
+	today := time.Now().In(h.tz)
+	earlier, err := q.ListOnThisDayPhotos(r.Context(), sqlc.ListOnThisDayPhotosParams{
+		MonthDay: today.Format("01-02"),
+		Year:     today.Format("2006"),
+		Limit:    onThisDayLimit,
+	})
+	if err != nil {
+		log.Printf("failed to load on this day photos: %v", err)
+	}
+	onThisDayPhotos := make([]sqlc.ListTimelinePhotosRow, 0, len(earlier))
+	for _, p := range earlier {
+		onThisDayPhotos = append(onThisDayPhotos, sqlc.ListTimelinePhotosRow(p))
+	}
+
+	data := struct {
+		timelinePage
+		Years     []timelineYear
+		OnThisDay []onThisDayYear
+		Today     time.Time
+		Jumped    bool
+	}{
+		timelinePage: page,
+		Years:        timelineYears(months),
+		OnThisDay:    onThisDay(onThisDayPhotos, today),
+		Today:        today,
+		Jumped:       query.Get("before") != "",
+	}
+	if err := h.RenderTemplate(w, "timeline.html", data); err != nil {
+		log.Printf("template render error for timeline: %v", err)
+		http.Error(w, "template render error", http.StatusInternalServerError)
+	}
+}
@@ -0,0 +1,151 @@
+package handler_test
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"html"
+	"net/http"
+	"net/http/httptest"
+	"regexp"
+	"strings"
+	"testing"
+	"time"
+
+	"familyshare/internal/db/sqlc"
+	"familyshare/internal/testutil"
+)
+
+func getTimeline(h http.HandlerFunc, target string, htmx bool) *httptest.ResponseRecorder {
+	req := httptest.NewRequest("GET", target, nil)
+	if htmx {
+		req.Header.Set("HX-Request", "true")
+	}
+	w := httptest.NewRecorder()
+	h(w, req)
+	return w
+}
+
+func takenAt(t *testing.T, q *sqlc.Queries, photoID int64, at time.Time) {
+	t.Helper()
+	err := q.UpdatePhotoTakenAt(context.Background(), sqlc.UpdatePhotoTakenAtParams{
+		TakenAt: sql.NullTime{Time: at, Valid: true},
+		ID:      photoID,
+	})
+	if err != nil {
+		t.Fatalf("UpdatePhotoTakenAt: %v", err)
+	}
+}
+
+// timelineIDs returns the ids of the photos on a timeline page in order.
+func timelineIDs(body string) []string {
+	var ids []string
+	for _, m := range regexp.MustCompile(`id="photo-(\d+)"`).FindAllStringSubmatch(body, -1) {
+		ids = append(ids, m[1])
+	}
+	return ids
+}
+
+func TestAdminTimeline(t *testing.T) {
+	h, q, _ := setupBulkTest(t)
+	ctx := context.Background()
+
+	trip := testutil.CreateTestAlbum(t, q, "Trip", "")
+	home := testutil.CreateTestAlbum(t, q, "Home", "")
+	morning := testutil.CreateTestPhoto(t, q, trip.ID, "morning.webp")
+	evening := testutil.CreateTestPhoto(t, q, home.ID, "evening.webp")
+	old := testutil.CreateTestPhoto(t, q, trip.ID, "old.webp")
+	undated := testutil.CreateTestPhoto(t, q, home.ID, "undated.webp")
+	trashed := testutil.CreateTestPhoto(t, q, home.ID, "trashed.webp")
+	takenAt(t, q, morning.ID, time.Date(2021, 7, 4, 9, 0, 0, 0, time.UTC))
+	takenAt(t, q, evening.ID, time.Date(2021, 7, 4, 20, 0, 0, 0, time.UTC))
+	takenAt(t, q, old.ID, time.Date(2019, 3, 1, 12, 0, 0, 0, time.UTC))
+	if err := q.TrashPhoto(ctx, trashed.ID); err != nil {
+		t.Fatalf("TrashPhoto: %v", err)
+	}
+
+	w := getTimeline(h.AdminTimeline, "/admin/timeline", false)
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d", w.Code)
+	}
+	body := w.Body.String()
+	// Undated photos fall back to their upload time, today
+	want := []string{fmt.Sprint(undated.ID), fmt.Sprint(evening.ID), fmt.Sprint(morning.ID), fmt.Sprint(old.ID)}
+	if got := timelineIDs(body); strings.Join(got, ",") != strings.Join(want, ",") {
+		t.Errorf("expected photos %v in timeline order, got %v", want, got)
+	}
+	for _, s := range []string{"Sunday, July 4, 2021", "Friday, March 1, 2019", "2021 (2)", "July 2021 (2)", "Trip"} {
+		if !strings.Contains(body, s) {
+			t.Errorf("expected %q on the timeline", s)
+		}
+	}
+
+	// Jumping to a year starts the timeline at its end
+	w = getTimeline(h.AdminTimeline, "/admin/timeline?before=2020-01-01+00:00:00", false)
+	if got := timelineIDs(w.Body.String()); len(got) != 1 || got[0] != fmt.Sprint(old.ID) {
+		t.Errorf("expected only the 2019 photo, got %v", got)
+	}
+
+	for _, target := range []string{"/admin/timeline?before=yesterday", "/admin/timeline?before_id=x"} {
+		if w := getTimeline(h.AdminTimeline, target, false); w.Code != http.StatusBadRequest {
+			t.Errorf("%s: expected 400, got %d", target, w.Code)
+		}
+	}
+}
+
+func TestAdminTimeline_Paging(t *testing.T) {
+	h, q, _ := setupBulkTest(t)
+
+	album := testutil.CreateTestAlbum(t, q, "Party", "")
+	day := time.Date(2022, 12, 31, 18, 0, 0, 0, time.UTC)
+	for i := range 65 {
+		p := testutil.CreateTestPhoto(t, q, album.ID, fmt.Sprintf("party%02d.webp", i))
+		takenAt(t, q, p.ID, day.Add(time.Duration(i%3)*time.Minute))
+	}
+
+	w := getTimeline(h.AdminTimeline, "/admin/timeline", false)
+	body := w.Body.String()
+	first := timelineIDs(body)
+	if len(first) != 60 {
+		t.Fatalf("expected a first page of 60 photos, got %d", len(first))
+	}
+	next := regexp.MustCompile(`hx-get="([^"]+)"`).FindStringSubmatch(body)
+	if next == nil {
+		t.Fatalf("expected infinite scroll to the next page")
+	}
+
+	w = getTimeline(h.AdminTimeline, html.UnescapeString(next[1]), true)
+	body = w.Body.String()
+	rest := timelineIDs(body)
+	if len(rest) != 5 {
+		t.Fatalf("expected the last 5 photos, got %d", len(rest))
+	}
+	seen := map[string]bool{}
+	for _, id := range append(first, rest...) {
+		if seen[id] {
+			t.Errorf("photo %s shown twice", id)
+		}
+		seen[id] = true
+	}
+	if strings.Contains(body, "Saturday, December 31, 2022") || strings.Contains(body, "hx-get") {
+		t.Errorf("expected the continued day without heading or further pages")
+	}
+}
+
+func TestAdminTimeline_OnThisDay(t *testing.T) {
+	h, q, _ := setupBulkTest(t)
+
+	album := testutil.CreateTestAlbum(t, q, "Birthdays", "")
+	today := time.Now()
+	earlier := testutil.CreateTestPhoto(t, q, album.ID, "earlier.webp")
+	takenAt(t, q, earlier.ID, time.Date(today.Year()-4, today.Month(), today.Day(), 12, 0, 0, 0, time.UTC))
+	other := testutil.CreateTestPhoto(t, q, album.ID, "other.webp")
+	takenAt(t, q, other.ID, time.Date(today.Year()-4, today.Month(), today.Day(), 12, 0, 0, 0, time.UTC).AddDate(0, 0, 1))
+
+	body := getTimeline(h.AdminTimeline, "/admin/timeline", false).Body.String()
+	panel, _, _ := strings.Cut(body, `id="timeline"`)
+	ids := timelineIDs(panel)
+	if !strings.Contains(panel, "4 years ago") || len(ids) != 1 || ids[0] != fmt.Sprint(earlier.ID) {
+		t.Errorf("expected only the photo of this day 4 years ago in the panel, got %v", ids)
+	}
+}
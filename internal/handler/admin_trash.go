@@ -0,0 +1,252 @@
+package handler
+
+import (
+	"database/sql"
+	"log"
+	"net/http"
+	"os"
+	"strconv"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+
+	"familyshare/internal/db/sqlc"
+	"familyshare/internal/storage"
+)
+
+// removePhotoFiles deletes the stored files of purged photos (see
+// storage.PhotoFiles). A failed removal is logged and does not stop the rest;
+// it returns how many photo files failed to be removed.
+func (h *Handler) removePhotoFiles(photos []sqlc.Photo) int {
+	failed := 0
+	for _, photo := range photos {
+		files := storage.PhotoFiles(h.storage.BaseDir, photo.AlbumID, photo.ID, photo.Format, photo.CreatedAt, photo.OriginalFormat, photo.AnimationFormat)
+		for i, path := range files {
+			if err := os.Remove(path); err != nil && !os.IsNotExist(err) {
+				log.Printf("failed to delete file %s of photo %d: %v", path, photo.ID, err)
+				if i == 0 {
+					failed++
+				}
+			}
+		}
+	}
+	return failed
+}
+
+// ViewTrash handles GET /admin/trash
+func (h *Handler) ViewTrash(w http.ResponseWriter, r *http.Request) {
+	q := sqlc.New(h.db)
+
+	albums, err := q.ListTrashedAlbums(r.Context())
+	if err != nil {
+		log.Printf("failed to list trashed albums: %v", err)
+		http.Error(w, "failed to load trash", http.StatusInternalServerError)
+		return
+	}
+	photos, err := q.ListTrashedPhotos(r.Context())
+	if err != nil {
+		log.Printf("failed to list trashed photos: %v", err)
+		http.Error(w, "failed to load trash", http.StatusInternalServerError)
+		return
+	}
+
+	retentionDays := 0
+	if h.config != nil {
+		retentionDays = int(h.config.TrashRetention / (24 * time.Hour))
+	}
+
+	data := struct {
+		Albums        []sqlc.ListTrashedAlbumsRow
+		Photos        []sqlc.ListTrashedPhotosRow
+		RetentionDays int
+	}{
+		Albums:        albums,
+		Photos:        photos,
+		RetentionDays: retentionDays,
+	}
+
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	if err := h.RenderTemplate(w, "trash.html", data); err != nil {
+		log.Printf("template render error for trash: %v", err)
+		http.Error(w, "template render error", http.StatusInternalServerError)
+	}
+}
+
+// RestoreTrashedPhoto handles POST /admin/trash/photos/{id}/restore
+func (h *Handler) RestoreTrashedPhoto(w http.ResponseWriter, r *http.Request) {
+	idStr := chi.URLParam(r, "id")
+	id, err := strconv.ParseInt(idStr, 10, 64)
+	if err != nil {
+		http.Error(w, "invalid id", http.StatusBadRequest)
+		return
+	}
+
+	q := sqlc.New(h.db)
+
+	photo, err := q.GetPhotoIncludingDeleted(r.Context(), id)
+	if err != nil || !photo.DeletedAt.Valid {
+		http.Error(w, "photo not in trash", http.StatusNotFound)
+		return
+	}
+	if _, err := q.GetAlbum(r.Context(), photo.AlbumID); err != nil {
+		http.Error(w, "restore the photo's album first", http.StatusConflict)
+		return
+	}
+
+	if err := q.RestorePhoto(r.Context(), id); err != nil {
+		log.Printf("failed to restore photo %d: %v", id, err)
+		http.Error(w, "failed to restore photo", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("HX-Refresh", "true")
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// PurgeTrashedPhoto handles DELETE /admin/trash/photos/{id}
+func (h *Handler) PurgeTrashedPhoto(w http.ResponseWriter, r *http.Request) {
+	idStr := chi.URLParam(r, "id")
+	id, err := strconv.ParseInt(idStr, 10, 64)
+	if err != nil {
+		http.Error(w, "invalid id", http.StatusBadRequest)
+		return
+	}
+
+	q := sqlc.New(h.db)
+
+	photo, err := q.GetPhotoIncludingDeleted(r.Context(), id)
+	if err != nil || !photo.DeletedAt.Valid {
+		http.Error(w, "photo not in trash", http.StatusNotFound)
+		return
+	}
+
+	if err := q.DeletePhoto(r.Context(), id); err != nil {
+		log.Printf("failed to delete photo %d from database: %v", id, err)
+		http.Error(w, "failed to delete photo", http.StatusInternalServerError)
+		return
+	}
+	// Don't return error - photo already deleted from DB
+	h.removePhotoFiles([]sqlc.Photo{photo})
+
+	w.Header().Set("HX-Refresh", "true")
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// RestoreTrashedAlbum handles POST /admin/trash/albums/{id}/restore
+// Photos trashed on their own before the album stay in the trash.
+func (h *Handler) RestoreTrashedAlbum(w http.ResponseWriter, r *http.Request) {
+	idStr := chi.URLParam(r, "id")
+	id, err := strconv.ParseInt(idStr, 10, 64)
+	if err != nil {
+		http.Error(w, "invalid id", http.StatusBadRequest)
+		return
+	}
+
+	q := sqlc.New(h.db)
+
+	album, err := q.GetAlbumIncludingDeleted(r.Context(), id)
+	if err != nil || !album.DeletedAt.Valid {
+		http.Error(w, "album not in trash", http.StatusNotFound)
+		return
+	}
+
+	if err := q.RestoreAlbum(r.Context(), id); err != nil {
+		log.Printf("failed to restore album %d: %v", id, err)
+		http.Error(w, "failed to restore album", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("HX-Refresh", "true")
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// PurgeTrashedAlbum handles DELETE /admin/trash/albums/{id}
+// Removes the album, all of its photos and their files for good.
+func (h *Handler) PurgeTrashedAlbum(w http.ResponseWriter, r *http.Request) {
+	idStr := chi.URLParam(r, "id")
+	id, err := strconv.ParseInt(idStr, 10, 64)
+	if err != nil {
+		http.Error(w, "invalid id", http.StatusBadRequest)
+		return
+	}
+
+	q := sqlc.New(h.db)
+
+	album, err := q.GetAlbumIncludingDeleted(r.Context(), id)
+	if err != nil || !album.DeletedAt.Valid {
+		http.Error(w, "album not in trash", http.StatusNotFound)
+		return
+	}
+
+	photos, err := q.ListPhotosForAlbumIncludingDeleted(r.Context(), id)
+	if err != nil {
+		log.Printf("failed to list photos of album %d: %v", id, err)
+		http.Error(w, "failed to delete album", http.StatusInternalServerError)
+		return
+	}
+
+	// Delete the album (cascade will delete photos from DB via foreign key)
+	if err := q.DeleteAlbum(r.Context(), id); err != nil {
+		log.Printf("failed to delete album %d: %v", id, err)
+		http.Error(w, "failed to delete album", http.StatusInternalServerError)
+		return
+	}
+	h.removePhotoFiles(photos)
+
+	w.Header().Set("HX-Refresh", "true")
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// EmptyTrash handles POST /admin/trash/empty
+func (h *Handler) EmptyTrash(w http.ResponseWriter, r *http.Request) {
+	// The purges commit together, so files are only removed once no row
+	// refers to them and a failure leaves both rows and files in place
+	tx, err := h.db.BeginTx(r.Context(), nil)
+	if err != nil {
+		log.Printf("failed to begin trash transaction: %v", err)
+		http.Error(w, "failed to empty trash", http.StatusInternalServerError)
+		return
+	}
+	defer tx.Rollback()
+	q := sqlc.New(tx)
+
+	// Everything trashed up to now; CURRENT_TIMESTAMP has second precision
+	cutoff := sql.NullTime{Time: time.Now().UTC().Add(time.Second), Valid: true}
+
+	var purged []sqlc.Photo
+	albumPhotos, err := q.PurgePhotosOfTrashedAlbums(r.Context(), cutoff)
+	if err != nil {
+		log.Printf("failed to purge photos of trashed albums: %v", err)
+		http.Error(w, "failed to empty trash", http.StatusInternalServerError)
+		return
+	}
+	for _, p := range albumPhotos {
+		purged = append(purged, sqlc.Photo{ID: p.ID, AlbumID: p.AlbumID, Format: p.Format, CreatedAt: p.CreatedAt, OriginalFormat: p.OriginalFormat, AnimationFormat: p.AnimationFormat})
+	}
+
+	photos, err := q.PurgeTrashedPhotos(r.Context(), cutoff)
+	if err != nil {
+		log.Printf("failed to purge trashed photos: %v", err)
+		http.Error(w, "failed to empty trash", http.StatusInternalServerError)
+		return
+	}
+	for _, p := range photos {
+		purged = append(purged, sqlc.Photo{ID: p.ID, AlbumID: p.AlbumID, Format: p.Format, CreatedAt: p.CreatedAt, OriginalFormat: p.OriginalFormat, AnimationFormat: p.AnimationFormat})
+	}
+
+	if _, err := q.PurgeTrashedAlbums(r.Context(), cutoff); err != nil {
+		log.Printf("failed to purge trashed albums: %v", err)
+		http.Error(w, "failed to empty trash", http.StatusInternalServerError)
+		return
+	}
+	if err := tx.Commit(); err != nil {
+		log.Printf("failed to commit emptying the trash: %v", err)
+		http.Error(w, "failed to empty trash", http.StatusInternalServerError)
+		return
+	}
+
+	h.removePhotoFiles(purged)
+
+	w.Header().Set("HX-Refresh", "true")
+	w.WriteHeader(http.StatusNoContent)
+}
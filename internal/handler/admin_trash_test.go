@@ -0,0 +1,185 @@
+package handler_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/go-chi/chi/v5"
+
+	"familyshare/internal/db/sqlc"
+	"familyshare/internal/testutil"
+)
+
+// idRequest calls a handler with the {id} route parameter set.
+func idRequest(handle http.HandlerFunc, method string, id int64) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(method, "/admin", nil)
+	rctx := chi.NewRouteContext()
+	rctx.URLParams.Add("id", strconv.FormatInt(id, 10))
+	req = req.WithContext(context.WithValue(req.Context(), chi.RouteCtxKey, rctx))
+	w := httptest.NewRecorder()
+	handle(w, req)
+	return w
+}
+
+func TestTrash_RestoreAndPurgePhoto(t *testing.T) {
+	h, q, baseDir := setupBulkTest(t)
+	ctx := context.Background()
+
+	album := testutil.CreateTestAlbum(t, q, "Trip", "")
+	photo, path := storedPhoto(t, q, baseDir, album.ID, "beach.webp")
+
+	// Not in the trash yet
+	if w := idRequest(h.PurgeTrashedPhoto, "DELETE", photo.ID); w.Code != http.StatusNotFound {
+		t.Fatalf("expected 404 purging a live photo, got %d", w.Code)
+	}
+
+	if err := q.TrashPhoto(ctx, photo.ID); err != nil {
+		t.Fatalf("TrashPhoto: %v", err)
+	}
+
+	w := httptest.NewRecorder()
+	h.ViewTrash(w, httptest.NewRequest("GET", "/admin/trash", nil))
+	if w.Code != http.StatusOK || !strings.Contains(w.Body.String(), "beach.webp") {
+		t.Fatalf("expected trashed photo listed, got %d", w.Code)
+	}
+
+	if w := idRequest(h.RestoreTrashedPhoto, "POST", photo.ID); w.Code != http.StatusNoContent {
+		t.Fatalf("expected 204 on restore, got %d: %s", w.Code, w.Body.String())
+	}
+	if _, err := q.GetPhoto(ctx, photo.ID); err != nil {
+		t.Fatalf("expected photo restored: %v", err)
+	}
+
+	if err := q.TrashPhoto(ctx, photo.ID); err != nil {
+		t.Fatalf("TrashPhoto: %v", err)
+	}
+	if w := idRequest(h.PurgeTrashedPhoto, "DELETE", photo.ID); w.Code != http.StatusNoContent {
+		t.Fatalf("expected 204 on purge, got %d: %s", w.Code, w.Body.String())
+	}
+	if _, err := q.GetPhotoIncludingDeleted(ctx, photo.ID); err == nil {
+		t.Errorf("expected photo row purged")
+	}
+	if _, err := os.Stat(path); !os.IsNotExist(err) {
+		t.Errorf("expected photo file removed, got: %v", err)
+	}
+}
+
+func TestTrash_AlbumHiddenUntilRestored(t *testing.T) {
+	h, q := setupCommentTest(t)
+	ctx := context.Background()
+
+	album := testutil.CreateTestAlbum(t, q, "Trip", "")
+	photo := testutil.CreateTestPhoto(t, q, album.ID, "beach.webp")
+	if _, err := q.CreateShareLink(ctx, sqlc.CreateShareLinkParams{Token: "trash-token", TargetType: "album", TargetID: album.ID}); err != nil {
+		t.Fatalf("CreateShareLink: %v", err)
+	}
+
+	if err := q.TrashAlbum(ctx, album.ID); err != nil {
+		t.Fatalf("TrashAlbum: %v", err)
+	}
+
+	if w := getShare(h.ViewShareLink, "trash-token", ""); w.Code != http.StatusNotFound {
+		t.Errorf("expected share of trashed album to 404, got %d", w.Code)
+	}
+	if albums, _ := q.ListAlbums(ctx, sqlc.ListAlbumsParams{Limit: 10}); len(albums) != 0 {
+		t.Errorf("expected trashed album hidden from list, got %d", len(albums))
+	}
+	if _, err := q.GetPhoto(ctx, photo.ID); err == nil {
+		t.Errorf("expected photos of trashed album hidden")
+	}
+
+	// A photo can't come back while its album is in the trash
+	if err := q.TrashPhoto(ctx, photo.ID); err != nil {
+		t.Fatalf("TrashPhoto: %v", err)
+	}
+	if w := idRequest(h.RestoreTrashedPhoto, "POST", photo.ID); w.Code != http.StatusConflict {
+		t.Errorf("expected 409 restoring photo of trashed album, got %d", w.Code)
+	}
+	if err := q.RestorePhoto(ctx, photo.ID); err != nil {
+		t.Fatalf("RestorePhoto: %v", err)
+	}
+
+	if w := idRequest(h.RestoreTrashedAlbum, "POST", album.ID); w.Code != http.StatusNoContent {
+		t.Fatalf("expected 204 on album restore, got %d: %s", w.Code, w.Body.String())
+	}
+	if w := getShare(h.ViewShareLink, "trash-token", ""); w.Code != http.StatusOK {
+		t.Errorf("expected share to work after restore, got %d", w.Code)
+	}
+	if _, err := q.GetPhoto(ctx, photo.ID); err != nil {
+		t.Errorf("expected photo visible after album restore: %v", err)
+	}
+}
+
+func TestTrash_PurgeAlbumRemovesFiles(t *testing.T) {
+	h, q, baseDir := setupBulkTest(t)
+	ctx := context.Background()
+
+	album := testutil.CreateTestAlbum(t, q, "Trip", "")
+	_, path1 := storedPhoto(t, q, baseDir, album.ID, "1.webp")
+	p2, path2 := storedPhoto(t, q, baseDir, album.ID, "2.webp")
+	// Photos trashed on their own before the album go too
+	if err := q.TrashPhoto(ctx, p2.ID); err != nil {
+		t.Fatalf("TrashPhoto: %v", err)
+	}
+	if err := q.TrashAlbum(ctx, album.ID); err != nil {
+		t.Fatalf("TrashAlbum: %v", err)
+	}
+
+	if w := idRequest(h.PurgeTrashedAlbum, "DELETE", album.ID); w.Code != http.StatusNoContent {
+		t.Fatalf("expected 204, got %d: %s", w.Code, w.Body.String())
+	}
+	if _, err := q.GetAlbumIncludingDeleted(ctx, album.ID); err == nil {
+		t.Errorf("expected album purged")
+	}
+	for _, path := range []string{path1, path2} {
+		if _, err := os.Stat(path); !os.IsNotExist(err) {
+			t.Errorf("expected file %s removed, got: %v", path, err)
+		}
+	}
+}
+
+func TestEmptyTrash(t *testing.T) {
+	h, q, baseDir := setupBulkTest(t)
+	ctx := context.Background()
+
+	kept := testutil.CreateTestAlbum(t, q, "Kept", "")
+	trashed := testutil.CreateTestAlbum(t, q, "Trashed", "")
+	live, livePath := storedPhoto(t, q, baseDir, kept.ID, "live.webp")
+	loose, loosePath := storedPhoto(t, q, baseDir, kept.ID, "loose.webp")
+	_, albumPath := storedPhoto(t, q, baseDir, trashed.ID, "album.webp")
+	if err := q.TrashPhoto(ctx, loose.ID); err != nil {
+		t.Fatalf("TrashPhoto: %v", err)
+	}
+	if err := q.TrashAlbum(ctx, trashed.ID); err != nil {
+		t.Fatalf("TrashAlbum: %v", err)
+	}
+
+	w := httptest.NewRecorder()
+	h.EmptyTrash(w, httptest.NewRequest("POST", "/admin/trash/empty", nil))
+	if w.Code != http.StatusNoContent {
+		t.Fatalf("expected 204, got %d: %s", w.Code, w.Body.String())
+	}
+
+	if albums, _ := q.ListTrashedAlbums(ctx); len(albums) != 0 {
+		t.Errorf("expected no trashed albums left, got %d", len(albums))
+	}
+	if photos, _ := q.ListTrashedPhotos(ctx); len(photos) != 0 {
+		t.Errorf("expected no trashed photos left, got %d", len(photos))
+	}
+	for _, path := range []string{loosePath, albumPath} {
+		if _, err := os.Stat(path); !os.IsNotExist(err) {
+			t.Errorf("expected file %s removed, got: %v", path, err)
+		}
+	}
+	if _, err := q.GetPhoto(ctx, live.ID); err != nil {
+		t.Errorf("expected live photo kept: %v", err)
+	}
+	if _, err := os.Stat(livePath); err != nil {
+		t.Errorf("expected live file kept: %v", err)
+	}
+}
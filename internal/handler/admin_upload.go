@@ -3,7 +3,6 @@ package handler
 import (
 	"context"
 	"errors"
-	"fmt"
 	"io"
 	"log"
 	"net/http"
@@ -13,28 +12,81 @@ import (
 	"github.com/go-chi/chi/v5"
 
 	"familyshare/internal/db/sqlc"
+	"familyshare/internal/i18n"
 	"familyshare/internal/pipeline"
 )
 
 var errUploadTooLarge = errors.New("upload exceeds per-file limit")
 
-func friendlyUploadError(err error, maxPerFile int64) string {
+const maxUploadFileSize = int64(25 << 20) // 25MB per file
+
+// friendlyUploadError explains why an upload failed, in locale.
+func friendlyUploadError(locale string, err error, maxPerFile int64) string {
 	if err == nil {
 		return ""
 	}
 
 	switch {
 	case errors.Is(err, errUploadTooLarge), errors.Is(err, pipeline.ErrTooLarge):
-		return fmt.Sprintf("File is too large. Max %dMB.", maxPerFile>>20)
+		return i18n.T(locale, "upload.error_too_large", maxPerFile>>20)
 	case errors.Is(err, pipeline.ErrNotAnImage):
-		return "Unsupported file type. Please upload a JPG, PNG, WebP, GIF, or AVIF."
+		return i18n.T(locale, "upload.error_not_image")
 	case errors.Is(err, pipeline.ErrInvalidDimensions):
-		return fmt.Sprintf("Image dimensions are invalid. Max %dx%d pixels.", pipeline.MaxDimension, pipeline.MaxDimension)
+		return i18n.T(locale, "upload.error_dimensions", pipeline.MaxDimension, pipeline.MaxDimension)
 	case errors.Is(err, pipeline.ErrDecodeFailed):
-		return "We couldn't read that image. It may be corrupted."
+		return i18n.T(locale, "upload.error_decode")
 	default:
-		return "Upload failed. Please try again."
+		return i18n.T(locale, "upload.error_failed")
+	}
+}
+
+// uploadStats counts the jobs of an album's upload queue.
+type uploadStats struct {
+	PendingCount    int64
+	ProcessingCount int64
+	CompletedCount  int64
+	FailedCount     int64
+	Percent         int
+}
+
+func newUploadStats(pending, processing, completed, failed int64) uploadStats {
+	total := pending + processing + completed + failed
+	percent := 0
+	if total > 0 {
+		percent = int(float64(completed+failed) / float64(total) * 100)
 	}
+	return uploadStats{
+		PendingCount:    pending,
+		ProcessingCount: processing,
+		CompletedCount:  completed,
+		FailedCount:     failed,
+		Percent:         percent,
+	}
+}
+
+// queueStats converts the GetQueueStatus aggregates, which are NULL for an
+// album without jobs.
+func queueStats(status sqlc.GetQueueStatusRow) uploadStats {
+	return newUploadStats(
+		int64(status.PendingCount.Float64),
+		int64(status.ProcessingCount.Float64),
+		int64(status.CompletedCount.Float64),
+		int64(status.FailedCount.Float64),
+	)
+}
+
+// Active reports whether jobs are still waiting or running.
+func (s uploadStats) Active() bool {
+	return s.PendingCount+s.ProcessingCount > 0
+}
+
+// uploadProgress is the data of the upload_progress fragment. Live keeps the
+// fragment connected to the album's event stream.
+type uploadProgress struct {
+	Album       struct{ ID int64 }
+	Stats       uploadStats
+	StatsLoaded bool
+	Live        bool
 }
 
 // AdminUploadStatus returns htmx partial with progress of background processing
@@ -58,67 +110,12 @@ func (h *Handler) AdminUploadStatus(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	// Calculate statistics
-	total := 0.0
-	if status.PendingCount.Valid {
-		total += status.PendingCount.Float64
-	}
-	if status.ProcessingCount.Valid {
-		total += status.ProcessingCount.Float64
-	}
-	if status.CompletedCount.Valid {
-		total += status.CompletedCount.Float64
-	}
-	if status.FailedCount.Valid {
-		total += status.FailedCount.Float64
-	}
-
-	processed := 0.0
-	if status.CompletedCount.Valid {
-		processed += status.CompletedCount.Float64
-	}
-	if status.FailedCount.Valid {
-		processed += status.FailedCount.Float64
-	}
-
-	percent := 0
-	if total > 0 {
-		percent = int((processed / total) * 100)
-	}
-
-	activeCount := int64(status.PendingCount.Float64) + int64(status.ProcessingCount.Float64)
-	polling := activeCount > 0
-
-	data := struct {
-		Album struct {
-			ID int64
-		}
-		Stats struct {
-			PendingCount    int64
-			ProcessingCount int64
-			CompletedCount  int64
-			FailedCount     int64
-			Percent         int
-		}
-		StatsLoaded bool
-		Polling     bool
-	}{
-		Album: struct{ ID int64 }{ID: albumID},
-		Stats: struct {
-			PendingCount    int64
-			ProcessingCount int64
-			CompletedCount  int64
-			FailedCount     int64
-			Percent         int
-		}{
-			PendingCount:    int64(status.PendingCount.Float64),
-			ProcessingCount: int64(status.ProcessingCount.Float64),
-			CompletedCount:  int64(status.CompletedCount.Float64),
-			FailedCount:     int64(status.FailedCount.Float64),
-			Percent:         percent,
-		},
+	stats := queueStats(status)
+	data := uploadProgress{
+		Album:       struct{ ID int64 }{ID: albumID},
+		Stats:       stats,
 		StatsLoaded: true,
-		Polling:     polling,
+		Live:        stats.Active(),
 	}
 
 	w.Header().Set("Content-Type", "text/html; charset=utf-8")
@@ -154,7 +151,7 @@ func (h *Handler) AdminUploadPhotos(w http.ResponseWriter, r *http.Request) {
 	}
 	_ = os.MkdirAll(tmpBaseDir, 0700)
 
-	const maxPerFile = int64(25 << 20) // 25MB per file
+	const maxPerFile = maxUploadFileSize
 
 	filesQueued := 0
 
@@ -205,7 +202,7 @@ func (h *Handler) AdminUploadPhotos(w http.ResponseWriter, r *http.Request) {
 		}
 
 		// Enqueue the job
-		_, err = h.queries.EnqueueJob(context.Background(), sqlc.EnqueueJobParams{
+		job, err := h.queries.EnqueueJob(context.Background(), sqlc.EnqueueJobParams{
 			AlbumID:          albumID,
 			OriginalFilename: filename,
 			TempFilepath:     tmp.Name(),
@@ -215,6 +212,7 @@ func (h *Handler) AdminUploadPhotos(w http.ResponseWriter, r *http.Request) {
 			os.Remove(tmp.Name())
 			continue
 		}
+		h.publishJob(job, "pending")
 
 		filesQueued++
 	}
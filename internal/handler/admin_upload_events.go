@@ -0,0 +1,158 @@
+package handler
+
+import (
+	"bytes"
+	"fmt"
+	"log"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/go-chi/chi/v5"
+
+	"familyshare/internal/db/sqlc"
+	"familyshare/internal/worker"
+)
+
+// uploadFile is a row of the per-file list in the upload_file fragment.
+// Update marks a row the client already shows, which is swapped in place.
+type uploadFile struct {
+	JobID    int64
+	Filename string
+	Status   string
+	Reason   string
+	Update   bool
+}
+
+// jobTally tracks the status of each job of an album so counts stay right
+// when an event repeats a change the initial snapshot already saw.
+type jobTally map[int64]string
+
+func (t jobTally) apply(e worker.JobEvent) {
+	if e.Status == "" {
+		delete(t, e.JobID)
+		return
+	}
+	t[e.JobID] = e.Status
+}
+
+func (t jobTally) stats() uploadStats {
+	counts := make(map[string]int64, 4)
+	for _, status := range t {
+		counts[status]++
+	}
+	return newUploadStats(counts["pending"], counts["processing"], counts["completed"], counts["failed"])
+}
+
+// publishJob tells the album's progress streams about a job the handlers
+// queued, requeued or discarded.
+func (h *Handler) publishJob(job sqlc.ProcessingQueue, status string) {
+	if h.worker == nil {
+		return
+	}
+	h.worker.Events().Publish(worker.JobEvent{
+		JobID:    job.ID,
+		AlbumID:  job.AlbumID,
+		Filename: job.OriginalFilename,
+		Status:   status,
+	})
+}
+
+// writeEvent sends a rendered fragment as a server-sent event.
+func (h *Handler) writeEvent(w http.ResponseWriter, event, tmpl string, data interface{}) error {
+	var buf bytes.Buffer
+	h.tmplMu.RLock()
+	err := h.templates.ExecuteTemplate(&buf, tmpl, data)
+	h.tmplMu.RUnlock()
+	if err != nil {
+		return err
+	}
+	var msg strings.Builder
+	fmt.Fprintf(&msg, "event: %s\n", event)
+	for _, line := range strings.Split(strings.TrimSpace(buf.String()), "\n") {
+		fmt.Fprintf(&msg, "data: %s\n", line)
+	}
+	msg.WriteString("\n")
+	if _, err := w.Write([]byte(msg.String())); err != nil {
+		return err
+	}
+	return http.NewResponseController(w).Flush()
+}
+
+// AlbumEvents handles GET /admin/albums/{id}/events
+// It streams the album's upload progress as server-sent events: "progress"
+// carries the counts, "file" a row per job that changed and "done" the final
+// upload_progress fragment, after which the stream ends.
+func (h *Handler) AlbumEvents(w http.ResponseWriter, r *http.Request) {
+	idStr := chi.URLParam(r, "id")
+	albumID, err := strconv.ParseInt(idStr, 10, 64)
+	if err != nil {
+		http.Error(w, "invalid id", http.StatusBadRequest)
+		return
+	}
+	if h.worker == nil {
+		http.Error(w, "live progress unavailable", http.StatusServiceUnavailable)
+		return
+	}
+
+	// Subscribe before loading the snapshot so no change falls in between
+	events, unsubscribe := h.worker.Events().Subscribe(albumID)
+	defer unsubscribe()
+
+	jobs, err := h.queries.ListJobStatusesByAlbum(r.Context(), albumID)
+	if err != nil {
+		log.Printf("failed to list jobs of album %d: %v", albumID, err)
+		http.Error(w, "failed to load upload progress", http.StatusInternalServerError)
+		return
+	}
+	tally := make(jobTally, len(jobs))
+	for _, job := range jobs {
+		tally[job.ID] = job.Status
+	}
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("X-Accel-Buffering", "no") // don't let a reverse proxy buffer the stream
+	w.WriteHeader(http.StatusOK)
+
+	progress := uploadProgress{Album: struct{ ID int64 }{ID: albumID}, StatsLoaded: true}
+	shown := make(map[int64]bool)
+	for {
+		progress.Stats = tally.stats()
+		progress.Live = progress.Stats.Active()
+		if !progress.Live {
+			if err := h.writeEvent(w, "done", "upload_progress", progress); err != nil {
+				log.Printf("failed to send upload progress: %v", err)
+			}
+			return
+		}
+		if err := h.writeEvent(w, "progress", "upload_stats", progress); err != nil {
+			return
+		}
+
+		var e worker.JobEvent
+		var ok bool
+		select {
+		case <-r.Context().Done():
+			return
+		case e, ok = <-events:
+			if !ok {
+				// Fell behind or shutting down; the client reconnects and reloads
+				return
+			}
+		}
+
+		tally.apply(e)
+		if e.Status == "" {
+			continue
+		}
+		row := uploadFile{JobID: e.JobID, Filename: e.Filename, Status: e.Status, Update: shown[e.JobID]}
+		if e.Error != "" {
+			row.Reason = friendlyUploadError(h.locale, jobError(e.Error), maxUploadFileSize)
+		}
+		shown[e.JobID] = true
+		if err := h.writeEvent(w, "file", "upload_file", row); err != nil {
+			return
+		}
+	}
+}
@@ -0,0 +1,139 @@
+package handler_test
+
+import (
+	"bufio"
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/go-chi/chi/v5"
+
+	"familyshare/internal/config"
+	"familyshare/internal/db/sqlc"
+	"familyshare/internal/handler"
+	"familyshare/internal/storage"
+	"familyshare/internal/testutil"
+	"familyshare/internal/worker"
+	"familyshare/web"
+)
+
+// readEvent reads the next server-sent event from a stream.
+func readEvent(t *testing.T, r *bufio.Reader) (string, string) {
+	t.Helper()
+	var name string
+	var data []string
+	for {
+		line, err := r.ReadString('\n')
+		if err != nil {
+			t.Fatalf("read event: %v", err)
+		}
+		line = strings.TrimRight(line, "\n")
+		switch {
+		case line == "":
+			return name, strings.Join(data, "\n")
+		case strings.HasPrefix(line, "event: "):
+			name = strings.TrimPrefix(line, "event: ")
+		case strings.HasPrefix(line, "data: "):
+			data = append(data, strings.TrimPrefix(line, "data: "))
+		}
+	}
+}
+
+func TestAlbumEvents(t *testing.T) {
+	dbConn, q, dbCleanup := testutil.SetupTestDB(t)
+	t.Cleanup(dbCleanup)
+	store := storage.New(t.TempDir())
+	cfg := &config.Config{RateLimitShare: 60, RateLimitAdmin: 10}
+	wk := worker.NewWorker(dbConn, store, cfg)
+	h := handler.New(dbConn, store, web.EmbedFS, cfg, wk)
+
+	r := chi.NewRouter()
+	r.Get("/admin/albums/{id}/events", h.AlbumEvents)
+	srv := httptest.NewServer(r)
+	t.Cleanup(srv.Close)
+
+	ctx := context.Background()
+	album := testutil.CreateTestAlbum(t, q, "Trip", "")
+	var jobs []sqlc.ProcessingQueue
+	for _, name := range []string{"beach.jpg", "IMG_2041.HEIC"} {
+		job, err := q.EnqueueJob(ctx, sqlc.EnqueueJobParams{AlbumID: album.ID, OriginalFilename: name, TempFilepath: "/tmp/" + name})
+		if err != nil {
+			t.Fatalf("EnqueueJob: %v", err)
+		}
+		jobs = append(jobs, job)
+	}
+
+	res, err := http.Get(srv.URL + "/admin/albums/" + strconv.FormatInt(album.ID, 10) + "/events")
+	if err != nil {
+		t.Fatalf("GET events: %v", err)
+	}
+	defer res.Body.Close()
+	if ct := res.Header.Get("Content-Type"); ct != "text/event-stream" {
+		t.Fatalf("expected an event stream, got %q", ct)
+	}
+	stream := bufio.NewReader(res.Body)
+
+	if name, data := readEvent(t, stream); name != "progress" || !strings.Contains(data, "Pending: <strong>2</strong>") {
+		t.Fatalf("expected initial progress, got %s: %s", name, data)
+	}
+
+	publish := func(job sqlc.ProcessingQueue, status, msg string) {
+		wk.Events().Publish(worker.JobEvent{JobID: job.ID, AlbumID: job.AlbumID, Filename: job.OriginalFilename, Status: status, Error: msg})
+	}
+
+	publish(jobs[1], "processing", "")
+	if name, data := readEvent(t, stream); name != "file" || !strings.Contains(data, "IMG_2041.HEIC") || strings.Contains(data, "hx-swap-oob") {
+		t.Fatalf("expected a new file row, got %s: %s", name, data)
+	}
+	if _, data := readEvent(t, stream); !strings.Contains(data, "Processing: <strong>1</strong>") {
+		t.Fatalf("expected updated counts, got %s", data)
+	}
+
+	publish(jobs[1], "failed", "validate decode: uploaded file is not an image")
+	name, data := readEvent(t, stream)
+	if name != "file" || !strings.Contains(data, "hx-swap-oob") || !strings.Contains(data, "Unsupported file type") {
+		t.Fatalf("expected the row updated with the reason, got %s: %s", name, data)
+	}
+	readEvent(t, stream)
+
+	// Events of other albums are not streamed
+	publish(sqlc.ProcessingQueue{ID: 999, AlbumID: album.ID + 1, OriginalFilename: "other.jpg"}, "completed", "")
+
+	publish(jobs[0], "completed", "")
+	if name, data := readEvent(t, stream); name != "file" || !strings.Contains(data, "beach.jpg") {
+		t.Fatalf("expected the completed row, got %s: %s", name, data)
+	}
+	name, data = readEvent(t, stream)
+	if name != "done" || !strings.Contains(data, "Completed with some errors") {
+		t.Fatalf("expected the final fragment, got %s: %s", name, data)
+	}
+	if strings.Contains(data, "sse-connect") {
+		t.Errorf("expected the final fragment to disconnect")
+	}
+}
+
+func TestAlbumEvents_NothingQueued(t *testing.T) {
+	dbConn, q, dbCleanup := testutil.SetupTestDB(t)
+	t.Cleanup(dbCleanup)
+	store := storage.New(t.TempDir())
+	cfg := &config.Config{RateLimitShare: 60, RateLimitAdmin: 10}
+	h := handler.New(dbConn, store, web.EmbedFS, cfg, worker.NewWorker(dbConn, store, cfg))
+
+	album := testutil.CreateTestAlbum(t, q, "Trip", "")
+	w := idRequest(h.AlbumEvents, "GET", album.ID)
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d", w.Code)
+	}
+	if !strings.HasPrefix(w.Body.String(), "event: done\n") {
+		t.Errorf("expected the stream to finish at once, got %s", w.Body.String())
+	}
+
+	// Without a worker there are no events to stream
+	hNoWorker, _, _ := setupBulkTest(t)
+	if w := idRequest(hNoWorker.AlbumEvents, "GET", album.ID); w.Code != http.StatusServiceUnavailable {
+		t.Errorf("expected 503 without a worker, got %d", w.Code)
+	}
+}
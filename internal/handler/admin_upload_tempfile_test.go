@@ -39,7 +39,7 @@ func TestTempFilesRemovedAfterSuccess(t *testing.T) {
 	defer os.RemoveAll(tmpd)
 	os.Setenv("TEMP_UPLOAD_DIR", tmpd)
 
-	store := storage.New("./data")
+	store := storage.New(t.TempDir())
 	cfg := &config.Config{RateLimitShare: 60, RateLimitAdmin: 10}
 	wrk := worker.NewWorker(dbConn, store, cfg)
 	ctx, cancel := context.WithCancel(context.Background())
@@ -61,7 +61,7 @@ func TestTempFilesRemovedAfterSuccess(t *testing.T) {
 	if err != nil {
 		t.Fatalf("create form file: %v", err)
 	}
-	_, _ = fw.Write([]byte("notreallyjpeg"))
+	_, _ = fw.Write(makeJPEG(t, 20, 10).Bytes())
 	mw.Close()
 
 	req := httptest.NewRequest("POST", "/admin/albums/"+strconv.FormatInt(album.ID, 10)+"/photos", &body)
@@ -73,7 +73,7 @@ func TestTempFilesRemovedAfterSuccess(t *testing.T) {
 	w := httptest.NewRecorder()
 	h.AdminUploadPhotos(w, req)
 
-	// Wait for worker to pick up and process (it will fail due to bad image, but that triggers cleanup)
+	// Wait for worker to pick up and process
 	deadline := time.Now().Add(5 * time.Second)
 	var processed bool
 	for time.Now().Before(deadline) {
@@ -99,8 +99,8 @@ func TestTempFilesRemovedAfterSuccess(t *testing.T) {
 	}
 }
 
-// Test that temp files are removed after failed upload (invalid image)
-func TestTempFilesRemovedAfterFailure(t *testing.T) {
+// Test that a failed upload (invalid image) keeps its temp file for a retry
+func TestTempFilesKeptAfterFailure(t *testing.T) {
 	dbConn, err := db.InitDB(":memory:")
 	if err != nil {
 		t.Fatalf("InitDB: %v", err)
@@ -129,7 +129,7 @@ func TestTempFilesRemovedAfterFailure(t *testing.T) {
 		t.Fatalf("create album: %v", err)
 	}
 
-	// invalid file (text) - handler should still remove temp
+	// invalid file (text) - the job fails but keeps its input for a retry
 	var body bytes.Buffer
 	mw := multipart.NewWriter(&body)
 	fw, err := mw.CreateFormFile("photos", "bad.txt")
@@ -168,7 +168,11 @@ func TestTempFilesRemovedAfterFailure(t *testing.T) {
 	if err != nil {
 		t.Fatalf("glob: %v", err)
 	}
-	if len(files) != 0 {
-		t.Fatalf("expected no temp files after failure, found: %v", files)
+	var tempPath string
+	if err := dbConn.QueryRow("SELECT temp_filepath FROM processing_queue LIMIT 1").Scan(&tempPath); err != nil {
+		t.Fatalf("query job: %v", err)
+	}
+	if len(files) != 1 || files[0] != tempPath {
+		t.Fatalf("expected the failed job's temp file kept, found: %v", files)
 	}
 }
@@ -45,10 +45,24 @@ func TestFriendlyUploadErrorMapping(t *testing.T) {
 
 	for _, tc := range cases {
 		t.Run(tc.name, func(t *testing.T) {
-			msg := friendlyUploadError(tc.err, maxPerFile)
+			msg := friendlyUploadError("en", tc.err, maxPerFile)
 			if !strings.Contains(strings.ToLower(msg), strings.ToLower(tc.want)) {
 				t.Fatalf("expected message containing %q, got %q", tc.want, msg)
 			}
 		})
 	}
 }
+
+func TestJobErrorMapping(t *testing.T) {
+	// Messages as the worker stores them on failed jobs
+	cases := map[string]string{
+		"validate decode: " + pipeline.ErrNotAnImage.Error():   "Unsupported file type",
+		"validate decode: " + pipeline.ErrDecodeFailed.Error(): "couldn't read",
+		"failed to open temp file: no such file":               "Upload failed",
+	}
+	for stored, want := range cases {
+		if msg := friendlyUploadError("en", jobError(stored), maxUploadFileSize); !strings.Contains(msg, want) {
+			t.Errorf("jobError(%q): expected %q in %q", stored, want, msg)
+		}
+	}
+}
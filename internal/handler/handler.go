@@ -8,13 +8,19 @@ import (
 	"log"
 	"net/http"
 	"sync"
+	"time"
 
 	"strings"
 
+	"familyshare/internal/botdetect"
 	"familyshare/internal/config"
 	"familyshare/internal/db/sqlc"
+	"familyshare/internal/i18n"
 	"familyshare/internal/metrics"
+	"familyshare/internal/middleware"
+	"familyshare/internal/requestip"
 	"familyshare/internal/security"
+	"familyshare/internal/settings"
 	"familyshare/internal/storage"
 	"familyshare/internal/worker"
 )
@@ -24,21 +30,35 @@ type Handler struct {
 	queries   *sqlc.Queries
 	storage   *storage.Storage
 	templates *template.Template
+	localized map[string]*template.Template
 	embedFS   embed.FS
 	tmplMu    sync.RWMutex
 	config    *config.Config
 	metrics   *metrics.Logger
 	worker    *worker.Worker
+	csrf      *middleware.CSRF
+	bots      *botdetect.Classifier
+	tz        *time.Location
+	locale    string
+	settings  *settings.Store
 }
 
 func New(database *sql.DB, store *storage.Storage, embedFS embed.FS, cfg *config.Config, worker *worker.Worker) *Handler {
 	debug := cfg != nil && cfg.Debug
+	csrfSecret := ""
+	var bots *botdetect.Classifier
 	if cfg != nil {
+		if cfg.BotDetection {
+			bots = botdetect.New(cfg.BotUserAgents, cfg.BotIPRanges)
+		}
+		csrfSecret = cfg.CSRFSecret
 		security.SetTrustedProxyCIDRs(cfg.TrustedProxyCIDRs)
 		if err := security.SetViewerHashSecret(cfg.ViewerHashSecret, cfg.RequireViewerHashSecret); err != nil {
 			log.Fatalf("viewer hash secret configuration error: %v", err)
 		}
 	}
+	site := settings.New(database)
+
 	// Parse templates from embedded filesystem. Try several common patterns so
 	// New works whether the embed.FS contains files under "web/templates/..."
 	// (cmd embed) or under "templates/..." (web package embed).
@@ -70,7 +90,7 @@ func New(database *sql.DB, store *storage.Storage, embedFS embed.FS, cfg *config
 		if debug {
 			log.Printf("template files to parse: %v", files)
 		}
-		tmpl, err = template.ParseFS(embedFS, files...)
+		tmpl, err = template.New("base").Funcs(templateFuncs(timezone(cfg), i18n.Default, site)).ParseFS(embedFS, files...)
 		if err != nil {
 			log.Printf("template parse error: %v", err)
 			// If parsing fails, fall back to an empty template set to avoid panics in tests.
@@ -89,14 +109,33 @@ func New(database *sql.DB, store *storage.Storage, embedFS embed.FS, cfg *config
 		log.Printf("loaded templates: %v", names)
 	}
 
+	// Each language has its own copy of the templates, whose functions
+	// write text in it
+	localized := make(map[string]*template.Template, len(i18n.Locales))
+	for _, l := range i18n.Locales {
+		clone, err := tmpl.Clone()
+		if err != nil {
+			log.Printf("template clone error for %s: %v", l.Code, err)
+			clone = tmpl
+		}
+		localized[l.Code] = clone.Funcs(templateFuncs(timezone(cfg), l.Code, site))
+	}
+
 	return &Handler{
 		db:        database,
 		queries:   sqlc.New(database),
 		storage:   store,
-		templates: tmpl,
+		templates: localized[instanceLocale(cfg)],
+		localized: localized,
 		embedFS:   embedFS,
 		config:    cfg,
 		metrics:   metrics.New(database),
+		worker:    worker,
+		csrf:      middleware.NewCSRF(csrfSecret),
+		bots:      bots,
+		tz:        timezone(cfg),
+		locale:    instanceLocale(cfg),
+		settings:  site,
 	}
 }
 
@@ -108,11 +147,33 @@ func (h *Handler) RenderTemplate(w http.ResponseWriter, name string, data interf
 	return h.templates.ExecuteTemplate(w, name, data)
 }
 
+// renderTemplateIn renders a template with data in locale, or in the
+// instance's language if locale isn't supported.
+func (h *Handler) renderTemplateIn(w http.ResponseWriter, locale, name string, data interface{}) error {
+	h.tmplMu.RLock()
+	defer h.tmplMu.RUnlock()
+
+	tmpl, ok := h.localized[locale]
+	if !ok {
+		tmpl = h.templates
+	}
+	return tmpl.ExecuteTemplate(w, name, data)
+}
+
 // IsHTMX checks if request is an HTMX request
 func IsHTMX(r *http.Request) bool {
 	return r.Header.Get("HX-Request") == "true"
 }
 
+// isBot reports whether a request comes from a bot such as a link previewer
+// or mail scanner. It is always false with bot detection turned off.
+func (h *Handler) isBot(r *http.Request) bool {
+	if h.bots == nil {
+		return false
+	}
+	return h.bots.IsBot(r.UserAgent(), requestip.ClientIP(r, h.config.TrustedProxyCIDRs))
+}
+
 func (h *Handler) cookieOptions(r *http.Request) security.CookieOptions {
 	secure := false
 	if h.config != nil {
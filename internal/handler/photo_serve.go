@@ -1,6 +1,7 @@
 package handler
 
 import (
+	"context"
 	"database/sql"
 	"log"
 	"net/http"
@@ -8,6 +9,7 @@ import (
 	"strings"
 	"time"
 
+	"familyshare/internal/db/sqlc"
 	"familyshare/internal/storage"
 
 	"github.com/go-chi/chi/v5"
@@ -23,24 +25,15 @@ func (h *Handler) ServePhoto(w http.ResponseWriter, r *http.Request) {
 	}
 
 	// Get photo from database to find album_id
+	// Trashed photos are still served to the admin so the trash page can show them
 	ctx := r.Context()
-	photo, err := h.queries.GetPhoto(ctx, photoID)
+	photo, err := h.queries.GetPhotoIncludingDeleted(ctx, photoID)
 	if err != nil {
 		http.NotFound(w, r)
 		return
 	}
 
-	// Build the file path using storage.PhotoPathAt
-	// Extract extension from filename
-	ext := strings.ToLower(photo.Format)
-	if ext == "" {
-		ext = "webp"
-	}
-	createdAt := time.Now().UTC()
-	if photo.CreatedAt.Valid {
-		createdAt = photo.CreatedAt.Time.UTC()
-	}
-	photoPath := storage.PhotoPathAt(h.storage.BaseDir, photo.AlbumID, photoID, ext, createdAt)
+	photoPath := h.servedPhotoPath(r, photo)
 
 	// Set cache header for admin-served photos (private)
 	w.Header().Set("Cache-Control", "private, max-age=3600")
@@ -64,7 +57,7 @@ func (h *Handler) ServeSharedPhoto(w http.ResponseWriter, r *http.Request) {
 	}
 
 	ctx := r.Context()
-	link, err := h.queries.GetShareLinkByToken(ctx, token)
+	link, err := h.shareLinkByToken(ctx, token)
 	if err != nil {
 		if err == sql.ErrNoRows {
 			http.NotFound(w, r)
@@ -75,48 +68,71 @@ func (h *Handler) ServeSharedPhoto(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	if link.RevokedAt.Valid {
+	if !h.shareLinkUsable(ctx, link) {
 		http.NotFound(w, r)
 		return
 	}
 
-	if link.ExpiresAt.Valid && time.Now().UTC().After(link.ExpiresAt.Time) {
+	photo, err := h.queries.GetPhoto(ctx, photoID)
+	if err != nil {
 		http.NotFound(w, r)
 		return
 	}
 
+	if !h.shareIncludesPhoto(ctx, link, photo) {
+		http.NotFound(w, r)
+		return
+	}
+
+	photoPath := h.servedPhotoPath(r, photo)
+
+	// Shared photos are safe to cache publicly for a short duration
+	w.Header().Set("Cache-Control", "public, max-age=86400")
+	http.ServeFile(w, r, photoPath)
+}
+
+// shareLinkUsable reports whether the content of a share link can be served:
+// it is neither revoked nor expired and within its view limit. Unlike opening
+// the link, it doesn't count a view.
+func (h *Handler) shareLinkUsable(ctx context.Context, link sqlc.ShareLink) bool {
+	if link.RevokedAt.Valid {
+		return false
+	}
+	if link.ExpiresAt.Valid && time.Now().UTC().After(link.ExpiresAt.Time) {
+		return false
+	}
 	if link.MaxViews.Valid {
 		uniqueViews, err := h.queries.CountUniqueShareLinkViews(ctx, link.ID)
 		if err != nil {
 			log.Printf("error counting views for shared photo: %v", err)
 		} else if uniqueViews >= link.MaxViews.Int64 {
-			http.NotFound(w, r)
-			return
+			return false
 		}
 	}
+	return true
+}
 
-	photo, err := h.queries.GetPhoto(ctx, photoID)
-	if err != nil {
-		http.NotFound(w, r)
-		return
-	}
-
+// shareIncludesPhoto reports whether the share link's target covers the photo.
+func (h *Handler) shareIncludesPhoto(ctx context.Context, link sqlc.ShareLink, photo sqlc.Photo) bool {
 	switch link.TargetType {
 	case "album":
-		if photo.AlbumID != link.TargetID {
-			http.NotFound(w, r)
-			return
-		}
+		return photo.AlbumID == link.TargetID
 	case "photo":
-		if photo.ID != link.TargetID {
-			http.NotFound(w, r)
-			return
-		}
-	default:
-		http.NotFound(w, r)
-		return
+		return photo.ID == link.TargetID
+	case "tag":
+		tagged, err := h.queries.CountPhotoTag(ctx, sqlc.CountPhotoTagParams{PhotoID: photo.ID, TagID: link.TargetID})
+		return err == nil && tagged > 0
 	}
+	return false
+}
 
+// servedPhotoPath resolves the file to serve for a photo: its kept animation
+// when the request asks for it with animated=1 and there is one, otherwise
+// the stored photo.
+func (h *Handler) servedPhotoPath(r *http.Request, photo sqlc.Photo) string {
+	if r.URL.Query().Get("animated") == "1" && photo.AnimationFormat.Valid {
+		return storage.AnimationPath(h.storage.BaseDir, photo.ID, photo.AnimationFormat.String)
+	}
 	ext := strings.ToLower(photo.Format)
 	if ext == "" {
 		ext = "webp"
@@ -125,9 +141,5 @@ func (h *Handler) ServeSharedPhoto(w http.ResponseWriter, r *http.Request) {
 	if photo.CreatedAt.Valid {
 		createdAt = photo.CreatedAt.Time.UTC()
 	}
-	photoPath := storage.PhotoPathAt(h.storage.BaseDir, photo.AlbumID, photo.ID, ext, createdAt)
-
-	// Shared photos are safe to cache publicly for a short duration
-	w.Header().Set("Cache-Control", "public, max-age=86400")
-	http.ServeFile(w, r, photoPath)
+	return storage.PhotoPathAt(h.storage.BaseDir, photo.AlbumID, photo.ID, ext, createdAt)
 }
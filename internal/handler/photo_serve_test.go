@@ -1,15 +1,19 @@
 package handler_test
 
 import (
+	"context"
+	"database/sql"
 	"net/http"
 	"net/http/httptest"
 	"os"
 	"path/filepath"
 	"strconv"
+	"strings"
 	"testing"
 	"time"
 
 	"familyshare/internal/config"
+	"familyshare/internal/db/sqlc"
 	"familyshare/internal/handler"
 	"familyshare/internal/security"
 	"familyshare/internal/storage"
@@ -245,3 +249,60 @@ func TestAdminPhotoRoute_RequiresAuth(t *testing.T) {
 func int64ToStr(id int64) string {
 	return strconv.FormatInt(id, 10)
 }
+
+// Test that animated=1 serves the kept animation of an animated photo, and the
+// poster of a still one
+func TestServeSharedPhoto_Animation(t *testing.T) {
+	db, q, dbCleanup := testutil.SetupTestDB(t)
+	defer dbCleanup()
+
+	storageDir, storageCleanup := testutil.SetupTestStorage(t)
+	defer storageCleanup()
+
+	cfg := &config.Config{DataDir: storageDir, RateLimitShare: 100000}
+	h := handler.New(db, storage.New(storageDir), web.EmbedFS, cfg, nil)
+
+	album := testutil.CreateTestAlbum(t, q, "Stickers", "")
+	animated := testutil.CreateTestPhoto(t, q, album.ID, "dance.webp")
+	still := testutil.CreateTestPhoto(t, q, album.ID, "still.webp")
+	for _, p := range []sqlc.Photo{*animated, *still} {
+		if err := storage.AtomicWrite(storage.PhotoPathAt(storageDir, album.ID, p.ID, p.Format, p.CreatedAt.Time), strings.NewReader("poster")); err != nil {
+			t.Fatalf("write poster: %v", err)
+		}
+	}
+	if err := storage.AtomicWrite(storage.AnimationPath(storageDir, animated.ID, "gif"), strings.NewReader("GIF89a")); err != nil {
+		t.Fatalf("write animation: %v", err)
+	}
+	if err := q.SetPhotoAnimationFormat(context.Background(), sqlc.SetPhotoAnimationFormatParams{AnimationFormat: sql.NullString{String: "gif", Valid: true}, ID: animated.ID}); err != nil {
+		t.Fatalf("SetPhotoAnimationFormat: %v", err)
+	}
+
+	token, err := security.GenerateSecureToken()
+	if err != nil {
+		t.Fatalf("failed to generate token: %v", err)
+	}
+	testutil.CreateTestShareLink(t, q, album.ID, token, 0, time.Now().UTC().Add(time.Hour))
+
+	r := chi.NewRouter()
+	h.RegisterRoutes(r)
+
+	tests := []struct {
+		photoID int64
+		query   string
+		body    string
+		ctype   string
+	}{
+		{animated.ID, "", "poster", "image/webp"},
+		{animated.ID, "?animated=1", "GIF89a", "image/gif"},
+		{still.ID, "?animated=1", "poster", "image/webp"},
+	}
+	for _, tt := range tests {
+		req := httptest.NewRequest("GET", "/s/"+token+"/photos/"+int64ToStr(tt.photoID)+".webp"+tt.query, nil)
+		req.Header.Set("X-Forwarded-For", "203.0.113.9")
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+		if w.Code != http.StatusOK || w.Body.String() != tt.body || w.Header().Get("Content-Type") != tt.ctype {
+			t.Errorf("photo %d%s: expected %q as %s, got %d %q %s", tt.photoID, tt.query, tt.body, tt.ctype, w.Code, w.Body.String(), w.Header().Get("Content-Type"))
+		}
+	}
+}
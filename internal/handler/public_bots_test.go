@@ -0,0 +1,118 @@
+package handler_test
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/netip"
+	"strings"
+	"testing"
+	"time"
+
+	"familyshare/internal/config"
+	"familyshare/internal/db/sqlc"
+	"familyshare/internal/handler"
+	"familyshare/internal/storage"
+	"familyshare/internal/testutil"
+	"familyshare/web"
+)
+
+const browserUA = "Mozilla/5.0 (Windows NT 10.0; Win64; x64) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/120.0.0.0 Safari/537.36"
+
+// setupShareWithBots returns a handler with bot detection turned on, which
+// also treats 198.51.100.0/24 as bots.
+func setupShareWithBots(t *testing.T) (*handler.Handler, *sqlc.Queries) {
+	t.Helper()
+	dbConn, q, dbCleanup := testutil.SetupTestDB(t)
+	t.Cleanup(dbCleanup)
+	storageDir := t.TempDir()
+	t.Setenv("STORAGE_PATH", storageDir)
+	cfg := &config.Config{
+		RateLimitShare: 60,
+		RateLimitAdmin: 10,
+		BotDetection:   true,
+		BotIPRanges:    []netip.Prefix{netip.MustParsePrefix("198.51.100.0/24")},
+	}
+	return handler.New(dbConn, storage.New(storageDir), web.EmbedFS, cfg, nil), q
+}
+
+// waitForBotViews waits for the asynchronously logged bot views of a link.
+func waitForBotViews(t *testing.T, q *sqlc.Queries, linkID, want int64) {
+	t.Helper()
+	var got int64
+	for deadline := time.Now().Add(2 * time.Second); time.Now().Before(deadline); time.Sleep(10 * time.Millisecond) {
+		links, err := q.ListShareLinksWithDetails(context.Background(), sqlc.ListShareLinksWithDetailsParams{Limit: 10})
+		if err != nil {
+			t.Fatalf("ListShareLinksWithDetails: %v", err)
+		}
+		for _, l := range links {
+			if l.ID == linkID {
+				got = l.BotViews
+			}
+		}
+		if got == want {
+			return
+		}
+	}
+	t.Errorf("expected %d bot views, got %d", want, got)
+}
+
+func TestViewShareLink_Bots(t *testing.T) {
+	h, q := setupShareWithBots(t)
+	ctx := context.Background()
+
+	album := testutil.CreateTestAlbum(t, q, "Private Party", "")
+	photo := testutil.CreateTestPhoto(t, q, album.ID, "cake.webp")
+	link := testutil.CreateTestShareLink(t, q, album.ID, "bot-token", 1, time.Time{})
+
+	// The link previewer and the mail scanner get the minimal page
+	for _, ua := range []string{whatsAppUA, "Mozilla/5.0 (compatible; Barracuda Sentinel)"} {
+		w := getShare(h.ViewShareLink, link.Token, "/s/bot-token", withHeader("User-Agent", ua))
+		if w.Code != http.StatusOK {
+			t.Fatalf("%s: expected 200, got %d", ua, w.Code)
+		}
+		body := w.Body.String()
+		if strings.Contains(body, "Private Party") || strings.Contains(body, "/photos/") {
+			t.Errorf("%s: expected nothing of the album without a link preview", ua)
+		}
+		if w.Header().Get("X-Robots-Tag") == "" || len(w.Result().Cookies()) != 0 {
+			t.Errorf("%s: expected a noindex page without cookies", ua)
+		}
+	}
+
+	// A scanner in a configured IP range posing as a browser
+	scanner := getShare(h.ViewShareLink, link.Token, "", withHeader("User-Agent", browserUA), fromIP("198.51.100.9"))
+	if strings.Contains(scanner.Body.String(), "Private Party") {
+		t.Errorf("expected the configured IP range to get the minimal page")
+	}
+
+	if views, _ := q.CountUniqueShareLinkViews(ctx, link.ID); views != 0 {
+		t.Fatalf("expected bots not to use up the view, got %d", views)
+	}
+	waitForBotViews(t, q, link.ID, 3)
+
+	// The recipient still gets the only view
+	w := getShare(h.ViewShareLink, link.Token, "", withHeader("User-Agent", browserUA), fromIP("203.0.113.7"))
+	if w.Code != http.StatusOK || !strings.Contains(w.Body.String(), fmt.Sprintf("/s/bot-token/photos/%d.webp", photo.ID)) {
+		t.Fatalf("expected the recipient to see the album, got %d", w.Code)
+	}
+	if views, _ := q.CountUniqueShareLinkViews(ctx, link.ID); views != 1 {
+		t.Errorf("expected the recipient's view to count, got %d", views)
+	}
+}
+
+func TestViewShareLink_BotDetectionOff(t *testing.T) {
+	h, q, cleanup := setupTestHandlerForShare(t)
+	defer cleanup()
+
+	album := testutil.CreateTestAlbum(t, q, "Holidays", "")
+	link := testutil.CreateTestShareLink(t, q, album.ID, "no-bots-token", 0, time.Time{})
+
+	w := getShare(h.ViewShareLink, link.Token, "/s/no-bots-token", withHeader("User-Agent", whatsAppUA))
+	if !strings.Contains(w.Body.String(), "Holidays") {
+		t.Errorf("expected bots to be served like viewers with detection off")
+	}
+	if views, _ := q.CountUniqueShareLinkViews(context.Background(), link.ID); views != 1 {
+		t.Errorf("expected the view to count with detection off, got %d", views)
+	}
+}
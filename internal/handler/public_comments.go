@@ -0,0 +1,102 @@
+package handler
+
+import (
+	"database/sql"
+	"errors"
+	"log"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/go-chi/chi/v5"
+
+	"familyshare/internal/db/sqlc"
+)
+
+const (
+	maxCommentNameLength = 50
+	maxCommentBodyLength = 1000
+)
+
+// PostShareComment handles POST /s/{token}/photos/{id}/comments
+// Guests have no admin session, so the form carries a share-scoped CSRF token.
+func (h *Handler) PostShareComment(w http.ResponseWriter, r *http.Request) {
+	token := chi.URLParam(r, "token")
+	photoID, err := strconv.ParseInt(chi.URLParam(r, "id"), 10, 64)
+	if token == "" || err != nil {
+		http.NotFound(w, r)
+		return
+	}
+
+	if err := r.ParseForm(); err != nil {
+		http.Error(w, "invalid form", http.StatusBadRequest)
+		return
+	}
+
+	ctx := r.Context()
+	link, err := h.activeShareLink(ctx, token)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) || errors.Is(err, errShareInactive) {
+			http.NotFound(w, r)
+			return
+		}
+		log.Printf("error loading share link for comment: %v", err)
+		http.Error(w, "Internal server error", http.StatusInternalServerError)
+		return
+	}
+
+	if !link.AllowComments {
+		http.Error(w, "comments are disabled for this link", http.StatusForbidden)
+		return
+	}
+
+	_, viewerHash := h.shareViewerHash(r, link)
+	if !h.csrf.ValidScopedToken(shareActionScope(token, viewerHash), r.PostFormValue("csrf_token")) {
+		http.Error(w, "CSRF token invalid", http.StatusForbidden)
+		return
+	}
+
+	photo, err := h.queries.GetPhoto(ctx, photoID)
+	if err != nil || !h.shareIncludesPhoto(ctx, link, photo) {
+		http.NotFound(w, r)
+		return
+	}
+
+	name := strings.Join(strings.Fields(r.PostFormValue("display_name")), " ")
+	body := strings.TrimSpace(r.PostFormValue("body"))
+	if name == "" || body == "" {
+		http.Error(w, "name and comment are required", http.StatusBadRequest)
+		return
+	}
+	if len([]rune(name)) > maxCommentNameLength {
+		http.Error(w, "name is too long", http.StatusBadRequest)
+		return
+	}
+	if len([]rune(body)) > maxCommentBodyLength {
+		http.Error(w, "comment is too long", http.StatusBadRequest)
+		return
+	}
+
+	comment, err := h.queries.CreatePhotoComment(ctx, sqlc.CreatePhotoCommentParams{
+		PhotoID:     photo.ID,
+		ShareLinkID: link.ID,
+		DisplayName: name,
+		Body:        body,
+	})
+	if err != nil {
+		log.Printf("failed to create comment on photo %d: %v", photo.ID, err)
+		http.Error(w, "failed to post comment", http.StatusInternalServerError)
+		return
+	}
+
+	if !IsHTMX(r) {
+		http.Redirect(w, r, "/s/"+token, http.StatusSeeOther)
+		return
+	}
+
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	if err := h.renderTemplateIn(w, h.shareLocale(w, r, link), "comment_item", comment); err != nil {
+		log.Printf("template render error for comment_item: %v", err)
+		http.Error(w, "template render error", http.StatusInternalServerError)
+	}
+}
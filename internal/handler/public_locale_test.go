@@ -0,0 +1,114 @@
+package handler_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"familyshare/internal/db/sqlc"
+	"familyshare/internal/testutil"
+)
+
+func TestShareLocale_AcceptLanguage(t *testing.T) {
+	h, q, cleanup := setupTestHandlerForShare(t)
+	defer cleanup()
+
+	album := testutil.CreateTestAlbum(t, q, "Natal", "")
+	testutil.CreateTestPhoto(t, q, album.ID, "tree.webp")
+	link := testutil.CreateTestShareLink(t, q, album.ID, "locale-token", 0, time.Time{})
+
+	for _, tc := range []struct {
+		header, locale, want string
+	}{
+		{"", "en", "1 photo"},
+		{"pt-BR,pt;q=0.9,en;q=0.8", "pt", "Compartilhado pelo FamilyShare"},
+		{"es-AR", "es", "Compartido con FamilyShare"},
+		{"de-DE", "en", "Shared via FamilyShare"},
+	} {
+		w := getShare(h.ViewShareLink, link.Token, "", withHeader("Accept-Language", tc.header))
+		if w.Code != http.StatusOK {
+			t.Fatalf("%q: expected status 200, got %d", tc.header, w.Code)
+		}
+		body := w.Body.String()
+		if !strings.Contains(body, tc.want) || !strings.Contains(body, `lang="`+tc.locale+`"`) {
+			t.Errorf("%q: expected a page in %s with %q", tc.header, tc.locale, tc.want)
+		}
+		if got := w.Header().Get("Content-Language"); got != tc.locale {
+			t.Errorf("%q: expected Content-Language %s, got %q", tc.header, tc.locale, got)
+		}
+		if got := w.Header().Get("Vary"); got != "Accept-Language" {
+			t.Errorf("%q: expected the page to vary by Accept-Language, got %q", tc.header, got)
+		}
+	}
+}
+
+func TestShareLocale_LinkOverride(t *testing.T) {
+	h, q, cleanup := setupTestHandlerForShare(t)
+	defer cleanup()
+	ctx := context.Background()
+
+	album := testutil.CreateTestAlbum(t, q, "Navidad", "")
+	testutil.CreateTestPhoto(t, q, album.ID, "tree.webp")
+
+	form := url.Values{"target_type": {"album"}, "target_id": {strconv.FormatInt(album.ID, 10)}, "locale": {"es"}}
+	w := httptest.NewRecorder()
+	h.CreateShareLink(w, shareAdminRequest("POST", "/admin/shares", "", form))
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected status 200, got %d: %s", w.Code, w.Body.String())
+	}
+	links, err := q.ListShareLinks(ctx, sqlc.ListShareLinksParams{Limit: 10})
+	if err != nil || len(links) != 1 || links[0].Locale.String != "es" {
+		t.Fatalf("expected one link in es, got %+v (%v)", links, err)
+	}
+	link := links[0]
+
+	// The link's language wins over the browser's
+	w = getShare(h.ViewShareLink, link.Token, "", withHeader("Accept-Language", "pt-BR"))
+	if body := w.Body.String(); !strings.Contains(body, "Compartido con FamilyShare") || w.Header().Get("Content-Language") != "es" {
+		t.Errorf("expected the page in Spanish, got %q", w.Header().Get("Content-Language"))
+	}
+	if got := w.Header().Get("Vary"); got != "" {
+		t.Errorf("expected no Vary header for a link with a language, got %q", got)
+	}
+
+	// Going back to automatic follows the browser again
+	w = httptest.NewRecorder()
+	h.UpdateShareLink(w, shareAdminRequest("PUT", "/admin/shares/"+strconv.FormatInt(link.ID, 10), strconv.FormatInt(link.ID, 10), url.Values{"locale": {""}}))
+	if w.Code != http.StatusNoContent {
+		t.Fatalf("expected status 204, got %d: %s", w.Code, w.Body.String())
+	}
+	if w := getShare(h.ViewShareLink, link.Token, "", withHeader("Accept-Language", "pt-BR")); w.Header().Get("Content-Language") != "pt" {
+		t.Errorf("expected the page in Portuguese, got %q", w.Header().Get("Content-Language"))
+	}
+
+	w = httptest.NewRecorder()
+	h.UpdateShareLink(w, shareAdminRequest("PUT", "/admin/shares/"+strconv.FormatInt(link.ID, 10), strconv.FormatInt(link.ID, 10), url.Values{"locale": {"fr"}}))
+	if w.Code != http.StatusBadRequest {
+		t.Errorf("expected status 400 for an unsupported language, got %d", w.Code)
+	}
+}
+
+func TestShareLocale_Unavailable(t *testing.T) {
+	h, q, cleanup := setupTestHandlerForShare(t)
+	defer cleanup()
+
+	album := testutil.CreateTestAlbum(t, q, "Natal", "")
+	link := testutil.CreateTestShareLink(t, q, album.ID, "revoked-locale-token", 0, time.Time{})
+	if err := q.RevokeShareLink(context.Background(), link.ID); err != nil {
+		t.Fatalf("revoke: %v", err)
+	}
+
+	w := getShare(h.ViewShareLink, link.Token, "", withHeader("Accept-Language", "pt-BR"))
+	if w.Code != http.StatusGone || !strings.Contains(w.Body.String(), "Este link de compartilhamento foi revogado") {
+		t.Errorf("expected the revoked page in Portuguese, got %d", w.Code)
+	}
+	w = getShare(h.ViewShareLink, "no-such-token", "", withHeader("Accept-Language", "es"))
+	if w.Code != http.StatusNotFound || !strings.Contains(w.Body.String(), "Enlace para compartir no encontrado") {
+		t.Errorf("expected the not found page in Spanish, got %d", w.Code)
+	}
+}
@@ -0,0 +1,143 @@
+package handler
+
+import (
+	"context"
+	"database/sql"
+	"log"
+	"net/http"
+	"os"
+
+	"github.com/go-chi/chi/v5"
+
+	"familyshare/internal/db/sqlc"
+	"familyshare/internal/pipeline"
+	"familyshare/internal/storage"
+)
+
+// openGraph is the link preview of a share page.
+type openGraph struct {
+	Title       string
+	Description string
+	URL         string
+	ImageURL    string
+}
+
+// absoluteURL returns the absolute URL of a path on this server, as link
+// previews require.
+func (h *Handler) absoluteURL(r *http.Request, path string) string {
+	scheme := "http"
+	if r.TLS != nil || (h.config != nil && h.config.ForceHTTPS) {
+		scheme = "https"
+	}
+	return scheme + "://" + r.Host + path
+}
+
+// openGraphFor returns the link preview of a share page, or nil when the link
+// hasn't opted in to previews.
+func (h *Handler) openGraphFor(r *http.Request, link sqlc.ShareLink, title string) *openGraph {
+	if !link.OpenGraph {
+		return nil
+	}
+	og := &openGraph{
+		Title:       title,
+		Description: link.Message.String,
+		URL:         h.absoluteURL(r, "/s/"+link.Token),
+	}
+	if _, err := h.sharePreviewPhoto(r.Context(), link); err == nil {
+		og.ImageURL = h.absoluteURL(r, "/s/"+link.Token+"/preview.jpg")
+	}
+	return og
+}
+
+// sharePreviewPhoto picks the photo of a share link's preview image: the
+// shared photo, the album cover or else the album's first photo, or the
+// first tagged photo.
+func (h *Handler) sharePreviewPhoto(ctx context.Context, link sqlc.ShareLink) (sqlc.Photo, error) {
+	q := sqlc.New(h.db)
+	switch link.TargetType {
+	case "photo":
+		return q.GetPhoto(ctx, link.TargetID)
+	case "album":
+		album, err := q.GetAlbum(ctx, link.TargetID)
+		if err != nil {
+			return sqlc.Photo{}, err
+		}
+		if album.CoverPhotoID.Valid {
+			cover, err := q.GetPhoto(ctx, album.CoverPhotoID.Int64)
+			if err == nil && cover.AlbumID == album.ID {
+				return cover, nil
+			}
+		}
+		photos, err := listAlbumPhotos(ctx, q, album, 1, 0)
+		if err != nil {
+			return sqlc.Photo{}, err
+		}
+		if len(photos) == 0 {
+			return sqlc.Photo{}, sql.ErrNoRows
+		}
+		return photos[0], nil
+	case "tag":
+		photos, err := q.ListPhotosByTag(ctx, sqlc.ListPhotosByTagParams{TagID: link.TargetID, Limit: 1})
+		if err != nil {
+			return sqlc.Photo{}, err
+		}
+		if len(photos) == 0 {
+			return sqlc.Photo{}, sql.ErrNoRows
+		}
+		return photos[0], nil
+	}
+	return sqlc.Photo{}, sql.ErrNoRows
+}
+
+// SharePreviewImage handles GET /s/{token}/preview.jpg
+// It serves the link preview image of a share link that opted in to
+// previews. Crawlers fetch it to show the preview, so it doesn't count a
+// view. The preview is built on first use and again when the photo changes.
+func (h *Handler) SharePreviewImage(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	link, err := h.shareLinkByToken(ctx, chi.URLParam(r, "token"))
+	if err != nil {
+		if err != sql.ErrNoRows {
+			log.Printf("error loading share link for preview: %v", err)
+		}
+		http.NotFound(w, r)
+		return
+	}
+	if !link.OpenGraph || !h.shareLinkUsable(ctx, link) {
+		http.NotFound(w, r)
+		return
+	}
+
+	photo, err := h.sharePreviewPhoto(ctx, link)
+	if err != nil {
+		if err != sql.ErrNoRows {
+			log.Printf("error loading preview photo: %v", err)
+		}
+		http.NotFound(w, r)
+		return
+	}
+
+	src := photoFilePath(h.storage.BaseDir, photo)
+	dst := storage.PreviewPath(h.storage.BaseDir, photo.ID)
+	if previewStale(src, dst) {
+		if err := pipeline.WritePreview(src, dst); err != nil {
+			log.Printf("failed to build preview of photo %d: %v", photo.ID, err)
+			http.NotFound(w, r)
+			return
+		}
+	}
+
+	w.Header().Set("Cache-Control", "public, max-age=3600")
+	http.ServeFile(w, r, dst)
+}
+
+// previewStale reports whether the preview at dst is missing or older than
+// the stored photo at src, which edits and reprocessing replace.
+func previewStale(src, dst string) bool {
+	preview, err := os.Stat(dst)
+	if err != nil {
+		return true
+	}
+	photo, err := os.Stat(src)
+	return err == nil && photo.ModTime().After(preview.ModTime())
+}
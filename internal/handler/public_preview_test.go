@@ -0,0 +1,86 @@
+package handler_test
+
+import (
+	"bytes"
+	"context"
+	"database/sql"
+	"image"
+	"image/jpeg"
+	"image/png"
+	"net/http"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"familyshare/internal/db/sqlc"
+	"familyshare/internal/storage"
+	"familyshare/internal/testutil"
+)
+
+const whatsAppUA = "WhatsApp/2.23.20.0 A"
+
+func TestSharePreview_OpenGraph(t *testing.T) {
+	h, q := setupShareWithBots(t)
+	ctx := context.Background()
+
+	album := testutil.CreateTestAlbum(t, q, "Summer 2024", "")
+	photo := testutil.CreateTestPhoto(t, q, album.ID, "beach.webp")
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, image.NewRGBA(image.Rect(0, 0, 1920, 1080))); err != nil {
+		t.Fatalf("encode photo: %v", err)
+	}
+	if err := storage.AtomicWrite(storage.PhotoPathAt(os.Getenv("STORAGE_PATH"), album.ID, photo.ID, photo.Format, photo.CreatedAt.Time), &buf); err != nil {
+		t.Fatalf("write photo: %v", err)
+	}
+
+	plain := testutil.CreateTestShareLink(t, q, album.ID, "plain-token", 0, time.Time{})
+	if body := getShare(h.ViewShareLink, plain.Token, "/s/plain-token").Body.String(); strings.Contains(body, "og:title") {
+		t.Errorf("expected no link preview without opting in")
+	}
+	if w := getShare(h.SharePreviewImage, plain.Token, "/s/plain-token/preview.jpg", withHeader("User-Agent", whatsAppUA)); w.Code != http.StatusNotFound {
+		t.Errorf("expected no preview image without opting in, got %d", w.Code)
+	}
+
+	link, err := q.CreateShareLink(ctx, sqlc.CreateShareLinkParams{
+		Token:      "preview-token",
+		TargetType: "album",
+		TargetID:   album.ID,
+		MaxViews:   sql.NullInt64{Int64: 1, Valid: true},
+		Message:    sql.NullString{String: "Photos from the lake house", Valid: true},
+		OpenGraph:  true,
+	})
+	if err != nil {
+		t.Fatalf("CreateShareLink: %v", err)
+	}
+
+	// The crawler sees the preview and doesn't use up the only view
+	body := getShare(h.ViewShareLink, link.Token, "/s/preview-token", withHeader("User-Agent", whatsAppUA)).Body.String()
+	for _, want := range []string{
+		`<meta property="og:title" content="Summer 2024">`,
+		`<meta property="og:description" content="Photos from the lake house">`,
+		`<meta property="og:image" content="http://photos.example.com/s/preview-token/preview.jpg">`,
+	} {
+		if !strings.Contains(body, want) {
+			t.Errorf("expected %s on the page", want)
+		}
+	}
+	w := getShare(h.SharePreviewImage, link.Token, "/s/preview-token/preview.jpg", withHeader("User-Agent", whatsAppUA))
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected the preview image, got %d", w.Code)
+	}
+	cfg, err := jpeg.DecodeConfig(w.Body)
+	if err != nil || cfg.Width != 1120 || cfg.Height != 630 {
+		t.Errorf("expected a 1120x630 JPEG preview, got %dx%d (%v)", cfg.Width, cfg.Height, err)
+	}
+	if views, _ := q.CountUniqueShareLinkViews(ctx, link.ID); views != 0 {
+		t.Fatalf("expected the preview not to count views, got %d", views)
+	}
+
+	if w := getShare(h.ViewShareLink, link.Token, "/s/preview-token", withHeader("User-Agent", "Mozilla/5.0")); w.Code != http.StatusOK {
+		t.Fatalf("expected the viewer to see the album, got %d", w.Code)
+	}
+	if views, _ := q.CountUniqueShareLinkViews(ctx, link.ID); views != 1 {
+		t.Errorf("expected the viewer to count, got %d", views)
+	}
+}
@@ -0,0 +1,274 @@
+package handler
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"log"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+
+	"familyshare/internal/db/sqlc"
+)
+
+// reactionKinds lists the reactions viewers can choose from, in display
+// order. Their labels are message catalog keys.
+var reactionKinds = []struct {
+	Key   string
+	Emoji string
+	Label string
+}{
+	{"heart", "❤️", "reaction.heart"},
+	{"laugh", "😂", "reaction.laugh"},
+	{"wow", "😮", "reaction.wow"},
+	{"clap", "👏", "reaction.clap"},
+}
+
+// errShareInactive is returned for links that exist but can no longer be used.
+var errShareInactive = errors.New("share link revoked or expired")
+
+// reactionCount is one emoji with its tally for a photo.
+type reactionCount struct {
+	Key   string
+	Emoji string
+	Label string
+	Count int64
+	Mine  bool
+}
+
+// photoReactions is the data rendered by the public "photo_reactions" template.
+type photoReactions struct {
+	Token   string
+	CSRF    string
+	PhotoID int64
+	Counts  []reactionCount
+}
+
+// sharePhoto is a photo on a public page together with its reaction bar.
+type sharePhoto struct {
+	sqlc.Photo
+	Reactions photoReactions
+}
+
+// reactionTally maps photo ID to reaction key to count.
+type reactionTally map[int64]map[string]int64
+
+func (t reactionTally) add(photoID int64, reaction string, count int64) {
+	if t[photoID] == nil {
+		t[photoID] = map[string]int64{}
+	}
+	t[photoID][reaction] += count
+}
+
+// counts expands a photo's tally into every known reaction, in display order.
+func (t reactionTally) counts(photoID int64, mine string) []reactionCount {
+	counts := make([]reactionCount, 0, len(reactionKinds))
+	for _, kind := range reactionKinds {
+		counts = append(counts, reactionCount{
+			Key:   kind.Key,
+			Emoji: kind.Emoji,
+			Label: kind.Label,
+			Count: t[photoID][kind.Key],
+			Mine:  kind.Key == mine,
+		})
+	}
+	return counts
+}
+
+// summary lists only the reactions a photo actually received.
+func (t reactionTally) summary(photoID int64) []reactionCount {
+	var out []reactionCount
+	for _, c := range t.counts(photoID, "") {
+		if c.Count > 0 {
+			out = append(out, c)
+		}
+	}
+	return out
+}
+
+// total sums all reactions on a photo.
+func (t reactionTally) total(photoID int64) int64 {
+	var n int64
+	for _, c := range t[photoID] {
+		n += c
+	}
+	return n
+}
+
+// validReaction reports whether key is one of reactionKinds.
+func validReaction(key string) bool {
+	for _, kind := range reactionKinds {
+		if kind.Key == key {
+			return true
+		}
+	}
+	return false
+}
+
+// shareActionScope binds the CSRF token of guest forms (comments, reactions)
+// to one share link and one viewer, so a token lifted from one link cannot be
+// replayed against another.
+func shareActionScope(token, viewerHash string) string {
+	return "share-action:" + token + ":" + viewerHash
+}
+
+// activeShareLink loads a share link that is neither revoked nor expired.
+// It returns sql.ErrNoRows for unknown tokens and errShareInactive otherwise.
+func (h *Handler) activeShareLink(ctx context.Context, token string) (sqlc.ShareLink, error) {
+	link, err := h.shareLinkByToken(ctx, token)
+	if err != nil {
+		return link, err
+	}
+	if link.RevokedAt.Valid || (link.ExpiresAt.Valid && time.Now().UTC().After(link.ExpiresAt.Time)) {
+		return link, errShareInactive
+	}
+	return link, nil
+}
+
+// sharePhotos attaches the viewer's reaction bar to each photo of a public page.
+func (h *Handler) sharePhotos(r *http.Request, link sqlc.ShareLink, photos []sqlc.Photo, tally reactionTally) []sharePhoto {
+	_, viewerHash := h.shareViewerHash(r, link)
+	csrf := h.csrf.ScopedToken(shareActionScope(link.Token, viewerHash))
+
+	mine := map[int64]string{}
+	rows, err := h.queries.ListViewerReactions(r.Context(), viewerHash)
+	if err != nil {
+		log.Printf("error loading viewer reactions: %v", err)
+	}
+	for _, row := range rows {
+		mine[row.PhotoID] = row.Reaction
+	}
+
+	out := make([]sharePhoto, 0, len(photos))
+	for _, p := range photos {
+		out = append(out, sharePhoto{
+			Photo: p,
+			Reactions: photoReactions{
+				Token:   link.Token,
+				CSRF:    csrf,
+				PhotoID: p.ID,
+				Counts:  tally.counts(p.ID, mine[p.ID]),
+			},
+		})
+	}
+	return out
+}
+
+// photoReactionTally loads the reaction counts of a single photo.
+func photoReactionTally(ctx context.Context, q *sqlc.Queries, photoID int64) reactionTally {
+	tally := reactionTally{}
+	rows, err := q.ListReactionCountsForPhoto(ctx, photoID)
+	if err != nil {
+		log.Printf("error loading reactions for photo %d: %v", photoID, err)
+	}
+	for _, row := range rows {
+		tally.add(row.PhotoID, row.Reaction, row.Count)
+	}
+	return tally
+}
+
+// PostShareReaction handles POST /s/{token}/photos/{id}/reactions
+// Choosing the viewer's current reaction again withdraws it.
+func (h *Handler) PostShareReaction(w http.ResponseWriter, r *http.Request) {
+	token := chi.URLParam(r, "token")
+	photoID, err := strconv.ParseInt(chi.URLParam(r, "id"), 10, 64)
+	if token == "" || err != nil {
+		http.NotFound(w, r)
+		return
+	}
+
+	if err := r.ParseForm(); err != nil {
+		http.Error(w, "invalid form", http.StatusBadRequest)
+		return
+	}
+
+	reaction := r.PostFormValue("reaction")
+	if !validReaction(reaction) {
+		http.Error(w, "invalid reaction", http.StatusBadRequest)
+		return
+	}
+
+	ctx := r.Context()
+	link, err := h.activeShareLink(ctx, token)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) || errors.Is(err, errShareInactive) {
+			http.NotFound(w, r)
+			return
+		}
+		log.Printf("error loading share link for reaction: %v", err)
+		http.Error(w, "Internal server error", http.StatusInternalServerError)
+		return
+	}
+
+	_, viewerHash := h.shareViewerHash(r, link)
+	if !h.csrf.ValidScopedToken(shareActionScope(token, viewerHash), r.PostFormValue("csrf_token")) {
+		http.Error(w, "CSRF token invalid", http.StatusForbidden)
+		return
+	}
+
+	photo, err := h.queries.GetPhoto(ctx, photoID)
+	if err != nil || !h.shareIncludesPhoto(ctx, link, photo) {
+		http.NotFound(w, r)
+		return
+	}
+
+	current, err := h.queries.GetViewerReaction(ctx, sqlc.GetViewerReactionParams{PhotoID: photo.ID, ViewerHash: viewerHash})
+	if err != nil && err != sql.ErrNoRows {
+		log.Printf("error loading reaction on photo %d: %v", photo.ID, err)
+		http.Error(w, "failed to save reaction", http.StatusInternalServerError)
+		return
+	}
+
+	removed := current == reaction
+	if removed {
+		err = h.queries.DeletePhotoReaction(ctx, sqlc.DeletePhotoReactionParams{PhotoID: photo.ID, ViewerHash: viewerHash})
+	} else {
+		err = h.queries.UpsertPhotoReaction(ctx, sqlc.UpsertPhotoReactionParams{
+			PhotoID:     photo.ID,
+			ShareLinkID: sql.NullInt64{Int64: link.ID, Valid: true},
+			ViewerHash:  viewerHash,
+			Reaction:    reaction,
+		})
+	}
+	if err != nil {
+		log.Printf("failed to save reaction on photo %d: %v", photo.ID, err)
+		http.Error(w, "failed to save reaction", http.StatusInternalServerError)
+		return
+	}
+
+	// Log reaction event (fire and forget)
+	go func(photoID, shareID int64) {
+		logCtx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+		defer cancel()
+		if removed {
+			_ = h.metrics.LogReactionRemoved(logCtx, photoID, shareID)
+		} else {
+			_ = h.metrics.LogReaction(logCtx, photoID, shareID)
+		}
+	}(photo.ID, link.ID)
+
+	if !IsHTMX(r) {
+		http.Redirect(w, r, "/s/"+token, http.StatusSeeOther)
+		return
+	}
+
+	mine := reaction
+	if removed {
+		mine = ""
+	}
+	data := photoReactions{
+		Token:   token,
+		CSRF:    h.csrf.ScopedToken(shareActionScope(token, viewerHash)),
+		PhotoID: photo.ID,
+		Counts:  photoReactionTally(ctx, h.queries, photo.ID).counts(photo.ID, mine),
+	}
+
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	if err := h.renderTemplateIn(w, h.shareLocale(w, r, link), "photo_reactions", data); err != nil {
+		log.Printf("template render error for photo_reactions: %v", err)
+		http.Error(w, "template render error", http.StatusInternalServerError)
+	}
+}
@@ -0,0 +1,182 @@
+package handler_test
+
+import (
+	"context"
+	"database/sql"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/go-chi/chi/v5"
+
+	"familyshare/internal/db/sqlc"
+	"familyshare/internal/handler"
+	"familyshare/internal/testutil"
+)
+
+func postReaction(h *handler.Handler, token string, photoID int64, cookies []*http.Cookie, csrf, reaction string) *httptest.ResponseRecorder {
+	vals := url.Values{}
+	vals.Set("csrf_token", csrf)
+	vals.Set("reaction", reaction)
+	idStr := strconv.FormatInt(photoID, 10)
+	req := httptest.NewRequest("POST", "/s/"+token+"/photos/"+idStr+"/reactions", strings.NewReader(vals.Encode()))
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.Header.Set("HX-Request", "true")
+	for _, c := range cookies {
+		req.AddCookie(c)
+	}
+	rctx := chi.NewRouteContext()
+	rctx.URLParams.Add("token", token)
+	rctx.URLParams.Add("id", idStr)
+	req = req.WithContext(context.WithValue(req.Context(), chi.RouteCtxKey, rctx))
+	w := httptest.NewRecorder()
+	h.PostShareReaction(w, req)
+	return w
+}
+
+func reactionCounts(t *testing.T, q *sqlc.Queries, photoID int64) map[string]int64 {
+	t.Helper()
+	rows, err := q.ListReactionCountsForPhoto(context.Background(), photoID)
+	if err != nil {
+		t.Fatalf("ListReactionCountsForPhoto: %v", err)
+	}
+	counts := map[string]int64{}
+	for _, row := range rows {
+		counts[row.Reaction] = row.Count
+	}
+	return counts
+}
+
+func TestPostShareReaction_OnePerViewer(t *testing.T) {
+	h, q := setupCommentTest(t)
+	ctx := context.Background()
+
+	album := testutil.CreateTestAlbum(t, q, "Yearbook", "")
+	photo := testutil.CreateTestPhoto(t, q, album.ID, "beach.webp")
+	if _, err := q.CreateShareLink(ctx, sqlc.CreateShareLinkParams{Token: "react-token", TargetType: "album", TargetID: album.ID}); err != nil {
+		t.Fatalf("CreateShareLink: %v", err)
+	}
+
+	cookies, csrf := visitShare(t, h, "react-token")
+	if csrf == "" {
+		t.Fatal("expected reaction form with CSRF token on album page")
+	}
+
+	w := postReaction(h, "react-token", photo.ID, cookies, csrf, "heart")
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d: %s", w.Code, w.Body.String())
+	}
+	if !strings.Contains(w.Body.String(), `aria-pressed="true"`) {
+		t.Errorf("expected the chosen reaction to be marked in the response")
+	}
+	if got := reactionCounts(t, q, photo.ID); got["heart"] != 1 {
+		t.Fatalf("expected one heart, got %v", got)
+	}
+
+	// Choosing another emoji replaces the viewer's reaction
+	postReaction(h, "react-token", photo.ID, cookies, csrf, "laugh")
+	if got := reactionCounts(t, q, photo.ID); got["heart"] != 0 || got["laugh"] != 1 {
+		t.Fatalf("expected reaction replaced by laugh, got %v", got)
+	}
+
+	// Choosing the same emoji again withdraws it
+	postReaction(h, "react-token", photo.ID, cookies, csrf, "laugh")
+	if got := reactionCounts(t, q, photo.ID); len(got) != 0 {
+		t.Fatalf("expected reaction withdrawn, got %v", got)
+	}
+}
+
+func TestPostShareReaction_Rejected(t *testing.T) {
+	h, q := setupCommentTest(t)
+	ctx := context.Background()
+
+	album := testutil.CreateTestAlbum(t, q, "Yearbook", "")
+	photo := testutil.CreateTestPhoto(t, q, album.ID, "beach.webp")
+	otherAlbum := testutil.CreateTestAlbum(t, q, "Private", "")
+	other := testutil.CreateTestPhoto(t, q, otherAlbum.ID, "secret.webp")
+	if _, err := q.CreateShareLink(ctx, sqlc.CreateShareLinkParams{Token: "react-token", TargetType: "album", TargetID: album.ID}); err != nil {
+		t.Fatalf("CreateShareLink: %v", err)
+	}
+
+	cookies, csrf := visitShare(t, h, "react-token")
+
+	tests := []struct {
+		name     string
+		photoID  int64
+		csrf     string
+		reaction string
+		want     int
+	}{
+		{"unknown reaction", photo.ID, csrf, "poop", http.StatusBadRequest},
+		{"missing csrf", photo.ID, "", "heart", http.StatusForbidden},
+		{"photo outside link", other.ID, csrf, "heart", http.StatusNotFound},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := postReaction(h, "react-token", tt.photoID, cookies, tt.csrf, tt.reaction)
+			if w.Code != tt.want {
+				t.Errorf("expected %d, got %d: %s", tt.want, w.Code, w.Body.String())
+			}
+		})
+	}
+
+	if got := reactionCounts(t, q, photo.ID); len(got) != 0 {
+		t.Fatalf("expected no reactions stored, got %v", got)
+	}
+}
+
+func TestReactionCounts_ShownOnGridAndAdminAlbum(t *testing.T) {
+	h, q := setupCommentTest(t)
+	ctx := context.Background()
+
+	album := testutil.CreateTestAlbum(t, q, "Yearbook", "")
+	photo := testutil.CreateTestPhoto(t, q, album.ID, "beach.webp")
+	link, err := q.CreateShareLink(ctx, sqlc.CreateShareLinkParams{Token: "count-token", TargetType: "album", TargetID: album.ID})
+	if err != nil {
+		t.Fatalf("CreateShareLink: %v", err)
+	}
+	for i, viewer := range []string{"viewer-a", "viewer-b", "viewer-c"} {
+		reaction := "heart"
+		if i == 2 {
+			reaction = "wow"
+		}
+		if err := q.UpsertPhotoReaction(ctx, sqlc.UpsertPhotoReactionParams{
+			PhotoID:     photo.ID,
+			ShareLinkID: sql.NullInt64{Int64: link.ID, Valid: true},
+			ViewerHash:  viewer,
+			Reaction:    reaction,
+		}); err != nil {
+			t.Fatalf("UpsertPhotoReaction: %v", err)
+		}
+	}
+
+	// Public grid (HTMX page fragment)
+	req := httptest.NewRequest("GET", "/s/count-token?page=1", nil)
+	req.Header.Set("HX-Request", "true")
+	rctx := chi.NewRouteContext()
+	rctx.URLParams.Add("token", "count-token")
+	req = req.WithContext(context.WithValue(req.Context(), chi.RouteCtxKey, rctx))
+	w := httptest.NewRecorder()
+	h.ViewShareLink(w, req)
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d", w.Code)
+	}
+	if !strings.Contains(w.Body.String(), `<span class="reaction-count">2</span>`) {
+		t.Errorf("expected heart count of 2 in grid, got %s", w.Body.String())
+	}
+
+	// Admin album view aggregates the same counts
+	req = httptest.NewRequest("GET", "/admin/albums/1", nil)
+	rctx = chi.NewRouteContext()
+	rctx.URLParams.Add("id", strconv.FormatInt(album.ID, 10))
+	req = req.WithContext(context.WithValue(req.Context(), chi.RouteCtxKey, rctx))
+	w = httptest.NewRecorder()
+	h.ViewAlbum(w, req)
+	body := w.Body.String()
+	if !strings.Contains(body, "Most Loved") || !strings.Contains(body, "❤️ 2") || !strings.Contains(body, "😮 1") {
+		t.Errorf("expected reaction summary on admin album view")
+	}
+}
@@ -10,6 +10,7 @@ import (
 	"time"
 
 	"familyshare/internal/db/sqlc"
+	"familyshare/internal/i18n"
 	"familyshare/internal/security"
 
 	"github.com/go-chi/chi/v5"
@@ -17,40 +18,71 @@ import (
 
 // ViewShareLink handles public access to shared albums or photos via token
 func (h *Handler) ViewShareLink(w http.ResponseWriter, r *http.Request) {
+	link, ok := h.openShareLink(w, r)
+	if !ok {
+		return
+	}
+
+	// Render content based on target type
+	switch link.TargetType {
+	case "album":
+		h.renderShareAlbum(w, r, link)
+	case "photo":
+		h.renderSharePhoto(w, r, link)
+	case "tag":
+		h.renderShareTag(w, r, link)
+	default:
+		h.renderShareExpired(w, h.shareLocale(w, r, link), "expired.invalid_type", http.StatusBadRequest)
+	}
+}
+
+// openShareLink loads the share link of the request and checks that it can
+// be viewed, rendering the error page when it can't. An open link counts a
+// view for the viewer.
+func (h *Handler) openShareLink(w http.ResponseWriter, r *http.Request) (sqlc.ShareLink, bool) {
 	token := chi.URLParam(r, "token")
 	if token == "" {
-		h.renderShareExpired(w, "Invalid share link", http.StatusBadRequest)
-		return
+		h.renderShareExpired(w, h.shareLocale(w, r, sqlc.ShareLink{}), "expired.invalid", http.StatusBadRequest)
+		return sqlc.ShareLink{}, false
 	}
 
 	q := sqlc.New(h.db)
 
 	// 1. Load share link
-	link, err := q.GetShareLinkByToken(r.Context(), token)
+	link, recipientID, err := h.shareLinkRecipient(r.Context(), token)
 	if err != nil {
 		if err == sql.ErrNoRows {
-			h.renderShareExpired(w, "Share link not found", http.StatusNotFound)
+			h.renderShareExpired(w, h.shareLocale(w, r, sqlc.ShareLink{}), "expired.not_found", http.StatusNotFound)
 		} else {
 			log.Printf("error loading share link: %v", err)
 			http.Error(w, "Internal server error", http.StatusInternalServerError)
 		}
-		return
+		return sqlc.ShareLink{}, false
 	}
 
+	locale := h.shareLocale(w, r, link)
+
 	// 2. Check if revoked
 	if link.RevokedAt.Valid {
-		h.renderShareExpired(w, "This share link has been revoked", http.StatusGone)
-		return
+		h.renderShareExpired(w, locale, "expired.revoked", http.StatusGone)
+		return sqlc.ShareLink{}, false
 	}
 
 	// 3. Check expiration
 	if link.ExpiresAt.Valid && time.Now().UTC().After(link.ExpiresAt.Time) {
-		h.renderShareExpired(w, "This share link has expired", http.StatusGone)
-		return
+		h.renderShareExpired(w, locale, "expired.expired", http.StatusGone)
+		return sqlc.ShareLink{}, false
+	}
+
+	// Link previewers, mail scanners and URL checkers get a minimal page
+	// that doesn't count as a view
+	if h.isBot(r) {
+		h.renderShareBot(w, r, link)
+		return sqlc.ShareLink{}, false
 	}
 
 	// 4. Get or create viewer hash
-	viewerHash := security.GetViewerHash(r, token)
+	viewerToken, viewerHash := h.shareViewerHash(r, link)
 
 	// 5. Check view limit (before tracking the view)
 	if link.MaxViews.Valid {
@@ -59,8 +91,8 @@ func (h *Handler) ViewShareLink(w http.ResponseWriter, r *http.Request) {
 			log.Printf("error counting views: %v", err)
 			// Continue anyway, don't block access on count error
 		} else if uniqueViews >= link.MaxViews.Int64 {
-			h.renderShareExpired(w, "This share link has reached its view limit", http.StatusGone)
-			return
+			h.renderShareExpired(w, locale, "expired.view_limit", http.StatusGone)
+			return sqlc.ShareLink{}, false
 		}
 	}
 
@@ -68,6 +100,7 @@ func (h *Handler) ViewShareLink(w http.ResponseWriter, r *http.Request) {
 	err = q.IncrementShareLinkView(r.Context(), sqlc.IncrementShareLinkViewParams{
 		ShareLinkID: link.ID,
 		ViewerHash:  viewerHash,
+		RecipientID: recipientID,
 	})
 	if err != nil {
 		log.Printf("error tracking view: %v", err)
@@ -82,28 +115,84 @@ func (h *Handler) ViewShareLink(w http.ResponseWriter, r *http.Request) {
 	}(link.ID)
 
 	// 7. Set viewer hash cookie for future visits
-	security.SetViewerHashCookie(w, token, viewerHash, &link.ExpiresAt.Time, h.cookieOptions(r))
+	security.SetViewerHashCookie(w, viewerToken, viewerHash, &link.ExpiresAt.Time, h.cookieOptions(r))
+	return link, true
+}
 
-	// 8. Render content based on target type
-	switch link.TargetType {
-	case "album":
-		h.renderShareAlbum(w, r, link)
-	case "photo":
-		h.renderSharePhoto(w, r, link)
-	default:
-		h.renderShareExpired(w, "Invalid share link type", http.StatusBadRequest)
+// shareViewerHash returns the viewer hash of a request to a share link and
+// the token it is kept under: the recipient's token for a recipient's link,
+// and otherwise the link's own token, so a viewer opening the link by its
+// slug and by its token counts once.
+func (h *Handler) shareViewerHash(r *http.Request, link sqlc.ShareLink) (string, string) {
+	token := link.Token
+	if link.Slug.Valid && link.Token == link.Slug.String {
+		own, err := h.queries.GetShareLink(r.Context(), link.ID)
+		if err != nil {
+			log.Printf("error loading share link %d: %v", link.ID, err)
+		} else {
+			token = own.Token
+		}
 	}
+	return token, security.GetViewerHash(r, token)
+}
+
+// shareLinkByToken loads the share link a token opens, which may be the
+// link's slug or the token of one of its recipients. See shareLinkRecipient.
+func (h *Handler) shareLinkByToken(ctx context.Context, token string) (sqlc.ShareLink, error) {
+	link, _, err := h.shareLinkRecipient(ctx, token)
+	return link, err
+}
+
+// shareLinkRecipient loads the share link a token opens and the recipient
+// whose token it is, if any. A link opened by its slug or a recipient's token
+// carries that instead of its own token, so its pages link back through it. A
+// recipient's link counts as revoked once they are. A link with recipients
+// only opens through their tokens: its own token and slug are sql.ErrNoRows.
+func (h *Handler) shareLinkRecipient(ctx context.Context, token string) (sqlc.ShareLink, sql.NullInt64, error) {
+	link, err := h.queries.GetShareLinkByToken(ctx, token)
+	if err == sql.ErrNoRows && len(token) <= security.MaxSlugLength {
+		link, err = h.queries.GetShareLinkBySlug(ctx, sql.NullString{String: token, Valid: true})
+		link.Token = token
+	}
+	if err == nil {
+		recipients, err := h.queries.CountShareLinkRecipients(ctx, link.ID)
+		if err != nil {
+			return sqlc.ShareLink{}, sql.NullInt64{}, err
+		}
+		if recipients > 0 {
+			return sqlc.ShareLink{}, sql.NullInt64{}, sql.ErrNoRows
+		}
+		return link, sql.NullInt64{}, nil
+	}
+	if err != sql.ErrNoRows {
+		return sqlc.ShareLink{}, sql.NullInt64{}, err
+	}
+
+	recipient, err := h.queries.GetShareLinkRecipientByToken(ctx, token)
+	if err != nil {
+		return sqlc.ShareLink{}, sql.NullInt64{}, err
+	}
+	link, err = h.queries.GetShareLink(ctx, recipient.ShareLinkID)
+	if err != nil {
+		return sqlc.ShareLink{}, sql.NullInt64{}, err
+	}
+	link.Token = recipient.Token
+	if recipient.RevokedAt.Valid && !link.RevokedAt.Valid {
+		link.RevokedAt = recipient.RevokedAt
+	}
+	return link, sql.NullInt64{Int64: recipient.ID, Valid: true}, nil
 }
 
 // renderShareAlbum renders the public album view with HTMX pagination
 func (h *Handler) renderShareAlbum(w http.ResponseWriter, r *http.Request, link sqlc.ShareLink) {
 	q := sqlc.New(h.db)
+	locale := h.shareLocale(w, r, link)
 
 	// Load album
 	album, err := q.GetAlbum(r.Context(), link.TargetID)
 	if err != nil {
 		if err == sql.ErrNoRows {
-			h.renderShareExpired(w, "Album not found", http.StatusNotFound)
+			h.renderShareExpired(w, locale, "expired.album_not_found", http.StatusNotFound)
 		} else {
 			log.Printf("error loading album: %v", err)
 			http.Error(w, "Internal server error", http.StatusInternalServerError)
@@ -123,11 +212,7 @@ func (h *Handler) renderShareAlbum(w http.ResponseWriter, r *http.Request, link
 	offset := (pageNum - 1) * pageSize
 
 	// Load photos for this page (fetch one extra to check if there are more)
-	photos, err := q.ListPhotosByAlbum(r.Context(), sqlc.ListPhotosByAlbumParams{
-		AlbumID: album.ID,
-		Limit:   int64(pageSize + 1),
-		Offset:  int64(offset),
-	})
+	photos, err := listAlbumPhotos(r.Context(), q, album, int64(pageSize+1), int64(offset))
 	if err != nil {
 		log.Printf("error loading photos: %v", err)
 		photos = []sqlc.Photo{} // Show empty album on error
@@ -139,23 +224,63 @@ func (h *Handler) renderShareAlbum(w http.ResponseWriter, r *http.Request, link
 		photos = photos[:pageSize] // Trim the extra photo
 	}
 
+	photoCount, err := q.CountAlbumPhotos(r.Context(), album.ID)
+	if err != nil {
+		log.Printf("error counting photos: %v", err)
+	}
+
+	tally := reactionTally{}
+	counts, err := q.ListReactionCountsForAlbum(r.Context(), album.ID)
+	if err != nil {
+		log.Printf("error loading reactions: %v", err)
+	}
+	for _, c := range counts {
+		tally.add(c.PhotoID, c.Reaction, c.Count)
+	}
+
 	// Check if this is an HTMX request
 	isHTMX := r.Header.Get("HX-Request") == "true"
 
+	// Album links can show where the album's photos were taken
+	var places *photoMap
+	if link.ShowMap && !isHTMX {
+		m := h.photoMapFor(r.Context(), sql.NullInt64{Int64: album.ID, Valid: true}, "/s/"+link.Token+"/map/clusters", sharePhotoURL(link.Token))
+		if len(m.Places) > 0 {
+			places = &m
+		}
+	}
+
+	// Albums can show their cover photo as a header
+	var coverURL string
+	if album.CoverHeader && album.CoverPhotoID.Valid && !isHTMX {
+		cover, err := q.GetPhoto(r.Context(), album.CoverPhotoID.Int64)
+		if err == nil && cover.AlbumID == album.ID {
+			coverURL = sharePhotoURL(link.Token)(cover.ID)
+		}
+	}
+
 	data := struct {
-		Album    sqlc.Album
-		Photos   []sqlc.Photo
-		Token    string
-		Page     int
-		NextPage int
-		HasMore  bool
+		Album      sqlc.Album
+		PhotoCount int64
+		Photos     []sharePhoto
+		Token      string
+		Page       int
+		NextPage   int
+		HasMore    bool
+		Map        *photoMap
+		Preview    *openGraph
+		CoverURL   string
 	}{
-		Album:    album,
-		Photos:   photos,
-		Token:    link.Token,
-		Page:     pageNum,
-		NextPage: pageNum + 1,
-		HasMore:  hasMore,
+		Album:      album,
+		PhotoCount: photoCount,
+		Photos:     h.sharePhotos(r, link, photos, tally),
+		Token:      link.Token,
+		Page:       pageNum,
+		NextPage:   pageNum + 1,
+		HasMore:    hasMore,
+		Map:        places,
+		Preview:    h.openGraphFor(r, link, album.Title),
+		CoverURL:   coverURL,
 	}
 
 	w.Header().Set("Content-Type", "text/html; charset=utf-8")
@@ -167,7 +292,98 @@ func (h *Handler) renderShareAlbum(w http.ResponseWriter, r *http.Request, link
 		templateName = "share_album.html"
 	}
 
-	if err := h.RenderTemplate(w, templateName, data); err != nil {
+	if err := h.renderTemplateIn(w, locale, templateName, data); err != nil {
+		log.Printf("template render error for %s: %v", templateName, err)
+		http.Error(w, "template render error", http.StatusInternalServerError)
+	}
+}
+
+// renderShareTag renders every photo carrying a tag, across albums, using the
+// album gallery template so pagination and the lightbox behave the same way.
+func (h *Handler) renderShareTag(w http.ResponseWriter, r *http.Request, link sqlc.ShareLink) {
+	q := sqlc.New(h.db)
+	locale := h.shareLocale(w, r, link)
+
+	tag, err := q.GetTag(r.Context(), link.TargetID)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			h.renderShareExpired(w, locale, "expired.tag_not_found", http.StatusNotFound)
+		} else {
+			log.Printf("error loading tag: %v", err)
+			http.Error(w, "Internal server error", http.StatusInternalServerError)
+		}
+		return
+	}
+
+	pageNum := 1
+	if pageStr := r.URL.Query().Get("page"); pageStr != "" {
+		if p, err := strconv.Atoi(pageStr); err == nil && p > 0 {
+			pageNum = p
+		}
+	}
+
+	const pageSize = 20
+	offset := (pageNum - 1) * pageSize
+
+	photos, err := q.ListPhotosByTag(r.Context(), sqlc.ListPhotosByTagParams{
+		TagID:  tag.ID,
+		Limit:  int64(pageSize + 1),
+		Offset: int64(offset),
+	})
+	if err != nil {
+		log.Printf("error loading tagged photos: %v", err)
+		photos = []sqlc.Photo{}
+	}
+
+	hasMore := len(photos) > pageSize
+	if hasMore {
+		photos = photos[:pageSize]
+	}
+
+	photoCount, err := q.CountPhotosByTag(r.Context(), tag.ID)
+	if err != nil {
+		log.Printf("error counting tagged photos: %v", err)
+	}
+
+	tally := reactionTally{}
+	counts, err := q.ListReactionCountsForTag(r.Context(), tag.ID)
+	if err != nil {
+		log.Printf("error loading reactions: %v", err)
+	}
+	for _, c := range counts {
+		tally.add(c.PhotoID, c.Reaction, c.Count)
+	}
+
+	data := struct {
+		Album      sqlc.Album
+		PhotoCount int64
+		Photos     []sharePhoto
+		Token      string
+		Page       int
+		NextPage   int
+		HasMore    bool
+		Map        *photoMap
+		Preview    *openGraph
+		CoverURL   string
+	}{
+		Album:      sqlc.Album{Title: tag.Name},
+		PhotoCount: photoCount,
+		Photos:     h.sharePhotos(r, link, photos, tally),
+		Token:      link.Token,
+		Page:       pageNum,
+		NextPage:   pageNum + 1,
+		HasMore:    hasMore,
+		Preview:    h.openGraphFor(r, link, tag.Name),
+	}
+
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+
+	templateName := "share_album.html"
+	if r.Header.Get("HX-Request") == "true" {
+		templateName = "photo_grid_partial.html"
+	}
+
+	if err := h.renderTemplateIn(w, locale, templateName, data); err != nil {
 		log.Printf("template render error for %s: %v", templateName, err)
 		http.Error(w, "template render error", http.StatusInternalServerError)
 	}
@@ -176,12 +392,13 @@ func (h *Handler) renderShareAlbum(w http.ResponseWriter, r *http.Request, link
 // renderSharePhoto renders the public single photo view
 func (h *Handler) renderSharePhoto(w http.ResponseWriter, r *http.Request, link sqlc.ShareLink) {
 	q := sqlc.New(h.db)
+	locale := h.shareLocale(w, r, link)
 
 	// Load photo
 	photo, err := q.GetPhoto(r.Context(), link.TargetID)
 	if err != nil {
 		if err == sql.ErrNoRows {
-			h.renderShareExpired(w, "Photo not found", http.StatusNotFound)
+			h.renderShareExpired(w, locale, "expired.photo_not_found", http.StatusNotFound)
 		} else {
 			log.Printf("error loading photo: %v", err)
 			http.Error(w, "Internal server error", http.StatusInternalServerError)
@@ -196,25 +413,146 @@ func (h *Handler) renderSharePhoto(w http.ResponseWriter, r *http.Request, link
 		// Continue with empty album
 	}
 
+	previewTitle := photo.Filename
+	if album.Title != "" {
+		previewTitle = album.Title
+	}
+
+	// The reaction bar and the comment form share one CSRF token
+	shared := h.sharePhotos(r, link, []sqlc.Photo{photo}, photoReactionTally(r.Context(), q, photo.ID))[0]
+
+	var comments []sqlc.PhotoComment
+	if link.AllowComments {
+		comments, err = q.ListVisiblePhotoComments(r.Context(), sqlc.ListVisiblePhotoCommentsParams{
+			PhotoID:     photo.ID,
+			ShareLinkID: link.ID,
+		})
+		if err != nil {
+			log.Printf("error loading comments: %v", err)
+		}
+	}
+
 	data := struct {
-		Photo sqlc.Photo
-		Album sqlc.Album
-		Token string
+		Photo         sqlc.Photo
+		Reactions     photoReactions
+		Album         sqlc.Album
+		Token         string
+		AllowComments bool
+		Comments      []sqlc.PhotoComment
+		Preview       *openGraph
 	}{
-		Photo: photo,
-		Album: album,
-		Token: link.Token,
+		Photo:         photo,
+		Reactions:     shared.Reactions,
+		Album:         album,
+		Token:         link.Token,
+		AllowComments: link.AllowComments,
+		Comments:      comments,
+		Preview:       h.openGraphFor(r, link, previewTitle),
 	}
 
 	w.Header().Set("Content-Type", "text/html; charset=utf-8")
-	if err := h.RenderTemplate(w, "share_photo.html", data); err != nil {
+	if err := h.renderTemplateIn(w, locale, "share_photo.html", data); err != nil {
 		log.Printf("template render error for share_photo: %v", err)
 		http.Error(w, "template render error", http.StatusInternalServerError)
 	}
 }
 
-// renderShareExpired renders the error page for expired/invalid links
-func (h *Handler) renderShareExpired(w http.ResponseWriter, message string, statusCode int) {
+// sharePhotoURL returns the URL of a photo under a share link.
+func sharePhotoURL(token string) func(int64) string {
+	return func(id int64) string {
+		return "/s/" + token + "/photos/" + strconv.FormatInt(id, 10) + ".webp"
+	}
+}
+
+// ShareMapClusters handles GET /s/{token}/map/clusters
+// Query: bbox (west,south,east,north) and zoom. Only album links with the
+// map turned on have one; like loading their photos, it doesn't count a view.
+func (h *Handler) ShareMapClusters(w http.ResponseWriter, r *http.Request) {
+	link, err := h.shareLinkByToken(r.Context(), chi.URLParam(r, "token"))
+	if err != nil {
+		if err != sql.ErrNoRows {
+			log.Printf("error loading share link for map: %v", err)
+		}
+		http.NotFound(w, r)
+		return
+	}
+	if link.TargetType != "album" || !link.ShowMap || !h.shareLinkUsable(r.Context(), link) {
+		http.NotFound(w, r)
+		return
+	}
+	h.writeMapClusters(w, r, sql.NullInt64{Int64: link.TargetID, Valid: true}, sharePhotoURL(link.Token))
+}
+
+// renderShareBot renders the page bots get instead of a share link's
+// content: only the link preview when the link has one, and nothing that
+// tracks a viewer. The visit is recorded as a bot view.
+func (h *Handler) renderShareBot(w http.ResponseWriter, r *http.Request, link sqlc.ShareLink) {
+	go func(shareID int64) {
+		logCtx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+		defer cancel()
+		_ = h.metrics.LogShareBotView(logCtx, shareID)
+	}(link.ID)
+
+	locale := h.shareLocale(w, r, link)
+	data := struct {
+		Title   string
+		Preview *openGraph
+	}{
+		Title: i18n.T(locale, "share.untitled"),
+	}
+	if link.OpenGraph {
+		data.Title = h.shareTitle(r.Context(), link, locale)
+		data.Preview = h.openGraphFor(r, link, data.Title)
+	}
+
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	w.Header().Set("X-Robots-Tag", "noindex, nofollow")
+	if err := h.renderTemplateIn(w, locale, "share_bot.html", data); err != nil {
+		log.Printf("template render error for share_bot: %v", err)
+		http.Error(w, "template render error", http.StatusInternalServerError)
+	}
+}
+
+// shareTitle returns the title of a share link's content: the album title,
+// the tag name, or the album title or file name of a photo.
+func (h *Handler) shareTitle(ctx context.Context, link sqlc.ShareLink, locale string) string {
+	q := sqlc.New(h.db)
+	switch link.TargetType {
+	case "album":
+		if album, err := q.GetAlbum(ctx, link.TargetID); err == nil {
+			return album.Title
+		}
+	case "tag":
+		if tag, err := q.GetTag(ctx, link.TargetID); err == nil {
+			return tag.Name
+		}
+	case "photo":
+		if photo, err := q.GetPhoto(ctx, link.TargetID); err == nil {
+			if album, err := q.GetAlbum(ctx, photo.AlbumID); err == nil && album.Title != "" {
+				return album.Title
+			}
+			return photo.Filename
+		}
+	}
+	return i18n.T(locale, "share.untitled")
+}
+
+// shareLocale returns the language of a share link's pages: the one chosen
+// for the link, or else the one the viewer's browser asks for.
+func (h *Handler) shareLocale(w http.ResponseWriter, r *http.Request, link sqlc.ShareLink) string {
+	locale := link.Locale.String
+	if !i18n.Supported(locale) {
+		locale = i18n.Negotiate(r.Header.Get("Accept-Language"), h.locale)
+		w.Header().Set("Vary", "Accept-Language")
+	}
+	w.Header().Set("Content-Language", locale)
+	return locale
+}
+
+// renderShareExpired renders the error page for expired/invalid links, with
+// the message of key in locale
+func (h *Handler) renderShareExpired(w http.ResponseWriter, locale, key string, statusCode int) {
+	message := i18n.T(locale, key)
 	data := struct {
 		Message string
 	}{
@@ -223,7 +561,7 @@ func (h *Handler) renderShareExpired(w http.ResponseWriter, message string, stat
 
 	w.Header().Set("Content-Type", "text/html; charset=utf-8")
 	w.WriteHeader(statusCode)
-	if err := h.RenderTemplate(w, "share_expired.html", data); err != nil {
+	if err := h.renderTemplateIn(w, locale, "share_expired.html", data); err != nil {
 		log.Printf("template render error for share_expired: %v", err)
 		http.Error(w, fmt.Sprintf("Error: %s", message), statusCode)
 	}
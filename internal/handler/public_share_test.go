@@ -35,6 +35,38 @@ func setupTestHandlerForShare(t *testing.T) (*handler.Handler, *sqlc.Queries, fu
 	return h, q, cleanup
 }
 
+// shareRequestOption changes the request getShare sends.
+type shareRequestOption func(*http.Request)
+
+// withHeader sets a header of the request.
+func withHeader(name, value string) shareRequestOption {
+	return func(r *http.Request) { r.Header.Set(name, value) }
+}
+
+// fromIP sends the request from ip.
+func fromIP(ip string) shareRequestOption {
+	return func(r *http.Request) { r.RemoteAddr = ip + ":4321" }
+}
+
+// getShare sends a GET request for path, /s/{token} when empty, to a public
+// share handler with the token route parameter set.
+func getShare(handle http.HandlerFunc, token, path string, opts ...shareRequestOption) *httptest.ResponseRecorder {
+	if path == "" {
+		path = "/s/" + token
+	}
+	req := httptest.NewRequest("GET", path, nil)
+	req.Host = "photos.example.com"
+	for _, opt := range opts {
+		opt(req)
+	}
+	rctx := chi.NewRouteContext()
+	rctx.URLParams.Add("token", token)
+	req = req.WithContext(context.WithValue(req.Context(), chi.RouteCtxKey, rctx))
+	w := httptest.NewRecorder()
+	handle(w, req)
+	return w
+}
+
 func TestViewShareLink_NotFound(t *testing.T) {
 	h, _, cleanup := setupTestHandlerForShare(t)
 	defer cleanup()
@@ -0,0 +1,254 @@
+package handler
+
+import (
+	"context"
+	"database/sql"
+	"log"
+	"net/http"
+	"slices"
+	"strconv"
+
+	"github.com/go-chi/chi/v5"
+
+	"familyshare/internal/db/sqlc"
+)
+
+// Slideshow settings: the seconds each photo is shown and the photos loaded
+// per page.
+const (
+	slideshowDefaultInterval = 8
+	slideshowMinInterval     = 3
+	slideshowMaxInterval     = 600
+	slideshowPageSize        = 20
+)
+
+// Slideshow orders. The album order follows the album's sort mode; shuffle
+// keeps it on the server and is shuffled by the page as photos load.
+const (
+	slideshowOrderAlbum    = "album"
+	slideshowOrderNewest   = "newest"
+	slideshowOrderCaptured = "captured"
+	slideshowOrderShuffle  = "shuffle"
+)
+
+// slideshowOrder reads the order query parameter, defaulting to the album order.
+func slideshowOrder(r *http.Request) string {
+	switch order := r.URL.Query().Get("order"); order {
+	case slideshowOrderNewest, slideshowOrderCaptured, slideshowOrderShuffle:
+		return order
+	}
+	return slideshowOrderAlbum
+}
+
+// slideshowInterval reads the interval query parameter in seconds, clamped
+// to the allowed range.
+func slideshowInterval(r *http.Request) int {
+	interval, err := strconv.Atoi(r.URL.Query().Get("interval"))
+	if err != nil {
+		return slideshowDefaultInterval
+	}
+	return min(max(interval, slideshowMinInterval), slideshowMaxInterval)
+}
+
+// slideshowIntervals returns the intervals offered by the slideshow, including
+// the current one.
+func slideshowIntervals(current int) []int {
+	intervals := []int{3, 5, 8, 15, 30, 60}
+	if !slices.Contains(intervals, current) {
+		intervals = append(intervals, current)
+		slices.Sort(intervals)
+	}
+	return intervals
+}
+
+// slideshowPageNum reads the 1-based page query parameter.
+func slideshowPageNum(r *http.Request) int {
+	if p, err := strconv.Atoi(r.URL.Query().Get("page")); err == nil && p > 0 {
+		return p
+	}
+	return 1
+}
+
+// slideshowSlide is one photo of a slideshow. The dimensions let the page
+// lay out the next photo before it has loaded.
+type slideshowSlide struct {
+	ID      int64
+	URL     string
+	Width   int64
+	Height  int64
+	Caption string
+}
+
+// slideshowSlides is a page of slides together with what the page needs to
+// fetch the next one.
+type slideshowSlides struct {
+	Token    string
+	Order    string
+	Slides   []slideshowSlide
+	HasMore  bool
+	NextPage int
+}
+
+// slideshowPhotos loads a page of a share link's photos in the given order
+// and the title of the shared content.
+func (h *Handler) slideshowPhotos(ctx context.Context, link sqlc.ShareLink, order string, page int) (string, []sqlc.Photo, bool, error) {
+	q := sqlc.New(h.db)
+	limit, offset := int64(slideshowPageSize+1), int64((page-1)*slideshowPageSize)
+
+	var title string
+	var photos []sqlc.Photo
+	switch link.TargetType {
+	case "album":
+		album, err := q.GetAlbum(ctx, link.TargetID)
+		if err != nil {
+			return "", nil, false, err
+		}
+		title = album.Title
+		switch order {
+		case slideshowOrderNewest:
+			album.SortMode = sortModeUpload
+		case slideshowOrderCaptured:
+			album.SortMode = sortModeCapture
+		}
+		photos, err = listAlbumPhotos(ctx, q, album, limit, offset)
+		if err != nil {
+			return "", nil, false, err
+		}
+	case "tag":
+		tag, err := q.GetTag(ctx, link.TargetID)
+		if err != nil {
+			return "", nil, false, err
+		}
+		title = tag.Name
+		photos, err = q.ListPhotosByTag(ctx, sqlc.ListPhotosByTagParams{TagID: tag.ID, Limit: limit, Offset: offset})
+		if err != nil {
+			return "", nil, false, err
+		}
+	case "photo":
+		photo, err := q.GetPhoto(ctx, link.TargetID)
+		if err != nil {
+			return "", nil, false, err
+		}
+		title = photo.Filename
+		if album, err := q.GetAlbum(ctx, photo.AlbumID); err == nil {
+			title = album.Title
+		}
+		if page == 1 {
+			photos = []sqlc.Photo{photo}
+		}
+	default:
+		return "", nil, false, sql.ErrNoRows
+	}
+
+	hasMore := len(photos) > slideshowPageSize
+	if hasMore {
+		photos = photos[:slideshowPageSize]
+	}
+	return title, photos, hasMore, nil
+}
+
+// newSlideshowSlides builds the slides of a page of photos.
+func newSlideshowSlides(link sqlc.ShareLink, order string, photos []sqlc.Photo, hasMore bool, page int) slideshowSlides {
+	slides := make([]slideshowSlide, 0, len(photos))
+	for _, p := range photos {
+		url := "/s/" + link.Token + "/photos/" + strconv.FormatInt(p.ID, 10) + ".webp"
+		if p.AnimationFormat.Valid {
+			url += "?animated=1"
+		}
+		slides = append(slides, slideshowSlide{
+			ID:      p.ID,
+			URL:     url,
+			Width:   p.Width,
+			Height:  p.Height,
+			Caption: p.Caption.String,
+		})
+	}
+	return slideshowSlides{
+		Token:    link.Token,
+		Order:    order,
+		Slides:   slides,
+		HasMore:  hasMore,
+		NextPage: page + 1,
+	}
+}
+
+// ViewSlideshow handles GET /s/{token}/slideshow
+// Query: interval (seconds, 3-600) and order (album, newest, captured or
+// shuffle). Opening the slideshow counts as a view of the link, like the
+// gallery; the pages of photos it loads afterwards don't.
+func (h *Handler) ViewSlideshow(w http.ResponseWriter, r *http.Request) {
+	link, ok := h.openShareLink(w, r)
+	if !ok {
+		return
+	}
+
+	locale := h.shareLocale(w, r, link)
+	order := slideshowOrder(r)
+	title, photos, hasMore, err := h.slideshowPhotos(r.Context(), link, order, 1)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			h.renderShareExpired(w, locale, "expired.content_not_found", http.StatusNotFound)
+		} else {
+			log.Printf("error loading slideshow photos: %v", err)
+			http.Error(w, "Internal server error", http.StatusInternalServerError)
+		}
+		return
+	}
+
+	// The first page of slides is part of the page
+	interval := slideshowInterval(r)
+	data := struct {
+		Title     string
+		Interval  int
+		Intervals []int
+		slideshowSlides
+	}{
+		Title:           title,
+		Interval:        interval,
+		Intervals:       slideshowIntervals(interval),
+		slideshowSlides: newSlideshowSlides(link, order, photos, hasMore, 1),
+	}
+
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	if err := h.renderTemplateIn(w, locale, "share_slideshow.html", data); err != nil {
+		log.Printf("template render error for share_slideshow: %v", err)
+		http.Error(w, "template render error", http.StatusInternalServerError)
+	}
+}
+
+// SlideshowPhotos handles GET /s/{token}/slideshow/photos
+// Query: page and order. It renders the next page of slides for a running
+// slideshow under the same access checks as the link, without counting a
+// view.
+func (h *Handler) SlideshowPhotos(w http.ResponseWriter, r *http.Request) {
+	link, err := h.shareLinkByToken(r.Context(), chi.URLParam(r, "token"))
+	if err != nil {
+		if err != sql.ErrNoRows {
+			log.Printf("error loading share link for slideshow: %v", err)
+		}
+		http.NotFound(w, r)
+		return
+	}
+	if !h.shareLinkUsable(r.Context(), link) {
+		http.NotFound(w, r)
+		return
+	}
+
+	order, page := slideshowOrder(r), slideshowPageNum(r)
+	_, photos, hasMore, err := h.slideshowPhotos(r.Context(), link, order, page)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			http.NotFound(w, r)
+			return
+		}
+		log.Printf("error loading slideshow photos: %v", err)
+		http.Error(w, "Internal server error", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	if err := h.RenderTemplate(w, "slideshow_slides.html", newSlideshowSlides(link, order, photos, hasMore, page)); err != nil {
+		log.Printf("template render error for slideshow_slides: %v", err)
+		http.Error(w, "template render error", http.StatusInternalServerError)
+	}
+}
@@ -0,0 +1,87 @@
+package handler_test
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"familyshare/internal/testutil"
+)
+
+func TestViewSlideshow(t *testing.T) {
+	h, q, cleanup := setupTestHandlerForShare(t)
+	defer cleanup()
+	ctx := context.Background()
+
+	album := testutil.CreateTestAlbum(t, q, "Holidays", "")
+	for i := range 25 {
+		testutil.CreateTestPhoto(t, q, album.ID, fmt.Sprintf("photo%02d.webp", i))
+	}
+	link := testutil.CreateTestShareLink(t, q, album.ID, "slideshow-token", 0, time.Time{})
+
+	w := getShare(h.ViewSlideshow, link.Token, "/s/"+link.Token+"/slideshow?interval=1&order=newest")
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d", w.Code)
+	}
+	body := w.Body.String()
+	if n := strings.Count(body, "data-slide-id="); n != 20 {
+		t.Errorf("expected the first 20 slides, got %d", n)
+	}
+	if !strings.Contains(body, "/s/slideshow-token/slideshow/photos?page=2&order=newest") {
+		t.Errorf("expected a link to the next page of slides")
+	}
+	if !strings.Contains(body, `data-slide-width="1920"`) {
+		t.Errorf("expected slide dimensions for preloading")
+	}
+	if !strings.Contains(body, "slideshow(3, ") {
+		t.Errorf("expected the interval clamped to 3 seconds")
+	}
+
+	views, err := q.CountUniqueShareLinkViews(ctx, link.ID)
+	if err != nil || views != 1 {
+		t.Fatalf("expected opening the slideshow to count one view, got %d (%v)", views, err)
+	}
+
+	// Later pages don't count views, even for new viewers
+	w = getShare(h.SlideshowPhotos, link.Token, "/s/"+link.Token+"/slideshow?page=2&order=newest")
+	if w.Code != http.StatusOK {
+		t.Fatalf("page 2: expected 200, got %d", w.Code)
+	}
+	if n := strings.Count(w.Body.String(), "data-slide-id="); n != 5 || strings.Contains(w.Body.String(), "data-next-page") {
+		t.Errorf("expected the last 5 slides without a next page, got %d", n)
+	}
+	if views, _ := q.CountUniqueShareLinkViews(ctx, link.ID); views != 1 {
+		t.Errorf("expected paging not to count views, got %d", views)
+	}
+}
+
+func TestViewSlideshow_Access(t *testing.T) {
+	h, q, cleanup := setupTestHandlerForShare(t)
+	defer cleanup()
+	ctx := context.Background()
+
+	album := testutil.CreateTestAlbum(t, q, "Holidays", "")
+	testutil.CreateTestPhoto(t, q, album.ID, "photo.webp")
+	expired := testutil.CreateTestShareLink(t, q, album.ID, "expired-slideshow", 0, time.Now().Add(-time.Hour))
+	revoked := testutil.CreateTestShareLink(t, q, album.ID, "revoked-slideshow", 0, time.Time{})
+	if err := q.RevokeShareLink(ctx, revoked.ID); err != nil {
+		t.Fatalf("RevokeShareLink: %v", err)
+	}
+
+	for _, token := range []string{expired.Token, revoked.Token} {
+		if w := getShare(h.ViewSlideshow, token, "/s/"+token+"/slideshow"); w.Code != http.StatusGone {
+			t.Errorf("%s: expected 410, got %d", token, w.Code)
+		}
+		if w := getShare(h.SlideshowPhotos, token, "/s/"+token+"/slideshow?page=2"); w.Code != http.StatusNotFound {
+			t.Errorf("%s: expected 404 for slides, got %d", token, w.Code)
+		}
+	}
+	for _, handle := range []http.HandlerFunc{h.ViewSlideshow, h.SlideshowPhotos} {
+		if w := getShare(handle, "unknown-token", "/s/unknown-token/slideshow"); w.Code != http.StatusNotFound {
+			t.Errorf("expected 404 for an unknown link, got %d", w.Code)
+		}
+	}
+}
@@ -30,6 +30,7 @@ func (h *Handler) RegisterRoutes(r chi.Router) {
 
 	// Public routes
 	r.Get("/", h.HomePage)
+	r.Get("/branding/logo.webp", h.ServeLogo)
 
 	// Share link routes - apply rate limiting to prevent brute-force token guessing
 	r.Route("/s", func(r chi.Router) {
@@ -42,13 +43,27 @@ func (h *Handler) RegisterRoutes(r chi.Router) {
 		})
 		r.Use(shareLimiter.Middleware())
 		r.Get("/{token}", h.ViewShareLink)
+		r.Get("/{token}/slideshow", h.ViewSlideshow)
+		r.Get("/{token}/slideshow/photos", h.SlideshowPhotos)
+		r.Get("/{token}/map/clusters", h.ShareMapClusters)
+		r.Get("/{token}/preview.jpg", h.SharePreviewImage)
 		r.Get("/{token}/photos/{id}.webp", h.ServeSharedPhoto)
+
+		// Guest comments get their own, stricter limit
+		commentLimiter := middleware.NewRateLimiter(middleware.RateLimitConfig{
+			RequestsPerMinute: h.config.RateLimitComments,
+			LockoutDuration:   15 * time.Minute,
+			MaxViolations:     5,
+			TemplateRenderer:  h,
+			TrustedProxyCIDRs: h.config.TrustedProxyCIDRs,
+		})
+		r.With(commentLimiter.Middleware()).Post("/{token}/photos/{id}/comments", h.PostShareComment)
+		r.Post("/{token}/photos/{id}/reactions", h.PostShareReaction)
 	})
 
 	// Admin routes - apply stricter rate limiting
 	r.Route("/admin", func(r chi.Router) {
-		csrf := middleware.NewCSRF(h.config.CSRFSecret)
-		r.Use(csrf.Middleware())
+		r.Use(h.csrf.Middleware())
 
 		// Apply rate limiting before auth to prevent brute-force login attempts
 		adminLimiter := middleware.NewRateLimiter(middleware.RateLimitConfig{
@@ -76,6 +91,7 @@ func (h *Handler) RegisterRoutes(r chi.Router) {
 			// Admin pages
 			r.Get("/", h.AdminDashboard)
 			r.Get("/albums", h.ListAlbums)
+			r.Get("/search", h.AdminSearch)
 
 			// Album management
 			r.Post("/albums", h.CreateAlbum)
@@ -84,21 +100,78 @@ func (h *Handler) RegisterRoutes(r chi.Router) {
 			r.Post("/albums/{id}", h.UpdateAlbum)
 			r.Put("/albums/{id}", h.UpdateAlbum)
 			r.Delete("/albums/{id}", h.DeleteAlbum)
+			r.Post("/albums/{id}/order", h.ReorderAlbumPhotos)
 
 			// Photo upload
 			r.Get("/upload/status", h.AdminUploadStatus)
+			r.Get("/albums/{id}/events", h.AlbumEvents)
 			r.Post("/albums/{id}/photos", h.AdminUploadPhotos)
+			r.Get("/uploads/failed", h.ListFailedUploads)
+			r.Post("/uploads/failed/retry", h.RetryAllFailedUploads)
+			r.Post("/uploads/failed/{id}/retry", h.RetryFailedUpload)
+			r.Delete("/uploads/failed/{id}", h.DiscardFailedUpload)
+
+			// Processing profiles
+			r.Get("/profiles", h.ListProfiles)
+			r.Post("/profiles", h.CreateProfile)
+			r.Post("/profiles/{id}", h.UpdateProfile)
+			r.Delete("/profiles/{id}", h.DeleteProfile)
+			r.Post("/reprocess", h.ReprocessPhotos)
+			r.Get("/reprocess/status", h.ReprocessStatus)
+
+			// Instance settings
+			r.Get("/settings", h.AdminSettings)
+			r.Post("/settings", h.UpdateSettings)
+			r.Post("/settings/logo", h.UploadLogo)
+			r.Delete("/settings/logo", h.DeleteLogo)
 
 			// Photo management
+			r.Get("/photos", h.ListPhotos)
+			r.Get("/timeline", h.AdminTimeline)
+			r.Get("/map", h.AdminMap)
+			r.Get("/map/clusters", h.AdminMapClusters)
+			r.Post("/photos/tags", h.BulkTagPhotos)
+			r.Post("/photos/move", h.MovePhotos)
+			r.Post("/photos/copy", h.CopyPhotos)
+			r.Post("/photos/delete", h.BulkDeletePhotos)
+			r.Post("/photos/rotate", h.BulkRotatePhotos)
 			r.Get("/photos/{id}.webp", h.ServePhoto)
 			r.Delete("/photos/{id}", h.DeletePhoto)
 			r.Post("/photos/{id}/set-cover", h.SetCoverPhoto)
 			r.Post("/photos/{id}/rotate", h.AdminRotatePhoto)
+			r.Get("/photos/{id}/edit", h.EditPhotoPage)
+			r.Post("/photos/{id}/edit", h.EditPhoto)
+			r.Post("/photos/{id}/revert", h.RevertPhoto)
+			r.Post("/photos/{id}/caption", h.UpdatePhotoCaption)
+
+			// Trash
+			r.Get("/trash", h.ViewTrash)
+			r.Post("/trash/empty", h.EmptyTrash)
+			r.Post("/trash/photos/{id}/restore", h.RestoreTrashedPhoto)
+			r.Delete("/trash/photos/{id}", h.PurgeTrashedPhoto)
+			r.Post("/trash/albums/{id}/restore", h.RestoreTrashedAlbum)
+			r.Delete("/trash/albums/{id}", h.PurgeTrashedAlbum)
+
+			// Guest comment moderation
+			r.Get("/comments", h.ListComments)
+			r.Post("/comments/{id}/hide", h.HideComment)
+			r.Post("/comments/{id}/unhide", h.UnhideComment)
+			r.Delete("/comments/{id}", h.DeleteComment)
+
+			// Tag management
+			r.Delete("/tags/{id}", h.DeleteTag)
 
 			// Share link management
 			r.Get("/shares", h.ListShareLinks)
 			r.Post("/shares", h.CreateShareLink)
+			r.Get("/shares/{id}/edit", h.EditShareLinkForm)
+			r.Post("/shares/{id}", h.UpdateShareLink)
+			r.Put("/shares/{id}", h.UpdateShareLink)
 			r.Delete("/shares/{id}", h.RevokeShareLink)
+			r.Post("/shares/{id}/reactivate", h.ReactivateShareLink)
+			r.Post("/shares/{id}/reset-views", h.ResetShareLinkViews)
+			r.Get("/shares/{id}/qr", h.ShareLinkQRCode)
+			r.Delete("/shares/{id}/recipients/{recipientID}", h.RevokeShareLinkRecipient)
 		})
 	})
 }
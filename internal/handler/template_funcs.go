@@ -0,0 +1,113 @@
+package handler
+
+import (
+	"context"
+	"html/template"
+	"log"
+	"time"
+
+	"familyshare/internal/config"
+	"familyshare/internal/i18n"
+	"familyshare/internal/settings"
+	"familyshare/internal/timefmt"
+)
+
+// timezone returns the instance's timezone, UTC unless configured.
+func timezone(cfg *config.Config) *time.Location {
+	if cfg != nil && cfg.Timezone != nil {
+		return cfg.Timezone
+	}
+	return time.UTC
+}
+
+// instanceLocale returns the instance's language, English unless configured.
+func instanceLocale(cfg *config.Config) string {
+	if cfg != nil && i18n.Supported(cfg.Locale) {
+		return cfg.Locale
+	}
+	return i18n.Default
+}
+
+// templateFuncs returns the functions templates use to show text in locale,
+// times in the instance's timezone and the instance's branding:
+//
+//	{{t "share.slideshow"}}
+//	{{plural "share.photo_count" .PhotoCount}}
+//	{{date "date" (localTime .CreatedAt.Time)}}
+//	{{(localTime .CreatedAt.Time).Format "2006-01-02 15:04"}}
+//	expires {{relativeTime .ExpiresAt.Time}}
+//	<input type="datetime-local" value="{{inputTime .ExpiresAt.Time}}">
+//	times are in {{timezone}}
+//	<html lang="{{locale}}">
+//	{{with (branding).LogoURL}}<img src="{{.}}">{{end}}
+func templateFuncs(loc *time.Location, locale string, site *settings.Store) template.FuncMap {
+	return template.FuncMap{
+		"t": func(key string, args ...any) string {
+			return i18n.T(locale, key, args...)
+		},
+		"plural": func(key string, n any, args ...any) string {
+			return i18n.N(locale, key, count(n), args...)
+		},
+		"date": func(format string, t time.Time) string {
+			return i18n.Date(locale, "format."+format, t)
+		},
+		"locale": func() string {
+			return locale
+		},
+		"locales": func() []i18n.Locale {
+			return i18n.Locales
+		},
+		"localTime": func(t time.Time) time.Time {
+			return t.In(loc)
+		},
+		"relativeTime": func(t time.Time) string {
+			return relativeTime(locale, timefmt.Relative(t, time.Now().In(loc)))
+		},
+		"inputTime": func(t time.Time) string {
+			return timefmt.FormatInput(t, loc)
+		},
+		"timezone": func() string {
+			return loc.String()
+		},
+		"branding": func() settings.Branding {
+			// Pages still render, unbranded, without their settings
+			b, err := site.Branding(context.Background())
+			if err != nil {
+				log.Printf("failed to load branding: %v", err)
+			}
+			return b
+		},
+	}
+}
+
+// count reads the count of a plural message, which templates pass as any
+// integer type.
+func count(n any) int64 {
+	switch n := n.(type) {
+	case int:
+		return int64(n)
+	case int32:
+		return int64(n)
+	case int64:
+		return n
+	case float64:
+		return int64(n)
+	}
+	return 0
+}
+
+// relativeTime tells a span of time in words, such as "in 3 days".
+func relativeTime(locale string, s timefmt.Span) string {
+	switch {
+	case s.Unit == timefmt.Now:
+		return i18n.T(locale, "time.now")
+	case s.Unit == timefmt.Day && s.N == 1:
+		return i18n.T(locale, "time.tomorrow")
+	case s.Unit == timefmt.Day && s.N == -1:
+		return i18n.T(locale, "time.yesterday")
+	case s.N > 0:
+		return i18n.N(locale, "time.in_"+string(s.Unit), int64(s.N))
+	default:
+		return i18n.N(locale, "time."+string(s.Unit)+"_ago", int64(-s.N))
+	}
+}
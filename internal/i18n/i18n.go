@@ -0,0 +1,152 @@
+// Package i18n translates the text of the pages into the languages of the
+// family. Each locale has a message catalog of keys and fmt formats in
+// locales/<code>.json. A plural message has a form per plural category, as
+// the keys <key>.one and <key>.other.
+package i18n
+
+import (
+	"embed"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Default is the locale of pages when nothing else is asked for, and the
+// locale of messages missing from a catalog.
+const Default = "en"
+
+// Locale is a language the pages can be shown in.
+type Locale struct {
+	Code string // the language subtag, such as "pt"
+	Name string // the name of the language in itself
+}
+
+// Locales lists the languages with a catalog.
+var Locales = []Locale{
+	{Code: "en", Name: "English"},
+	{Code: "pt", Name: "Português"},
+	{Code: "es", Name: "Español"},
+}
+
+//go:embed locales/*.json
+var catalogFiles embed.FS
+
+var catalogs = loadCatalogs()
+
+func loadCatalogs() map[string]map[string]string {
+	all := make(map[string]map[string]string, len(Locales))
+	for _, l := range Locales {
+		data, err := catalogFiles.ReadFile("locales/" + l.Code + ".json")
+		if err != nil {
+			panic(fmt.Sprintf("i18n: missing catalog for %s: %v", l.Code, err))
+		}
+		messages := map[string]string{}
+		if err := json.Unmarshal(data, &messages); err != nil {
+			panic(fmt.Sprintf("i18n: invalid catalog for %s: %v", l.Code, err))
+		}
+		all[l.Code] = messages
+	}
+	return all
+}
+
+// Supported reports whether code is the code of one of the Locales.
+func Supported(code string) bool {
+	_, ok := catalogs[code]
+	return ok
+}
+
+// Negotiate picks the locale of the languages a browser asks for in its
+// Accept-Language header, in order of preference, or else fallback. Regional
+// variants match their language, so pt-BR picks pt.
+func Negotiate(acceptLanguage, fallback string) string {
+	type choice struct {
+		code string
+		q    float64
+	}
+	var choices []choice
+	for _, part := range strings.Split(acceptLanguage, ",") {
+		tag, params, _ := strings.Cut(strings.TrimSpace(part), ";")
+		q := 1.0
+		if v, ok := strings.CutPrefix(strings.TrimSpace(params), "q="); ok {
+			parsed, err := strconv.ParseFloat(v, 64)
+			if err != nil {
+				continue
+			}
+			q = parsed
+		}
+		code, _, _ := strings.Cut(strings.ToLower(strings.TrimSpace(tag)), "-")
+		if q > 0 && Supported(code) {
+			choices = append(choices, choice{code, q})
+		}
+	}
+	sort.SliceStable(choices, func(i, j int) bool { return choices[i].q > choices[j].q })
+	if len(choices) > 0 {
+		return choices[0].code
+	}
+	return fallback
+}
+
+// T returns the message of key in locale, formatted with args. Messages
+// missing from the catalog come from the Default one, and else read as the
+// key itself.
+func T(locale, key string, args ...any) string {
+	msg, ok := catalogs[locale][key]
+	if !ok {
+		msg, ok = catalogs[Default][key]
+	}
+	if !ok {
+		return key
+	}
+	if len(args) == 0 {
+		return msg
+	}
+	return fmt.Sprintf(msg, args...)
+}
+
+// N returns the plural form of key for the count n in locale, formatted with
+// n followed by args.
+func N(locale, key string, n int64, args ...any) string {
+	form := key + "." + PluralCategory(locale, n)
+	if _, ok := catalogs[locale][form]; !ok {
+		form = key + ".other"
+	}
+	return T(locale, form, append([]any{n}, args...)...)
+}
+
+// PluralCategory returns the plural category of the count n in locale: "one"
+// or "other". Portuguese follows Brazil, where zero takes the singular.
+func PluralCategory(locale string, n int64) string {
+	switch {
+	case n == 1, locale == "pt" && n == 0:
+		return "one"
+	default:
+		return "other"
+	}
+}
+
+// Date formats t with the layout the catalog of locale has under key, a Go
+// time layout whose month and weekday names are written in the language.
+func Date(locale, key string, t time.Time) string {
+	// Names go in after formatting, through placeholders that no layout
+	// element matches, so they can't be read as layout elements themselves
+	names := []struct {
+		layout, placeholder, key string
+	}{
+		{"January", "\x01", "month." + strconv.Itoa(int(t.Month()))},
+		{"Jan", "\x02", "month_short." + strconv.Itoa(int(t.Month()))},
+		{"Monday", "\x03", "weekday." + strconv.Itoa(int(t.Weekday()))},
+		{"Mon", "\x04", "weekday_short." + strconv.Itoa(int(t.Weekday()))},
+	}
+	layout := T(locale, key)
+	for _, n := range names {
+		layout = strings.ReplaceAll(layout, n.layout, n.placeholder)
+	}
+	s := t.Format(layout)
+	for _, n := range names {
+		s = strings.ReplaceAll(s, n.placeholder, T(locale, n.key))
+	}
+	return s
+}
@@ -0,0 +1,162 @@
+package i18n
+
+import (
+	"io/fs"
+	"os"
+	"path/filepath"
+	"reflect"
+	"regexp"
+	"strings"
+	"testing"
+	"time"
+)
+
+var verbPattern = regexp.MustCompile(`%[-+# 0]*[0-9]*(?:\.[0-9]+)?[a-zA-Z%]`)
+
+func TestCatalogsHaveEveryMessage(t *testing.T) {
+	for _, l := range Locales {
+		if l.Code == Default {
+			continue
+		}
+		for key, want := range catalogs[Default] {
+			got, ok := catalogs[l.Code][key]
+			if !ok {
+				t.Errorf("%s: missing %q", l.Code, key)
+				continue
+			}
+			// The arguments are the same whatever the language
+			if w, g := verbPattern.FindAllString(want, -1), verbPattern.FindAllString(got, -1); !reflect.DeepEqual(w, g) {
+				t.Errorf("%s: %q has verbs %v, expected %v", l.Code, key, g, w)
+			}
+		}
+		for key := range catalogs[l.Code] {
+			if _, ok := catalogs[Default][key]; !ok {
+				t.Errorf("%s: %q isn't in the %s catalog", l.Code, key, Default)
+			}
+		}
+	}
+}
+
+func TestCatalogsHaveEveryPluralForm(t *testing.T) {
+	for _, l := range Locales {
+		for key := range catalogs[l.Code] {
+			base, ok := strings.CutSuffix(key, ".one")
+			if !ok {
+				continue
+			}
+			if _, ok := catalogs[l.Code][base+".other"]; !ok {
+				t.Errorf("%s: %q has no other form", l.Code, base)
+			}
+		}
+	}
+}
+
+// Every message the templates ask for is in the catalog, so none of them
+// shows up as its key.
+func TestCatalogsHaveTemplateMessages(t *testing.T) {
+	calls := regexp.MustCompile(`\{\{-?\s*\(?(t|plural) "([^"]+)"`)
+	found := 0
+	err := filepath.WalkDir("../../web/templates", func(path string, d fs.DirEntry, err error) error {
+		if err != nil || d.IsDir() || filepath.Ext(path) != ".html" {
+			return err
+		}
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return err
+		}
+		for _, m := range calls.FindAllStringSubmatch(string(data), -1) {
+			found++
+			key := m[2]
+			if m[1] == "plural" {
+				key += ".other"
+			}
+			if _, ok := catalogs[Default][key]; !ok {
+				t.Errorf("%s: %q isn't in the catalog", filepath.Base(path), key)
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if found == 0 {
+		t.Error("expected templates to use the catalog")
+	}
+}
+
+func TestNegotiate(t *testing.T) {
+	for _, tc := range []struct {
+		header, want string
+	}{
+		{"", "en"},
+		{"pt-BR,pt;q=0.9,en;q=0.8", "pt"},
+		{"es-MX", "es"},
+		{"de-DE,es;q=0.5,pt;q=0.7", "pt"},
+		{"fr, de", "en"},
+		{"pt;q=0", "en"},
+		{"en;q=0.2, es", "es"},
+	} {
+		if got := Negotiate(tc.header, Default); got != tc.want {
+			t.Errorf("Negotiate(%q): expected %s, got %s", tc.header, tc.want, got)
+		}
+	}
+	if got := Negotiate("fr", "pt"); got != "pt" {
+		t.Errorf("expected the fallback pt, got %s", got)
+	}
+}
+
+func TestT(t *testing.T) {
+	if got := T("pt", "share.load_more"); got != "Carregar mais fotos" {
+		t.Errorf("expected the Portuguese message, got %q", got)
+	}
+	if got := T("es", "slideshow.label", "Navidad"); got != "Presentación de Navidad" {
+		t.Errorf("expected a formatted message, got %q", got)
+	}
+	if got := T("xx", "share.load_more"); got != "Load More Photos" {
+		t.Errorf("expected the English message for an unknown locale, got %q", got)
+	}
+	if got := T("pt", "no.such.key"); got != "no.such.key" {
+		t.Errorf("expected the key of a missing message, got %q", got)
+	}
+}
+
+func TestN(t *testing.T) {
+	for _, tc := range []struct {
+		locale string
+		n      int64
+		want   string
+	}{
+		{"en", 0, "0 photos"},
+		{"en", 1, "1 photo"},
+		{"en", 2, "2 photos"},
+		{"pt", 0, "0 foto"},
+		{"pt", 1, "1 foto"},
+		{"pt", 5, "5 fotos"},
+		{"es", 0, "0 fotos"},
+		{"es", 1, "1 foto"},
+	} {
+		if got := N(tc.locale, "common.photo_count", tc.n); got != tc.want {
+			t.Errorf("%s %d: expected %q, got %q", tc.locale, tc.n, tc.want, got)
+		}
+	}
+	if got := N("es", "common.view_count", 3); got != "3 vistas" {
+		t.Errorf("expected 3 vistas, got %q", got)
+	}
+}
+
+func TestDate(t *testing.T) {
+	day := time.Date(2025, time.March, 4, 15, 30, 0, 0, time.UTC)
+	for _, tc := range []struct {
+		locale, key, want string
+	}{
+		{"en", "format.datetime", "Mar 04, 2025 15:30"},
+		{"en", "format.full_date", "Tuesday, March 4, 2025"},
+		{"pt", "format.full_date", "terça-feira, 4 de março de 2025"},
+		{"es", "format.month_year", "marzo de 2025"},
+		{"es", "format.date", "04/03/2025"},
+	} {
+		if got := Date(tc.locale, tc.key, day); got != tc.want {
+			t.Errorf("%s %s: expected %q, got %q", tc.locale, tc.key, tc.want, got)
+		}
+	}
+}
@@ -19,6 +19,9 @@ type Janitor struct {
 	storagePath string
 	tempUploadDir string
 	interval    time.Duration
+	trashRetention time.Duration
+	failedJobRetention time.Duration
+	shareLinkRetention time.Duration
 	stopChan    chan struct{}
 	doneChan    chan struct{}
 }
@@ -29,6 +32,13 @@ type Config struct {
 	StoragePath string
 	TempUploadDir string
 	Interval    time.Duration
+	// TrashRetention is how long deleted albums and photos stay restorable
+	TrashRetention time.Duration
+	// FailedJobRetention is how long failed uploads keep their input for retries
+	FailedJobRetention time.Duration
+	// ShareLinkRetention is how long expired and revoked share links keep
+	// their views before they are deleted
+	ShareLinkRetention time.Duration
 }
 
 // New creates a new Janitor instance
@@ -36,6 +46,15 @@ func New(cfg Config) *Janitor {
 	if cfg.Interval == 0 {
 		cfg.Interval = 6 * time.Hour // default to 6 hours
 	}
+	if cfg.TrashRetention == 0 {
+		cfg.TrashRetention = 30 * 24 * time.Hour
+	}
+	if cfg.FailedJobRetention == 0 {
+		cfg.FailedJobRetention = 7 * 24 * time.Hour
+	}
+	if cfg.ShareLinkRetention == 0 {
+		cfg.ShareLinkRetention = 30 * 24 * time.Hour
+	}
 
 	return &Janitor{
 		db:          cfg.DB,
@@ -43,6 +62,9 @@ func New(cfg Config) *Janitor {
 		storagePath: cfg.StoragePath,
 		tempUploadDir: cfg.TempUploadDir,
 		interval:    cfg.Interval,
+		trashRetention: cfg.TrashRetention,
+		failedJobRetention: cfg.FailedJobRetention,
+		shareLinkRetention: cfg.ShareLinkRetention,
 		stopChan:    make(chan struct{}),
 		doneChan:    make(chan struct{}),
 	}
@@ -91,8 +113,11 @@ func (j *Janitor) runCleanup(ctx context.Context) {
 	j.deleteExpiredSessions(ctx)
 	j.deleteExpiredShareLinks(ctx)
 	j.deleteOrphanedPhotos(ctx)
+	j.purgeTrash(ctx)
 	j.deleteOldActivityEvents(ctx)
-	j.cleanupTempFiles()
+	j.reclaimStaleJobs(ctx)
+	j.deleteExpiredFailedJobs(ctx)
+	j.cleanupTempFiles(ctx)
 
 	duration := time.Since(start)
 	log.Printf("Janitor: cleanup cycle completed in %v", duration)
@@ -108,9 +133,11 @@ func (j *Janitor) deleteExpiredSessions(ctx context.Context) {
 	log.Println("Janitor: deleted expired sessions")
 }
 
-// deleteExpiredShareLinks removes expired and revoked share links
+// deleteExpiredShareLinks removes share links expired or revoked longer than
+// the retention period, together with their views
 func (j *Janitor) deleteExpiredShareLinks(ctx context.Context) {
-	links, err := j.queries.DeleteExpiredShareLinks(ctx)
+	cutoff := sql.NullTime{Time: time.Now().UTC().Add(-j.shareLinkRetention), Valid: true}
+	links, err := j.queries.DeleteExpiredShareLinks(ctx, cutoff)
 	if err != nil {
 		log.Printf("Janitor: failed to delete expired share links: %v", err)
 		return
@@ -137,26 +164,9 @@ func (j *Janitor) deleteOrphanedPhotos(ctx context.Context) {
 
 	deletedCount := 0
 	for _, photo := range photos {
-		createdAt := time.Now().UTC()
-		if photo.CreatedAt.Valid {
-			createdAt = photo.CreatedAt.Time.UTC()
-		}
-		// Delete main photo file
-		photoPath := storage.PhotoPathAt(j.storagePath, photo.AlbumID, photo.ID, photo.Format, createdAt)
-		if err := j.deleteFile(photoPath); err != nil {
-			log.Printf("Janitor: failed to delete photo file %s: %v", photoPath, err)
-		} else {
+		if j.deletePhotoFiles(photo.ID, photo.AlbumID, photo.Format, photo.CreatedAt, photo.OriginalFormat, photo.AnimationFormat) {
 			deletedCount++
 		}
-
-		// Delete thumbnail if exists
-		thumbPath := storage.ThumbnailPathAt(j.storagePath, photo.AlbumID, photo.ID, createdAt)
-		if err := j.deleteFile(thumbPath); err != nil {
-			// Thumbnails are optional, don't log error if not found
-			if !os.IsNotExist(err) {
-				log.Printf("Janitor: failed to delete thumbnail %s: %v", thumbPath, err)
-			}
-		}
 	}
 
 	log.Printf("Janitor: deleted %d orphaned photo files", deletedCount)
@@ -165,6 +175,64 @@ func (j *Janitor) deleteOrphanedPhotos(ctx context.Context) {
 	j.cleanupEmptyDirs()
 }
 
+// deletePhotoFiles removes the files of a photo (see storage.PhotoFiles),
+// reporting whether the photo file itself was deleted
+func (j *Janitor) deletePhotoFiles(photoID, albumID int64, format string, created sql.NullTime, original, animation sql.NullString) bool {
+	deleted := true
+	for i, path := range storage.PhotoFiles(j.storagePath, albumID, photoID, format, created, original, animation) {
+		err := j.deleteFile(path)
+		if i == 0 && err != nil {
+			log.Printf("Janitor: failed to delete photo file %s: %v", path, err)
+			deleted = false
+		} else if err != nil && !os.IsNotExist(err) {
+			// The other files are optional, so a missing one is fine
+			log.Printf("Janitor: failed to delete %s: %v", path, err)
+		}
+	}
+	return deleted
+}
+
+// purgeTrash permanently deletes albums and photos that have been in the trash
+// longer than the retention period, together with their files
+func (j *Janitor) purgeTrash(ctx context.Context) {
+	cutoff := sql.NullTime{Time: time.Now().UTC().Add(-j.trashRetention), Valid: true}
+
+	deletedCount := 0
+	albumPhotos, err := j.queries.PurgePhotosOfTrashedAlbums(ctx, cutoff)
+	if err != nil {
+		log.Printf("Janitor: failed to purge photos of trashed albums: %v", err)
+		return
+	}
+	for _, photo := range albumPhotos {
+		if j.deletePhotoFiles(photo.ID, photo.AlbumID, photo.Format, photo.CreatedAt, photo.OriginalFormat, photo.AnimationFormat) {
+			deletedCount++
+		}
+	}
+
+	photos, err := j.queries.PurgeTrashedPhotos(ctx, cutoff)
+	if err != nil {
+		log.Printf("Janitor: failed to purge trashed photos: %v", err)
+		return
+	}
+	for _, photo := range photos {
+		if j.deletePhotoFiles(photo.ID, photo.AlbumID, photo.Format, photo.CreatedAt, photo.OriginalFormat, photo.AnimationFormat) {
+			deletedCount++
+		}
+	}
+
+	albums, err := j.queries.PurgeTrashedAlbums(ctx, cutoff)
+	if err != nil {
+		log.Printf("Janitor: failed to purge trashed albums: %v", err)
+		return
+	}
+
+	if len(albums) > 0 || len(albumPhotos)+len(photos) > 0 {
+		log.Printf("Janitor: purged %d albums and %d photos from the trash (%d files deleted)",
+			len(albums), len(albumPhotos)+len(photos), deletedCount)
+		j.cleanupEmptyDirs()
+	}
+}
+
 // deleteFile removes a file from disk
 func (j *Janitor) deleteFile(path string) error {
 	return os.Remove(path)
@@ -194,13 +262,58 @@ func (j *Janitor) cleanupEmptyDirs() {
 	})
 }
 
-// cleanupTempFiles removes orphaned temporary upload files older than 15 minutes
-func (j *Janitor) cleanupTempFiles() {
-	if err := storage.CleanOrphanedTempFiles(15*time.Minute, j.tempUploadDir); err != nil {
+// cleanupTempFiles removes orphaned temporary upload files older than 15 minutes.
+// Inputs of queued and failed jobs are kept so they can still be processed or retried.
+func (j *Janitor) cleanupTempFiles(ctx context.Context) {
+	paths, err := j.queries.ListJobTempFiles(ctx)
+	if err != nil {
+		log.Printf("Janitor: failed to list job temp files: %v", err)
+		return
+	}
+	keep := make(map[string]bool, len(paths))
+	for _, p := range paths {
+		keep[filepath.Clean(p)] = true
+	}
+
+	if err := storage.CleanOrphanedTempFilesExcept(15*time.Minute, j.tempUploadDir, keep); err != nil {
 		log.Printf("Janitor: failed to cleanup temp files: %v", err)
 	}
 }
 
+// reclaimStaleJobs puts processing jobs whose lease expired (their worker
+// crashed or hung) back in the queue
+func (j *Janitor) reclaimStaleJobs(ctx context.Context) {
+	ids, err := j.queries.ReclaimExpiredJobs(ctx)
+	if err != nil {
+		log.Printf("Janitor: failed to reclaim stale jobs: %v", err)
+		return
+	}
+	if len(ids) > 0 {
+		log.Printf("Janitor: requeued %d stale processing jobs", len(ids))
+	}
+}
+
+// deleteExpiredFailedJobs drops failed jobs past the retention period along
+// with the temp input kept for retrying them
+func (j *Janitor) deleteExpiredFailedJobs(ctx context.Context) {
+	cutoff := sql.NullTime{Time: time.Now().UTC().Add(-j.failedJobRetention), Valid: true}
+
+	paths, err := j.queries.DeleteExpiredFailedJobs(ctx, cutoff)
+	if err != nil {
+		log.Printf("Janitor: failed to delete expired failed jobs: %v", err)
+		return
+	}
+
+	for _, p := range paths {
+		if err := j.deleteFile(p); err != nil && !os.IsNotExist(err) {
+			log.Printf("Janitor: failed to delete temp file %s: %v", p, err)
+		}
+	}
+	if len(paths) > 0 {
+		log.Printf("Janitor: deleted %d expired failed jobs", len(paths))
+	}
+}
+
 // deleteOldActivityEvents removes activity events older than 90 days
 func (j *Janitor) deleteOldActivityEvents(ctx context.Context) {
 	ninetyDaysAgo := time.Now().UTC().Add(-90 * 24 * time.Hour)
@@ -102,18 +102,27 @@ func TestJanitorDeleteExpiredShareLinks(t *testing.T) {
 		t.Fatalf("Failed to create active share link: %v", err)
 	}
 
-	// Create an expired share link
+	// Create share links that expired before and within the retention period
 	expiredLink, err := queries.CreateShareLink(ctx, sqlc.CreateShareLinkParams{
 		Token:      "expired-token",
 		TargetType: "album",
 		TargetID:   album.ID,
-		ExpiresAt:  sql.NullTime{Time: time.Now().UTC().Add(-24 * time.Hour), Valid: true},
+		ExpiresAt:  sql.NullTime{Time: time.Now().UTC().Add(-48 * time.Hour), Valid: true},
 	})
 	if err != nil {
 		t.Fatalf("Failed to create expired share link: %v", err)
 	}
+	recentlyExpiredLink, err := queries.CreateShareLink(ctx, sqlc.CreateShareLinkParams{
+		Token:      "recently-expired-token",
+		TargetType: "album",
+		TargetID:   album.ID,
+		ExpiresAt:  sql.NullTime{Time: time.Now().UTC().Add(-1 * time.Hour), Valid: true},
+	})
+	if err != nil {
+		t.Fatalf("Failed to create recently expired share link: %v", err)
+	}
 
-	// Create a revoked share link
+	// Create share links revoked before and within the retention period
 	revokedLink, err := queries.CreateShareLink(ctx, sqlc.CreateShareLinkParams{
 		Token:      "revoked-token",
 		TargetType: "album",
@@ -123,18 +132,31 @@ func TestJanitorDeleteExpiredShareLinks(t *testing.T) {
 	if err != nil {
 		t.Fatalf("Failed to create revoked share link: %v", err)
 	}
-
-	// Revoke the link
-	err = queries.RevokeShareLink(ctx, revokedLink.ID)
+	recentlyRevokedLink, err := queries.CreateShareLink(ctx, sqlc.CreateShareLinkParams{
+		Token:      "recently-revoked-token",
+		TargetType: "album",
+		TargetID:   album.ID,
+	})
 	if err != nil {
-		t.Fatalf("Failed to revoke share link: %v", err)
+		t.Fatalf("Failed to create recently revoked share link: %v", err)
+	}
+
+	// Revoke the links, the first one two days ago
+	for _, id := range []int64{revokedLink.ID, recentlyRevokedLink.ID} {
+		if err := queries.RevokeShareLink(ctx, id); err != nil {
+			t.Fatalf("Failed to revoke share link: %v", err)
+		}
+	}
+	if _, err := database.ExecContext(ctx, "UPDATE share_links SET revoked_at = datetime('now', '-2 days') WHERE id = ?", revokedLink.ID); err != nil {
+		t.Fatalf("Failed to age revoked share link: %v", err)
 	}
 
 	// Create janitor and run cleanup
 	j := New(Config{
-		DB:          database,
-		StoragePath: tmpDir,
-		Interval:    1 * time.Hour,
+		DB:                 database,
+		StoragePath:        tmpDir,
+		Interval:           1 * time.Hour,
+		ShareLinkRetention: 24 * time.Hour,
 	})
 
 	j.deleteExpiredShareLinks(ctx)
@@ -145,17 +167,22 @@ func TestJanitorDeleteExpiredShareLinks(t *testing.T) {
 		t.Errorf("Active share link should still exist, got error: %v", err)
 	}
 
-	// Verify expired link was deleted
+	// Verify links past the retention period were deleted
 	_, err = queries.GetShareLink(ctx, expiredLink.ID)
 	if err == nil {
 		t.Error("Expired share link should have been deleted")
 	}
-
-	// Verify revoked link was deleted
 	_, err = queries.GetShareLink(ctx, revokedLink.ID)
 	if err == nil {
 		t.Error("Revoked share link should have been deleted")
 	}
+
+	// Verify links within the retention period are kept
+	for _, id := range []int64{recentlyExpiredLink.ID, recentlyRevokedLink.ID} {
+		if _, err := queries.GetShareLink(ctx, id); err != nil {
+			t.Errorf("Share link %d within the retention period should still exist, got error: %v", id, err)
+		}
+	}
 }
 
 func TestJanitorDeleteOrphanedPhotos(t *testing.T) {
@@ -420,7 +447,7 @@ func TestJanitorCleanupTempFiles_CustomDir(t *testing.T) {
 		Interval:      1 * time.Hour,
 	})
 
-	j.cleanupTempFiles()
+	j.cleanupTempFiles(context.Background())
 
 	if _, err := os.Stat(oldFile); !os.IsNotExist(err) {
 		t.Fatalf("expected old temp file to be removed")
@@ -429,3 +456,212 @@ func TestJanitorCleanupTempFiles_CustomDir(t *testing.T) {
 		t.Fatalf("expected new temp file to remain, stat error: %v", err)
 	}
 }
+
+func TestJanitorPurgeTrash(t *testing.T) {
+	database, queries, tmpDir := setupTestDB(t)
+	defer database.Close()
+
+	ctx := context.Background()
+
+	createPhoto := func(albumID int64, name string) (sqlc.Photo, string) {
+		t.Helper()
+		photo, err := queries.CreatePhoto(ctx, sqlc.CreatePhotoParams{
+			AlbumID:   albumID,
+			Filename:  name,
+			Width:     800,
+			Height:    600,
+			SizeBytes: 12345,
+			Format:    "webp",
+		})
+		if err != nil {
+			t.Fatalf("Failed to create photo: %v", err)
+		}
+		path := storage.PhotoPathAt(tmpDir, photo.AlbumID, photo.ID, photo.Format, photo.CreatedAt.Time.UTC())
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatalf("Failed to create photo directory: %v", err)
+		}
+		if err := os.WriteFile(path, []byte(name), 0644); err != nil {
+			t.Fatalf("Failed to create photo file: %v", err)
+		}
+		return photo, path
+	}
+
+	kept, err := queries.CreateAlbum(ctx, sqlc.CreateAlbumParams{Title: "Kept"})
+	if err != nil {
+		t.Fatalf("Failed to create album: %v", err)
+	}
+	trashedAlbum, err := queries.CreateAlbum(ctx, sqlc.CreateAlbumParams{Title: "Trashed"})
+	if err != nil {
+		t.Fatalf("Failed to create album: %v", err)
+	}
+
+	oldPhoto, oldPath := createPhoto(kept.ID, "old.webp")
+	recentPhoto, recentPath := createPhoto(kept.ID, "recent.webp")
+	albumPhoto, albumPhotoPath := createPhoto(trashedAlbum.ID, "album.webp")
+
+	// Trash everything, then age all but the recent photo past the retention period
+	if err := queries.TrashPhoto(ctx, oldPhoto.ID); err != nil {
+		t.Fatalf("Failed to trash photo: %v", err)
+	}
+	if err := queries.TrashPhoto(ctx, recentPhoto.ID); err != nil {
+		t.Fatalf("Failed to trash photo: %v", err)
+	}
+	if err := queries.TrashAlbum(ctx, trashedAlbum.ID); err != nil {
+		t.Fatalf("Failed to trash album: %v", err)
+	}
+	if _, err := database.Exec("UPDATE photos SET deleted_at = datetime('now', '-40 days') WHERE id = ?", oldPhoto.ID); err != nil {
+		t.Fatalf("Failed to age photo: %v", err)
+	}
+	if _, err := database.Exec("UPDATE albums SET deleted_at = datetime('now', '-40 days') WHERE id = ?", trashedAlbum.ID); err != nil {
+		t.Fatalf("Failed to age album: %v", err)
+	}
+
+	j := New(Config{
+		DB:             database,
+		StoragePath:    tmpDir,
+		Interval:       1 * time.Hour,
+		TrashRetention: 30 * 24 * time.Hour,
+	})
+
+	j.purgeTrash(ctx)
+
+	for _, id := range []int64{oldPhoto.ID, albumPhoto.ID} {
+		if _, err := queries.GetPhotoIncludingDeleted(ctx, id); err == nil {
+			t.Errorf("Photo %d should have been purged", id)
+		}
+	}
+	for _, path := range []string{oldPath, albumPhotoPath} {
+		if _, err := os.Stat(path); !os.IsNotExist(err) {
+			t.Errorf("Photo file %s should have been deleted", path)
+		}
+	}
+	if _, err := queries.GetAlbumIncludingDeleted(ctx, trashedAlbum.ID); err == nil {
+		t.Error("Trashed album should have been purged")
+	}
+
+	// Still inside the retention period
+	if _, err := queries.GetPhotoIncludingDeleted(ctx, recentPhoto.ID); err != nil {
+		t.Errorf("Recently trashed photo should still exist: %v", err)
+	}
+	if _, err := os.Stat(recentPath); err != nil {
+		t.Errorf("Recently trashed photo file should still exist: %v", err)
+	}
+	if _, err := queries.GetAlbum(ctx, kept.ID); err != nil {
+		t.Errorf("Album outside the trash should still exist: %v", err)
+	}
+}
+
+func TestJanitorFailedJobInputs(t *testing.T) {
+	database, queries, tmpDir := setupTestDB(t)
+	defer database.Close()
+
+	ctx := context.Background()
+
+	customTemp := filepath.Join(tmpDir, "tmp_uploads")
+	if err := os.MkdirAll(customTemp, 0o700); err != nil {
+		t.Fatalf("mkdir custom temp: %v", err)
+	}
+
+	album, err := queries.CreateAlbum(ctx, sqlc.CreateAlbumParams{Title: "Uploads"})
+	if err != nil {
+		t.Fatalf("Failed to create album: %v", err)
+	}
+
+	// Both inputs are older than the temp file cutoff
+	failedJob := func(name string) (sqlc.ProcessingQueue, string) {
+		t.Helper()
+		path := filepath.Join(customTemp, "upload-"+name+".tmp")
+		if err := os.WriteFile(path, []byte(name), 0o600); err != nil {
+			t.Fatalf("write temp file: %v", err)
+		}
+		oldTime := time.Now().UTC().Add(-time.Hour)
+		if err := os.Chtimes(path, oldTime, oldTime); err != nil {
+			t.Fatalf("chtimes temp file: %v", err)
+		}
+		job, err := queries.EnqueueJob(ctx, sqlc.EnqueueJobParams{AlbumID: album.ID, OriginalFilename: name + ".jpg", TempFilepath: path})
+		if err != nil {
+			t.Fatalf("Failed to enqueue job: %v", err)
+		}
+		if err := queries.UpdateJobStatus(ctx, sqlc.UpdateJobStatusParams{
+			Status:       "failed",
+			ErrorMessage: sql.NullString{String: "boom", Valid: true},
+			ID:           job.ID,
+		}); err != nil {
+			t.Fatalf("Failed to fail job: %v", err)
+		}
+		return job, path
+	}
+
+	expired, expiredPath := failedJob("expired")
+	recent, recentPath := failedJob("recent")
+	if _, err := database.Exec("UPDATE processing_queue SET updated_at = datetime('now', '-10 days') WHERE id = ?", expired.ID); err != nil {
+		t.Fatalf("Failed to age job: %v", err)
+	}
+
+	j := New(Config{
+		DB:                 database,
+		StoragePath:        tmpDir,
+		TempUploadDir:      customTemp,
+		Interval:           1 * time.Hour,
+		FailedJobRetention: 7 * 24 * time.Hour,
+	})
+
+	j.deleteExpiredFailedJobs(ctx)
+	j.cleanupTempFiles(ctx)
+
+	if _, err := queries.GetJob(ctx, expired.ID); err == nil {
+		t.Error("Expired failed job should have been deleted")
+	}
+	if _, err := os.Stat(expiredPath); !os.IsNotExist(err) {
+		t.Error("Input of expired failed job should have been deleted")
+	}
+
+	if _, err := queries.GetJob(ctx, recent.ID); err != nil {
+		t.Errorf("Recent failed job should still exist: %v", err)
+	}
+	if _, err := os.Stat(recentPath); err != nil {
+		t.Errorf("Input of recent failed job should be kept for retries: %v", err)
+	}
+}
+
+func TestJanitorReclaimStaleJobs(t *testing.T) {
+	database, queries, tmpDir := setupTestDB(t)
+	defer database.Close()
+
+	ctx := context.Background()
+
+	album, err := queries.CreateAlbum(ctx, sqlc.CreateAlbumParams{Title: "Uploads"})
+	if err != nil {
+		t.Fatalf("Failed to create album: %v", err)
+	}
+	for _, name := range []string{"stale.jpg", "running.jpg"} {
+		if _, err := queries.EnqueueJob(ctx, sqlc.EnqueueJobParams{AlbumID: album.ID, OriginalFilename: name, TempFilepath: "/tmp/" + name}); err != nil {
+			t.Fatalf("Failed to enqueue job: %v", err)
+		}
+	}
+
+	// One lease already expired, the other is still held by a live worker
+	stale, err := queries.GetNextPendingJob(ctx, "-1 minutes")
+	if err != nil {
+		t.Fatalf("Failed to take job: %v", err)
+	}
+	running, err := queries.GetNextPendingJob(ctx, "+2 minutes")
+	if err != nil {
+		t.Fatalf("Failed to take job: %v", err)
+	}
+
+	j := New(Config{
+		DB:          database,
+		StoragePath: tmpDir,
+		Interval:    1 * time.Hour,
+	})
+
+	j.reclaimStaleJobs(ctx)
+
+	if job, _ := queries.GetJob(ctx, stale.ID); job.Status != "pending" {
+		t.Errorf("Job with expired lease should be pending again, got %s", job.Status)
+	}
+	if job, _ := queries.GetJob(ctx, running.ID); job.Status != "processing" {
+		t.Errorf("Job with a live lease should keep processing, got %s", job.Status)
+	}
+}
@@ -19,6 +19,14 @@ const (
 	EventAlbumView EventType = "album_view"
 	EventPhotoView EventType = "photo_view"
 	EventShareView EventType = "share_view"
+
+	// Reaction events record share viewers adding or withdrawing an emoji
+	EventReactionAdd    EventType = "reaction_add"
+	EventReactionRemove EventType = "reaction_remove"
+
+	// Bot views record share link visits by link previewers and scanners,
+	// which don't count as views
+	EventShareBotView EventType = "share_bot_view"
 )
 
 // Logger handles activity event logging
@@ -99,6 +107,21 @@ func (l *Logger) LogShareView(ctx context.Context, shareLinkID int64) error {
 	return l.LogEvent(ctx, EventShareView, nil, nil, &shareLinkID)
 }
 
+// LogShareBotView logs a bot visiting a share link
+func (l *Logger) LogShareBotView(ctx context.Context, shareLinkID int64) error {
+	return l.LogEvent(ctx, EventShareBotView, nil, nil, &shareLinkID)
+}
+
+// LogReaction logs a viewer reacting to a photo through a share link
+func (l *Logger) LogReaction(ctx context.Context, photoID, shareLinkID int64) error {
+	return l.LogEvent(ctx, EventReactionAdd, nil, &photoID, &shareLinkID)
+}
+
+// LogReactionRemoved logs a viewer withdrawing their reaction to a photo
+func (l *Logger) LogReactionRemoved(ctx context.Context, photoID, shareLinkID int64) error {
+	return l.LogEvent(ctx, EventReactionRemove, nil, &photoID, &shareLinkID)
+}
+
 // Stats holds aggregated metrics
 type Stats struct {
 	Uploads7Days     int64
@@ -109,6 +132,8 @@ type Stats struct {
 	PhotoViews30Days int64
 	ShareViews7Days  int64
 	ShareViews30Days int64
+	Reactions7Days   int64
+	Reactions30Days  int64
 }
 
 // GetStats retrieves activity statistics for the dashboard
@@ -144,6 +169,12 @@ func (l *Logger) GetStats(ctx context.Context) (*Stats, error) {
 	}
 	stats.ShareViews7Days = shareViews7
 
+	reactions7, err := l.queries.CountReactionsSince(ctx, sql.NullTime{Time: sevenDaysAgo, Valid: true})
+	if err != nil {
+		return nil, err
+	}
+	stats.Reactions7Days = reactions7
+
 	// Get 30-day stats
 	uploads30, err := l.queries.CountUploadsSince(ctx, sql.NullTime{Time: thirtyDaysAgo, Valid: true})
 	if err != nil {
@@ -169,5 +200,11 @@ func (l *Logger) GetStats(ctx context.Context) (*Stats, error) {
 	}
 	stats.ShareViews30Days = shareViews30
 
+	reactions30, err := l.queries.CountReactionsSince(ctx, sql.NullTime{Time: thirtyDaysAgo, Valid: true})
+	if err != nil {
+		return nil, err
+	}
+	stats.Reactions30Days = reactions30
+
 	return stats, nil
 }
@@ -200,6 +200,23 @@ func TestGetStats(t *testing.T) {
 		t.Fatalf("Failed to create recent share view event: %v", err)
 	}
 
+	// Recent reaction plus a withdrawal; only additions are counted
+	if err := logger.LogReaction(ctx, 1, 1); err != nil {
+		t.Fatalf("Failed to log reaction: %v", err)
+	}
+	if err := logger.LogReactionRemoved(ctx, 1, 1); err != nil {
+		t.Fatalf("Failed to log reaction removal: %v", err)
+	}
+
+	// Old reaction (20 days ago)
+	_, err = database.Exec(`
+		INSERT INTO activity_events (event_type, album_id, photo_id, share_link_id, created_at)
+		VALUES (?, ?, ?, ?, ?)
+	`, "reaction_add", nil, 2, 1, now.Add(-20*24*time.Hour))
+	if err != nil {
+		t.Fatalf("Failed to create old reaction event: %v", err)
+	}
+
 	// Get stats
 	stats, err := logger.GetStats(ctx)
 	if err != nil {
@@ -221,6 +238,12 @@ func TestGetStats(t *testing.T) {
 	if stats.ShareViews30Days != 1 {
 		t.Errorf("Expected 1 share view in last 30 days, got %d", stats.ShareViews30Days)
 	}
+	if stats.Reactions7Days != 1 {
+		t.Errorf("Expected 1 reaction in last 7 days, got %d", stats.Reactions7Days)
+	}
+	if stats.Reactions30Days != 2 {
+		t.Errorf("Expected 2 reactions in last 30 days, got %d", stats.Reactions30Days)
+	}
 }
 
 func TestGetStatsEmpty(t *testing.T) {
@@ -255,6 +278,9 @@ func TestGetStatsEmpty(t *testing.T) {
 	if stats.ShareViews30Days != 0 {
 		t.Errorf("Expected 0 share views in last 30 days, got %d", stats.ShareViews30Days)
 	}
+	if stats.Reactions7Days != 0 || stats.Reactions30Days != 0 {
+		t.Errorf("Expected 0 reactions, got %d/%d", stats.Reactions7Days, stats.Reactions30Days)
+	}
 }
 
 func TestEventTypeConstants(t *testing.T) {
@@ -270,4 +296,10 @@ func TestEventTypeConstants(t *testing.T) {
 	if EventShareView != "share_view" {
 		t.Errorf("Expected EventShareView to be 'share_view', got '%s'", EventShareView)
 	}
+	if EventReactionAdd != "reaction_add" {
+		t.Errorf("Expected EventReactionAdd to be 'reaction_add', got '%s'", EventReactionAdd)
+	}
+	if EventReactionRemove != "reaction_remove" {
+		t.Errorf("Expected EventReactionRemove to be 'reaction_remove', got '%s'", EventReactionRemove)
+	}
 }
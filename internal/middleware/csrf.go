@@ -141,3 +141,18 @@ func splitToken(value string) (string, string, error) {
 	}
 	return parts[0], parts[1], nil
 }
+
+// ScopedToken returns a stateless CSRF token bound to scope. It is used where
+// the admin cookie is not available, e.g. guest forms on share pages, where the
+// scope ties the token to a share link and viewer.
+func (c *CSRF) ScopedToken(scope string) string {
+	return c.sign("scope:" + scope)
+}
+
+// ValidScopedToken reports whether value is the token for scope.
+func (c *CSRF) ValidScopedToken(scope, value string) bool {
+	if value == "" {
+		return false
+	}
+	return hmac.Equal([]byte(value), []byte(c.ScopedToken(scope)))
+}
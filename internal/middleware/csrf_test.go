@@ -75,3 +75,21 @@ func TestCSRF_InvalidHeaderRejected(t *testing.T) {
 		t.Fatalf("expected 403, got %d", postRec.Result().StatusCode)
 	}
 }
+
+func TestCSRF_ScopedToken(t *testing.T) {
+	csrf := NewCSRF("test-secret")
+
+	token := csrf.ScopedToken("share:abc:viewer1")
+	if !csrf.ValidScopedToken("share:abc:viewer1", token) {
+		t.Fatalf("expected token to validate for its own scope")
+	}
+	if csrf.ValidScopedToken("share:abc:viewer2", token) {
+		t.Fatalf("expected token to be rejected for another scope")
+	}
+	if csrf.ValidScopedToken("share:abc:viewer1", "") {
+		t.Fatalf("expected empty token to be rejected")
+	}
+	if NewCSRF("other-secret").ValidScopedToken("share:abc:viewer1", token) {
+		t.Fatalf("expected token to be rejected under a different secret")
+	}
+}
@@ -0,0 +1,570 @@
+package pipeline
+
+import (
+	"bytes"
+	"context"
+	"database/sql"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"image"
+	"image/draw"
+	"io"
+	"log"
+	"os"
+	"strings"
+
+	webp "github.com/chai2010/webp"
+
+	"familyshare/internal/db/sqlc"
+	"familyshare/internal/storage"
+)
+
+// Default limits for the animations kept of animated uploads.
+const (
+	DefaultAnimationMaxFrames = 300
+	DefaultAnimationMaxBytes  = 20 << 20
+)
+
+// AnimationLimits bound the animations kept of animated GIF, WebP and AVIF
+// uploads. The stored photo of an animated upload is always a still poster
+// of its first frame; the animation itself is kept unchanged next to it when
+// it is within both limits. A zero limit keeps no animations.
+type AnimationLimits struct {
+	MaxFrames int
+	MaxBytes  int64
+}
+
+// DefaultAnimationLimits returns the built-in animation limits.
+func DefaultAnimationLimits() AnimationLimits {
+	return AnimationLimits{MaxFrames: DefaultAnimationMaxFrames, MaxBytes: DefaultAnimationMaxBytes}
+}
+
+// allows reports whether an animation of frames frames and size bytes is kept.
+func (l AnimationLimits) allows(frames int, size int64) bool {
+	return frames <= l.MaxFrames && size <= l.MaxBytes
+}
+
+// AnimationInfo returns the format and frame count of an encoded image. Still
+// images and formats that can't be animated have a single frame.
+func AnimationInfo(data []byte) (string, int) {
+	switch {
+	case bytes.HasPrefix(data, []byte("GIF87a")) || bytes.HasPrefix(data, []byte("GIF89a")):
+		return "gif", max(gifFrames(data), 1)
+	case isWebP(data):
+		frames := 0
+		animated := false
+		webpChunks(data, func(fourCC string, payload []byte) bool {
+			switch fourCC {
+			case "VP8X":
+				animated = len(payload) > 0 && payload[0]&0x02 != 0
+			case "ANMF":
+				frames++
+			}
+			return true
+		})
+		if !animated {
+			return "webp", 1
+		}
+		return "webp", max(frames, 1)
+	case isAVIF(data):
+		return "avif", max(avifFrames(data), 1)
+	}
+	return "", 1
+}
+
+// gifFrames counts the image descriptors of a GIF.
+func gifFrames(data []byte) int {
+	if len(data) < 13 {
+		return 0
+	}
+	pos := 13
+	if data[10]&0x80 != 0 {
+		// global color table
+		pos += 3 << (data[10]&0x07 + 1)
+	}
+	frames := 0
+	for pos < len(data) {
+		switch data[pos] {
+		case 0x21: // extension: label, then data sub-blocks
+			pos = skipGIFSubBlocks(data, pos+2)
+		case 0x2C: // image descriptor
+			if pos+10 > len(data) {
+				return frames
+			}
+			flags := data[pos+9]
+			pos += 10
+			if flags&0x80 != 0 {
+				// local color table
+				pos += 3 << (flags&0x07 + 1)
+			}
+			frames++
+			// LZW minimum code size, then the image data sub-blocks
+			pos = skipGIFSubBlocks(data, pos+1)
+		default: // trailer
+			return frames
+		}
+	}
+	return frames
+}
+
+func skipGIFSubBlocks(data []byte, pos int) int {
+	for pos < len(data) {
+		n := int(data[pos])
+		pos++
+		if n == 0 {
+			return pos
+		}
+		pos += n
+	}
+	return len(data)
+}
+
+func isWebP(data []byte) bool {
+	return len(data) >= 12 && string(data[0:4]) == "RIFF" && string(data[8:12]) == "WEBP"
+}
+
+// webpChunks calls fn with each top-level chunk of a WebP file until it
+// returns false.
+func webpChunks(data []byte, fn func(fourCC string, payload []byte) bool) {
+	if !isWebP(data) {
+		return
+	}
+	riffChunks(data[12:], func(fourCC string, payload, _ []byte) bool {
+		return fn(fourCC, payload)
+	})
+}
+
+// riffChunks calls fn with the payload and the whole chunk, header and
+// padding included, of each chunk of a RIFF chunk list until it returns
+// false. Chunks are padded to an even size.
+func riffChunks(data []byte, fn func(fourCC string, payload, chunk []byte) bool) {
+	for len(data) >= 8 {
+		size := int(binary.LittleEndian.Uint32(data[4:8]))
+		if size < 0 || size > len(data)-8 {
+			return
+		}
+		next := min(8+size+size&1, len(data))
+		if !fn(string(data[0:4]), data[8:8+size], data[:next]) {
+			return
+		}
+		data = data[next:]
+	}
+}
+
+func uint24(b []byte) int {
+	return int(b[0]) | int(b[1])<<8 | int(b[2])<<16
+}
+
+// webpPoster decodes the first frame of an animated WebP onto its canvas.
+// The WebP decoder only reads still images, so the frame's bitstream is
+// rewrapped as one.
+func webpPoster(data []byte) (image.Image, error) {
+	var canvasW, canvasH int
+	var frame []byte
+	webpChunks(data, func(fourCC string, payload []byte) bool {
+		switch fourCC {
+		case "VP8X":
+			if len(payload) >= 10 {
+				canvasW, canvasH = uint24(payload[4:7])+1, uint24(payload[7:10])+1
+			}
+		case "ANMF":
+			frame = payload
+			return false
+		}
+		return true
+	})
+	if frame == nil || len(frame) < 16 || canvasW == 0 {
+		return nil, errors.New("no animation frame")
+	}
+	x, y := uint24(frame[0:3])*2, uint24(frame[3:6])*2
+	w, h := uint24(frame[6:9])+1, uint24(frame[9:12])+1
+	// The header sizes go up to 2^24 per side, so they are checked before
+	// anything is allocated for them
+	if canvasW > MaxDimension || canvasH > MaxDimension || x+w > canvasW || y+h > canvasH {
+		return nil, ErrInvalidDimensions
+	}
+
+	var alpha, bitstream []byte
+	riffChunks(frame[16:], func(fourCC string, _, chunk []byte) bool {
+		switch fourCC {
+		case "ALPH":
+			alpha = chunk
+		case "VP8 ", "VP8L":
+			bitstream = chunk
+			return false
+		}
+		return true
+	})
+	if bitstream == nil {
+		return nil, errors.New("animation frame has no image data")
+	}
+
+	var body bytes.Buffer
+	body.WriteString("WEBP")
+	if alpha != nil {
+		// A lossy frame with alpha needs the extended format
+		ext := make([]byte, 18)
+		copy(ext, "VP8X")
+		binary.LittleEndian.PutUint32(ext[4:8], 10)
+		ext[8] = 0x10
+		putUint24(ext[12:15], w-1)
+		putUint24(ext[15:18], h-1)
+		body.Write(ext)
+		body.Write(alpha)
+	}
+	body.Write(bitstream)
+	still := make([]byte, 8, 8+body.Len())
+	copy(still, "RIFF")
+	binary.LittleEndian.PutUint32(still[4:8], uint32(body.Len()))
+	still = append(still, body.Bytes()...)
+
+	cfg, err := webp.DecodeConfig(bytes.NewReader(still))
+	if err != nil {
+		return nil, err
+	}
+	if cfg.Width != w || cfg.Height != h {
+		return nil, ErrInvalidDimensions
+	}
+	img, err := webp.Decode(bytes.NewReader(still))
+	if err != nil {
+		return nil, err
+	}
+	if x == 0 && y == 0 && w == canvasW && h == canvasH {
+		return img, nil
+	}
+	canvas := image.NewNRGBA(image.Rect(0, 0, canvasW, canvasH))
+	draw.Draw(canvas, image.Rect(x, y, x+w, y+h), img, img.Bounds().Min, draw.Src)
+	return canvas, nil
+}
+
+func putUint24(b []byte, v int) {
+	b[0], b[1], b[2] = byte(v), byte(v>>8), byte(v>>16)
+}
+
+// avifFrames counts the samples of the first track of an AVIF image
+// sequence. Still AVIF images have no tracks.
+func avifFrames(data []byte) int {
+	stts := findBox(data, "moov", "trak", "mdia", "minf", "stbl", "stts")
+	if len(stts) < 8 {
+		return 1
+	}
+	// version and flags, entry count, then (sample count, sample delta) pairs
+	entries := int(binary.BigEndian.Uint32(stts[4:8]))
+	frames := 0
+	for i := 0; i < entries && 16+8*i <= len(stts); i++ {
+		frames += int(binary.BigEndian.Uint32(stts[8+8*i:]))
+	}
+	return frames
+}
+
+// findBox returns the payload of the ISO BMFF box at path, descending into
+// the container boxes named before the last element.
+func findBox(data []byte, path ...string) []byte {
+	var found []byte
+	eachBox(data, func(boxType string, payload []byte) bool {
+		if boxType != path[0] {
+			return true
+		}
+		if len(path) == 1 {
+			found = payload
+		} else {
+			found = findBox(payload, path[1:]...)
+		}
+		return false
+	})
+	return found
+}
+
+// eachBox calls fn with the type and payload of each ISO BMFF box of data
+// until it returns false.
+func eachBox(data []byte, fn func(boxType string, payload []byte) bool) {
+	for len(data) >= 8 {
+		size, header := uint64(binary.BigEndian.Uint32(data[0:4])), uint64(8)
+		switch size {
+		case 0:
+			size = uint64(len(data))
+		case 1:
+			if len(data) < 16 {
+				return
+			}
+			size, header = binary.BigEndian.Uint64(data[8:16]), 16
+		}
+		if size < header || size > uint64(len(data)) {
+			return
+		}
+		if !fn(string(data[4:8]), data[header:size]) {
+			return
+		}
+		data = data[size:]
+	}
+}
+
+// stripAnimationMetadata removes the EXIF and XMP blocks, which can hold
+// where a photo was taken, from an animation that is otherwise kept
+// unchanged. GIFs can only carry XMP.
+func stripAnimationMetadata(format string, data []byte) []byte {
+	switch format {
+	case "gif":
+		return stripGIFMetadata(data)
+	case "webp":
+		return stripWebPMetadata(data)
+	case "avif":
+		stripAVIFMetadata(data)
+	}
+	return data
+}
+
+// stripGIFMetadata drops the XMP application extensions of a GIF.
+func stripGIFMetadata(data []byte) []byte {
+	if len(data) < 13 {
+		return data
+	}
+	pos := 13
+	if data[10]&0x80 != 0 {
+		// global color table
+		pos += 3 << (data[10]&0x07 + 1)
+	}
+	if pos > len(data) {
+		return data
+	}
+	out := append([]byte(nil), data[:pos]...)
+	for pos < len(data) {
+		start := pos
+		switch data[pos] {
+		case 0x21: // extension: label, then data sub-blocks
+			pos = min(skipGIFSubBlocks(data, pos+2), len(data))
+			if isGIFXMP(data[start:pos]) {
+				continue
+			}
+		case 0x2C: // image descriptor
+			if pos+10 > len(data) {
+				return append(out, data[start:]...)
+			}
+			flags := data[pos+9]
+			pos += 10
+			if flags&0x80 != 0 {
+				// local color table
+				pos += 3 << (flags&0x07 + 1)
+			}
+			pos = min(skipGIFSubBlocks(data, pos+1), len(data))
+		default: // trailer
+			return append(out, data[start:]...)
+		}
+		out = append(out, data[start:pos]...)
+	}
+	return out
+}
+
+// isGIFXMP reports whether a GIF extension block is an XMP application
+// extension.
+func isGIFXMP(block []byte) bool {
+	return len(block) >= 14 && block[1] == 0xFF && block[2] == 11 && string(block[3:14]) == "XMP DataXMP"
+}
+
+// stripWebPMetadata drops the EXIF and XMP chunks of a WebP and the VP8X
+// flags announcing them.
+func stripWebPMetadata(data []byte) []byte {
+	if !isWebP(data) {
+		return data
+	}
+	body := []byte("WEBP")
+	riffChunks(data[12:], func(fourCC string, _, chunk []byte) bool {
+		switch fourCC {
+		case "EXIF", "XMP ":
+			return true
+		case "VP8X":
+			if len(chunk) > 8 {
+				chunk = bytes.Clone(chunk)
+				chunk[8] &^= 0x08 | 0x04
+			}
+		}
+		body = append(body, chunk...)
+		return true
+	})
+	out := make([]byte, 8, 8+len(body))
+	copy(out, "RIFF")
+	binary.LittleEndian.PutUint32(out[4:8], uint32(len(body)))
+	return append(out, body...)
+}
+
+// stripAVIFMetadata blanks the Exif and XMP items of an AVIF in place.
+// Removing them would move every item stored after them, so their bytes are
+// zeroed instead.
+func stripAVIFMetadata(data []byte) {
+	meta := findBox(data, "meta")
+	if len(meta) < 4 {
+		return
+	}
+	meta = meta[4:]
+	items := avifMetadataItems(findBox(meta, "iinf"))
+	if len(items) == 0 {
+		return
+	}
+	idat := findBox(meta, "idat")
+
+	// iloc locates the bytes of each item, in the file or in idat
+	r := boxReader{b: findBox(meta, "iloc")}
+	version := r.uint(1)
+	r.uint(3)
+	sizes := r.uint(1)
+	offsetSize, lengthSize := int(sizes>>4), int(sizes&0x0f)
+	sizes = r.uint(1)
+	baseOffsetSize, indexSize := int(sizes>>4), 0
+	if version == 1 || version == 2 {
+		indexSize = int(sizes & 0x0f)
+	}
+	idSize := 2
+	if version == 2 {
+		idSize = 4
+	}
+	count := r.uint(idSize)
+	for i := uint64(0); i < count && !r.failed; i++ {
+		id := r.uint(idSize)
+		method := uint64(0)
+		if version == 1 || version == 2 {
+			method = r.uint(2) & 0x0f
+		}
+		r.uint(2) // data_reference_index
+		base := r.uint(baseOffsetSize)
+		extents := r.uint(2)
+		for j := uint64(0); j < extents && !r.failed; j++ {
+			r.uint(indexSize)
+			offset, length := base+r.uint(offsetSize), r.uint(lengthSize)
+			if !items[id] {
+				continue
+			}
+			src := data
+			if method == 1 {
+				src = idat
+			} else if method != 0 {
+				continue
+			}
+			if length == 0 || offset > uint64(len(src)) || length > uint64(len(src))-offset {
+				continue
+			}
+			clear(src[offset : offset+length])
+		}
+	}
+}
+
+// avifMetadataItems returns the IDs of the Exif and XMP items listed in an
+// iinf box.
+func avifMetadataItems(iinf []byte) map[uint64]bool {
+	r := boxReader{b: iinf}
+	version := r.uint(1)
+	r.uint(3)
+	if version == 0 { // entry_count
+		r.uint(2)
+	} else {
+		r.uint(4)
+	}
+	if r.failed {
+		return nil
+	}
+	items := map[uint64]bool{}
+	eachBox(r.b, func(boxType string, payload []byte) bool {
+		if boxType != "infe" {
+			return true
+		}
+		e := boxReader{b: payload}
+		version := e.uint(1)
+		e.uint(3)
+		if version < 2 {
+			return true
+		}
+		idSize := 2
+		if version == 3 {
+			idSize = 4
+		}
+		id := e.uint(idSize)
+		e.uint(2) // item_protection_index
+		itemType := string(e.bytes(4))
+		if e.failed {
+			return true
+		}
+		if itemType == "mime" {
+			// item_name, then content_type
+			if _, rest, ok := bytes.Cut(e.b, []byte{0}); ok {
+				contentType, _, _ := bytes.Cut(rest, []byte{0})
+				if string(contentType) == "application/rdf+xml" {
+					items[id] = true
+				}
+			}
+		} else if itemType == "Exif" {
+			items[id] = true
+		}
+		return true
+	})
+	return items
+}
+
+// boxReader reads the big-endian fields of an ISO BMFF box. Reading past
+// the end sets failed and returns zeros.
+type boxReader struct {
+	b      []byte
+	failed bool
+}
+
+func (r *boxReader) bytes(n int) []byte {
+	if n > len(r.b) {
+		r.failed = true
+		r.b = nil
+		return nil
+	}
+	b := r.b[:n]
+	r.b = r.b[n:]
+	return b
+}
+
+func (r *boxReader) uint(n int) uint64 {
+	var v uint64
+	for _, c := range r.bytes(n) {
+		v = v<<8 | uint64(c)
+	}
+	return v
+}
+
+// keepAnimation stores an animated upload next to its poster and records its
+// format. The animation is kept unchanged, except for its metadata when the
+// profile strips it. It returns "" for still uploads and for animations over
+// the limits, which keep only the poster.
+func keepAnimation(ctx context.Context, db *sql.DB, baseDir string, photoID int64, upload io.ReadSeeker, maxBytes int64, contentType string, profile Profile) (string, error) {
+	if !strings.HasPrefix(contentType, "image/gif") && !strings.HasPrefix(contentType, "image/webp") && !strings.HasPrefix(contentType, "image/avif") {
+		return "", nil
+	}
+	if _, err := upload.Seek(0, io.SeekStart); err != nil {
+		return "", fmt.Errorf("rewind upload: %w", err)
+	}
+	data, err := io.ReadAll(io.LimitReader(upload, maxBytes))
+	if err != nil {
+		return "", fmt.Errorf("read upload: %w", err)
+	}
+
+	format, frames := AnimationInfo(data)
+	if frames < 2 {
+		return "", nil
+	}
+	if !profile.Animation.allows(frames, int64(len(data))) {
+		log.Printf("animation of photo %d has %d frames and %d bytes, over the limits; keeping a still", photoID, frames, len(data))
+		return "", nil
+	}
+
+	if profile.StripMetadata {
+		data = stripAnimationMetadata(format, data)
+	}
+
+	path := storage.AnimationPath(baseDir, photoID, format)
+	if err := storage.AtomicWrite(path, bytes.NewReader(data)); err != nil {
+		return "", fmt.Errorf("write animation: %w", err)
+	}
+	err = sqlc.New(db).SetPhotoAnimationFormat(ctx, sqlc.SetPhotoAnimationFormatParams{
+		AnimationFormat: sql.NullString{String: format, Valid: true},
+		ID:              photoID,
+	})
+	if err != nil {
+		_ = os.Remove(path)
+		return "", fmt.Errorf("record animation: %w", err)
+	}
+	return format, nil
+}
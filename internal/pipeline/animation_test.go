@@ -0,0 +1,377 @@
+package pipeline
+
+import (
+	"bytes"
+	"context"
+	"encoding/binary"
+	"errors"
+	"image"
+	"image/color"
+	"image/color/palette"
+	"image/gif"
+	"os"
+	"path/filepath"
+	"testing"
+
+	webp "github.com/chai2010/webp"
+
+	"familyshare/internal/db"
+	"familyshare/internal/db/sqlc"
+	"familyshare/internal/storage"
+)
+
+// animatedGIF encodes one solid frame per color.
+func animatedGIF(t *testing.T, w, h int, colors ...color.Color) []byte {
+	t.Helper()
+	anim := &gif.GIF{}
+	for _, c := range colors {
+		frame := image.NewPaletted(image.Rect(0, 0, w, h), palette.Plan9)
+		for i := range frame.Pix {
+			frame.Pix[i] = uint8(color.Palette(palette.Plan9).Index(c))
+		}
+		anim.Image = append(anim.Image, frame)
+		anim.Delay = append(anim.Delay, 10)
+	}
+	var buf bytes.Buffer
+	if err := gif.EncodeAll(&buf, anim); err != nil {
+		t.Fatalf("encode gif: %v", err)
+	}
+	return buf.Bytes()
+}
+
+// animatedWebP builds an animated WebP of w x h from frames placed at the
+// given even offsets, reusing the bitstream of each frame encoded as a still.
+func animatedWebP(t *testing.T, w, h int, frames []image.Image, offsets []image.Point) []byte {
+	t.Helper()
+	chunk := func(fourCC string, payload []byte) []byte {
+		c := make([]byte, 8, 8+len(payload)+1)
+		copy(c, fourCC)
+		binary.LittleEndian.PutUint32(c[4:], uint32(len(payload)))
+		c = append(c, payload...)
+		if len(payload)%2 == 1 {
+			c = append(c, 0)
+		}
+		return c
+	}
+
+	vp8x := make([]byte, 10)
+	vp8x[0] = 0x02 | 0x10 // animation, alpha
+	putUint24(vp8x[4:], w-1)
+	putUint24(vp8x[7:], h-1)
+	body := append([]byte("WEBP"), chunk("VP8X", vp8x)...)
+	body = append(body, chunk("ANIM", make([]byte, 6))...)
+
+	for i, frame := range frames {
+		var still bytes.Buffer
+		if err := webp.Encode(&still, frame, &webp.Options{Lossless: true}); err != nil {
+			t.Fatalf("encode frame: %v", err)
+		}
+		anmf := make([]byte, 16)
+		putUint24(anmf[0:], offsets[i].X/2)
+		putUint24(anmf[3:], offsets[i].Y/2)
+		putUint24(anmf[6:], frame.Bounds().Dx()-1)
+		putUint24(anmf[9:], frame.Bounds().Dy()-1)
+		putUint24(anmf[12:], 100)
+		riffChunks(still.Bytes()[12:], func(fourCC string, _, raw []byte) bool {
+			if fourCC != "VP8X" {
+				anmf = append(anmf, raw...)
+			}
+			return true
+		})
+		body = append(body, chunk("ANMF", anmf)...)
+	}
+	return chunk("RIFF", body)
+}
+
+// withWebPMetadata adds EXIF and XMP chunks to a WebP and flags them in its
+// VP8X chunk.
+func withWebPMetadata(data []byte, exif, xmp string) []byte {
+	body := []byte("WEBP")
+	riffChunks(data[12:], func(fourCC string, _, chunk []byte) bool {
+		if fourCC == "VP8X" {
+			chunk = bytes.Clone(chunk)
+			chunk[8] |= 0x08 | 0x04
+		}
+		body = append(body, chunk...)
+		return true
+	})
+	for _, c := range []struct{ fourCC, payload string }{{"EXIF", exif}, {"XMP ", xmp}} {
+		header := make([]byte, 8)
+		copy(header, c.fourCC)
+		binary.LittleEndian.PutUint32(header[4:], uint32(len(c.payload)))
+		body = append(append(body, header...), c.payload...)
+		if len(c.payload)%2 == 1 {
+			body = append(body, 0)
+		}
+	}
+	out := make([]byte, 8)
+	copy(out, "RIFF")
+	binary.LittleEndian.PutUint32(out[4:], uint32(len(body)))
+	return append(out, body...)
+}
+
+// bmffBox encodes an ISO BMFF box.
+func bmffBox(boxType string, payload ...[]byte) []byte {
+	body := bytes.Join(payload, nil)
+	b := binary.BigEndian.AppendUint32(nil, uint32(8+len(body)))
+	return append(append(b, boxType...), body...)
+}
+
+func solid(w, h int, c color.Color) *image.NRGBA {
+	img := image.NewNRGBA(image.Rect(0, 0, w, h))
+	for y := 0; y < h; y++ {
+		for x := 0; x < w; x++ {
+			img.Set(x, y, c)
+		}
+	}
+	return img
+}
+
+func TestAnimationInfo(t *testing.T) {
+	red, blue := color.RGBA{255, 0, 0, 255}, color.RGBA{0, 0, 255, 255}
+	var stillWebP bytes.Buffer
+	if err := webp.Encode(&stillWebP, solid(4, 4, red), &webp.Options{Lossless: true}); err != nil {
+		t.Fatalf("encode webp: %v", err)
+	}
+	avifs, err := os.ReadFile("testdata/images/animated.avifs")
+	if err != nil {
+		t.Fatalf("read avifs: %v", err)
+	}
+	jpg, err := os.ReadFile("testdata/images/sample.jpg")
+	if err != nil {
+		t.Fatalf("read jpg: %v", err)
+	}
+
+	tests := []struct {
+		name   string
+		data   []byte
+		format string
+		frames int
+	}{
+		{"animated gif", animatedGIF(t, 8, 6, red, blue, red), "gif", 3},
+		{"still gif", animatedGIF(t, 8, 6, red), "gif", 1},
+		{"animated webp", animatedWebP(t, 8, 8, []image.Image{solid(8, 8, red), solid(4, 4, blue)}, []image.Point{{}, {2, 2}}), "webp", 2},
+		{"still webp", stillWebP.Bytes(), "webp", 1},
+		{"animated avif", avifs, "avif", 17},
+		{"jpeg", jpg, "", 1},
+	}
+	for _, tt := range tests {
+		format, frames := AnimationInfo(tt.data)
+		if format != tt.format || frames != tt.frames {
+			t.Errorf("%s: expected %s with %d frames, got %s with %d", tt.name, tt.format, tt.frames, format, frames)
+		}
+	}
+
+	// Truncated data doesn't read past the end
+	gifData := animatedGIF(t, 8, 6, red, blue)
+	for n := 0; n < len(gifData); n++ {
+		AnimationInfo(gifData[:n])
+	}
+}
+
+func TestValidateAndDecode_AnimatedWebPPoster(t *testing.T) {
+	red, blue := color.RGBA{255, 0, 0, 255}, color.RGBA{0, 0, 255, 255}
+
+	// The first frame covers part of the canvas; the rest is transparent
+	data := animatedWebP(t, 8, 6, []image.Image{solid(4, 2, red), solid(8, 6, blue)}, []image.Point{{2, 2}, {}})
+	img, _, err := ValidateAndDecode(bytes.NewReader(data), int64(len(data)))
+	if err != nil {
+		t.Fatalf("decode animated webp: %v", err)
+	}
+	if img.Bounds().Dx() != 8 || img.Bounds().Dy() != 6 {
+		t.Fatalf("expected 8x6 canvas, got %v", img.Bounds())
+	}
+	if r, _, b, a := img.At(3, 3).RGBA(); r>>8 != 255 || b != 0 || a>>8 != 255 {
+		t.Errorf("expected the first frame at its offset, got %v", img.At(3, 3))
+	}
+	if _, _, _, a := img.At(0, 0).RGBA(); a != 0 {
+		t.Errorf("expected transparent canvas outside the first frame, got %v", img.At(0, 0))
+	}
+}
+
+func TestValidateAndDecode_AnimatedWebPOversized(t *testing.T) {
+	red := color.RGBA{255, 0, 0, 255}
+	frames := []image.Image{solid(4, 4, red), solid(4, 4, red)}
+
+	// A small frame on a 60000x60000 canvas would allocate gigabytes
+	data := animatedWebP(t, 60000, 60000, frames, []image.Point{{}, {}})
+	if _, _, err := ValidateAndDecode(bytes.NewReader(data), int64(len(data))); !errors.Is(err, ErrInvalidDimensions) {
+		t.Errorf("oversized canvas: expected ErrInvalidDimensions, got %v", err)
+	}
+
+	// A frame reaching past the canvas
+	data = animatedWebP(t, 8, 8, frames, []image.Point{{6, 0}, {}})
+	if _, _, err := ValidateAndDecode(bytes.NewReader(data), int64(len(data))); !errors.Is(err, ErrInvalidDimensions) {
+		t.Errorf("frame past the canvas: expected ErrInvalidDimensions, got %v", err)
+	}
+}
+
+func TestProcessAndSave_Animation(t *testing.T) {
+	tmp := t.TempDir()
+	d, err := db.InitDB(filepath.Join(tmp, "test.db"))
+	if err != nil {
+		t.Fatalf("init db: %v", err)
+	}
+	defer d.Close()
+
+	ctx := WithSkipUploadEvent(context.Background())
+	q := sqlc.New(d)
+	alb, err := q.CreateAlbum(ctx, sqlc.CreateAlbumParams{Title: "stickers"})
+	if err != nil {
+		t.Fatalf("create album: %v", err)
+	}
+
+	upload := animatedGIF(t, 40, 20, color.RGBA{255, 0, 0, 255}, color.RGBA{0, 0, 255, 255})
+	profile := DefaultProfile("webp")
+	profile.MaxDimension = 10
+	photo, err := ProcessAndSaveWithProfile(ctx, d, alb.ID, bytes.NewReader(upload), 10<<20, tmp, profile)
+	if err != nil {
+		t.Fatalf("process and save failed: %v", err)
+	}
+
+	// The animation is kept unchanged next to a resized still poster
+	if photo.Format != "webp" || photo.Width != 10 || photo.Height != 5 {
+		t.Errorf("expected a 10x5 webp poster, got %s %dx%d", photo.Format, photo.Width, photo.Height)
+	}
+	if stored, _ := q.GetPhoto(ctx, photo.ID); stored.AnimationFormat.String != "gif" {
+		t.Fatalf("expected animation format gif recorded, got %v", stored.AnimationFormat)
+	}
+	kept, err := os.ReadFile(storage.AnimationPath(tmp, photo.ID, "gif"))
+	if err != nil || !bytes.Equal(kept, upload) {
+		t.Fatalf("expected animation kept unchanged: %v", err)
+	}
+
+	// Over either limit only the poster is stored
+	for _, limits := range []AnimationLimits{{MaxFrames: 1, MaxBytes: 10 << 20}, {MaxFrames: 10, MaxBytes: 10}, {}} {
+		profile.Animation = limits
+		still, err := ProcessAndSaveWithProfile(ctx, d, alb.ID, bytes.NewReader(upload), 10<<20, tmp, profile)
+		if err != nil {
+			t.Fatalf("process and save failed: %v", err)
+		}
+		if still.AnimationFormat.Valid {
+			t.Errorf("%+v: expected no animation kept", limits)
+		}
+		if _, err := os.Stat(storage.AnimationPath(tmp, still.ID, "gif")); !os.IsNotExist(err) {
+			t.Errorf("%+v: expected no animation file, got %v", limits, err)
+		}
+	}
+}
+
+func TestProcessAndSave_AnimationMetadata(t *testing.T) {
+	tmp := t.TempDir()
+	d, err := db.InitDB(filepath.Join(tmp, "test.db"))
+	if err != nil {
+		t.Fatalf("init db: %v", err)
+	}
+	defer d.Close()
+
+	ctx := WithSkipUploadEvent(context.Background())
+	q := sqlc.New(d)
+	alb, err := q.CreateAlbum(ctx, sqlc.CreateAlbumParams{Title: "stickers"})
+	if err != nil {
+		t.Fatalf("create album: %v", err)
+	}
+
+	red, blue := color.RGBA{255, 0, 0, 255}, color.RGBA{0, 0, 255, 255}
+	plain := animatedWebP(t, 8, 8, []image.Image{solid(8, 8, red), solid(8, 8, blue)}, []image.Point{{}, {}})
+	upload := withWebPMetadata(plain, "Exif\x00\x00GPS-EXIF", "<x:xmpmeta>GPS-XMP</x:xmpmeta>")
+
+	for _, strip := range []bool{true, false} {
+		profile := DefaultProfile("webp")
+		profile.StripMetadata = strip
+		photo, err := ProcessAndSaveWithProfile(ctx, d, alb.ID, bytes.NewReader(upload), 10<<20, tmp, profile)
+		if err != nil {
+			t.Fatalf("process and save failed: %v", err)
+		}
+		kept, err := os.ReadFile(storage.AnimationPath(tmp, photo.ID, "webp"))
+		if err != nil {
+			t.Fatalf("read animation: %v", err)
+		}
+		if !strip {
+			if !bytes.Equal(kept, upload) {
+				t.Errorf("expected the animation kept unchanged when metadata is kept")
+			}
+			continue
+		}
+		if !bytes.Equal(kept, plain) {
+			t.Errorf("expected the animation without its EXIF and XMP chunks")
+		}
+		if _, frames := AnimationInfo(kept); frames != 2 {
+			t.Errorf("expected the stripped animation to keep 2 frames, got %d", frames)
+		}
+	}
+}
+
+func TestStripGIFMetadata(t *testing.T) {
+	red, blue := color.RGBA{255, 0, 0, 255}, color.RGBA{0, 0, 255, 255}
+	plain := animatedGIF(t, 8, 6, red, blue)
+
+	// An XMP application extension ends with a "magic trailer" so readers
+	// skipping it as sub-blocks land on its terminator
+	xmp := append([]byte("\x21\xFF\x0BXMP DataXMP"), "<x:xmpmeta>GPS-XMP</x:xmpmeta>"...)
+	xmp = append(xmp, 0x01)
+	for b := 0xFF; b >= 0; b-- {
+		xmp = append(xmp, byte(b))
+	}
+	xmp = append(xmp, 0x00)
+	comment := []byte("\x21\xFE\x05hello\x00")
+	trailer := len(plain) - 1
+	data := append(append(append(bytes.Clone(plain[:trailer]), xmp...), comment...), plain[trailer:]...)
+	if _, frames := AnimationInfo(data); frames != 2 {
+		t.Fatalf("expected the GIF with XMP to have 2 frames, got %d", frames)
+	}
+
+	stripped := stripAnimationMetadata("gif", data)
+	want := append(append(bytes.Clone(plain[:trailer]), comment...), plain[trailer:]...)
+	if !bytes.Equal(stripped, want) {
+		t.Errorf("expected only the XMP extension dropped")
+	}
+	if anim, err := gif.DecodeAll(bytes.NewReader(stripped)); err != nil || len(anim.Image) != 2 {
+		t.Errorf("expected the stripped GIF to decode with 2 frames: %v", err)
+	}
+	if got := stripAnimationMetadata("gif", plain); !bytes.Equal(got, plain) {
+		t.Errorf("expected a GIF without XMP unchanged")
+	}
+}
+
+func TestStripAVIFMetadata(t *testing.T) {
+	infe := func(id uint16, itemType string, extra ...byte) []byte {
+		p := []byte{2, 0, 0, 0}
+		p = binary.BigEndian.AppendUint16(p, id)
+		p = append(p, 0, 0)
+		return bmffBox("infe", append(append(p, itemType...), extra...))
+	}
+	items := [][]byte{[]byte("AV1-IMAGE"), []byte("\x00\x00\x00\x00GPS-EXIF"), []byte("<x:xmpmeta>GPS-XMP</x:xmpmeta>")}
+
+	build := func(mdatOffset int) []byte {
+		iloc := []byte{0, 0, 0, 0, 0x44, 0x00}
+		iloc = binary.BigEndian.AppendUint16(iloc, uint16(len(items)))
+		offset := mdatOffset + 8
+		for i, item := range items {
+			iloc = binary.BigEndian.AppendUint16(iloc, uint16(i+1))
+			iloc = append(iloc, 0, 0, 0, 1) // data_reference_index, extent_count
+			iloc = binary.BigEndian.AppendUint32(iloc, uint32(offset))
+			iloc = binary.BigEndian.AppendUint32(iloc, uint32(len(item)))
+			offset += len(item)
+		}
+		iinf := append([]byte{0, 0, 0, 0, 0, 3}, infe(1, "av01")...)
+		iinf = append(iinf, infe(2, "Exif")...)
+		iinf = append(iinf, infe(3, "mime", append([]byte("\x00"), "application/rdf+xml\x00"...)...)...)
+		meta := bmffBox("meta", []byte{0, 0, 0, 0}, bmffBox("iinf", iinf), bmffBox("iloc", iloc))
+		return append(bmffBox("ftyp", []byte("avis\x00\x00\x00\x00")), meta...)
+	}
+	header := build(len(build(0)))
+	data := append(header, bmffBox("mdat", items...)...)
+	size := len(data)
+
+	stripAVIFMetadata(data)
+	if len(data) != size {
+		t.Fatalf("expected the size to stay %d, got %d", size, len(data))
+	}
+	if bytes.Contains(data, []byte("GPS-")) {
+		t.Errorf("expected the Exif and XMP items blanked")
+	}
+	if !bytes.Contains(data, items[0]) {
+		t.Errorf("expected the image item kept")
+	}
+}
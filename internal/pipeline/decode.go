@@ -2,6 +2,7 @@ package pipeline
 
 import (
 	"bytes"
+	"errors"
 	"fmt"
 	"image"
 	"image/jpeg"
@@ -56,10 +57,18 @@ func ValidateAndDecode(r io.Reader, maxBytes int64) (image.Image, string, error)
 	case strings.HasPrefix(ct, "image/gif"):
 		img, _, decodeErr = image.Decode(bytes.NewReader(data))
 	case strings.HasPrefix(ct, "image/webp"):
-		img, decodeErr = webp.Decode(bytes.NewReader(data))
+		if _, frames := AnimationInfo(data); frames > 1 {
+			// The decoder can't read animations; their first frame is the poster
+			img, decodeErr = webpPoster(data)
+		} else {
+			img, decodeErr = webp.Decode(bytes.NewReader(data))
+		}
 	default:
 		return nil, ct, ErrNotAnImage
 	}
+	if errors.Is(decodeErr, ErrInvalidDimensions) {
+		return nil, ct, ErrInvalidDimensions
+	}
 	if decodeErr != nil {
 		return nil, ct, fmt.Errorf("%w: %v", ErrDecodeFailed, decodeErr)
 	}
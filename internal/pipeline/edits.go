@@ -0,0 +1,168 @@
+package pipeline
+
+import (
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"image"
+	"math"
+
+	"github.com/disintegration/imaging"
+)
+
+// Edits are the non-destructive changes made to a photo. They are stored with
+// the photo and applied to its original every time the photo is rendered, so
+// they never stack up quality loss and can be reverted.
+//
+// Edits apply in a fixed order: rotation, mirroring, crop, then adjustments.
+// A vertical flip is stored as a mirror plus a half turn.
+type Edits struct {
+	// Rotate is the rotation in degrees counter-clockwise: 0, 90, 180 or 270.
+	Rotate int `json:"rotate,omitempty"`
+	// Mirror flips the rotated image horizontally.
+	Mirror bool `json:"mirror,omitempty"`
+	// Crop keeps part of the rotated and mirrored image.
+	Crop *Crop `json:"crop,omitempty"`
+	// Adjustments in percent, from -100 to 100.
+	Brightness float64 `json:"brightness,omitempty"`
+	Contrast   float64 `json:"contrast,omitempty"`
+	Saturation float64 `json:"saturation,omitempty"`
+}
+
+// Crop is a rectangle in fractions of the image width and height.
+type Crop struct {
+	X float64 `json:"x"`
+	Y float64 `json:"y"`
+	W float64 `json:"w"`
+	H float64 `json:"h"`
+}
+
+// minCrop is the smallest crop side, as a fraction of the image.
+const minCrop = 0.01
+
+// Valid reports whether c lies within the image and isn't empty.
+func (c Crop) Valid() bool {
+	return c.X >= 0 && c.Y >= 0 && c.W >= minCrop && c.H >= minCrop &&
+		c.X+c.W <= 1+1e-9 && c.Y+c.H <= 1+1e-9
+}
+
+// ParseEdits reads the edits stored with a photo. A photo without edits has
+// a NULL or empty value.
+func ParseEdits(s sql.NullString) (Edits, error) {
+	var e Edits
+	if !s.Valid || s.String == "" {
+		return e, nil
+	}
+	if err := json.Unmarshal([]byte(s.String), &e); err != nil {
+		return Edits{}, fmt.Errorf("parse edits: %w", err)
+	}
+	return e, nil
+}
+
+// Value returns e as stored with a photo; a photo without edits stores NULL.
+func (e Edits) Value() sql.NullString {
+	if e.IsZero() {
+		return sql.NullString{}
+	}
+	data, _ := json.Marshal(e)
+	return sql.NullString{String: string(data), Valid: true}
+}
+
+// IsZero reports whether e leaves the photo unchanged.
+func (e Edits) IsZero() bool {
+	return e.Rotate == 0 && !e.Mirror && e.Crop == nil &&
+		e.Brightness == 0 && e.Contrast == 0 && e.Saturation == 0
+}
+
+// RotateBy turns the edited photo by angle degrees counter-clockwise. The
+// crop turns with it.
+func (e *Edits) RotateBy(angle int) {
+	angle = ((angle % 360) + 360) % 360
+	if e.Mirror {
+		// A mirror reverses the direction of the rotation applied before it
+		e.Rotate = ((e.Rotate-angle)%360 + 360) % 360
+	} else {
+		e.Rotate = (e.Rotate + angle) % 360
+	}
+	if e.Crop != nil {
+		for i := 0; i < angle/90; i++ {
+			c := *e.Crop
+			e.Crop = &Crop{X: c.Y, Y: 1 - c.X - c.W, W: c.H, H: c.W}
+		}
+	}
+}
+
+// Flip mirrors the edited photo horizontally, or vertically when vertical is
+// set. The crop is mirrored with it.
+func (e *Edits) Flip(vertical bool) {
+	e.Mirror = !e.Mirror
+	if e.Crop != nil {
+		c := *e.Crop
+		e.Crop = &Crop{X: 1 - c.X - c.W, Y: c.Y, W: c.W, H: c.H}
+	}
+	if vertical {
+		// A vertical flip is a horizontal one turned upside down. The
+		// half turn goes before the mirror, where it commutes with it.
+		e.Rotate = (e.Rotate + 180) % 360
+		if e.Crop != nil {
+			c := *e.Crop
+			e.Crop = &Crop{X: 1 - c.X - c.W, Y: 1 - c.Y - c.H, W: c.W, H: c.H}
+		}
+	}
+}
+
+// CropTo narrows the crop to c, given in fractions of the photo as it is
+// currently shown, i.e. already cropped. It returns ErrCropTooSmall, leaving
+// the crop alone, when the narrowed crop would be smaller than minCrop.
+func (e *Edits) CropTo(c Crop) error {
+	if e.Crop != nil {
+		cur := *e.Crop
+		c = Crop{
+			X: cur.X + c.X*cur.W,
+			Y: cur.Y + c.Y*cur.H,
+			W: c.W * cur.W,
+			H: c.H * cur.H,
+		}
+	}
+	if !c.Valid() {
+		return ErrCropTooSmall
+	}
+	e.Crop = &c
+	return nil
+}
+
+// Apply renders the edits onto img, which must already have its EXIF
+// orientation applied.
+func (e Edits) Apply(img image.Image) image.Image {
+	if e.Rotate != 0 {
+		img = imaging.Rotate(img, float64(e.Rotate), image.Transparent)
+	}
+	if e.Mirror {
+		img = imaging.FlipH(img)
+	}
+	if e.Crop != nil && e.Crop.Valid() {
+		b := img.Bounds()
+		w, h := float64(b.Dx()), float64(b.Dy())
+		x0 := b.Min.X + int(math.Round(e.Crop.X*w))
+		y0 := b.Min.Y + int(math.Round(e.Crop.Y*h))
+		x1 := b.Min.X + int(math.Round((e.Crop.X+e.Crop.W)*w))
+		y1 := b.Min.Y + int(math.Round((e.Crop.Y+e.Crop.H)*h))
+		if x1 > x0 && y1 > y0 {
+			img = imaging.Crop(img, image.Rect(x0, y0, x1, y1))
+		}
+	}
+	if e.Brightness != 0 {
+		img = imaging.AdjustBrightness(img, clampPercent(e.Brightness))
+	}
+	if e.Contrast != 0 {
+		img = imaging.AdjustContrast(img, clampPercent(e.Contrast))
+	}
+	if e.Saturation != 0 {
+		img = imaging.AdjustSaturation(img, clampPercent(e.Saturation))
+	}
+	return img
+}
+
+func clampPercent(v float64) float64 {
+	return math.Max(-100, math.Min(100, v))
+}
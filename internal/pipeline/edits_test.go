@@ -0,0 +1,140 @@
+package pipeline
+
+import (
+	"database/sql"
+	"errors"
+	"image"
+	"image/color"
+	"math"
+	"testing"
+
+	"github.com/disintegration/imaging"
+)
+
+// numberedImage gives every pixel a distinct color so transforms can be
+// compared exactly.
+func numberedImage(w, h int) *image.NRGBA {
+	img := image.NewNRGBA(image.Rect(0, 0, w, h))
+	for y := 0; y < h; y++ {
+		for x := 0; x < w; x++ {
+			img.Set(x, y, color.NRGBA{R: uint8(x * 40), G: uint8(y * 40), B: 100, A: 255})
+		}
+	}
+	return img
+}
+
+func sameImage(a, b image.Image) bool {
+	if a.Bounds().Dx() != b.Bounds().Dx() || a.Bounds().Dy() != b.Bounds().Dy() {
+		return false
+	}
+	for y := 0; y < a.Bounds().Dy(); y++ {
+		for x := 0; x < a.Bounds().Dx(); x++ {
+			ar, ag, ab, aa := a.At(a.Bounds().Min.X+x, a.Bounds().Min.Y+y).RGBA()
+			br, bg, bb, ba := b.At(b.Bounds().Min.X+x, b.Bounds().Min.Y+y).RGBA()
+			if ar != br || ag != bg || ab != bb || aa != ba {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+func TestEdits_ApplyMatchesOperations(t *testing.T) {
+	src := numberedImage(4, 2)
+
+	type op struct {
+		name   string
+		edit   func(*Edits)
+		direct func(image.Image) image.Image
+	}
+	// The crop keeps the top left quarter, which is whole pixels at any
+	// orientation of a 4x2 image
+	crop := op{"crop", func(e *Edits) { e.CropTo(Crop{X: 0, Y: 0, W: 0.5, H: 0.5}) },
+		func(img image.Image) image.Image {
+			return imaging.Crop(img, image.Rect(0, 0, img.Bounds().Dx()/2, img.Bounds().Dy()/2))
+		}}
+	left := op{"rotate left", func(e *Edits) { e.RotateBy(90) }, func(img image.Image) image.Image { return imaging.Rotate90(img) }}
+	right := op{"rotate right", func(e *Edits) { e.RotateBy(-90) }, func(img image.Image) image.Image { return imaging.Rotate270(img) }}
+	flipH := op{"flip horizontal", func(e *Edits) { e.Flip(false) }, func(img image.Image) image.Image { return imaging.FlipH(img) }}
+	flipV := op{"flip vertical", func(e *Edits) { e.Flip(true) }, func(img image.Image) image.Image { return imaging.FlipV(img) }}
+
+	sequences := [][]op{
+		{left},
+		{flipH, left},
+		{left, flipV, right},
+		{crop, left},
+		{left, crop, flipH},
+		{flipV, crop, right, flipH},
+		{flipH, flipV, left, left, right},
+	}
+	for _, seq := range sequences {
+		var e Edits
+		var want image.Image = src
+		name := ""
+		for _, o := range seq {
+			o.edit(&e)
+			want = o.direct(want)
+			name += o.name + ", "
+		}
+		if got := e.Apply(src); !sameImage(got, want) {
+			t.Errorf("%s: edits %+v don't match the operations", name, e)
+		}
+	}
+}
+
+func TestEdits_Storage(t *testing.T) {
+	if e, err := ParseEdits(sql.NullString{}); err != nil || !e.IsZero() {
+		t.Errorf("expected no edits for NULL, got %+v %v", e, err)
+	}
+	if v := (Edits{}).Value(); v.Valid {
+		t.Errorf("expected NULL for no edits, got %q", v.String)
+	}
+
+	e := Edits{Rotate: 270, Mirror: true, Crop: &Crop{X: 0.1, Y: 0.2, W: 0.5, H: 0.5}, Brightness: 10, Saturation: -20}
+	got, err := ParseEdits(e.Value())
+	if err != nil {
+		t.Fatalf("parse: %v", err)
+	}
+	if got.Rotate != 270 || !got.Mirror || *got.Crop != *e.Crop || got.Brightness != 10 || got.Saturation != -20 || got.Contrast != 0 {
+		t.Errorf("expected %+v back, got %+v", e, got)
+	}
+
+	if _, err := ParseEdits(sql.NullString{String: "{", Valid: true}); err == nil {
+		t.Errorf("expected error for malformed edits")
+	}
+}
+
+func TestEdits_CropTo(t *testing.T) {
+	var e Edits
+	if err := e.CropTo(Crop{X: 0.5, Y: 0.5, W: 0.1, H: 0.1}); err != nil {
+		t.Fatalf("crop: %v", err)
+	}
+	if err := e.CropTo(Crop{X: 0.5, Y: 0, W: 0.5, H: 1}); err != nil {
+		t.Fatalf("narrow crop: %v", err)
+	}
+	want := Crop{X: 0.55, Y: 0.5, W: 0.05, H: 0.1}
+	if c := *e.Crop; math.Abs(c.X-want.X) > 1e-9 || math.Abs(c.Y-want.Y) > 1e-9 || math.Abs(c.W-want.W) > 1e-9 || math.Abs(c.H-want.H) > 1e-9 {
+		t.Errorf("expected %+v, got %+v", want, c)
+	}
+
+	// Narrowing below the smallest crop leaves the crop alone
+	if err := e.CropTo(Crop{X: 0, Y: 0, W: 0.1, H: 1}); !errors.Is(err, ErrCropTooSmall) {
+		t.Errorf("expected ErrCropTooSmall, got %v", err)
+	}
+	if c := *e.Crop; math.Abs(c.W-want.W) > 1e-9 {
+		t.Errorf("expected the crop unchanged, got %+v", c)
+	}
+}
+
+func TestEdits_Adjustments(t *testing.T) {
+	src := imaging.New(2, 2, color.NRGBA{R: 100, G: 100, B: 100, A: 255})
+	got := Edits{Brightness: 50}.Apply(src)
+	r, _, _, _ := got.At(0, 0).RGBA()
+	if r>>8 <= 100 {
+		t.Errorf("expected brighter pixel, got %d", r>>8)
+	}
+
+	if (Crop{X: 0.5, Y: 0, W: 0.6, H: 1}).Valid() || (Crop{W: 0, H: 1}).Valid() || !(Crop{X: 0.5, W: 0.5, H: 1}).Valid() {
+		t.Errorf("unexpected crop validation")
+	}
+}
@@ -1,8 +1,12 @@
 package pipeline
 
 import (
+	"encoding/binary"
 	"image"
 	"io"
+	"math"
+	"strings"
+	"time"
 
 	"github.com/disintegration/imaging"
 	"github.com/rwcarlsen/goexif/exif"
@@ -39,6 +43,118 @@ func ApplyEXIFOrientation(img image.Image, r io.ReadSeeker) (image.Image, error)
 	return orientationTransform(img, orient), nil
 }
 
+// outputEXIF returns the EXIF block of r as TIFF data for embedding into the
+// stored photo, or nil when r has none. The orientation is reset to normal
+// because the pixels were already rotated.
+func outputEXIF(r io.ReadSeeker) []byte {
+	if r == nil {
+		return nil
+	}
+	if _, err := r.Seek(0, io.SeekStart); err != nil {
+		return nil
+	}
+	x, err := exif.Decode(r)
+	if err != nil || len(x.Raw) == 0 {
+		return nil
+	}
+	raw := append([]byte(nil), x.Raw...)
+	resetOrientation(raw)
+	return raw
+}
+
+// resetOrientation sets the Orientation tag in IFD0 of TIFF data to 1.
+func resetOrientation(raw []byte) {
+	if len(raw) < 8 {
+		return
+	}
+	var order binary.ByteOrder
+	switch string(raw[:2]) {
+	case "II":
+		order = binary.LittleEndian
+	case "MM":
+		order = binary.BigEndian
+	default:
+		return
+	}
+	ifd := int(order.Uint32(raw[4:8]))
+	if ifd < 8 || ifd+2 > len(raw) {
+		return
+	}
+	entries := int(order.Uint16(raw[ifd:]))
+	for i := 0; i < entries; i++ {
+		entry := ifd + 2 + i*12
+		if entry+12 > len(raw) {
+			return
+		}
+		if order.Uint16(raw[entry:]) == 0x0112 {
+			order.PutUint16(raw[entry+8:], 1)
+			return
+		}
+	}
+}
+
+// exifDateTimeLayout is the EXIF 2.x timestamp format ("YYYY:MM:DD HH:MM:SS").
+const exifDateTimeLayout = "2006:01:02 15:04:05"
+
+// CaptureTime reads the capture timestamp (DateTimeOriginal, falling back to
+// DateTime) from r. EXIF carries no time zone, so the wall-clock value is
+// returned as UTC. ok is false when the image has no usable timestamp.
+func CaptureTime(r io.ReadSeeker) (t time.Time, ok bool) {
+	if r == nil {
+		return time.Time{}, false
+	}
+	if _, err := r.Seek(0, io.SeekStart); err != nil {
+		return time.Time{}, false
+	}
+
+	x, err := exif.Decode(r)
+	if err != nil {
+		return time.Time{}, false
+	}
+
+	for _, field := range []exif.FieldName{exif.DateTimeOriginal, exif.DateTime} {
+		tag, err := x.Get(field)
+		if err != nil {
+			continue
+		}
+		raw, err := tag.StringVal()
+		if err != nil {
+			continue
+		}
+		t, err := time.ParseInLocation(exifDateTimeLayout, strings.TrimSpace(strings.TrimRight(raw, "\x00")), time.UTC)
+		if err != nil || t.Year() < 1900 {
+			continue
+		}
+		return t, true
+	}
+	return time.Time{}, false
+}
+
+// Location reads the GPS position of r in decimal degrees. ok is false when
+// the image has no usable position; 0,0 is treated as missing because
+// cameras without a fix often record it.
+func Location(r io.ReadSeeker) (lat, lon float64, ok bool) {
+	if r == nil {
+		return 0, 0, false
+	}
+	if _, err := r.Seek(0, io.SeekStart); err != nil {
+		return 0, 0, false
+	}
+
+	x, err := exif.Decode(r)
+	if err != nil {
+		return 0, 0, false
+	}
+	lat, lon, err = x.LatLong()
+	if err != nil || math.IsNaN(lat) || math.IsNaN(lon) || (lat == 0 && lon == 0) {
+		return 0, 0, false
+	}
+	if lat < -90 || lat > 90 || lon < -180 || lon > 180 {
+		return 0, 0, false
+	}
+	return lat, lon, true
+}
+
 // orientationTransform applies the necessary flip/rotation for EXIF orientation
 // values 1-8. Unknown values return the original image.
 func orientationTransform(img image.Image, orientation int) image.Image {
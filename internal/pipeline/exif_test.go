@@ -2,12 +2,20 @@ package pipeline
 
 import (
 	"bytes"
+	"context"
+	"encoding/binary"
 	"image"
 	"image/color"
 	"image/jpeg"
 	"image/png"
 	"io"
+	"math"
+	"path/filepath"
 	"testing"
+	"time"
+
+	"familyshare/internal/db"
+	"familyshare/internal/db/sqlc"
 )
 
 // Helper to build a simple image with a colored pixel to track transforms.
@@ -107,3 +115,165 @@ func TestApplyEXIFOrientation_CorruptReader(t *testing.T) {
 	}
 	_ = r
 }
+
+// jpegWithDateTimeOriginal builds a JPEG whose APP1 segment holds a minimal
+// little-endian TIFF structure: IFD0 -> Exif IFD -> DateTimeOriginal.
+func jpegWithDateTimeOriginal(t *testing.T, stamp string) []byte {
+	t.Helper()
+	plain := &bytes.Buffer{}
+	if err := jpeg.Encode(plain, coloredImage(4, 4), nil); err != nil {
+		t.Fatalf("jpeg encode: %v", err)
+	}
+
+	le := binary.LittleEndian
+	tiff := &bytes.Buffer{}
+	tiff.Write([]byte{'I', 'I', 0x2A, 0x00})
+	_ = binary.Write(tiff, le, uint32(8)) // IFD0 offset
+	// IFD0: one entry pointing at the Exif IFD (offset 26)
+	_ = binary.Write(tiff, le, uint16(1))
+	_ = binary.Write(tiff, le, []uint16{0x8769, 4})
+	_ = binary.Write(tiff, le, []uint32{1, 26, 0})
+	// Exif IFD: DateTimeOriginal, ASCII, value at offset 44
+	value := append([]byte(stamp), 0)
+	_ = binary.Write(tiff, le, uint16(1))
+	_ = binary.Write(tiff, le, []uint16{0x9003, 2})
+	_ = binary.Write(tiff, le, []uint32{uint32(len(value)), 44, 0})
+	tiff.Write(value)
+
+	payload := append([]byte("Exif\x00\x00"), tiff.Bytes()...)
+	out := &bytes.Buffer{}
+	out.Write([]byte{0xFF, 0xD8, 0xFF, 0xE1})
+	_ = binary.Write(out, binary.BigEndian, uint16(len(payload)+2))
+	out.Write(payload)
+	out.Write(plain.Bytes()[2:]) // skip the original SOI
+	return out.Bytes()
+}
+
+func TestCaptureTime(t *testing.T) {
+	data := jpegWithDateTimeOriginal(t, "2021:07:04 15:30:00")
+	got, ok := CaptureTime(bytes.NewReader(data))
+	if !ok {
+		t.Fatal("expected capture time from EXIF")
+	}
+	want := time.Date(2021, 7, 4, 15, 30, 0, 0, time.UTC)
+	if !got.Equal(want) {
+		t.Fatalf("expected %v, got %v", want, got)
+	}
+}
+
+func TestCaptureTime_Missing(t *testing.T) {
+	buf := &bytes.Buffer{}
+	if err := jpeg.Encode(buf, coloredImage(4, 4), nil); err != nil {
+		t.Fatalf("jpeg encode: %v", err)
+	}
+	if _, ok := CaptureTime(bytes.NewReader(buf.Bytes())); ok {
+		t.Fatal("expected no capture time for JPEG without EXIF")
+	}
+	if _, ok := CaptureTime(bytes.NewReader(jpegWithDateTimeOriginal(t, "0000:00:00 00:00:00"))); ok {
+		t.Fatal("expected zeroed EXIF timestamp to be ignored")
+	}
+	if _, ok := CaptureTime(nil); ok {
+		t.Fatal("expected no capture time for nil reader")
+	}
+}
+
+// jpegWithGPS builds a JPEG whose EXIF holds a GPS position.
+func jpegWithGPS(t *testing.T, lat, lon float64) []byte {
+	t.Helper()
+	plain := &bytes.Buffer{}
+	if err := jpeg.Encode(plain, coloredImage(4, 4), nil); err != nil {
+		t.Fatalf("jpeg encode: %v", err)
+	}
+
+	ref := func(v float64, pos, neg byte) uint32 {
+		if v < 0 {
+			return uint32(neg)
+		}
+		return uint32(pos)
+	}
+	dms := func(v float64) []uint32 {
+		v = math.Abs(v)
+		deg := math.Floor(v)
+		min := math.Floor((v - deg) * 60)
+		sec := ((v-deg)*60 - min) * 60
+		return []uint32{uint32(deg), 1, uint32(min), 1, uint32(math.Round(sec * 10000)), 10000}
+	}
+
+	le := binary.LittleEndian
+	tiff := &bytes.Buffer{}
+	tiff.Write([]byte{'I', 'I', 0x2A, 0x00})
+	_ = binary.Write(tiff, le, uint32(8)) // IFD0 offset
+	// IFD0: one entry pointing at the GPS IFD (offset 26)
+	_ = binary.Write(tiff, le, uint16(1))
+	_ = binary.Write(tiff, le, []uint16{0x8825, 4})
+	_ = binary.Write(tiff, le, []uint32{1, 26, 0})
+	// GPS IFD: the references inline, the rationals at offsets 80 and 104
+	_ = binary.Write(tiff, le, uint16(4))
+	_ = binary.Write(tiff, le, []uint16{0x0001, 2})
+	_ = binary.Write(tiff, le, []uint32{2, ref(lat, 'N', 'S')})
+	_ = binary.Write(tiff, le, []uint16{0x0002, 5})
+	_ = binary.Write(tiff, le, []uint32{3, 80})
+	_ = binary.Write(tiff, le, []uint16{0x0003, 2})
+	_ = binary.Write(tiff, le, []uint32{2, ref(lon, 'E', 'W')})
+	_ = binary.Write(tiff, le, []uint16{0x0004, 5})
+	_ = binary.Write(tiff, le, []uint32{3, 104, 0})
+	_ = binary.Write(tiff, le, dms(lat))
+	_ = binary.Write(tiff, le, dms(lon))
+
+	payload := append([]byte("Exif\x00\x00"), tiff.Bytes()...)
+	out := &bytes.Buffer{}
+	out.Write([]byte{0xFF, 0xD8, 0xFF, 0xE1})
+	_ = binary.Write(out, binary.BigEndian, uint16(len(payload)+2))
+	out.Write(payload)
+	out.Write(plain.Bytes()[2:]) // skip the original SOI
+	return out.Bytes()
+}
+
+func TestLocation(t *testing.T) {
+	lat, lon, ok := Location(bytes.NewReader(jpegWithGPS(t, -33.8568, 151.2153)))
+	if !ok {
+		t.Fatal("expected a location from EXIF GPS")
+	}
+	if math.Abs(lat+33.8568) > 1e-6 || math.Abs(lon-151.2153) > 1e-6 {
+		t.Fatalf("expected -33.8568,151.2153, got %v,%v", lat, lon)
+	}
+
+	if _, _, ok := Location(bytes.NewReader(jpegWithGPS(t, 0, 0))); ok {
+		t.Error("expected 0,0 to be ignored")
+	}
+	if _, _, ok := Location(bytes.NewReader(jpegWithDateTimeOriginal(t, "2021:07:04 15:30:00"))); ok {
+		t.Error("expected no location without GPS")
+	}
+	if _, _, ok := Location(nil); ok {
+		t.Error("expected no location for nil reader")
+	}
+}
+
+func TestProcessAndSave_Location(t *testing.T) {
+	tmp := t.TempDir()
+	d, err := db.InitDB(filepath.Join(tmp, "test.db"))
+	if err != nil {
+		t.Fatalf("init db: %v", err)
+	}
+	defer d.Close()
+
+	ctx := WithSkipUploadEvent(context.Background())
+	q := sqlc.New(d)
+	alb, err := q.CreateAlbum(ctx, sqlc.CreateAlbumParams{Title: "trip"})
+	if err != nil {
+		t.Fatalf("create album: %v", err)
+	}
+
+	// The location is recorded even when the stored photo strips metadata
+	photo, err := ProcessAndSave(ctx, d, alb.ID, bytes.NewReader(jpegWithGPS(t, 48.8584, 2.2945)), 10<<20, tmp)
+	if err != nil {
+		t.Fatalf("process and save failed: %v", err)
+	}
+	stored, err := q.GetPhoto(ctx, photo.ID)
+	if err != nil {
+		t.Fatalf("GetPhoto: %v", err)
+	}
+	if !stored.Latitude.Valid || math.Abs(stored.Latitude.Float64-48.8584) > 1e-6 || math.Abs(stored.Longitude.Float64-2.2945) > 1e-6 {
+		t.Errorf("expected location 48.8584,2.2945 recorded, got %v,%v", stored.Latitude, stored.Longitude)
+	}
+}
@@ -0,0 +1,32 @@
+package pipeline
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+
+	"familyshare/internal/storage"
+)
+
+// LogoMaxDimension bounds the stored logo. Public pages show it in a
+// header, so it stays small even on high density screens.
+const LogoMaxDimension = 256
+
+// WriteLogo validates an uploaded logo, fits it within LogoMaxDimension and
+// stores it as WebP at dst, keeping any transparency.
+func WriteLogo(upload io.ReadSeeker, maxBytes int64, dst string) error {
+	img, _, err := ValidateAndDecode(upload, maxBytes)
+	if err != nil {
+		return fmt.Errorf("validate decode: %w", err)
+	}
+	if _, err := upload.Seek(0, io.SeekStart); err == nil {
+		img, _ = ApplyEXIFOrientation(img, upload)
+	}
+	img = Resize(img, LogoMaxDimension)
+
+	var buf bytes.Buffer
+	if err := EncodeWebP(img, &buf, DefaultWebPQuality); err != nil {
+		return fmt.Errorf("encode webp: %w", err)
+	}
+	return storage.AtomicWrite(dst, &buf)
+}
@@ -0,0 +1,41 @@
+package pipeline
+
+import (
+	"bytes"
+	"errors"
+	"image"
+	"image/png"
+	"os"
+	"path/filepath"
+	"testing"
+
+	webp "github.com/chai2010/webp"
+)
+
+func TestWriteLogo(t *testing.T) {
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, image.NewNRGBA(image.Rect(0, 0, 1000, 500))); err != nil {
+		t.Fatalf("encode png: %v", err)
+	}
+
+	dst := filepath.Join(t.TempDir(), "branding", "logo.webp")
+	if err := WriteLogo(bytes.NewReader(buf.Bytes()), 1<<20, dst); err != nil {
+		t.Fatalf("WriteLogo: %v", err)
+	}
+	data, err := os.ReadFile(dst)
+	if err != nil {
+		t.Fatalf("read logo: %v", err)
+	}
+	cfg, err := webp.DecodeConfig(bytes.NewReader(data))
+	if err != nil {
+		t.Fatalf("expected a WebP logo: %v", err)
+	}
+	if cfg.Width != LogoMaxDimension || cfg.Height != LogoMaxDimension/2 {
+		t.Errorf("expected the logo fitted within %d pixels, got %dx%d", LogoMaxDimension, cfg.Width, cfg.Height)
+	}
+
+	err = WriteLogo(bytes.NewReader([]byte("not an image")), 1<<20, dst)
+	if !errors.Is(err, ErrNotAnImage) {
+		t.Errorf("expected ErrNotAnImage, got %v", err)
+	}
+}
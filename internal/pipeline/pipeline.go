@@ -5,10 +5,16 @@ import (
 	"context"
 	"database/sql"
 	"fmt"
+	"image"
 	"io"
+	"log"
+	"os"
 	"strings"
 
+	webp "github.com/chai2010/webp"
+
 	"familyshare/internal/db/sqlc"
+	"familyshare/internal/storage"
 )
 
 // MaxPipelineDimension is the maximum width/height used when resizing images
@@ -37,9 +43,23 @@ func ProcessAndSaveWithFormat(
 	maxBytes int64,
 	baseDir string,
 	format string,
+) (*sqlc.Photo, error) {
+	return ProcessAndSaveWithProfile(ctx, db, albumID, upload, maxBytes, baseDir, DefaultProfile(format))
+}
+
+// ProcessAndSaveWithProfile runs the full pipeline with the size, encoding and
+// storage settings of a processing profile.
+func ProcessAndSaveWithProfile(
+	ctx context.Context,
+	db *sql.DB,
+	albumID int64,
+	upload io.ReadSeeker,
+	maxBytes int64,
+	baseDir string,
+	profile Profile,
 ) (*sqlc.Photo, error) {
 	// Validate and decode
-	img, _, err := ValidateAndDecode(upload, maxBytes)
+	img, contentType, err := ValidateAndDecode(upload, maxBytes)
 	if err != nil {
 		return nil, fmt.Errorf("validate decode: %w", err)
 	}
@@ -50,38 +70,137 @@ func ProcessAndSaveWithFormat(
 			img, _ = ApplyEXIFOrientation(img, upload)
 		}
 	}
+	takenAt, hasTakenAt := CaptureTime(upload)
+	lat, lon, hasLocation := Location(upload)
+
+	// Resize to the profile maximum
+	img = Resize(img, profile.maxDimension())
+
+	var raw []byte
+	if !profile.StripMetadata {
+		raw = outputEXIF(upload)
+	}
+	encoded, format, err := encodeWithProfile(img, profile, raw)
+	if err != nil {
+		return nil, err
+	}
+
+	sizeBytes := len(encoded)
+	// Save encoded data and create DB record
+	_, _, photo, err := SaveProcessedImage(ctx, db, baseDir, albumID, bytes.NewReader(encoded), img.Bounds().Dx(), img.Bounds().Dy(), sizeBytes, format)
+	if err != nil {
+		return nil, fmt.Errorf("save processed image: %w", err)
+	}
+
+	// Capture time only affects sorting, so a failure here is not fatal
+	if hasTakenAt {
+		taken := sql.NullTime{Time: takenAt, Valid: true}
+		if err := sqlc.New(db).UpdatePhotoTakenAt(ctx, sqlc.UpdatePhotoTakenAtParams{TakenAt: taken, ID: photo.ID}); err != nil {
+			log.Printf("failed to record capture time for photo %d: %v", photo.ID, err)
+		} else {
+			photo.TakenAt = taken
+		}
+	}
+
+	// Like the capture time, the location is not essential
+	if hasLocation {
+		if err := recordLocation(ctx, db, photo, lat, lon); err != nil {
+			log.Printf("failed to record location of photo %d: %v", photo.ID, err)
+		}
+	}
+
+	// The photo is usable without its original, so a failure here is not fatal
+	if profile.KeepOriginal {
+		if ext, err := archiveOriginal(ctx, db, baseDir, photo.ID, upload, maxBytes, contentType); err != nil {
+			log.Printf("failed to archive original of photo %d: %v", photo.ID, err)
+		} else {
+			photo.OriginalFormat = sql.NullString{String: ext, Valid: true}
+		}
+	}
 
-	// Resize to pipeline maximum
-	img = Resize(img, MaxPipelineDimension)
+	// The poster is usable without its animation, so a failure here is not fatal
+	if upload != nil {
+		if format, err := keepAnimation(ctx, db, baseDir, photo.ID, upload, maxBytes, contentType, profile); err != nil {
+			log.Printf("failed to keep animation of photo %d: %v", photo.ID, err)
+		} else if format != "" {
+			photo.AnimationFormat = sql.NullString{String: format, Valid: true}
+		}
+	}
 
-	format = normalizeFormat(format)
+	return photo, nil
+}
+
+// recordLocation stores the capture location of a photo.
+func recordLocation(ctx context.Context, db *sql.DB, photo *sqlc.Photo, lat, lon float64) error {
+	latitude := sql.NullFloat64{Float64: lat, Valid: true}
+	longitude := sql.NullFloat64{Float64: lon, Valid: true}
+	err := sqlc.New(db).UpdatePhotoLocation(ctx, sqlc.UpdatePhotoLocationParams{Latitude: latitude, Longitude: longitude, ID: photo.ID})
+	if err != nil {
+		return err
+	}
+	photo.Latitude, photo.Longitude = latitude, longitude
+	return nil
+}
+
+// encodeWithProfile encodes img in the format and quality of profile and
+// returns the encoded data with its format. The raw EXIF block is embedded
+// when the profile keeps metadata and the format can carry it.
+func encodeWithProfile(img image.Image, profile Profile, raw []byte) ([]byte, string, error) {
+	format := normalizeFormat(profile.Format)
 	if format == "" {
 		format = "webp"
 	}
 
-	// Encode
 	var buf bytes.Buffer
 	switch format {
 	case "avif":
-		if err := EncodeAVIF(img, &buf, DefaultAVIFQuality, DefaultAVIFSpeed); err != nil {
-			return nil, fmt.Errorf("encode avif: %w", err)
+		if err := EncodeAVIF(img, &buf, profile.Quality, profile.AVIFSpeed); err != nil {
+			return nil, "", fmt.Errorf("encode avif: %w", err)
 		}
 	case "webp":
-		if err := EncodeWebP(img, &buf, DefaultWebPQuality); err != nil {
-			return nil, fmt.Errorf("encode webp: %w", err)
+		quality := profile.Quality
+		if quality <= 0 {
+			quality = DefaultWebPQuality
+		}
+		if err := EncodeWebP(img, &buf, quality); err != nil {
+			return nil, "", fmt.Errorf("encode webp: %w", err)
 		}
 	default:
-		return nil, fmt.Errorf("unsupported format: %s", format)
+		return nil, "", fmt.Errorf("unsupported format: %s", format)
 	}
 
-	sizeBytes := buf.Len()
-	// Save encoded data and create DB record
-	_, _, photo, err := SaveProcessedImage(ctx, db, baseDir, albumID, bytes.NewReader(buf.Bytes()), img.Bounds().Dx(), img.Bounds().Dy(), sizeBytes, format)
-	if err != nil {
-		return nil, fmt.Errorf("save processed image: %w", err)
+	encoded := buf.Bytes()
+	if !profile.StripMetadata && format == "webp" && raw != nil {
+		// Keeping metadata is best effort; the photo is stored either way
+		if withEXIF, err := webp.SetMetadata(encoded, raw, "EXIF"); err != nil {
+			log.Printf("failed to embed EXIF metadata: %v", err)
+		} else {
+			encoded = withEXIF
+		}
 	}
+	return encoded, format, nil
+}
 
-	return photo, nil
+// archiveOriginal stores the uploaded file unchanged next to the processed
+// photo and records its format, returning the extension it was stored with.
+func archiveOriginal(ctx context.Context, db *sql.DB, baseDir string, photoID int64, upload io.ReadSeeker, maxBytes int64, contentType string) (string, error) {
+	if _, err := upload.Seek(0, io.SeekStart); err != nil {
+		return "", fmt.Errorf("rewind upload: %w", err)
+	}
+	ext := originalExtension(contentType)
+	path := storage.OriginalPath(baseDir, photoID, ext)
+	if err := storage.AtomicWrite(path, io.LimitReader(upload, maxBytes)); err != nil {
+		return "", fmt.Errorf("write original: %w", err)
+	}
+	err := sqlc.New(db).SetPhotoOriginalFormat(ctx, sqlc.SetPhotoOriginalFormatParams{
+		OriginalFormat: sql.NullString{String: ext, Valid: true},
+		ID:             photoID,
+	})
+	if err != nil {
+		_ = os.Remove(path)
+		return "", fmt.Errorf("record original: %w", err)
+	}
+	return ext, nil
 }
 
 func normalizeFormat(format string) string {
@@ -0,0 +1,46 @@
+package pipeline
+
+import (
+	"bytes"
+	"fmt"
+	"image/jpeg"
+	"os"
+
+	"github.com/disintegration/imaging"
+
+	"familyshare/internal/storage"
+)
+
+// Link preview size. Chat apps show previews small and some refuse large
+// images, so previews are JPEGs fitted within the size Open Graph suggests.
+const (
+	PreviewWidth   = 1200
+	PreviewHeight  = 630
+	previewQuality = 80
+	// maxPreviewSourceBytes bounds the stored photo read to build a preview
+	maxPreviewSourceBytes = 64 << 20
+)
+
+// WritePreview renders the link preview of the stored photo at src to dst.
+func WritePreview(src, dst string) error {
+	f, err := os.Open(src)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	img, _, err := ValidateAndDecode(f, maxPreviewSourceBytes)
+	if err != nil {
+		return fmt.Errorf("decode %s: %w", src, err)
+	}
+	b := img.Bounds()
+	if b.Dx() > PreviewWidth || b.Dy() > PreviewHeight {
+		img = imaging.Fit(img, PreviewWidth, PreviewHeight, imaging.Lanczos)
+	}
+
+	var buf bytes.Buffer
+	if err := jpeg.Encode(&buf, img, &jpeg.Options{Quality: previewQuality}); err != nil {
+		return fmt.Errorf("encode preview: %w", err)
+	}
+	return storage.AtomicWrite(dst, &buf)
+}
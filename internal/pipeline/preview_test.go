@@ -0,0 +1,43 @@
+package pipeline
+
+import (
+	"bytes"
+	"image"
+	"image/jpeg"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestWritePreview(t *testing.T) {
+	dir := t.TempDir()
+	src := filepath.Join(dir, "photo.webp")
+	var buf bytes.Buffer
+	if err := EncodeWebP(image.NewRGBA(image.Rect(0, 0, 2400, 1800)), &buf, DefaultWebPQuality); err != nil {
+		t.Fatalf("EncodeWebP: %v", err)
+	}
+	if err := os.WriteFile(src, buf.Bytes(), 0o644); err != nil {
+		t.Fatalf("write photo: %v", err)
+	}
+
+	dst := filepath.Join(dir, "previews", "1.jpg")
+	if err := WritePreview(src, dst); err != nil {
+		t.Fatalf("WritePreview: %v", err)
+	}
+	f, err := os.Open(dst)
+	if err != nil {
+		t.Fatalf("open preview: %v", err)
+	}
+	defer f.Close()
+	cfg, err := jpeg.DecodeConfig(f)
+	if err != nil {
+		t.Fatalf("expected a JPEG preview: %v", err)
+	}
+	if cfg.Width != 840 || cfg.Height != PreviewHeight {
+		t.Errorf("expected the photo fitted within %dx%d, got %dx%d", PreviewWidth, PreviewHeight, cfg.Width, cfg.Height)
+	}
+
+	if err := WritePreview(filepath.Join(dir, "missing.webp"), dst); err == nil {
+		t.Errorf("expected an error for a missing photo")
+	}
+}
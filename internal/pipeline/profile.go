@@ -0,0 +1,101 @@
+package pipeline
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"strings"
+
+	"familyshare/internal/db/sqlc"
+)
+
+// Profile controls how an upload is resized, encoded and stored.
+type Profile struct {
+	MaxDimension int
+	Format       string // webp or avif
+	Quality      int
+	AVIFSpeed    int
+	// KeepOriginal archives the uploaded file so the photo can be reprocessed.
+	KeepOriginal bool
+	// StripMetadata drops the EXIF block from the stored photo. Only WebP
+	// output can carry EXIF; AVIF output is always stripped. Kept animations
+	// lose their EXIF and XMP too.
+	StripMetadata bool
+	// Animation limits the animations kept of animated uploads.
+	Animation AnimationLimits
+}
+
+// DefaultProfile returns the built-in settings used for albums without a
+// processing profile.
+func DefaultProfile(format string) Profile {
+	format = normalizeFormat(format)
+	if format == "" {
+		format = "webp"
+	}
+	quality := DefaultWebPQuality
+	if format == "avif" {
+		quality = DefaultAVIFQuality
+	}
+	return Profile{
+		MaxDimension:  MaxPipelineDimension,
+		Format:        format,
+		Quality:       quality,
+		AVIFSpeed:     DefaultAVIFSpeed,
+		StripMetadata: true,
+		Animation:     DefaultAnimationLimits(),
+	}
+}
+
+// maxDimension is the longest side photos are resized to.
+func (p Profile) maxDimension() int {
+	if p.MaxDimension <= 0 {
+		return MaxPipelineDimension
+	}
+	return p.MaxDimension
+}
+
+// ProfileFrom converts a stored processing profile.
+func ProfileFrom(p sqlc.ProcessingProfile) Profile {
+	return Profile{
+		MaxDimension:  int(p.MaxDimension),
+		Format:        p.Format,
+		Quality:       int(p.Quality),
+		AVIFSpeed:     int(p.AvifSpeed),
+		KeepOriginal:  p.KeepOriginals,
+		StripMetadata: p.StripMetadata,
+		Animation:     DefaultAnimationLimits(),
+	}
+}
+
+// AlbumProfile returns the processing profile selected for an album, or the
+// default profile for defaultFormat when it has none. On error the default
+// profile is returned as well.
+func AlbumProfile(ctx context.Context, q *sqlc.Queries, albumID int64, defaultFormat string) (Profile, error) {
+	p, err := q.GetAlbumProcessingProfile(ctx, albumID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			err = nil
+		}
+		return DefaultProfile(defaultFormat), err
+	}
+	return ProfileFrom(p), nil
+}
+
+// originalExtension maps a detected upload content type to the extension its
+// archived original is stored with.
+func originalExtension(contentType string) string {
+	switch {
+	case strings.HasPrefix(contentType, "image/jpeg"):
+		return "jpg"
+	case strings.HasPrefix(contentType, "image/png"):
+		return "png"
+	case strings.HasPrefix(contentType, "image/gif"):
+		return "gif"
+	case strings.HasPrefix(contentType, "image/webp"):
+		return "webp"
+	case strings.HasPrefix(contentType, "image/avif"):
+		return "avif"
+	default:
+		return "bin"
+	}
+}
@@ -0,0 +1,120 @@
+package pipeline
+
+import (
+	"bytes"
+	"context"
+	"encoding/binary"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	webp "github.com/chai2010/webp"
+
+	"familyshare/internal/db"
+	"familyshare/internal/db/sqlc"
+	"familyshare/internal/storage"
+)
+
+func TestDefaultProfile(t *testing.T) {
+	p := DefaultProfile("")
+	if p.Format != "webp" || p.Quality != DefaultWebPQuality || p.MaxDimension != MaxPipelineDimension || !p.StripMetadata || p.KeepOriginal {
+		t.Errorf("unexpected webp default %+v", p)
+	}
+	if p := DefaultProfile(".AVIF"); p.Format != "avif" || p.Quality != DefaultAVIFQuality {
+		t.Errorf("unexpected avif default %+v", p)
+	}
+}
+
+func TestResetOrientation(t *testing.T) {
+	for _, order := range []binary.ByteOrder{binary.LittleEndian, binary.BigEndian} {
+		raw := make([]byte, 26)
+		if order == binary.LittleEndian {
+			copy(raw, "II*\x00")
+		} else {
+			copy(raw, "MM\x00*")
+		}
+		order.PutUint32(raw[4:], 8)
+		order.PutUint16(raw[8:], 1)
+		order.PutUint16(raw[10:], 0x0112) // Orientation
+		order.PutUint16(raw[12:], 3)      // SHORT
+		order.PutUint32(raw[14:], 1)
+		order.PutUint16(raw[18:], 6) // rotated 90°
+
+		resetOrientation(raw)
+		if got := order.Uint16(raw[18:]); got != 1 {
+			t.Errorf("%v: expected orientation 1, got %d", order, got)
+		}
+	}
+
+	// Malformed data is left alone
+	resetOrientation([]byte("II*\x00\xff\xff\xff\xff"))
+	resetOrientation(nil)
+}
+
+func TestProcessAndSaveWithProfile(t *testing.T) {
+	tmp := t.TempDir()
+	d, err := db.InitDB(filepath.Join(tmp, "test.db"))
+	if err != nil {
+		t.Fatalf("init db: %v", err)
+	}
+	defer d.Close()
+
+	ctx := WithSkipUploadEvent(context.Background())
+	q := sqlc.New(d)
+	alb, err := q.CreateAlbum(ctx, sqlc.CreateAlbumParams{Title: "wedding"})
+	if err != nil {
+		t.Fatalf("create album: %v", err)
+	}
+
+	upload := jpegWithDateTimeOriginal(t, "2021:07:04 15:30:00")
+	profile := Profile{MaxDimension: 2, Format: "webp", Quality: 90, KeepOriginal: true}
+	photo, err := ProcessAndSaveWithProfile(ctx, d, alb.ID, bytes.NewReader(upload), 10<<20, tmp, profile)
+	if err != nil {
+		t.Fatalf("process and save failed: %v", err)
+	}
+	if photo.Width != 2 || photo.Height != 2 {
+		t.Errorf("expected photo resized to 2x2, got %dx%d", photo.Width, photo.Height)
+	}
+
+	// The original is archived unchanged and recorded on the photo
+	if photo.OriginalFormat.String != "jpg" {
+		t.Fatalf("expected original format jpg, got %v", photo.OriginalFormat)
+	}
+	if stored, _ := q.GetPhoto(ctx, photo.ID); stored.OriginalFormat != photo.OriginalFormat {
+		t.Errorf("expected original format stored, got %v", stored.OriginalFormat)
+	}
+	original, err := os.ReadFile(storage.OriginalPath(tmp, photo.ID, "jpg"))
+	if err != nil || !bytes.Equal(original, upload) {
+		t.Fatalf("expected original archived unchanged: %v", err)
+	}
+
+	// Metadata is kept when the profile doesn't strip it
+	data, err := os.ReadFile(storage.PhotoPathAt(tmp, alb.ID, photo.ID, photo.Format, photo.CreatedAt.Time))
+	if err != nil {
+		t.Fatalf("read photo: %v", err)
+	}
+	raw, err := webp.GetMetadata(data, "EXIF")
+	if err != nil || len(raw) == 0 {
+		t.Fatalf("expected EXIF kept: %v", err)
+	}
+	if got, ok := CaptureTime(bytes.NewReader(raw)); !ok || !got.Equal(time.Date(2021, 7, 4, 15, 30, 0, 0, time.UTC)) {
+		t.Errorf("expected capture time in stored EXIF, got %v", got)
+	}
+
+	// The default profile strips it and keeps no original
+	stripped, err := ProcessAndSaveWithProfile(ctx, d, alb.ID, bytes.NewReader(upload), 10<<20, tmp, DefaultProfile("webp"))
+	if err != nil {
+		t.Fatalf("process and save failed: %v", err)
+	}
+	if stripped.OriginalFormat.Valid {
+		t.Errorf("expected no original kept")
+	}
+	data, err = os.ReadFile(storage.PhotoPathAt(tmp, alb.ID, stripped.ID, stripped.Format, stripped.CreatedAt.Time))
+	if err != nil {
+		t.Fatalf("read photo: %v", err)
+	}
+	if raw, _ := webp.GetMetadata(data, "EXIF"); len(raw) != 0 {
+		t.Errorf("expected EXIF stripped")
+	}
+}
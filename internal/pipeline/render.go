@@ -0,0 +1,170 @@
+package pipeline
+
+import (
+	"bytes"
+	"context"
+	"database/sql"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"strings"
+
+	webp "github.com/chai2010/webp"
+
+	"familyshare/internal/db/sqlc"
+	"familyshare/internal/storage"
+)
+
+// Reprocess re-encodes a stored photo with the settings of profile. It works
+// from the archived original when there is one, applying the photo's edits,
+// and from the stored file otherwise. The stored file is replaced atomically
+// and the new filename, format, size and dimensions are recorded.
+func Reprocess(ctx context.Context, db *sql.DB, baseDir string, photo sqlc.Photo, profile Profile) (*sqlc.Photo, error) {
+	edits, err := ParseEdits(photo.Edits)
+	if err != nil {
+		return nil, err
+	}
+	return render(ctx, db, baseDir, photo, edits, profile)
+}
+
+// Edit stores new edits for a photo and renders it with them. A photo
+// without an archived original first keeps its stored file as the original,
+// so every later edit and a revert start from the same pixels.
+func Edit(ctx context.Context, db *sql.DB, baseDir string, photo sqlc.Photo, edits Edits, profile Profile) (*sqlc.Photo, error) {
+	if err := ensureOriginal(ctx, db, baseDir, &photo); err != nil {
+		return nil, err
+	}
+	return render(ctx, db, baseDir, photo, edits, profile)
+}
+
+// render encodes a photo from its best source with edits and profile, then
+// swaps the stored file and records the result together with the edits.
+func render(ctx context.Context, db *sql.DB, baseDir string, photo sqlc.Photo, edits Edits, profile Profile) (*sqlc.Photo, error) {
+	current := storage.PhotoPathAt(baseDir, photo.AlbumID, photo.ID, photo.Format, photo.CreatedAt.Time)
+
+	data, fromOriginal, err := renderSource(baseDir, photo, current)
+	if err != nil {
+		return nil, err
+	}
+
+	img, _, err := ValidateAndDecode(bytes.NewReader(data), int64(len(data)))
+	if err != nil {
+		return nil, fmt.Errorf("validate decode: %w", err)
+	}
+
+	// The stored file already has the orientation and the edits applied
+	var raw []byte
+	if fromOriginal {
+		r := bytes.NewReader(data)
+		img, _ = ApplyEXIFOrientation(img, r)
+		// Photos processed before locations were captured get theirs now
+		if !photo.Latitude.Valid {
+			if lat, lon, ok := Location(r); ok {
+				if err := recordLocation(ctx, db, &photo, lat, lon); err != nil {
+					log.Printf("failed to record location of photo %d: %v", photo.ID, err)
+				}
+			}
+		}
+		img = edits.Apply(img)
+		if !profile.StripMetadata {
+			raw = outputEXIF(r)
+			if raw == nil {
+				// An original kept from a stored WebP carries its EXIF there
+				raw, _ = webp.GetMetadata(data, "EXIF")
+			}
+		}
+	} else if !profile.StripMetadata && photo.Format == "webp" {
+		raw, _ = webp.GetMetadata(data, "EXIF")
+	}
+
+	img = Resize(img, profile.maxDimension())
+	encoded, format, err := encodeWithProfile(img, profile, raw)
+	if err != nil {
+		return nil, err
+	}
+
+	path := storage.PhotoPathAt(baseDir, photo.AlbumID, photo.ID, format, photo.CreatedAt.Time)
+	if err := storage.AtomicWrite(path, bytes.NewReader(encoded)); err != nil {
+		return nil, fmt.Errorf("atomic write: %w", err)
+	}
+
+	filename := strings.TrimSuffix(photo.Filename, filepath.Ext(photo.Filename)) + "." + format
+	err = sqlc.New(db).UpdatePhotoRendition(ctx, sqlc.UpdatePhotoRenditionParams{
+		Filename:  filename,
+		Format:    format,
+		Width:     int64(img.Bounds().Dx()),
+		Height:    int64(img.Bounds().Dy()),
+		SizeBytes: int64(len(encoded)),
+		Edits:     edits.Value(),
+		ID:        photo.ID,
+	})
+	if err != nil {
+		// The old file is still in place unless it was overwritten
+		if path != current {
+			_ = os.Remove(path)
+		}
+		return nil, fmt.Errorf("update photo: %w", err)
+	}
+
+	// The photo now points at the new file
+	if path != current {
+		if err := os.Remove(current); err != nil && !os.IsNotExist(err) {
+			log.Printf("failed to remove previous file of photo %d: %v", photo.ID, err)
+		}
+	}
+
+	photo.Filename = filename
+	photo.Format = format
+	photo.Width = int64(img.Bounds().Dx())
+	photo.Height = int64(img.Bounds().Dy())
+	photo.SizeBytes = int64(len(encoded))
+	photo.Edits = edits.Value()
+	return &photo, nil
+}
+
+// renderSource reads the best available source of a photo: the archived
+// original, or the stored file at current when there is none.
+func renderSource(baseDir string, photo sqlc.Photo, current string) ([]byte, bool, error) {
+	if photo.OriginalFormat.Valid {
+		data, err := os.ReadFile(storage.OriginalPath(baseDir, photo.ID, photo.OriginalFormat.String))
+		if err == nil {
+			return data, true, nil
+		}
+		log.Printf("original of photo %d unavailable, using the stored file: %v", photo.ID, err)
+	}
+	data, err := os.ReadFile(current)
+	if err != nil {
+		return nil, false, fmt.Errorf("read photo: %w", err)
+	}
+	return data, false, nil
+}
+
+// ensureOriginal archives the stored file of a photo as its original when it
+// has none, and records it on photo.
+func ensureOriginal(ctx context.Context, db *sql.DB, baseDir string, photo *sqlc.Photo) error {
+	if photo.OriginalFormat.Valid {
+		if _, err := os.Stat(storage.OriginalPath(baseDir, photo.ID, photo.OriginalFormat.String)); err == nil {
+			return nil
+		}
+	}
+
+	f, err := os.Open(storage.PhotoPathAt(baseDir, photo.AlbumID, photo.ID, photo.Format, photo.CreatedAt.Time))
+	if err != nil {
+		return fmt.Errorf("open photo: %w", err)
+	}
+	defer f.Close()
+
+	path := storage.OriginalPath(baseDir, photo.ID, photo.Format)
+	if err := storage.AtomicWrite(path, f); err != nil {
+		return fmt.Errorf("write original: %w", err)
+	}
+	format := sql.NullString{String: photo.Format, Valid: true}
+	err = sqlc.New(db).SetPhotoOriginalFormat(ctx, sqlc.SetPhotoOriginalFormatParams{OriginalFormat: format, ID: photo.ID})
+	if err != nil {
+		_ = os.Remove(path)
+		return fmt.Errorf("record original: %w", err)
+	}
+	photo.OriginalFormat = format
+	return nil
+}
@@ -0,0 +1,177 @@
+package pipeline
+
+import (
+	"bytes"
+	"context"
+	"image/png"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"familyshare/internal/db"
+	"familyshare/internal/db/sqlc"
+	"familyshare/internal/storage"
+)
+
+func TestReprocess(t *testing.T) {
+	tmp := t.TempDir()
+	d, err := db.InitDB(filepath.Join(tmp, "test.db"))
+	if err != nil {
+		t.Fatalf("init db: %v", err)
+	}
+	defer d.Close()
+
+	ctx := WithSkipUploadEvent(context.Background())
+	q := sqlc.New(d)
+	alb, err := q.CreateAlbum(ctx, sqlc.CreateAlbumParams{Title: "garden"})
+	if err != nil {
+		t.Fatalf("create album: %v", err)
+	}
+
+	var upload bytes.Buffer
+	if err := png.Encode(&upload, coloredImage(8, 4)); err != nil {
+		t.Fatalf("png encode: %v", err)
+	}
+	keep := Profile{MaxDimension: 8, Format: "webp", Quality: 80, KeepOriginal: true, StripMetadata: true}
+	saved, err := ProcessAndSaveWithProfile(ctx, d, alb.ID, bytes.NewReader(upload.Bytes()), 10<<20, tmp, keep)
+	if err != nil {
+		t.Fatalf("process and save failed: %v", err)
+	}
+	if _, err := d.Exec(`UPDATE photos SET edits = '{"rotate":90}' WHERE id = ?`, saved.ID); err != nil {
+		t.Fatalf("store edits: %v", err)
+	}
+	photo, err := q.GetPhoto(ctx, saved.ID)
+	if err != nil {
+		t.Fatalf("get photo: %v", err)
+	}
+	oldPath := storage.PhotoPathAt(tmp, photo.AlbumID, photo.ID, photo.Format, photo.CreatedAt.Time)
+
+	// From the original: edits applied again, then resized and re-encoded
+	profile := Profile{MaxDimension: 4, Format: "avif", Quality: 50, AVIFSpeed: 10, StripMetadata: true}
+	got, err := Reprocess(ctx, d, tmp, photo, profile)
+	if err != nil {
+		t.Fatalf("reprocess failed: %v", err)
+	}
+	if got.Format != "avif" || got.Width != 2 || got.Height != 4 || !strings.HasSuffix(got.Filename, ".avif") {
+		t.Errorf("unexpected reprocessed photo %+v", got)
+	}
+	stored, err := q.GetPhoto(ctx, photo.ID)
+	if err != nil {
+		t.Fatalf("get photo: %v", err)
+	}
+	if stored.Format != got.Format || stored.Width != got.Width || stored.Height != got.Height || stored.SizeBytes != got.SizeBytes || stored.Filename != got.Filename {
+		t.Errorf("expected stored photo %+v to match %+v", stored, got)
+	}
+	fi, err := os.Stat(storage.PhotoPathAt(tmp, stored.AlbumID, stored.ID, "avif", stored.CreatedAt.Time))
+	if err != nil || fi.Size() != stored.SizeBytes {
+		t.Fatalf("expected new file of %d bytes: %v", stored.SizeBytes, err)
+	}
+	if _, err := os.Stat(oldPath); !os.IsNotExist(err) {
+		t.Errorf("expected previous webp file removed, got %v", err)
+	}
+
+	// Without an original the stored file is the source
+	plain, err := ProcessAndSaveWithProfile(ctx, d, alb.ID, bytes.NewReader(upload.Bytes()), 10<<20, tmp, Profile{MaxDimension: 8, Format: "webp", StripMetadata: true})
+	if err != nil {
+		t.Fatalf("process and save failed: %v", err)
+	}
+	got, err = Reprocess(ctx, d, tmp, *plain, Profile{MaxDimension: 4, Format: "webp", Quality: 60, StripMetadata: true})
+	if err != nil {
+		t.Fatalf("reprocess failed: %v", err)
+	}
+	if got.Width != 4 || got.Height != 2 {
+		t.Errorf("expected 4x2 from the stored file, got %dx%d", got.Width, got.Height)
+	}
+	path := storage.PhotoPathAt(tmp, plain.AlbumID, plain.ID, "webp", plain.CreatedAt.Time)
+	if fi, err := os.Stat(path); err != nil || fi.Size() != got.SizeBytes {
+		t.Errorf("expected file replaced in place: %v", err)
+	}
+
+	// A missing file fails without touching the record
+	if err := os.Remove(path); err != nil {
+		t.Fatalf("remove: %v", err)
+	}
+	if _, err := Reprocess(ctx, d, tmp, *got, DefaultProfile("avif")); err == nil {
+		t.Errorf("expected error for missing file")
+	}
+	if stored, _ := q.GetPhoto(ctx, plain.ID); stored.Format != "webp" {
+		t.Errorf("expected record unchanged, got format %s", stored.Format)
+	}
+}
+
+func TestEdit(t *testing.T) {
+	tmp := t.TempDir()
+	d, err := db.InitDB(filepath.Join(tmp, "test.db"))
+	if err != nil {
+		t.Fatalf("init db: %v", err)
+	}
+	defer d.Close()
+
+	ctx := WithSkipUploadEvent(context.Background())
+	q := sqlc.New(d)
+	alb, err := q.CreateAlbum(ctx, sqlc.CreateAlbumParams{Title: "garden"})
+	if err != nil {
+		t.Fatalf("create album: %v", err)
+	}
+
+	var upload bytes.Buffer
+	if err := png.Encode(&upload, coloredImage(8, 4)); err != nil {
+		t.Fatalf("png encode: %v", err)
+	}
+	profile := Profile{MaxDimension: 8, Format: "webp", Quality: 90, StripMetadata: true}
+	photo, err := ProcessAndSaveWithProfile(ctx, d, alb.ID, bytes.NewReader(upload.Bytes()), 10<<20, tmp, profile)
+	if err != nil {
+		t.Fatalf("process and save failed: %v", err)
+	}
+	stored, err := os.ReadFile(storage.PhotoPathAt(tmp, alb.ID, photo.ID, "webp", photo.CreatedAt.Time))
+	if err != nil {
+		t.Fatalf("read photo: %v", err)
+	}
+
+	// Edits keep the stored file as the original and render from it
+	edits := Edits{}
+	edits.RotateBy(90)
+	edits.CropTo(Crop{X: 0, Y: 0, W: 1, H: 0.5})
+	edited, err := Edit(ctx, d, tmp, *photo, edits, profile)
+	if err != nil {
+		t.Fatalf("edit failed: %v", err)
+	}
+	if edited.Width != 4 || edited.Height != 4 {
+		t.Errorf("expected rotated 4x8 cropped to 4x4, got %dx%d", edited.Width, edited.Height)
+	}
+	if edited.OriginalFormat.String != "webp" {
+		t.Fatalf("expected stored file kept as original, got %v", edited.OriginalFormat)
+	}
+	original, err := os.ReadFile(storage.OriginalPath(tmp, photo.ID, "webp"))
+	if err != nil || !bytes.Equal(original, stored) {
+		t.Fatalf("expected original to be the unedited stored file: %v", err)
+	}
+	got, err := q.GetPhoto(ctx, photo.ID)
+	if err != nil {
+		t.Fatalf("get photo: %v", err)
+	}
+	if saved, _ := ParseEdits(got.Edits); saved.Rotate != 90 || saved.Crop == nil || got.Width != 4 {
+		t.Errorf("expected edits and dimensions stored, got %+v %dx%d", saved, got.Width, got.Height)
+	}
+
+	// Further edits start from the original, not the edited file
+	edits.RotateBy(90)
+	edited, err = Edit(ctx, d, tmp, got, edits, profile)
+	if err != nil {
+		t.Fatalf("edit failed: %v", err)
+	}
+	if edited.Width != 4 || edited.Height != 4 {
+		t.Errorf("expected 4x4, got %dx%d", edited.Width, edited.Height)
+	}
+
+	// Reverting renders the original without edits
+	got, _ = q.GetPhoto(ctx, photo.ID)
+	reverted, err := Edit(ctx, d, tmp, got, Edits{}, profile)
+	if err != nil {
+		t.Fatalf("revert failed: %v", err)
+	}
+	if reverted.Width != 8 || reverted.Height != 4 || reverted.Edits.Valid {
+		t.Errorf("expected original 8x4 without edits, got %dx%d %v", reverted.Width, reverted.Height, reverted.Edits)
+	}
+}
@@ -7,6 +7,7 @@ var (
 	ErrTooLarge          = errors.New("image exceeds size limit")
 	ErrInvalidDimensions = errors.New("image dimensions out of range")
 	ErrDecodeFailed      = errors.New("failed to decode image")
+	ErrCropTooSmall      = errors.New("crop is too small")
 )
 
 // Default maximum dimension (width or height) allowed by validator.
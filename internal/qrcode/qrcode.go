@@ -0,0 +1,443 @@
+// Package qrcode encodes text as QR codes (ISO/IEC 18004) and draws them as
+// PNG or SVG. It covers what share links need: byte mode at error correction
+// level M, versions 1 to 10, which holds up to 213 bytes.
+package qrcode
+
+import (
+	"errors"
+)
+
+// ErrTooLong is returned for text that doesn't fit in the largest supported
+// version.
+var ErrTooLong = errors.New("qrcode: text too long")
+
+// QuietZone is the light border, in modules, that readers need around a code.
+const QuietZone = 4
+
+// Code is an encoded QR code: a square of dark and light modules.
+type Code struct {
+	Version int
+	Size    int
+	modules [][]bool
+}
+
+// Dark reports whether the module at column x and row y is dark.
+func (c *Code) Dark(x, y int) bool {
+	return c.modules[y][x]
+}
+
+// ecBlocks describes the error correction blocks of a version at level M:
+// the error correction codewords per block, then the number of blocks and
+// data codewords per block of the two groups.
+type ecBlocks struct {
+	ecPerBlock     int
+	blocks1, data1 int
+	blocks2, data2 int
+}
+
+var versionsM = [...]ecBlocks{
+	1:  {10, 1, 16, 0, 0},
+	2:  {16, 1, 28, 0, 0},
+	3:  {26, 1, 44, 0, 0},
+	4:  {18, 2, 32, 0, 0},
+	5:  {24, 2, 43, 0, 0},
+	6:  {16, 4, 27, 0, 0},
+	7:  {18, 4, 31, 0, 0},
+	8:  {22, 2, 38, 2, 39},
+	9:  {22, 3, 36, 2, 37},
+	10: {26, 4, 43, 1, 44},
+}
+
+// MaxVersion is the largest version Encode produces.
+const MaxVersion = len(versionsM) - 1
+
+func (b ecBlocks) dataCodewords() int {
+	return b.blocks1*b.data1 + b.blocks2*b.data2
+}
+
+// Encode encodes text in the smallest version that holds it.
+func Encode(text string) (*Code, error) {
+	data := []byte(text)
+	for version := 1; version <= MaxVersion; version++ {
+		if bits := 4 + countBits(version) + 8*len(data); bits <= 8*versionsM[version].dataCodewords() {
+			return encode(version, data), nil
+		}
+	}
+	return nil, ErrTooLong
+}
+
+// countBits is the length of the character count of byte mode.
+func countBits(version int) int {
+	if version < 10 {
+		return 8
+	}
+	return 16
+}
+
+func encode(version int, data []byte) *Code {
+	blocks := versionsM[version]
+	codewords := interleave(blocks, dataCodewords(version, data))
+
+	size := 4*version + 17
+	c := &Code{Version: version, Size: size, modules: newGrid(size)}
+	function := newGrid(size)
+	c.drawFunctionPatterns(function)
+	c.drawCodewords(function, codewords)
+
+	best, bestPenalty := 0, -1
+	for mask := 0; mask < 8; mask++ {
+		c.applyMask(function, mask)
+		c.drawFormatBits(function, mask)
+		if p := c.penalty(); bestPenalty < 0 || p < bestPenalty {
+			best, bestPenalty = mask, p
+		}
+		c.applyMask(function, mask) // masks undo themselves
+	}
+	c.applyMask(function, best)
+	c.drawFormatBits(function, best)
+	return c
+}
+
+func newGrid(size int) [][]bool {
+	grid := make([][]bool, size)
+	for y := range grid {
+		grid[y] = make([]bool, size)
+	}
+	return grid
+}
+
+// dataCodewords lays out the data in byte mode and pads it to the capacity of
+// the version.
+func dataCodewords(version int, data []byte) []byte {
+	var bb bitBuffer
+	bb.append(0b0100, 4)
+	bb.append(len(data), countBits(version))
+	for _, b := range data {
+		bb.append(int(b), 8)
+	}
+
+	capacity := 8 * versionsM[version].dataCodewords()
+	terminator := min(4, capacity-len(bb))
+	bb.append(0, terminator)
+	bb.append(0, (8-len(bb)%8)%8)
+	for pad := 0xEC; len(bb) < capacity; pad ^= 0xEC ^ 0x11 {
+		bb.append(pad, 8)
+	}
+	return bb.bytes()
+}
+
+// interleave splits the data into blocks, adds each block's error correction
+// codewords and interleaves the blocks as the symbol stores them.
+func interleave(b ecBlocks, data []byte) []byte {
+	divisor := rsDivisor(b.ecPerBlock)
+	var dataBlocks, ecc [][]byte
+	for i := 0; i < b.blocks1+b.blocks2; i++ {
+		n := b.data1
+		if i >= b.blocks1 {
+			n = b.data2
+		}
+		dataBlocks = append(dataBlocks, data[:n])
+		ecc = append(ecc, rsRemainder(data[:n], divisor))
+		data = data[n:]
+	}
+
+	var out []byte
+	for i := 0; i < max(b.data1, b.data2); i++ {
+		for _, block := range dataBlocks {
+			if i < len(block) {
+				out = append(out, block[i])
+			}
+		}
+	}
+	for i := 0; i < b.ecPerBlock; i++ {
+		for _, block := range ecc {
+			out = append(out, block[i])
+		}
+	}
+	return out
+}
+
+// bitBuffer is a sequence of bits, most significant first.
+type bitBuffer []bool
+
+func (bb *bitBuffer) append(value, length int) {
+	for i := length - 1; i >= 0; i-- {
+		*bb = append(*bb, value>>i&1 == 1)
+	}
+}
+
+func (bb bitBuffer) bytes() []byte {
+	out := make([]byte, len(bb)/8)
+	for i, bit := range bb {
+		if bit {
+			out[i/8] |= 0x80 >> (i % 8)
+		}
+	}
+	return out
+}
+
+// set draws a function module and marks it as one.
+func (c *Code) set(function [][]bool, x, y int, dark bool) {
+	c.modules[y][x] = dark
+	function[y][x] = true
+}
+
+func (c *Code) drawFunctionPatterns(function [][]bool) {
+	// Timing patterns
+	for i := 0; i < c.Size; i++ {
+		c.set(function, 6, i, i%2 == 0)
+		c.set(function, i, 6, i%2 == 0)
+	}
+
+	// Finder patterns with their separators
+	c.drawFinder(function, 3, 3)
+	c.drawFinder(function, c.Size-4, 3)
+	c.drawFinder(function, 3, c.Size-4)
+
+	// Alignment patterns, except where they'd overlap the finders
+	positions := alignmentPositions(c.Version)
+	last := len(positions) - 1
+	for i, x := range positions {
+		for j, y := range positions {
+			if i == 0 && j == 0 || i == 0 && j == last || i == last && j == 0 {
+				continue
+			}
+			for dy := -2; dy <= 2; dy++ {
+				for dx := -2; dx <= 2; dx++ {
+					c.set(function, x+dx, y+dy, max(abs(dx), abs(dy)) != 1)
+				}
+			}
+		}
+	}
+
+	// Reserve the format areas, drawn once the mask is chosen
+	c.drawFormatBits(function, 0)
+
+	if c.Version >= 7 {
+		c.drawVersion(function)
+	}
+}
+
+func (c *Code) drawFinder(function [][]bool, cx, cy int) {
+	for dy := -4; dy <= 4; dy++ {
+		for dx := -4; dx <= 4; dx++ {
+			x, y := cx+dx, cy+dy
+			if x < 0 || x >= c.Size || y < 0 || y >= c.Size {
+				continue
+			}
+			d := max(abs(dx), abs(dy))
+			c.set(function, x, y, d != 2 && d != 4)
+		}
+	}
+}
+
+// alignmentPositions returns the centre coordinates of the alignment
+// patterns along each axis.
+func alignmentPositions(version int) []int {
+	if version == 1 {
+		return nil
+	}
+	n := version/7 + 2
+	step := (version*4 + n*2 + 1) / (n*2 - 2) * 2
+	positions := make([]int, n)
+	positions[0] = 6
+	for i, pos := n-1, version*4+10; i >= 1; i, pos = i-1, pos-step {
+		positions[i] = pos
+	}
+	return positions
+}
+
+// formatBits returns the 15 format bits of level M with a mask: the level and
+// mask, their BCH code and the format mask.
+func formatBits(mask int) int {
+	const levelM = 0b00
+	data := levelM<<3 | mask
+	rem := data
+	for i := 0; i < 10; i++ {
+		rem = rem<<1 ^ (rem>>9)*0x537
+	}
+	return (data<<10 | rem) ^ 0x5412
+}
+
+func (c *Code) drawFormatBits(function [][]bool, mask int) {
+	bits := formatBits(mask)
+	bit := func(i int) bool { return bits>>i&1 == 1 }
+
+	// Around the top left finder
+	for i := 0; i <= 5; i++ {
+		c.set(function, 8, i, bit(i))
+	}
+	c.set(function, 8, 7, bit(6))
+	c.set(function, 8, 8, bit(7))
+	c.set(function, 7, 8, bit(8))
+	for i := 9; i < 15; i++ {
+		c.set(function, 14-i, 8, bit(i))
+	}
+
+	// Split between the other two finders
+	for i := 0; i < 8; i++ {
+		c.set(function, c.Size-1-i, 8, bit(i))
+	}
+	for i := 8; i < 15; i++ {
+		c.set(function, 8, c.Size-15+i, bit(i))
+	}
+	c.set(function, 8, c.Size-8, true) // always dark
+}
+
+func (c *Code) drawVersion(function [][]bool) {
+	rem := c.Version
+	for i := 0; i < 12; i++ {
+		rem = rem<<1 ^ (rem>>11)*0x1F25
+	}
+	bits := c.Version<<12 | rem
+	for i := 0; i < 18; i++ {
+		dark := bits>>i&1 == 1
+		a, b := c.Size-11+i%3, i/3
+		c.set(function, a, b, dark)
+		c.set(function, b, a, dark)
+	}
+}
+
+// drawCodewords places the codewords in the zigzag of two-module columns,
+// from the bottom right corner, skipping the function patterns.
+func (c *Code) drawCodewords(function [][]bool, codewords []byte) {
+	i := 0
+	for right := c.Size - 1; right >= 1; right -= 2 {
+		if right == 6 {
+			right = 5 // skip the vertical timing pattern
+		}
+		upward := (right+1)&2 == 0
+		for vert := 0; vert < c.Size; vert++ {
+			y := vert
+			if upward {
+				y = c.Size - 1 - vert
+			}
+			for j := 0; j < 2; j++ {
+				x := right - j
+				if function[y][x] || i >= len(codewords)*8 {
+					continue
+				}
+				c.modules[y][x] = codewords[i/8]>>(7-i%8)&1 == 1
+				i++
+			}
+		}
+	}
+}
+
+// applyMask flips the data modules the mask pattern selects.
+func (c *Code) applyMask(function [][]bool, mask int) {
+	for y := 0; y < c.Size; y++ {
+		for x := 0; x < c.Size; x++ {
+			if !function[y][x] && maskBit(mask, x, y) {
+				c.modules[y][x] = !c.modules[y][x]
+			}
+		}
+	}
+}
+
+func maskBit(mask, x, y int) bool {
+	switch mask {
+	case 0:
+		return (x+y)%2 == 0
+	case 1:
+		return y%2 == 0
+	case 2:
+		return x%3 == 0
+	case 3:
+		return (x+y)%3 == 0
+	case 4:
+		return (x/3+y/2)%2 == 0
+	case 5:
+		return x*y%2+x*y%3 == 0
+	case 6:
+		return (x*y%2+x*y%3)%2 == 0
+	default:
+		return ((x+y)%2+x*y%3)%2 == 0
+	}
+}
+
+// penalty scores how hard the symbol is to read; the mask with the lowest
+// score is used.
+func (c *Code) penalty() int {
+	penalty := 0
+	line := make([]bool, c.Size)
+	for _, vertical := range []bool{false, true} {
+		for i := 0; i < c.Size; i++ {
+			for j := 0; j < c.Size; j++ {
+				if vertical {
+					line[j] = c.modules[j][i]
+				} else {
+					line[j] = c.modules[i][j]
+				}
+			}
+			penalty += linePenalty(line)
+		}
+	}
+
+	// Blocks of 2x2 modules of one color
+	dark := 0
+	for y := 0; y < c.Size; y++ {
+		for x := 0; x < c.Size; x++ {
+			if c.modules[y][x] {
+				dark++
+			}
+			if x+1 < c.Size && y+1 < c.Size {
+				m := c.modules[y][x]
+				if m == c.modules[y][x+1] && m == c.modules[y+1][x] && m == c.modules[y+1][x+1] {
+					penalty += 3
+				}
+			}
+		}
+	}
+
+	// Balance of dark and light modules
+	total := c.Size * c.Size
+	percent := dark * 100 / total
+	penalty += abs(percent-50) / 5 * 10
+	return penalty
+}
+
+var finderLike = [][]bool{
+	{true, false, true, true, true, false, true, false, false, false, false},
+	{false, false, false, false, true, false, true, true, true, false, true},
+}
+
+// linePenalty scores a row or column for runs of one color and for patterns
+// that look like a finder.
+func linePenalty(line []bool) int {
+	penalty := 0
+	run := 1
+	for i := 1; i <= len(line); i++ {
+		if i < len(line) && line[i] == line[i-1] {
+			run++
+			continue
+		}
+		if run >= 5 {
+			penalty += run - 2
+		}
+		run = 1
+	}
+
+	for i := 0; i+11 <= len(line); i++ {
+		for _, pattern := range finderLike {
+			match := true
+			for j, dark := range pattern {
+				if line[i+j] != dark {
+					match = false
+					break
+				}
+			}
+			if match {
+				penalty += 40
+			}
+		}
+	}
+	return penalty
+}
+
+func abs(x int) int {
+	if x < 0 {
+		return -x
+	}
+	return x
+}
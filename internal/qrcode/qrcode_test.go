@@ -0,0 +1,225 @@
+package qrcode
+
+import (
+	"bytes"
+	"image/png"
+	"slices"
+	"strings"
+	"testing"
+)
+
+func TestRSRemainder(t *testing.T) {
+	// HELLO WORLD at version 1-M in alphanumeric mode
+	data := []byte{32, 91, 11, 120, 209, 114, 220, 77, 67, 64, 236, 17, 236, 17, 236, 17}
+	want := []byte{196, 35, 39, 119, 235, 215, 231, 226, 93, 23}
+	if got := rsRemainder(data, rsDivisor(10)); !slices.Equal(got, want) {
+		t.Errorf("expected %v, got %v", want, got)
+	}
+}
+
+func TestAlignmentPositions(t *testing.T) {
+	for version, want := range map[int][]int{1: nil, 2: {6, 18}, 6: {6, 34}, 7: {6, 22, 38}, 10: {6, 28, 50}} {
+		if got := alignmentPositions(version); !slices.Equal(got, want) {
+			t.Errorf("version %d: expected %v, got %v", version, want, got)
+		}
+	}
+}
+
+func TestEncode_Versions(t *testing.T) {
+	for _, tc := range []struct {
+		length, version int
+	}{{1, 1}, {14, 1}, {15, 2}, {62, 4}, {66, 5}, {180, 9}, {213, 10}} {
+		c, err := Encode(strings.Repeat("a", tc.length))
+		if err != nil {
+			t.Fatalf("%d bytes: %v", tc.length, err)
+		}
+		if c.Version != tc.version || c.Size != 4*tc.version+17 {
+			t.Errorf("%d bytes: expected version %d, got %d of size %d", tc.length, tc.version, c.Version, c.Size)
+		}
+	}
+	if _, err := Encode(strings.Repeat("a", 214)); err != ErrTooLong {
+		t.Errorf("expected ErrTooLong, got %v", err)
+	}
+}
+
+func TestEncode_RoundTrip(t *testing.T) {
+	for _, text := range []string{
+		"https://photos.example.com/s/reunion",
+		"https://photos.example.com/s/3q2-7wEAAAAeHUPNq9s-T0yV1AqV7ONmZ0Q5fwSuSxA=",
+		"https://photos.example.com/s/" + strings.Repeat("x", 150),
+	} {
+		c, err := Encode(text)
+		if err != nil {
+			t.Fatalf("Encode: %v", err)
+		}
+		if got := decode(t, c); got != text {
+			t.Errorf("version %d: expected %q back, got %q", c.Version, text, got)
+		}
+	}
+}
+
+func TestEncode_FinderPatterns(t *testing.T) {
+	c, err := Encode("https://photos.example.com/s/reunion")
+	if err != nil {
+		t.Fatalf("Encode: %v", err)
+	}
+	for _, corner := range [][2]int{{0, 0}, {c.Size - 7, 0}, {0, c.Size - 7}} {
+		for dy := 0; dy < 7; dy++ {
+			for dx := 0; dx < 7; dx++ {
+				ring := max(abs(dx-3), abs(dy-3))
+				if c.Dark(corner[0]+dx, corner[1]+dy) != (ring != 2) {
+					t.Fatalf("finder pattern at %v broken at %d,%d", corner, dx, dy)
+				}
+			}
+		}
+	}
+}
+
+// decode reads a code back: the format bits, the codewords under the mask,
+// each block's error correction and the byte mode data.
+func decode(t *testing.T, c *Code) string {
+	t.Helper()
+
+	// Format bits from the top left copy, checked against the other copy
+	var format, other int
+	for i := 0; i <= 5; i++ {
+		format |= b2i(c.Dark(8, i)) << i
+	}
+	format |= b2i(c.Dark(8, 7))<<6 | b2i(c.Dark(8, 8))<<7 | b2i(c.Dark(7, 8))<<8
+	for i := 9; i < 15; i++ {
+		format |= b2i(c.Dark(14-i, 8)) << i
+	}
+	for i := 0; i < 8; i++ {
+		other |= b2i(c.Dark(c.Size-1-i, 8)) << i
+	}
+	for i := 8; i < 15; i++ {
+		other |= b2i(c.Dark(8, c.Size-15+i)) << i
+	}
+	if format != other {
+		t.Fatalf("format copies differ: %015b and %015b", format, other)
+	}
+	mask := -1
+	for m := 0; m < 8; m++ {
+		if formatBits(m) == format {
+			mask = m
+		}
+	}
+	if mask < 0 {
+		t.Fatalf("format bits %015b aren't level M", format)
+	}
+
+	// Codewords in zigzag order under the mask
+	scratch := &Code{Version: c.Version, Size: c.Size, modules: newGrid(c.Size)}
+	function := newGrid(c.Size)
+	scratch.drawFunctionPatterns(function)
+	var bits bitBuffer
+	for right := c.Size - 1; right >= 1; right -= 2 {
+		if right == 6 {
+			right = 5
+		}
+		upward := (right+1)&2 == 0
+		for vert := 0; vert < c.Size; vert++ {
+			y := vert
+			if upward {
+				y = c.Size - 1 - vert
+			}
+			for j := 0; j < 2; j++ {
+				if x := right - j; !function[y][x] {
+					bits = append(bits, c.Dark(x, y) != maskBit(mask, x, y))
+				}
+			}
+		}
+	}
+	codewords := bits[:len(bits)/8*8].bytes()
+
+	// Undo the interleaving and check each block
+	b := versionsM[c.Version]
+	n := b.blocks1 + b.blocks2
+	blocks := make([][]byte, n)
+	i := 0
+	for k := 0; k < max(b.data1, b.data2); k++ {
+		for j := range blocks {
+			if j < b.blocks1 && k >= b.data1 {
+				continue
+			}
+			blocks[j] = append(blocks[j], codewords[i])
+			i++
+		}
+	}
+	var data []byte
+	for j, block := range blocks {
+		var ecc []byte
+		for k := 0; k < b.ecPerBlock; k++ {
+			ecc = append(ecc, codewords[i+k*n+j])
+		}
+		if !slices.Equal(rsRemainder(block, rsDivisor(b.ecPerBlock)), ecc) {
+			t.Fatalf("block %d fails its error correction", j)
+		}
+		data = append(data, block...)
+	}
+
+	// Byte mode segment
+	if data[0]>>4 != 0b0100 {
+		t.Fatalf("expected byte mode, got %04b", data[0]>>4)
+	}
+	var bb bitBuffer
+	for _, d := range data {
+		bb.append(int(d), 8)
+	}
+	length := 0
+	for _, bit := range bb[4 : 4+countBits(c.Version)] {
+		length = length<<1 | b2i(bit)
+	}
+	start := 4 + countBits(c.Version)
+	return string(bb[start : start+8*length].bytes())
+}
+
+func b2i(b bool) int {
+	if b {
+		return 1
+	}
+	return 0
+}
+
+func TestWritePNG(t *testing.T) {
+	c, err := Encode("https://photos.example.com/s/reunion")
+	if err != nil {
+		t.Fatalf("Encode: %v", err)
+	}
+	var buf bytes.Buffer
+	if err := c.WritePNG(&buf, 8); err != nil {
+		t.Fatalf("WritePNG: %v", err)
+	}
+	img, err := png.Decode(&buf)
+	if err != nil {
+		t.Fatalf("decode PNG: %v", err)
+	}
+	width := (c.Size + 2*QuietZone) * 8
+	if img.Bounds().Dx() != width || img.Bounds().Dy() != width {
+		t.Fatalf("expected %dx%d, got %v", width, width, img.Bounds())
+	}
+	if r, _, _, _ := img.At(0, 0).RGBA(); r == 0 {
+		t.Errorf("expected a light quiet zone")
+	}
+	if r, _, _, _ := img.At(QuietZone*8+3, QuietZone*8+3).RGBA(); r != 0 {
+		t.Errorf("expected the finder's corner to be dark")
+	}
+}
+
+func TestWriteSVG(t *testing.T) {
+	c, err := Encode("https://photos.example.com/s/reunion")
+	if err != nil {
+		t.Fatalf("Encode: %v", err)
+	}
+	var buf bytes.Buffer
+	if err := c.WriteSVG(&buf); err != nil {
+		t.Fatalf("WriteSVG: %v", err)
+	}
+	svg := buf.String()
+	if !strings.HasPrefix(svg, "<svg ") || !strings.Contains(svg, `viewBox="0 0 37 37"`) {
+		t.Errorf("expected a 37 unit SVG of version 3 with its quiet zone, got %.80s", svg)
+	}
+	if !strings.Contains(svg, "M4 4h1v1h-1z") {
+		t.Errorf("expected the finder's corner module drawn")
+	}
+}
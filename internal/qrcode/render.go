@@ -0,0 +1,53 @@
+package qrcode
+
+import (
+	"bufio"
+	"fmt"
+	"image"
+	"image/color"
+	"image/png"
+	"io"
+)
+
+// Image draws the code with its quiet zone, each module scale pixels wide.
+func (c *Code) Image(scale int) *image.Paletted {
+	width := (c.Size + 2*QuietZone) * scale
+	img := image.NewPaletted(image.Rect(0, 0, width, width), color.Palette{color.White, color.Black})
+	for y := 0; y < c.Size; y++ {
+		for x := 0; x < c.Size; x++ {
+			if !c.Dark(x, y) {
+				continue
+			}
+			for py := 0; py < scale; py++ {
+				row := img.Pix[((y+QuietZone)*scale+py)*img.Stride:]
+				for px := 0; px < scale; px++ {
+					row[(x+QuietZone)*scale+px] = 1
+				}
+			}
+		}
+	}
+	return img
+}
+
+// WritePNG writes the code as a PNG, each module scale pixels wide.
+func (c *Code) WritePNG(w io.Writer, scale int) error {
+	return png.Encode(w, c.Image(scale))
+}
+
+// WriteSVG writes the code as an SVG that scales to any size, one unit per
+// module.
+func (c *Code) WriteSVG(w io.Writer) error {
+	bw := bufio.NewWriter(w)
+	width := c.Size + 2*QuietZone
+	fmt.Fprintf(bw, `<svg xmlns="http://www.w3.org/2000/svg" viewBox="0 0 %d %d" shape-rendering="crispEdges">`, width, width)
+	fmt.Fprintf(bw, `<rect width="%d" height="%d" fill="#fff"/><path fill="#000" d="`, width, width)
+	for y := 0; y < c.Size; y++ {
+		for x := 0; x < c.Size; x++ {
+			if c.Dark(x, y) {
+				fmt.Fprintf(bw, "M%d %dh1v1h-1z", x+QuietZone, y+QuietZone)
+			}
+		}
+	}
+	bw.WriteString(`"/></svg>`)
+	return bw.Flush()
+}
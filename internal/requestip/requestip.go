@@ -10,6 +10,11 @@ import (
 
 // ParseTrustedProxyCIDRs parses a comma-separated list of CIDR ranges.
 func ParseTrustedProxyCIDRs(value string) ([]netip.Prefix, error) {
+	return ParseCIDRs(value)
+}
+
+// ParseCIDRs parses a comma-separated list of CIDR ranges.
+func ParseCIDRs(value string) ([]netip.Prefix, error) {
 	value = strings.TrimSpace(value)
 	if value == "" {
 		return nil, nil
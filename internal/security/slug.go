@@ -0,0 +1,58 @@
+package security
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// Slug length limits. Slugs stay shorter than tokens so the two never clash.
+const (
+	MinSlugLength = 3
+	MaxSlugLength = 32
+)
+
+var slugPattern = regexp.MustCompile(`^[a-z0-9]+(-[a-z0-9]+)*$`)
+
+// reservedSlugs are words that read like pages of the app rather than a
+// shared album, and so can't be slugs.
+var reservedSlugs = map[string]bool{
+	"admin":     true,
+	"api":       true,
+	"data":      true,
+	"edit":      true,
+	"health":    true,
+	"login":     true,
+	"logout":    true,
+	"map":       true,
+	"new":       true,
+	"photos":    true,
+	"preview":   true,
+	"qr":        true,
+	"s":         true,
+	"settings":  true,
+	"share":     true,
+	"shares":    true,
+	"slideshow": true,
+	"static":    true,
+}
+
+// NormalizeSlug returns the slug as stored: trimmed and lowercase.
+func NormalizeSlug(slug string) string {
+	return strings.ToLower(strings.TrimSpace(slug))
+}
+
+// ValidateSlug checks a normalized slug: lowercase letters, digits and
+// single hyphens between them, and not a reserved word.
+func ValidateSlug(slug string) error {
+	if len(slug) < MinSlugLength || len(slug) > MaxSlugLength {
+		return fmt.Errorf("slug must be %d to %d characters", MinSlugLength, MaxSlugLength)
+	}
+	if !slugPattern.MatchString(slug) {
+		return fmt.Errorf("slug can only contain letters, digits and hyphens between them")
+	}
+	if reservedSlugs[slug] {
+		return fmt.Errorf("slug %q is reserved", slug)
+	}
+	return nil
+}
@@ -0,0 +1,38 @@
+package security_test
+
+import (
+	"testing"
+
+	"familyshare/internal/security"
+)
+
+func TestNormalizeSlug(t *testing.T) {
+	if got := security.NormalizeSlug("  Reunion-2025 "); got != "reunion-2025" {
+		t.Errorf("expected reunion-2025, got %q", got)
+	}
+}
+
+func TestValidateSlug(t *testing.T) {
+	for _, slug := range []string{"reunion-2025", "abc", "grandmas-80th-birthday"} {
+		if err := security.ValidateSlug(slug); err != nil {
+			t.Errorf("%q: expected a valid slug, got %v", slug, err)
+		}
+	}
+	for _, slug := range []string{
+		"",
+		"ab",
+		"this-slug-is-far-too-long-to-read-out",
+		"Reunion",
+		"family reunion",
+		"-reunion",
+		"reunion-",
+		"re--union",
+		"reunion_2025",
+		"admin",
+		"slideshow",
+	} {
+		if err := security.ValidateSlug(slug); err == nil {
+			t.Errorf("%q: expected an invalid slug", slug)
+		}
+	}
+}
@@ -1,7 +1,9 @@
 package security
 
 import (
+	"crypto/hmac"
 	"crypto/rand"
+	"crypto/sha256"
 	"encoding/base64"
 )
 
@@ -18,3 +20,16 @@ func GenerateSecureToken() (string, error) {
 	}
 	return base64.URLEncoding.EncodeToString(b), nil
 }
+
+// GenerateSubToken derives a new token from a parent token, such as the token
+// of one recipient of a share link. Each call derives a different token, of
+// the same length as parent tokens, that reveals nothing of the parent.
+func GenerateSubToken(parent string) (string, error) {
+	nonce := make([]byte, TokenLength/2)
+	if _, err := rand.Read(nonce); err != nil {
+		return "", err
+	}
+	mac := hmac.New(sha256.New, []byte(parent))
+	mac.Write(nonce)
+	return base64.URLEncoding.EncodeToString(mac.Sum(nil)), nil
+}
@@ -210,3 +210,22 @@ func isURLSafeBase64Char(char rune) bool {
 		char == '_' ||
 		char == '='
 }
+
+func TestGenerateSubToken(t *testing.T) {
+	parent, err := security.GenerateSecureToken()
+	if err != nil {
+		t.Fatalf("GenerateSecureToken failed: %v", err)
+	}
+	a, err := security.GenerateSubToken(parent)
+	if err != nil {
+		t.Fatalf("GenerateSubToken failed: %v", err)
+	}
+	b, _ := security.GenerateSubToken(parent)
+
+	if a == b || a == parent {
+		t.Fatalf("expected distinct sub-tokens, got %q and %q", a, b)
+	}
+	if len(a) != len(parent) || strings.ContainsAny(a, "+/") || strings.Contains(a, parent[:8]) {
+		t.Fatalf("expected a URL-safe token like the parent, got %q", a)
+	}
+}
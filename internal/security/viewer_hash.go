@@ -82,7 +82,8 @@ func GetViewerHash(r *http.Request, token string) string {
 	return GenerateViewerHash(token, ip, userAgent)
 }
 
-// SetViewerHashCookie sets the viewer hash cookie
+// SetViewerHashCookie sets the viewer hash cookie. It is sent to every share
+// page, as a link opens through its token and its slug alike.
 func SetViewerHashCookie(w http.ResponseWriter, token, viewerHash string, expiresAt *time.Time, opts CookieOptions) {
 	cookieName := viewerHashCookieName(token)
 
@@ -98,7 +99,7 @@ func SetViewerHashCookie(w http.ResponseWriter, token, viewerHash string, expire
 	cookie := &http.Cookie{
 		Name:     cookieName,
 		Value:    viewerHash,
-		Path:     "/s/" + token,
+		Path:     "/s/",
 		MaxAge:   maxAge,
 		HttpOnly: true,
 		SameSite: opts.SameSite,
@@ -121,8 +121,8 @@ func TestSetViewerHashCookie(t *testing.T) {
 	if cookie.SameSite != http.SameSiteLaxMode {
 		t.Errorf("Expected SameSite=Lax, got %v", cookie.SameSite)
 	}
-	if cookie.Path != "/s/"+token {
-		t.Errorf("Expected path /s/%s, got %s", token, cookie.Path)
+	if cookie.Path != "/s/" {
+		t.Errorf("Expected path /s/, got %s", cookie.Path)
 	}
 }
 
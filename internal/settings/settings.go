@@ -0,0 +1,194 @@
+// Package settings keeps instance-wide settings, such as the branding of
+// public pages, in the settings table. Every share page reads them, so a
+// Store keeps them in memory and only goes back to the database after they
+// change.
+package settings
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+	"sync"
+	"unicode/utf8"
+
+	"familyshare/internal/db/sqlc"
+)
+
+// Keys of the branding settings
+const (
+	KeyFamilyName  = "branding.family_name"
+	KeyLogo        = "branding.logo"
+	KeyAccentColor = "branding.accent_color"
+	KeyFooterText  = "branding.footer_text"
+)
+
+// Limits of the branding text, which shows on every public page
+const (
+	MaxFamilyNameLength = 80
+	MaxFooterTextLength = 300
+)
+
+var accentColorPattern = regexp.MustCompile(`^#[0-9a-f]{6}$`)
+
+// ErrInvalidAccentColor is returned for an accent color that isn't a
+// #rrggbb hex color.
+var ErrInvalidAccentColor = errors.New("accent color must be a #rrggbb color")
+
+// Branding is how public pages present the instance. Empty fields keep the
+// FamilyShare defaults.
+type Branding struct {
+	FamilyName  string
+	AccentColor string
+	FooterText  string
+	// Logo is the version of the uploaded logo, which changes with every
+	// upload so browsers don't show a cached older one. It is empty without
+	// a logo.
+	Logo string
+}
+
+// LogoURL returns where public pages load the logo from, or an empty
+// string without a logo.
+func (b Branding) LogoURL() string {
+	if b.Logo == "" {
+		return ""
+	}
+	return "/branding/logo.webp?v=" + b.Logo
+}
+
+// Validate checks the branding an admin entered.
+func (b Branding) Validate() error {
+	if b.AccentColor != "" && !accentColorPattern.MatchString(b.AccentColor) {
+		return ErrInvalidAccentColor
+	}
+	if utf8.RuneCountInString(b.FamilyName) > MaxFamilyNameLength {
+		return fmt.Errorf("family name must be at most %d characters", MaxFamilyNameLength)
+	}
+	if utf8.RuneCountInString(b.FooterText) > MaxFooterTextLength {
+		return fmt.Errorf("footer text must be at most %d characters", MaxFooterTextLength)
+	}
+	return nil
+}
+
+// NormalizeAccentColor trims and lowercases a color from a form, so
+// "#3B82F6 " and "#3b82f6" are the same setting.
+func NormalizeAccentColor(color string) string {
+	return strings.ToLower(strings.TrimSpace(color))
+}
+
+// Store reads and writes settings, caching them in memory.
+type Store struct {
+	db *sql.DB
+
+	mu sync.RWMutex
+	// values is nil until the settings are first read, and after they
+	// change
+	values map[string]string
+	// version counts changes, so a read that raced with one doesn't cache
+	// what it read
+	version uint64
+}
+
+// New creates a settings store
+func New(db *sql.DB) *Store {
+	return &Store{db: db}
+}
+
+// All returns every setting by key. The map is shared with other callers
+// and must not be modified.
+func (s *Store) All(ctx context.Context) (map[string]string, error) {
+	s.mu.RLock()
+	values, version := s.values, s.version
+	s.mu.RUnlock()
+	if values != nil {
+		return values, nil
+	}
+
+	rows, err := sqlc.New(s.db).ListSettings(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("list settings: %w", err)
+	}
+	values = make(map[string]string, len(rows))
+	for _, row := range rows {
+		values[row.Key] = row.Value
+	}
+
+	s.mu.Lock()
+	if s.version == version {
+		s.values = values
+	}
+	s.mu.Unlock()
+	return values, nil
+}
+
+// Get returns a setting, or an empty string if it was never set.
+func (s *Store) Get(ctx context.Context, key string) (string, error) {
+	values, err := s.All(ctx)
+	if err != nil {
+		return "", err
+	}
+	return values[key], nil
+}
+
+// Set saves settings together, so readers never see half of a change.
+func (s *Store) Set(ctx context.Context, values map[string]string) error {
+	// Readers after this see the new values, or read them again if the
+	// write fails halfway
+	defer s.invalidate()
+
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	q := sqlc.New(tx)
+	for key, value := range values {
+		if err := q.UpsertSetting(ctx, sqlc.UpsertSettingParams{Key: key, Value: value}); err != nil {
+			return fmt.Errorf("save setting %s: %w", key, err)
+		}
+	}
+	return tx.Commit()
+}
+
+func (s *Store) invalidate() {
+	s.mu.Lock()
+	s.values = nil
+	s.version++
+	s.mu.Unlock()
+}
+
+// Branding returns the branding of public pages.
+func (s *Store) Branding(ctx context.Context) (Branding, error) {
+	values, err := s.All(ctx)
+	if err != nil {
+		return Branding{}, err
+	}
+	return Branding{
+		FamilyName:  values[KeyFamilyName],
+		AccentColor: values[KeyAccentColor],
+		FooterText:  values[KeyFooterText],
+		Logo:        values[KeyLogo],
+	}, nil
+}
+
+// SetBranding validates and saves the text and color of the branding. The
+// logo is set with SetLogo, as it changes with an upload.
+func (s *Store) SetBranding(ctx context.Context, b Branding) error {
+	if err := b.Validate(); err != nil {
+		return err
+	}
+	return s.Set(ctx, map[string]string{
+		KeyFamilyName:  b.FamilyName,
+		KeyAccentColor: b.AccentColor,
+		KeyFooterText:  b.FooterText,
+	})
+}
+
+// SetLogo records the version of a newly uploaded logo, or that there is
+// no logo for an empty version.
+func (s *Store) SetLogo(ctx context.Context, version string) error {
+	return s.Set(ctx, map[string]string{KeyLogo: version})
+}
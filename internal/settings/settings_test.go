@@ -0,0 +1,85 @@
+package settings
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"familyshare/internal/db/sqlc"
+	"familyshare/internal/testutil"
+)
+
+func TestStoreCachesUntilSet(t *testing.T) {
+	db, q, cleanup := testutil.SetupTestDB(t)
+	defer cleanup()
+	ctx := context.Background()
+	store := New(db)
+
+	b, err := store.Branding(ctx)
+	if err != nil {
+		t.Fatalf("Branding: %v", err)
+	}
+	if b != (Branding{}) {
+		t.Errorf("expected no branding, got %+v", b)
+	}
+
+	// A change behind the store's back isn't read again
+	if err := q.UpsertSetting(ctx, sqlc.UpsertSettingParams{Key: KeyFamilyName, Value: "The Silvas"}); err != nil {
+		t.Fatalf("UpsertSetting: %v", err)
+	}
+	if name, _ := store.Get(ctx, KeyFamilyName); name != "" {
+		t.Errorf("expected the cached empty name, got %q", name)
+	}
+
+	// Saving through the store reads everything again
+	if err := store.SetLogo(ctx, "42"); err != nil {
+		t.Fatalf("SetLogo: %v", err)
+	}
+	b, err = store.Branding(ctx)
+	if err != nil {
+		t.Fatalf("Branding: %v", err)
+	}
+	if b.FamilyName != "The Silvas" || b.LogoURL() != "/branding/logo.webp?v=42" {
+		t.Errorf("expected the saved branding, got %+v", b)
+	}
+
+	want := Branding{FamilyName: "The Costas", AccentColor: "#aa3300", FooterText: "With love", Logo: "42"}
+	if err := store.SetBranding(ctx, want); err != nil {
+		t.Fatalf("SetBranding: %v", err)
+	}
+	if b, _ := store.Branding(ctx); b != want {
+		t.Errorf("expected %+v, got %+v", want, b)
+	}
+	rows, err := q.ListSettings(ctx)
+	if err != nil || len(rows) != 4 {
+		t.Errorf("expected 4 settings, got %d (%v)", len(rows), err)
+	}
+}
+
+func TestBrandingValidate(t *testing.T) {
+	for _, tc := range []struct {
+		name     string
+		branding Branding
+		ok       bool
+	}{
+		{"empty", Branding{}, true},
+		{"color", Branding{AccentColor: "#0a1b2c"}, true},
+		{"short color", Branding{AccentColor: "#abc"}, false},
+		{"named color", Branding{AccentColor: "red"}, false},
+		{"css injection", Branding{AccentColor: "#000000;}"}, false},
+		{"long name", Branding{FamilyName: strings.Repeat("a", MaxFamilyNameLength+1)}, false},
+		{"long footer", Branding{FooterText: strings.Repeat("ã", MaxFooterTextLength)}, true},
+		{"longer footer", Branding{FooterText: strings.Repeat("ã", MaxFooterTextLength+1)}, false},
+	} {
+		if err := tc.branding.Validate(); (err == nil) != tc.ok {
+			t.Errorf("%s: expected ok=%v, got %v", tc.name, tc.ok, err)
+		}
+	}
+	if err := (Branding{AccentColor: NormalizeAccentColor(" #AA3300 ")}).Validate(); err != nil {
+		t.Errorf("expected a normalized color to be valid, got %v", err)
+	}
+	if err := (Branding{AccentColor: "blue"}).Validate(); !errors.Is(err, ErrInvalidAccentColor) {
+		t.Errorf("expected ErrInvalidAccentColor, got %v", err)
+	}
+}
@@ -36,6 +36,12 @@ func (c *Cleanup) Execute() error {
 // It only touches files matching the prefix/suffix pattern used by uploads: "upload-*.tmp".
 // If dir is empty, it defaults to the system temp dir.
 func CleanOrphanedTempFiles(maxAge time.Duration, dir string) error {
+	return CleanOrphanedTempFilesExcept(maxAge, dir, nil)
+}
+
+// CleanOrphanedTempFilesExcept is CleanOrphanedTempFiles but never removes the
+// files listed in keep (full paths), such as inputs of queued or failed jobs.
+func CleanOrphanedTempFilesExcept(maxAge time.Duration, dir string, keep map[string]bool) error {
 	tmpDir := dir
 	if tmpDir == "" {
 		tmpDir = os.TempDir()
@@ -55,6 +61,9 @@ func CleanOrphanedTempFiles(maxAge time.Duration, dir string) error {
 			continue
 		}
 		full := filepath.Join(tmpDir, name)
+		if keep[full] {
+			continue
+		}
 		info, err := entry.Info()
 		if err != nil {
 			continue
@@ -44,3 +44,30 @@ func TestCleanOrphanedTempFiles(t *testing.T) {
 	// cleanup
 	_ = os.Remove(f2)
 }
+
+func TestCleanOrphanedTempFilesExcept(t *testing.T) {
+	tmp := t.TempDir()
+	kept := filepath.Join(tmp, "upload-failed.tmp")
+	orphan := filepath.Join(tmp, "upload-orphan.tmp")
+
+	old := time.Now().Add(-time.Hour)
+	for _, f := range []string{kept, orphan} {
+		if err := os.WriteFile(f, []byte("old"), 0600); err != nil {
+			t.Fatalf("write %s: %v", f, err)
+		}
+		if err := os.Chtimes(f, old, old); err != nil {
+			t.Fatalf("chtimes %s: %v", f, err)
+		}
+	}
+
+	if err := storage.CleanOrphanedTempFilesExcept(15*time.Minute, tmp, map[string]bool{kept: true}); err != nil {
+		t.Fatalf("clean: %v", err)
+	}
+
+	if _, err := os.Stat(kept); err != nil {
+		t.Fatalf("expected kept file to remain: %v", err)
+	}
+	if _, err := os.Stat(orphan); !os.IsNotExist(err) {
+		t.Fatalf("expected orphan removed, stat err: %v", err)
+	}
+}
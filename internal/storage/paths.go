@@ -1,6 +1,7 @@
 package storage
 
 import (
+	"database/sql"
 	"fmt"
 	"path/filepath"
 	"strconv"
@@ -40,3 +41,54 @@ func ThumbnailPathAt(baseDir string, albumID, photoID int64, createdAt time.Time
 	without := strings.TrimSuffix(p, ext)
 	return fmt.Sprintf("%s_thumb%s", without, ext)
 }
+
+// OriginalPath returns where the archived original upload of a photo is kept:
+// {baseDir}/originals/{photo_id}.{ext}
+// It only depends on the photo ID, so it survives moves between albums.
+func OriginalPath(baseDir string, photoID int64, format string) string {
+	ext := strings.ToLower(strings.TrimPrefix(format, "."))
+	return filepath.Join(baseDir, "originals", fmt.Sprintf("%d.%s", photoID, ext))
+}
+
+// AnimationPath returns where the animation of an animated upload is kept:
+// {baseDir}/animations/{photo_id}.{ext}
+// Like originals, it only depends on the photo ID.
+func AnimationPath(baseDir string, photoID int64, format string) string {
+	ext := strings.ToLower(strings.TrimPrefix(format, "."))
+	return filepath.Join(baseDir, "animations", fmt.Sprintf("%d.%s", photoID, ext))
+}
+
+// PreviewPath returns where the link preview image of a photo is kept:
+// {baseDir}/previews/{photo_id}.jpg
+// Previews are derived from the stored photo and can be rebuilt at any time.
+func PreviewPath(baseDir string, photoID int64) string {
+	return filepath.Join(baseDir, "previews", fmt.Sprintf("%d.jpg", photoID))
+}
+
+// PhotoFiles lists every file that may be stored for a photo: the photo
+// itself, which comes first, its thumbnail, its archived original and kept
+// animation when it has them, and its link preview. Deleting a photo deletes
+// all of them. A photo without a creation time is looked up as created now.
+func PhotoFiles(baseDir string, albumID, photoID int64, format string, created sql.NullTime, original, animation sql.NullString) []string {
+	createdAt := time.Now().UTC()
+	if created.Valid {
+		createdAt = created.Time.UTC()
+	}
+	files := []string{
+		PhotoPathAt(baseDir, albumID, photoID, format, createdAt),
+		ThumbnailPathAt(baseDir, albumID, photoID, createdAt),
+	}
+	if original.Valid {
+		files = append(files, OriginalPath(baseDir, photoID, original.String))
+	}
+	if animation.Valid {
+		files = append(files, AnimationPath(baseDir, photoID, animation.String))
+	}
+	return append(files, PreviewPath(baseDir, photoID))
+}
+
+// LogoPath returns where the logo of the instance's branding is kept:
+// {baseDir}/branding/logo.webp
+func LogoPath(baseDir string) string {
+	return filepath.Join(baseDir, "branding", "logo.webp")
+}
@@ -2,6 +2,7 @@ package storage
 
 import (
 	"bytes"
+	"database/sql"
 	"io"
 	"os"
 	"path/filepath"
@@ -32,6 +33,34 @@ func TestPhotoPathAndThumbnail(t *testing.T) {
 	if !strings.HasSuffix(th, "_thumb.webp") {
 		t.Fatalf("expected thumbnail suffix _thumb.webp got: %s", th)
 	}
+
+	if o := OriginalPath(tmp, 7, ".JPG"); o != filepath.Join(tmp, "originals", "7.jpg") {
+		t.Fatalf("unexpected original path: %s", o)
+	}
+	if a := AnimationPath(tmp, 7, "GIF"); a != filepath.Join(tmp, "animations", "7.gif") {
+		t.Fatalf("unexpected animation path: %s", a)
+	}
+	if pv := PreviewPath(tmp, 7); pv != filepath.Join(tmp, "previews", "7.jpg") {
+		t.Fatalf("unexpected preview path: %s", pv)
+	}
+}
+
+func TestPhotoFiles(t *testing.T) {
+	created := sql.NullTime{Time: time.Date(2025, time.December, 5, 12, 0, 0, 0, time.UTC), Valid: true}
+	files := PhotoFiles("/data", 42, 7, "avif", created, sql.NullString{}, sql.NullString{})
+	want := []string{
+		PhotoPathAt("/data", 42, 7, "avif", created.Time),
+		ThumbnailPathAt("/data", 42, 7, created.Time),
+		PreviewPath("/data", 7),
+	}
+	if strings.Join(files, ",") != strings.Join(want, ",") {
+		t.Errorf("expected %v, got %v", want, files)
+	}
+
+	files = PhotoFiles("/data", 42, 7, "avif", created, sql.NullString{String: "jpg", Valid: true}, sql.NullString{String: "gif", Valid: true})
+	if len(files) != 5 || files[2] != OriginalPath("/data", 7, "jpg") || files[3] != AnimationPath("/data", 7, "gif") {
+		t.Errorf("expected the original and animation listed, got %v", files)
+	}
 }
 
 func TestEnsureDir(t *testing.T) {
@@ -89,6 +118,40 @@ func TestAtomicWritePartialFailure(t *testing.T) {
 	}
 }
 
+func TestMoveAndCopyFile(t *testing.T) {
+	tmp := t.TempDir()
+	src := filepath.Join(tmp, "photos", "2026", "01", "1", "7.webp")
+	if err := AtomicWrite(src, bytes.NewReader([]byte("pixels"))); err != nil {
+		t.Fatalf("write src: %v", err)
+	}
+
+	copied := filepath.Join(tmp, "photos", "2026", "02", "3", "8.webp")
+	if err := CopyFile(src, copied); err != nil {
+		t.Fatalf("CopyFile: %v", err)
+	}
+	if b, err := os.ReadFile(copied); err != nil || string(b) != "pixels" {
+		t.Fatalf("unexpected copy contents %q: %v", b, err)
+	}
+	if _, err := os.Stat(src); err != nil {
+		t.Fatalf("expected source kept after copy: %v", err)
+	}
+
+	moved := filepath.Join(tmp, "photos", "2026", "01", "2", "7.webp")
+	if err := MoveFile(src, moved); err != nil {
+		t.Fatalf("MoveFile: %v", err)
+	}
+	if _, err := os.Stat(src); !os.IsNotExist(err) {
+		t.Fatalf("expected source removed after move, got: %v", err)
+	}
+	if b, err := os.ReadFile(moved); err != nil || string(b) != "pixels" {
+		t.Fatalf("unexpected moved contents %q: %v", b, err)
+	}
+
+	if err := MoveFile(src, moved); err == nil {
+		t.Fatalf("expected error moving a missing file")
+	}
+}
+
 func TestCleanupExecute(t *testing.T) {
 	tmp := t.TempDir()
 	f1 := filepath.Join(tmp, "a.tmp")
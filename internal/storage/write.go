@@ -47,3 +47,25 @@ func AtomicWrite(path string, data io.Reader) error {
 
 	return nil
 }
+
+// MoveFile renames src to dst, creating dst's directory as needed.
+// Both paths live under the same base dir, so the rename is atomic.
+func MoveFile(src, dst string) error {
+	if err := EnsureDir(filepath.Dir(dst)); err != nil {
+		return fmt.Errorf("ensure dir: %w", err)
+	}
+	if err := os.Rename(src, dst); err != nil {
+		return fmt.Errorf("rename: %w", err)
+	}
+	return nil
+}
+
+// CopyFile copies src to dst atomically.
+func CopyFile(src, dst string) error {
+	f, err := os.Open(src)
+	if err != nil {
+		return fmt.Errorf("open source: %w", err)
+	}
+	defer f.Close()
+	return AtomicWrite(dst, f)
+}
@@ -0,0 +1,91 @@
+// Package timefmt reads and writes times in the instance's timezone: the
+// wall clock times of form inputs, and spans of time relative to now.
+package timefmt
+
+import "time"
+
+// InputLayout is the layout of datetime-local form inputs.
+const InputLayout = "2006-01-02T15:04"
+
+// ParseInput reads the wall clock time of a datetime-local input in loc.
+// A time skipped by a daylight saving change reads as the time the clocks
+// jumped to.
+func ParseInput(value string, loc *time.Location) (time.Time, error) {
+	t, err := time.ParseInLocation(InputLayout, value, loc)
+	if err != nil {
+		return t, err
+	}
+	wall, _ := time.Parse(InputLayout, value)
+	got, _ := time.Parse(InputLayout, t.Format(InputLayout))
+	if skipped := wall.Sub(got); skipped > 0 {
+		t = t.Add(skipped)
+	}
+	return t, nil
+}
+
+// FormatInput writes t as the value of a datetime-local input in loc.
+func FormatInput(t time.Time, loc *time.Location) string {
+	return t.In(loc).Format(InputLayout)
+}
+
+// Unit is the unit of a Span.
+type Unit string
+
+const (
+	Now    Unit = "now"
+	Minute Unit = "minute"
+	Hour   Unit = "hour"
+	Day    Unit = "day"
+	Month  Unit = "month"
+	Year   Unit = "year"
+)
+
+// Span is a time relative to now in the unit it is best told in, such as
+// "in 3 days" or "2 hours ago": N is 3 days, or -2 hours for a time past.
+type Span struct {
+	N    int
+	Unit Unit
+}
+
+// Relative tells t relative to now. Beyond half a day it counts calendar
+// days in now's location, so a day across a daylight saving change is still
+// one day, and one day ahead is tomorrow even if fewer than 24 hours away.
+func Relative(t, now time.Time) Span {
+	d := t.Sub(now)
+	sign := 1
+	if d < 0 {
+		d, sign = -d, -1
+	}
+	switch {
+	case d < time.Minute:
+		return Span{0, Now}
+	case d < time.Hour:
+		return Span{sign * int(d/time.Minute), Minute}
+	case d < 12*time.Hour:
+		return Span{sign * int(d/time.Hour), Hour}
+	}
+
+	days := calendarDays(now, t.In(now.Location()))
+	if days < 0 {
+		days = -days
+	}
+	switch {
+	case days == 0:
+		return Span{sign * int(d/time.Hour), Hour}
+	case days < 45:
+		return Span{sign * days, Day}
+	case days < 548:
+		return Span{sign * (days / 30), Month}
+	default:
+		return Span{sign * (days / 365), Year}
+	}
+}
+
+// calendarDays counts the midnights from a to b on their wall clocks.
+func calendarDays(a, b time.Time) int {
+	y, m, d := a.Date()
+	from := time.Date(y, m, d, 0, 0, 0, 0, time.UTC)
+	y, m, d = b.Date()
+	to := time.Date(y, m, d, 0, 0, 0, 0, time.UTC)
+	return int(to.Sub(from) / (24 * time.Hour))
+}
@@ -0,0 +1,101 @@
+package timefmt
+
+import (
+	"testing"
+	"time"
+)
+
+func newYork(t *testing.T) *time.Location {
+	t.Helper()
+	loc, err := time.LoadLocation("America/New_York")
+	if err != nil {
+		t.Fatalf("LoadLocation: %v", err)
+	}
+	return loc
+}
+
+func TestParseInput(t *testing.T) {
+	loc := newYork(t)
+	for _, tc := range []struct {
+		value, utc string
+	}{
+		{"2024-01-15T18:00", "2024-01-15T23:00"},
+		{"2024-07-01T18:00", "2024-07-01T22:00"},
+		// The night clocks spring forward, 02:30 doesn't exist and means 03:30
+		{"2024-03-10T02:30", "2024-03-10T07:30"},
+		{"2024-03-10T03:30", "2024-03-10T07:30"},
+	} {
+		got, err := ParseInput(tc.value, loc)
+		if err != nil {
+			t.Fatalf("%s: %v", tc.value, err)
+		}
+		if got.UTC().Format(InputLayout) != tc.utc {
+			t.Errorf("%s: expected %s UTC, got %s", tc.value, tc.utc, got.UTC().Format(InputLayout))
+		}
+	}
+	if _, err := ParseInput("2024-13-01T10:00", loc); err == nil {
+		t.Errorf("expected an error for an invalid month")
+	}
+}
+
+func TestFormatInput(t *testing.T) {
+	loc := newYork(t)
+	utc := time.Date(2024, 11, 3, 6, 30, 0, 0, time.UTC) // after clocks fell back
+	if got := FormatInput(utc, loc); got != "2024-11-03T01:30" {
+		t.Errorf("expected 2024-11-03T01:30, got %s", got)
+	}
+	if got := FormatInput(utc, time.UTC); got != "2024-11-03T06:30" {
+		t.Errorf("expected 2024-11-03T06:30, got %s", got)
+	}
+}
+
+func TestRelative(t *testing.T) {
+	loc := newYork(t)
+	at := func(month time.Month, day, hour, min int) time.Time {
+		return time.Date(2024, month, day, hour, min, 0, 0, loc)
+	}
+	for _, tc := range []struct {
+		name   string
+		t, now time.Time
+		want   Span
+	}{
+		{"seconds", at(6, 1, 12, 0).Add(30 * time.Second), at(6, 1, 12, 0), Span{0, Now}},
+		{"minutes", at(6, 1, 12, 5), at(6, 1, 12, 0), Span{5, Minute}},
+		{"one hour ago", at(6, 1, 11, 0), at(6, 1, 12, 0), Span{-1, Hour}},
+		{"hours past midnight", at(6, 2, 1, 0), at(6, 1, 20, 0), Span{5, Hour}},
+		{"tomorrow", at(6, 2, 18, 0), at(6, 1, 12, 0), Span{1, Day}},
+		{"yesterday", at(5, 31, 9, 0), at(6, 1, 12, 0), Span{-1, Day}},
+		{"days", at(6, 4, 12, 0), at(6, 1, 12, 0), Span{3, Day}},
+		{"days ago", at(5, 20, 12, 0), at(6, 1, 12, 0), Span{-12, Day}},
+		{"months", at(9, 1, 12, 0), at(6, 1, 12, 0), Span{3, Month}},
+		{"years", time.Date(2026, 6, 1, 12, 0, 0, 0, loc), at(6, 1, 12, 0), Span{2, Year}},
+
+		// Spring forward: the day of March 10 has 23 hours
+		{"day across spring forward", at(3, 10, 12, 0), at(3, 9, 12, 0), Span{1, Day}},
+		{"days across spring forward", at(3, 11, 12, 0), at(3, 8, 12, 0), Span{3, Day}},
+		{"hours across spring forward", at(3, 10, 3, 30), at(3, 10, 1, 30), Span{1, Hour}},
+
+		// Fall back: the day of November 3 has 25 hours
+		{"days across fall back", at(11, 5, 12, 0), at(11, 2, 12, 0), Span{3, Day}},
+		{"day ago across fall back", at(11, 3, 0, 0), at(11, 4, 0, 0), Span{-1, Day}},
+		{"hours across fall back", at(11, 3, 3, 0), at(11, 3, 0, 30), Span{3, Hour}},
+	} {
+		if got := Relative(tc.t, tc.now); got != tc.want {
+			t.Errorf("%s: expected %v, got %v", tc.name, tc.want, got)
+		}
+	}
+}
+
+func TestRelative_UsesNowsLocation(t *testing.T) {
+	loc := newYork(t)
+	// 03:00 UTC on June 2 is still June 1 in New York
+	expires := time.Date(2024, 6, 2, 3, 0, 0, 0, time.UTC)
+	now := time.Date(2024, 6, 1, 9, 0, 0, 0, loc)
+	if got := Relative(expires, now); got != (Span{14, Hour}) {
+		t.Errorf("expected in 14 hours, got %v", got)
+	}
+	now = time.Date(2024, 5, 31, 9, 0, 0, 0, loc)
+	if got := Relative(expires, now); got != (Span{1, Day}) {
+		t.Errorf("expected tomorrow, got %v", got)
+	}
+}
@@ -0,0 +1,83 @@
+package worker
+
+import (
+	"image"
+	"io"
+	"os"
+	"sync"
+
+	"familyshare/internal/db/sqlc"
+	"familyshare/internal/storage"
+)
+
+// memoryBudget bounds the memory reserved by jobs running at the same time.
+type memoryBudget struct {
+	mu    sync.Mutex
+	cond  *sync.Cond
+	total int64
+	used  int64
+}
+
+func newMemoryBudget(total int64) *memoryBudget {
+	b := &memoryBudget{total: total}
+	b.cond = sync.NewCond(&b.mu)
+	return b
+}
+
+// acquire blocks until n bytes are free and reserves them. A request larger
+// than the whole budget is clamped to it, so a big image still runs, alone.
+// It returns the amount reserved, to be handed back to release.
+func (b *memoryBudget) acquire(n int64) int64 {
+	if n > b.total {
+		n = b.total
+	}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	for b.used+n > b.total {
+		b.cond.Wait()
+	}
+	b.used += n
+	return n
+}
+
+// release returns a reservation made by acquire.
+func (b *memoryBudget) release(n int64) {
+	b.mu.Lock()
+	b.used -= n
+	b.mu.Unlock()
+	b.cond.Broadcast()
+}
+
+// estimateJobMemory guesses the memory needed to process an upload: the file
+// read into memory plus the decoded image and its resized copy at 4 bytes per
+// pixel. Only the image header is read; f is rewound afterwards.
+func estimateJobMemory(f *os.File, size int64) int64 {
+	cfg, _, err := image.DecodeConfig(f)
+	if _, serr := f.Seek(0, io.SeekStart); serr != nil {
+		return size
+	}
+	if err != nil {
+		// Not a readable image; the pipeline will reject it quickly
+		return size
+	}
+	return size + int64(cfg.Width)*int64(cfg.Height)*4*2
+}
+
+// estimatePhotoMemory guesses the memory needed to reprocess a stored photo
+// from its archived original or, without one, from its stored file.
+func estimatePhotoMemory(baseDir string, photo sqlc.Photo) int64 {
+	path := storage.PhotoPathAt(baseDir, photo.AlbumID, photo.ID, photo.Format, photo.CreatedAt.Time)
+	if photo.OriginalFormat.Valid {
+		path = storage.OriginalPath(baseDir, photo.ID, photo.OriginalFormat.String)
+	}
+	f, err := os.Open(path)
+	if err != nil {
+		return photo.SizeBytes + photo.Width*photo.Height*4*2
+	}
+	defer f.Close()
+	fi, err := f.Stat()
+	if err != nil {
+		return photo.SizeBytes + photo.Width*photo.Height*4*2
+	}
+	return estimateJobMemory(f, fi.Size())
+}
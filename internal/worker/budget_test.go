@@ -0,0 +1,51 @@
+package worker
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestMemoryBudget(t *testing.T) {
+	b := newMemoryBudget(100)
+
+	first := b.acquire(60)
+	// Larger than the whole budget: clamped so it can still run alone
+	if got := newMemoryBudget(100).acquire(500); got != 100 {
+		t.Fatalf("expected oversized request clamped to 100, got %d", got)
+	}
+
+	acquired := make(chan int64)
+	go func() { acquired <- b.acquire(60) }()
+
+	select {
+	case <-acquired:
+		t.Fatal("second reservation should wait while the budget is used")
+	case <-time.After(50 * time.Millisecond):
+	}
+
+	b.release(first)
+	select {
+	case n := <-acquired:
+		b.release(n)
+	case <-time.After(time.Second):
+		t.Fatal("second reservation should proceed after release")
+	}
+}
+
+func TestEstimateJobMemory(t *testing.T) {
+	f, err := os.Open(filepath.Join("testdata", "images", "sample.jpg"))
+	if err != nil {
+		t.Fatalf("open: %v", err)
+	}
+	defer f.Close()
+	fi, _ := f.Stat()
+
+	if got := estimateJobMemory(f, fi.Size()); got <= fi.Size() {
+		t.Errorf("expected estimate above the file size for a decodable image, got %d", got)
+	}
+	if pos, _ := f.Seek(0, 1); pos != 0 {
+		t.Errorf("expected file rewound, at %d", pos)
+	}
+}
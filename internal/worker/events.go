@@ -0,0 +1,91 @@
+package worker
+
+import "sync"
+
+// JobEvent describes a status change of a processing job.
+type JobEvent struct {
+	JobID    int64
+	AlbumID  int64
+	Filename string
+	Status   string // new status; empty when the job was removed
+	Error    string // failure reason of a failed or retried job
+}
+
+// subscriberBuffer is how many events a subscriber may fall behind before it
+// is dropped.
+const subscriberBuffer = 64
+
+// Broker fans job events out to subscribers of an album. Publishing never
+// blocks the worker: a subscriber that can't keep up has its channel closed
+// and is expected to subscribe again and reload the queue state.
+type Broker struct {
+	mu     sync.Mutex
+	subs   map[int64]map[chan JobEvent]struct{}
+	closed bool
+}
+
+// NewBroker creates an empty broker.
+func NewBroker() *Broker {
+	return &Broker{subs: make(map[int64]map[chan JobEvent]struct{})}
+}
+
+// Subscribe returns a channel receiving the job events of an album and a
+// function to end the subscription. The channel is closed when the
+// subscription ends, the subscriber falls behind or the broker is closed.
+func (b *Broker) Subscribe(albumID int64) (<-chan JobEvent, func()) {
+	ch := make(chan JobEvent, subscriberBuffer)
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.closed {
+		close(ch)
+		return ch, func() {}
+	}
+	if b.subs[albumID] == nil {
+		b.subs[albumID] = make(map[chan JobEvent]struct{})
+	}
+	b.subs[albumID][ch] = struct{}{}
+
+	return ch, func() {
+		b.mu.Lock()
+		defer b.mu.Unlock()
+		b.remove(albumID, ch)
+	}
+}
+
+// remove closes and forgets a subscriber. The caller holds b.mu.
+func (b *Broker) remove(albumID int64, ch chan JobEvent) {
+	if _, ok := b.subs[albumID][ch]; !ok {
+		return
+	}
+	delete(b.subs[albumID], ch)
+	if len(b.subs[albumID]) == 0 {
+		delete(b.subs, albumID)
+	}
+	close(ch)
+}
+
+// Publish sends an event to the subscribers of its album.
+func (b *Broker) Publish(e JobEvent) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	for ch := range b.subs[e.AlbumID] {
+		select {
+		case ch <- e:
+		default:
+			b.remove(e.AlbumID, ch)
+		}
+	}
+}
+
+// Close ends all subscriptions, e.g. so streaming requests return on shutdown.
+func (b *Broker) Close() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.closed = true
+	for albumID, subs := range b.subs {
+		for ch := range subs {
+			b.remove(albumID, ch)
+		}
+	}
+}
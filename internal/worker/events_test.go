@@ -0,0 +1,88 @@
+package worker
+
+import (
+	"context"
+	"testing"
+
+	"familyshare/internal/config"
+	"familyshare/internal/db/sqlc"
+	"familyshare/internal/storage"
+	"familyshare/internal/testutil"
+)
+
+func TestBroker(t *testing.T) {
+	b := NewBroker()
+
+	events, unsubscribe := b.Subscribe(1)
+	other, _ := b.Subscribe(2)
+
+	b.Publish(JobEvent{JobID: 10, AlbumID: 1, Status: "processing"})
+	if e := <-events; e.JobID != 10 || e.Status != "processing" {
+		t.Errorf("unexpected event %+v", e)
+	}
+	select {
+	case e := <-other:
+		t.Errorf("expected no event for another album, got %+v", e)
+	default:
+	}
+
+	unsubscribe()
+	if _, ok := <-events; ok {
+		t.Errorf("expected channel closed after unsubscribe")
+	}
+	unsubscribe() // safe to call twice
+
+	b.Close()
+	if _, ok := <-other; ok {
+		t.Errorf("expected channel closed by Close")
+	}
+	if late, _ := b.Subscribe(1); late != nil {
+		if _, ok := <-late; ok {
+			t.Errorf("expected subscriptions after Close to be closed")
+		}
+	}
+}
+
+func TestBroker_DropsSlowSubscriber(t *testing.T) {
+	b := NewBroker()
+	events, unsubscribe := b.Subscribe(1)
+	defer unsubscribe()
+
+	// Publishing never blocks; a subscriber that falls behind is closed
+	for i := 0; i <= subscriberBuffer; i++ {
+		b.Publish(JobEvent{JobID: int64(i), AlbumID: 1, Status: "pending"})
+	}
+
+	n := 0
+	for range events {
+		n++
+	}
+	if n != subscriberBuffer {
+		t.Errorf("expected %d buffered events before the drop, got %d", subscriberBuffer, n)
+	}
+}
+
+func TestWorker_publishesJobChanges(t *testing.T) {
+	db, queries, cleanupDB := testutil.SetupTestDB(t)
+	defer cleanupDB()
+	ctx := context.Background()
+
+	album, err := queries.CreateAlbum(ctx, sqlc.CreateAlbumParams{Title: "Test"})
+	if err != nil {
+		t.Fatalf("create album: %v", err)
+	}
+	job, err := queries.EnqueueJob(ctx, sqlc.EnqueueJobParams{AlbumID: album.ID, OriginalFilename: "foo.jpg", TempFilepath: "/tmp/foo"})
+	if err != nil {
+		t.Fatalf("enqueue: %v", err)
+	}
+
+	w := NewWorker(db, storage.New(t.TempDir()), &config.Config{})
+	events, unsubscribe := w.Events().Subscribe(job.AlbumID)
+	defer unsubscribe()
+
+	w.failJob(ctx, job, "boom")
+	e := <-events
+	if e.JobID != job.ID || e.Status != "failed" || e.Error != "boom" || e.Filename != job.OriginalFilename {
+		t.Errorf("unexpected event %+v", e)
+	}
+}
@@ -0,0 +1,65 @@
+package worker
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+
+	"familyshare/internal/db/sqlc"
+)
+
+// ReprocessFilter selects the photos to reprocess. Zero values match every
+// photo.
+type ReprocessFilter struct {
+	AlbumID int64
+	Format  string // current format of the photo, e.g. webp
+}
+
+func (f ReprocessFilter) matches(p sqlc.ListReprocessCandidatesRow) bool {
+	if f.AlbumID != 0 && p.AlbumID != f.AlbumID {
+		return false
+	}
+	return f.Format == "" || strings.EqualFold(p.Format, f.Format)
+}
+
+// EnqueueReprocess queues a reprocess job for every photo matching filter
+// that isn't queued already, and returns the new jobs. The record of earlier
+// finished reprocess jobs is cleared first, so progress covers the new run.
+func EnqueueReprocess(ctx context.Context, db *sql.DB, filter ReprocessFilter) ([]sqlc.ProcessingQueue, error) {
+	tx, err := db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, fmt.Errorf("begin tx: %w", err)
+	}
+	defer tx.Rollback()
+
+	q := sqlc.New(tx)
+	if err := q.DeleteFinishedReprocessJobs(ctx); err != nil {
+		return nil, fmt.Errorf("clear finished jobs: %w", err)
+	}
+	photos, err := q.ListReprocessCandidates(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("list photos: %w", err)
+	}
+
+	var jobs []sqlc.ProcessingQueue
+	for _, p := range photos {
+		if !filter.matches(p) {
+			continue
+		}
+		job, err := q.EnqueueReprocessJob(ctx, sqlc.EnqueueReprocessJobParams{
+			AlbumID:          p.AlbumID,
+			OriginalFilename: p.Filename,
+			PhotoID:          sql.NullInt64{Int64: p.ID, Valid: true},
+		})
+		if err != nil {
+			return nil, fmt.Errorf("enqueue photo %d: %w", p.ID, err)
+		}
+		jobs = append(jobs, job)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("commit tx: %w", err)
+	}
+	return jobs, nil
+}
@@ -0,0 +1,110 @@
+package worker
+
+import (
+	"bytes"
+	"context"
+	"image"
+	"image/png"
+	"testing"
+
+	"familyshare/internal/config"
+	"familyshare/internal/storage"
+	"familyshare/internal/testutil"
+)
+
+func TestEnqueueReprocess(t *testing.T) {
+	db, queries, cleanupDB := testutil.SetupTestDB(t)
+	defer cleanupDB()
+	ctx := context.Background()
+
+	a := testutil.CreateTestAlbum(t, queries, "A", "")
+	b := testutil.CreateTestAlbum(t, queries, "B", "")
+	pa := testutil.CreateTestPhoto(t, queries, a.ID, "a.webp")
+	testutil.CreateTestPhoto(t, queries, b.ID, "b.webp")
+	trashed := testutil.CreateTestPhoto(t, queries, b.ID, "c.webp")
+	if err := queries.TrashPhoto(ctx, trashed.ID); err != nil {
+		t.Fatalf("trash photo: %v", err)
+	}
+
+	jobs, err := EnqueueReprocess(ctx, db, ReprocessFilter{Format: "avif"})
+	if err != nil || len(jobs) != 0 {
+		t.Fatalf("expected no avif photos queued, got %d: %v", len(jobs), err)
+	}
+
+	jobs, err = EnqueueReprocess(ctx, db, ReprocessFilter{AlbumID: a.ID})
+	if err != nil {
+		t.Fatalf("enqueue: %v", err)
+	}
+	if len(jobs) != 1 || jobs[0].PhotoID.Int64 != pa.ID || jobs[0].Kind != "reprocess" || jobs[0].AlbumID != a.ID {
+		t.Fatalf("unexpected jobs %+v", jobs)
+	}
+
+	// Queued photos aren't queued twice; trashed photos are skipped
+	jobs, err = EnqueueReprocess(ctx, db, ReprocessFilter{Format: "WEBP"})
+	if err != nil {
+		t.Fatalf("enqueue: %v", err)
+	}
+	if len(jobs) != 1 {
+		t.Errorf("expected only the photo of album B queued, got %d", len(jobs))
+	}
+}
+
+func TestWorker_reprocessJob(t *testing.T) {
+	db, queries, cleanupDB := testutil.SetupTestDB(t)
+	defer cleanupDB()
+	ctx := context.Background()
+	baseDir := t.TempDir()
+
+	album := testutil.CreateTestAlbum(t, queries, "A", "")
+	photo := testutil.CreateTestPhoto(t, queries, album.ID, "a.webp")
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, image.NewRGBA(image.Rect(0, 0, 8, 4))); err != nil {
+		t.Fatalf("png encode: %v", err)
+	}
+	if err := storage.AtomicWrite(storage.PhotoPathAt(baseDir, album.ID, photo.ID, "webp", photo.CreatedAt.Time), &buf); err != nil {
+		t.Fatalf("write photo: %v", err)
+	}
+	gone := testutil.CreateTestPhoto(t, queries, album.ID, "gone.webp")
+
+	jobs, err := EnqueueReprocess(ctx, db, ReprocessFilter{})
+	if err != nil || len(jobs) != 2 {
+		t.Fatalf("expected 2 jobs: %v", err)
+	}
+	if err := queries.DeletePhoto(ctx, gone.ID); err != nil {
+		t.Fatalf("delete photo: %v", err)
+	}
+
+	w := NewWorker(db, storage.New(baseDir), &config.Config{ImageFormat: "avif"})
+	for w.processNextJob(ctx) {
+	}
+
+	got, err := queries.GetPhoto(ctx, photo.ID)
+	if err != nil {
+		t.Fatalf("get photo: %v", err)
+	}
+	if got.Format != "avif" || got.Width != 8 || got.Height != 4 || got.SizeBytes == 150000 {
+		t.Errorf("expected photo re-encoded as avif, got %+v", got)
+	}
+	if job, _ := queries.GetJob(ctx, jobs[0].ID); job.Status != "completed" {
+		t.Errorf("expected job completed, got %s", job.Status)
+	}
+	if job, _ := queries.GetJob(ctx, jobs[1].ID); job.Status != "failed" || job.ErrorMessage.String != "photo no longer exists" {
+		t.Errorf("expected job of deleted photo failed, got %s %v", job.Status, job.ErrorMessage)
+	}
+
+	status, err := queries.GetReprocessStatus(ctx, jobs[0].ID)
+	if err != nil {
+		t.Fatalf("status: %v", err)
+	}
+	if status.CompletedCount != 1 || status.FailedCount != 1 || status.PendingCount != 0 {
+		t.Errorf("unexpected status %+v", status)
+	}
+
+	// A new run clears the record of the previous one
+	if _, err := EnqueueReprocess(ctx, db, ReprocessFilter{Format: "webp"}); err != nil {
+		t.Fatalf("enqueue: %v", err)
+	}
+	if status, _ := queries.GetReprocessStatus(ctx, 0); status.CompletedCount+status.FailedCount != 0 {
+		t.Errorf("expected finished jobs cleared, got %+v", status)
+	}
+}
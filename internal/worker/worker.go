@@ -6,6 +6,7 @@ import (
 	"fmt"
 	"log"
 	"os"
+	"strings"
 	"sync"
 	"time"
 
@@ -15,57 +16,145 @@ import (
 	"familyshare/internal/storage"
 )
 
-// Worker handles background processing of uploaded photos
+// Worker handles background processing of uploaded photos with a pool of
+// goroutines. Each job holds a lease in the queue that is renewed by heartbeats,
+// so jobs of a crashed process can be reclaimed.
 type Worker struct {
 	db      *sql.DB
 	queries *sqlc.Queries
 	store   *storage.Storage
 	cfg     *config.Config
-	trigger chan struct{} // Channel to wake up the worker immediately
-	wg      sync.WaitGroup // WaitGroup to wait for active jobs to finish
+	trigger chan struct{}  // Channel to wake up the worker immediately
+	wg      sync.WaitGroup // WaitGroup to wait for the pool and active jobs to finish
+	budget  *memoryBudget  // memory reserved by running jobs
+	events  *Broker        // job status changes for live progress
+
+	quit     chan struct{} // closed by Stop to end the polling loops
+	stopOnce sync.Once
+
+	// Jobs run on their own context so a shutdown lets them finish; it is only
+	// cancelled when they overrun the shutdown grace period.
+	jobCtx     context.Context
+	cancelJobs context.CancelFunc
 }
 
+const (
+	// jobLease is how long a job stays leased to a worker without a heartbeat.
+	jobLease = 2 * time.Minute
+	// heartbeatInterval is how often a running job renews its lease.
+	heartbeatInterval = 30 * time.Second
+	// shutdownGrace is how long Stop waits for running jobs before requeueing them.
+	shutdownGrace = 20 * time.Second
+)
+
 // NewWorker creates a new background worker
 func NewWorker(db *sql.DB, store *storage.Storage, cfg *config.Config) *Worker {
+	memoryMB := 512
+	if cfg != nil && cfg.WorkerMemoryMB > 0 {
+		memoryMB = cfg.WorkerMemoryMB
+	}
+	jobCtx, cancelJobs := context.WithCancel(context.Background())
 	return &Worker{
-		db:      db,
-		queries: sqlc.New(db),
-		store:   store,
-		cfg:     cfg,
-		trigger: make(chan struct{}, 1),
+		db:         db,
+		queries:    sqlc.New(db),
+		store:      store,
+		cfg:        cfg,
+		trigger:    make(chan struct{}, 1),
+		budget:     newMemoryBudget(int64(memoryMB) << 20),
+		events:     NewBroker(),
+		quit:       make(chan struct{}),
+		jobCtx:     jobCtx,
+		cancelJobs: cancelJobs,
+	}
+}
+
+// Events returns the broker the worker publishes job status changes to.
+func (w *Worker) Events() *Broker {
+	return w.events
+}
+
+// publish announces the new status of a job.
+func (w *Worker) publish(job sqlc.ProcessingQueue, status, msg string) {
+	w.events.Publish(JobEvent{
+		JobID:    job.ID,
+		AlbumID:  job.AlbumID,
+		Filename: job.OriginalFilename,
+		Status:   status,
+		Error:    msg,
+	})
+}
+
+// workerCount is the number of jobs processed concurrently.
+func (w *Worker) workerCount() int {
+	if w.cfg == nil || w.cfg.WorkerCount < 1 {
+		return 1
 	}
+	return w.cfg.WorkerCount
 }
 
-// Start runs the background worker loop in a goroutine
+// Start reclaims jobs whose lease expired, left processing by a previous run,
+// and starts the worker pool. Jobs with a live lease are left alone, as
+// another process, such as a reprocess command, may still be running them.
 func (w *Worker) Start(ctx context.Context) {
-	log.Println("Worker: started background processing queue")
+	if ids, err := w.queries.ReclaimExpiredJobs(ctx); err != nil {
+		log.Printf("Worker: failed to reclaim interrupted jobs: %v", err)
+	} else if len(ids) > 0 {
+		log.Printf("Worker: requeued %d jobs interrupted by a previous shutdown", len(ids))
+	}
+	w.StartPool(ctx)
+}
 
-	w.wg.Add(1)
-	go func() {
-		defer w.wg.Done()
-		
-		// Poll every 2 seconds as a fallback
-		ticker := time.NewTicker(2 * time.Second)
-		defer ticker.Stop()
-
-		for {
-			select {
-			case <-ctx.Done():
-				log.Println("Worker: context cancelled, stopping loop")
-				return
-			case <-ticker.C:
-				w.processBatch(ctx)
-			case <-w.trigger:
-				w.processBatch(ctx)
+// StartPool starts the worker pool without reclaiming interrupted jobs. It is
+// used by commands that may share the queue with a running server.
+func (w *Worker) StartPool(ctx context.Context) {
+	n := w.workerCount()
+	log.Printf("Worker: started background processing queue with %d workers", n)
+
+	for i := 0; i < n; i++ {
+		w.wg.Add(1)
+		go func() {
+			defer w.wg.Done()
+
+			// Poll every 2 seconds as a fallback
+			ticker := time.NewTicker(2 * time.Second)
+			defer ticker.Stop()
+
+			for {
+				select {
+				case <-ctx.Done():
+					return
+				case <-w.quit:
+					return
+				case <-ticker.C:
+					w.processBatch(ctx)
+				case <-w.trigger:
+					w.processBatch(ctx)
+				}
 			}
-		}
-	}()
+		}()
+	}
 }
 
-// Stop waits for the worker to finish current tasks
+// Stop ends the worker pool and waits for running jobs to finish. Jobs still
+// running after the grace period are cancelled and put back in the queue.
 func (w *Worker) Stop() {
 	log.Println("Worker: waiting for active jobs to finish...")
-	w.wg.Wait()
+	w.stopOnce.Do(func() { close(w.quit) })
+
+	done := make(chan struct{})
+	go func() {
+		w.wg.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+	case <-time.After(shutdownGrace):
+		log.Printf("Worker: jobs still running after %v, requeueing them", shutdownGrace)
+		w.cancelJobs()
+		<-done
+	}
+	w.cancelJobs()
 	log.Println("Worker: stopped")
 }
 
@@ -81,10 +170,15 @@ func (w *Worker) TriggerSignal() {
 // processBatch processes jobs until the queue is empty
 func (w *Worker) processBatch(ctx context.Context) {
 	for {
-		// Stop if context cancelled
+		// Stop if context cancelled or the pool is stopping
 		if ctx.Err() != nil {
 			return
 		}
+		select {
+		case <-w.quit:
+			return
+		default:
+		}
 
 		// Try to pick a job
 		didWork := w.processNextJob(ctx)
@@ -94,9 +188,30 @@ func (w *Worker) processBatch(ctx context.Context) {
 	}
 }
 
+// datetimeOffset formats a duration as an SQLite datetime modifier.
+func datetimeOffset(d time.Duration) string {
+	return fmt.Sprintf("+%d seconds", int(d/time.Second))
+}
+
+// heartbeat renews the lease of a running job until stop is closed.
+func (w *Worker) heartbeat(ctx context.Context, id int64, stop <-chan struct{}) {
+	ticker := time.NewTicker(heartbeatInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-stop:
+			return
+		case <-ticker.C:
+			if err := w.queries.HeartbeatJob(ctx, sqlc.HeartbeatJobParams{Lease: datetimeOffset(jobLease), ID: id}); err != nil {
+				log.Printf("Worker: failed to renew lease of job %d: %v", id, err)
+			}
+		}
+	}
+}
+
 func (w *Worker) processNextJob(ctx context.Context) bool {
-	// 1. Get next job (atomically marks as processing)
-	job, err := w.queries.GetNextPendingJob(ctx)
+	// 1. Get next job (atomically marks as processing and takes the lease)
+	job, err := w.queries.GetNextPendingJob(ctx, datetimeOffset(jobLease))
 	if err != nil {
 		if err == sql.ErrNoRows {
 			return false
@@ -105,69 +220,208 @@ func (w *Worker) processNextJob(ctx context.Context) bool {
 		return false
 	}
 
+	// Wake an idle worker for the rest of the queue
+	w.TriggerSignal()
+	w.publish(job, "processing", "")
+
+	// Bookkeeping must still reach the database while shutting down
+	ctx = context.WithoutCancel(ctx)
+
+	stopHeartbeat := make(chan struct{})
+	w.wg.Add(1)
+	go func() {
+		defer w.wg.Done()
+		w.heartbeat(ctx, job.ID, stopHeartbeat)
+	}()
+	defer close(stopHeartbeat)
+
+	if job.Kind == "reprocess" {
+		w.reprocessJob(ctx, job)
+		return true
+	}
+
 	log.Printf("Worker: processing job %d for file %s", job.ID, job.OriginalFilename)
 
 	// 2. Open Temp File
+	// The temp file is only removed once the job succeeds; failed jobs keep it
+	// so they can be retried until the janitor expires them.
 	f, err := os.Open(job.TempFilepath)
 	if err != nil {
-		w.failJob(ctx, job.ID, fmt.Sprintf("failed to open temp file: %v", err))
+		w.failJob(ctx, job, fmt.Sprintf("failed to open temp file: %v", err))
 		return true // we did work (processed a failure), continue
 	}
-	
-	// Ensure cleanup of filesystem
-	defer func() {
-		f.Close()
-		os.Remove(job.TempFilepath) // We are done with it, success or fail
-	}()
 
 	fi, err := f.Stat()
 	if err != nil {
-		w.failJob(ctx, job.ID, "failed to stat file")
+		f.Close()
+		w.failJob(ctx, job, "failed to stat file")
 		return true
 	}
 	size := fi.Size()
 
-	// 3. Process
-	format := "webp"
-	if w.cfg != nil && w.cfg.ImageFormat != "" {
-		format = w.cfg.ImageFormat
-	}
+	// Wait until the pool has memory for this image
+	reserved := w.budget.acquire(estimateJobMemory(f, size))
+	defer w.budget.release(reserved)
+
+	// 3. Process with the album's profile
+	profile := w.albumProfile(ctx, job.AlbumID)
 
-	// Use a new context for processing to ensure it doesn't get cancelled 
-	// mid-way if the batch context is tight (though here we pass app ctx)
-	// We inject a flag so pipeline knows context? Not strictly needed unless pipeline checks it.
-	
-	_, pErr := pipeline.ProcessAndSaveWithFormat(ctx, w.db, job.AlbumID, f, size, w.store.BaseDir, format)
+	// Process on the job context: a shutdown lets the job finish unless it
+	// overruns the grace period
+	photo, pErr := pipeline.ProcessAndSaveWithProfile(w.jobCtx, w.db, job.AlbumID, f, size, w.store.BaseDir, profile)
+	f.Close()
 
 	// 4. Update Status
 	if pErr != nil {
-		log.Printf("Worker: job %d failed: %v", job.ID, pErr)
-		w.failJob(ctx, job.ID, pErr.Error())
+		w.handleJobError(ctx, job, pErr)
 	} else {
-		w.completeJob(ctx, job.ID)
+		w.linkJobPhoto(ctx, job.ID, photo.ID)
+		w.completeJob(ctx, job)
+		os.Remove(job.TempFilepath) // We are done with it
 	}
 
 	return true // did work
 }
 
-func (w *Worker) failJob(ctx context.Context, id int64, msg string) {
+// reprocessJob re-encodes the photo of a reprocess job with the current
+// settings of its album.
+func (w *Worker) reprocessJob(ctx context.Context, job sqlc.ProcessingQueue) {
+	log.Printf("Worker: reprocessing photo %d (job %d)", job.PhotoID.Int64, job.ID)
+
+	photo, err := w.queries.GetPhoto(ctx, job.PhotoID.Int64)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			w.failJob(ctx, job, "photo no longer exists")
+		} else {
+			w.handleJobError(ctx, job, err)
+		}
+		return
+	}
+
+	reserved := w.budget.acquire(estimatePhotoMemory(w.store.BaseDir, photo))
+	defer w.budget.release(reserved)
+
+	profile := w.albumProfile(ctx, photo.AlbumID)
+	if _, err := pipeline.Reprocess(w.jobCtx, w.db, w.store.BaseDir, photo, profile); err != nil {
+		w.handleJobError(ctx, job, err)
+		return
+	}
+	w.completeJob(ctx, job)
+}
+
+// handleJobError records a failed attempt at a job: interrupted jobs go back
+// to the queue, transient errors are retried later and anything else fails
+// the job.
+func (w *Worker) handleJobError(ctx context.Context, job sqlc.ProcessingQueue, jobErr error) {
+	if w.jobCtx.Err() != nil {
+		// Cancelled by shutdown: leave the job for the next run
+		log.Printf("Worker: job %d interrupted by shutdown, requeueing", job.ID)
+		if err := w.queries.RequeueJob(ctx, job.ID); err != nil {
+			log.Printf("Worker: failed to requeue job %d: %v", job.ID, err)
+		} else {
+			w.publish(job, "pending", "")
+		}
+		return
+	}
+
+	log.Printf("Worker: job %d failed: %v", job.ID, jobErr)
+	if isTransient(jobErr) && job.Attempts < int64(w.maxRetries()) {
+		w.retryJobLater(ctx, job, jobErr.Error())
+	} else {
+		w.failJob(ctx, job, jobErr.Error())
+	}
+}
+
+// albumProfile returns the processing profile selected for an album, or the
+// instance defaults when it has none.
+func (w *Worker) albumProfile(ctx context.Context, albumID int64) pipeline.Profile {
+	format := "webp"
+	if w.cfg != nil && w.cfg.ImageFormat != "" {
+		format = w.cfg.ImageFormat
+	}
+	profile, err := pipeline.AlbumProfile(ctx, w.queries, albumID, format)
+	if err != nil {
+		log.Printf("Worker: failed to load processing profile of album %d, using defaults: %v", albumID, err)
+	}
+	if w.cfg != nil {
+		profile.Animation = pipeline.AnimationLimits{
+			MaxFrames: w.cfg.AnimationMaxFrames,
+			MaxBytes:  int64(w.cfg.AnimationMaxMB) << 20,
+		}
+	}
+	return profile
+}
+
+// retryBaseDelay is the wait before the first automatic retry; each further
+// retry waits twice as long.
+const retryBaseDelay = 5 * time.Second
+
+// isTransient reports whether a job error is worth retrying automatically.
+func isTransient(err error) bool {
+	msg := err.Error()
+	return strings.Contains(msg, "database is locked") ||
+		strings.Contains(msg, "database table is locked") ||
+		strings.Contains(msg, "SQLITE_BUSY")
+}
+
+func (w *Worker) maxRetries() int {
+	if w.cfg == nil {
+		return 3
+	}
+	return w.cfg.JobMaxRetries
+}
+
+// retryJobLater puts a job back in the queue after a backoff delay.
+func (w *Worker) retryJobLater(ctx context.Context, job sqlc.ProcessingQueue, msg string) {
+	delay := retryBaseDelay << job.Attempts
+	log.Printf("Worker: retrying job %d in %v (attempt %d)", job.ID, delay, job.Attempts+1)
+	err := w.queries.RetryJobLater(ctx, sqlc.RetryJobLaterParams{
+		ErrorMessage: sql.NullString{String: msg, Valid: true},
+		Delay:        datetimeOffset(delay),
+		ID:           job.ID,
+	})
+	if err != nil {
+		log.Printf("Worker: failed to reschedule job %d: %v", job.ID, err)
+		w.failJob(ctx, job, msg)
+		return
+	}
+	w.publish(job, "pending", msg)
+}
+
+func (w *Worker) failJob(ctx context.Context, job sqlc.ProcessingQueue, msg string) {
 	err := w.queries.UpdateJobStatus(ctx, sqlc.UpdateJobStatusParams{
 		Status:       "failed",
 		ErrorMessage: sql.NullString{String: msg, Valid: true},
-		ID:           id,
+		ID:           job.ID,
+	})
+	if err != nil {
+		log.Printf("Worker: failed to update status to failed for job %d: %v", job.ID, err)
+		return
+	}
+	w.publish(job, "failed", msg)
+}
+
+// linkJobPhoto records which photo a job produced so searches on the
+// original filename can find it.
+func (w *Worker) linkJobPhoto(ctx context.Context, id, photoID int64) {
+	err := w.queries.SetJobPhoto(ctx, sqlc.SetJobPhotoParams{
+		PhotoID: sql.NullInt64{Int64: photoID, Valid: true},
+		ID:      id,
 	})
 	if err != nil {
-		log.Printf("Worker: failed to update status to failed for job %d: %v", id, err)
+		log.Printf("Worker: failed to link photo %d to job %d: %v", photoID, id, err)
 	}
 }
 
-func (w *Worker) completeJob(ctx context.Context, id int64) {
+func (w *Worker) completeJob(ctx context.Context, job sqlc.ProcessingQueue) {
 	err := w.queries.UpdateJobStatus(ctx, sqlc.UpdateJobStatusParams{
 		Status:       "completed",
 		ErrorMessage: sql.NullString{},
-		ID:           id,
+		ID:           job.ID,
 	})
 	if err != nil {
-		log.Printf("Worker: failed to update status to completed for job %d: %v", id, err)
+		log.Printf("Worker: failed to update status to completed for job %d: %v", job.ID, err)
+		return
 	}
+	w.publish(job, "completed", "")
 }
@@ -3,6 +3,7 @@ package worker
 import (
 	"context"
 	"database/sql"
+	"errors"
 	"os"
 	"path/filepath"
 	"testing"
@@ -10,6 +11,7 @@ import (
 
 	"familyshare/internal/config"
 	"familyshare/internal/db/sqlc"
+	"familyshare/internal/pipeline"
 	"familyshare/internal/storage"
 	"familyshare/internal/testutil"
 
@@ -103,9 +105,9 @@ func TestWorker_Integration(t *testing.T) {
 		t.Error("expected error message, got empty")
 	}
 
-	// Verify temp file is gone (worker should cleanup)
-	if _, err := os.Stat(tmpFile); !os.IsNotExist(err) {
-		t.Error("temp file should be deleted after processing")
+	// Failed jobs keep their input so they can be retried
+	if _, err := os.Stat(tmpFile); err != nil {
+		t.Errorf("temp file should be kept after a failure: %v", err)
 	}
 }
 
@@ -189,7 +191,7 @@ func TestWorker_completeJob(t *testing.T) {
 	w := NewWorker(db, store, cfg)
 
 	// Action
-	w.completeJob(context.Background(), job.ID)
+	w.completeJob(context.Background(), job)
 
 	// Verify
 	var status string
@@ -206,3 +208,217 @@ func TestWorker_completeJob(t *testing.T) {
 		t.Errorf("expected no error message, got '%s'", errMsg.String)
 	}
 }
+
+func TestWorker_linkJobPhoto(t *testing.T) {
+	db, queries, cleanupDB := testutil.SetupTestDB(t)
+	defer cleanupDB()
+
+	album := testutil.CreateTestAlbum(t, queries, "Test", "")
+	photo := testutil.CreateTestPhoto(t, queries, album.ID, "out.webp")
+	job, err := queries.EnqueueJob(context.Background(), sqlc.EnqueueJobParams{
+		AlbumID:          album.ID,
+		OriginalFilename: "IMG_0001.jpg",
+		TempFilepath:     "/tmp/IMG_0001.jpg",
+	})
+	if err != nil {
+		t.Fatalf("enqueue: %v", err)
+	}
+
+	w := NewWorker(db, storage.New(t.TempDir()), &config.Config{})
+	w.linkJobPhoto(context.Background(), job.ID, photo.ID)
+
+	var photoID sql.NullInt64
+	if err := db.QueryRow("SELECT photo_id FROM processing_queue WHERE id = ?", job.ID).Scan(&photoID); err != nil {
+		t.Fatalf("query job: %v", err)
+	}
+	if !photoID.Valid || photoID.Int64 != photo.ID {
+		t.Fatalf("expected photo_id %d, got %+v", photo.ID, photoID)
+	}
+
+	// The search index entry for the upload now points at the photo
+	rows, err := queries.Search(context.Background(), sqlc.SearchParams{Query: `"IMG_0001"*`, Limit: 10})
+	if err != nil {
+		t.Fatalf("search: %v", err)
+	}
+	if len(rows) != 1 || rows[0].PhotoID != photo.ID {
+		t.Fatalf("expected upload hit linked to photo %d, got %+v", photo.ID, rows)
+	}
+}
+
+func TestIsTransient(t *testing.T) {
+	cases := map[error]bool{
+		errors.New("create photo record: database is locked (5) (SQLITE_BUSY)"): true,
+		errors.New("database table is locked"):                                  true,
+		errors.New("validate decode: uploaded file is not an image"):            false,
+	}
+	for err, want := range cases {
+		if got := isTransient(err); got != want {
+			t.Errorf("isTransient(%q) = %v, want %v", err, got, want)
+		}
+	}
+}
+
+func TestWorker_retryJobLater(t *testing.T) {
+	db, queries, cleanupDB := testutil.SetupTestDB(t)
+	defer cleanupDB()
+	ctx := context.Background()
+
+	album := testutil.CreateTestAlbum(t, queries, "Test", "")
+	if _, err := queries.EnqueueJob(ctx, sqlc.EnqueueJobParams{
+		AlbumID:          album.ID,
+		OriginalFilename: "foo.jpg",
+		TempFilepath:     "/tmp/foo",
+	}); err != nil {
+		t.Fatalf("enqueue: %v", err)
+	}
+
+	w := NewWorker(db, storage.New(t.TempDir()), &config.Config{JobMaxRetries: 3})
+
+	job, err := queries.GetNextPendingJob(ctx, "+120 seconds")
+	if err != nil {
+		t.Fatalf("pick job: %v", err)
+	}
+	w.retryJobLater(ctx, job, "database is locked")
+
+	retried, err := queries.GetJob(ctx, job.ID)
+	if err != nil {
+		t.Fatalf("get job: %v", err)
+	}
+	if retried.Status != "pending" || retried.Attempts != 1 {
+		t.Errorf("expected pending job with 1 attempt, got %s with %d", retried.Status, retried.Attempts)
+	}
+	if !retried.NextAttemptAt.Valid || !retried.NextAttemptAt.Time.After(time.Now().UTC()) {
+		t.Errorf("expected next attempt in the future, got %v", retried.NextAttemptAt)
+	}
+
+	// Not picked up again before the backoff has passed
+	if _, err := queries.GetNextPendingJob(ctx, "+120 seconds"); err != sql.ErrNoRows {
+		t.Errorf("expected no job ready during backoff, got %v", err)
+	}
+}
+
+func TestWorker_StartReclaimsInterruptedJobs(t *testing.T) {
+	db, queries, cleanupDB := testutil.SetupTestDB(t)
+	defer cleanupDB()
+	ctx := context.Background()
+
+	album := testutil.CreateTestAlbum(t, queries, "Test", "")
+	enqueue := func(name string) sqlc.ProcessingQueue {
+		job, err := queries.EnqueueJob(ctx, sqlc.EnqueueJobParams{
+			AlbumID:          album.ID,
+			OriginalFilename: name,
+			TempFilepath:     filepath.Join(t.TempDir(), "missing.tmp"),
+		})
+		if err != nil {
+			t.Fatalf("enqueue: %v", err)
+		}
+		return job
+	}
+	// Left processing by a process that crashed, its lease expired
+	stale := enqueue("stale.jpg")
+	if _, err := queries.GetNextPendingJob(ctx, "-10 seconds"); err != nil {
+		t.Fatalf("pick job: %v", err)
+	}
+	// Still being processed by another process
+	live := enqueue("live.jpg")
+	if _, err := queries.GetNextPendingJob(ctx, "+120 seconds"); err != nil {
+		t.Fatalf("pick job: %v", err)
+	}
+
+	w := NewWorker(db, storage.New(t.TempDir()), &config.Config{WorkerCount: 2})
+	runCtx, cancel := context.WithCancel(ctx)
+	w.Start(runCtx)
+
+	// The reclaimed job is processed again (and fails: its input is gone)
+	deadline := time.Now().Add(5 * time.Second)
+	var status string
+	for time.Now().Before(deadline) {
+		got, err := queries.GetJob(ctx, stale.ID)
+		if err != nil {
+			t.Fatalf("get job: %v", err)
+		}
+		if status = got.Status; status == "failed" {
+			break
+		}
+		time.Sleep(100 * time.Millisecond)
+	}
+	cancel()
+	w.Stop()
+
+	if status != "failed" {
+		t.Errorf("expected interrupted job to be picked up again, got %s", status)
+	}
+	if got, _ := queries.GetJob(ctx, live.ID); got.Status != "processing" {
+		t.Errorf("expected the job with a live lease left alone, got %s", got.Status)
+	}
+}
+
+func TestWorker_heartbeatRenewsLease(t *testing.T) {
+	_, queries, cleanupDB := testutil.SetupTestDB(t)
+	defer cleanupDB()
+	ctx := context.Background()
+
+	album := testutil.CreateTestAlbum(t, queries, "Test", "")
+	if _, err := queries.EnqueueJob(ctx, sqlc.EnqueueJobParams{AlbumID: album.ID, OriginalFilename: "foo.jpg", TempFilepath: "/tmp/foo"}); err != nil {
+		t.Fatalf("enqueue: %v", err)
+	}
+	job, err := queries.GetNextPendingJob(ctx, "+1 seconds")
+	if err != nil {
+		t.Fatalf("pick job: %v", err)
+	}
+
+	if err := queries.HeartbeatJob(ctx, sqlc.HeartbeatJobParams{Lease: datetimeOffset(jobLease), ID: job.ID}); err != nil {
+		t.Fatalf("heartbeat: %v", err)
+	}
+	renewed, _ := queries.GetJob(ctx, job.ID)
+	if !renewed.LeaseExpiresAt.Valid || !renewed.LeaseExpiresAt.Time.After(job.LeaseExpiresAt.Time.Add(time.Minute)) {
+		t.Errorf("expected lease extended, was %v now %v", job.LeaseExpiresAt, renewed.LeaseExpiresAt)
+	}
+	if !renewed.HeartbeatAt.Valid {
+		t.Errorf("expected heartbeat recorded")
+	}
+
+	// A renewed lease is not reclaimed
+	if ids, err := queries.ReclaimExpiredJobs(ctx); err != nil || len(ids) != 0 {
+		t.Errorf("expected renewed job kept, reclaimed %v: %v", ids, err)
+	}
+}
+
+func TestWorker_albumProfile(t *testing.T) {
+	db, queries, cleanupDB := testutil.SetupTestDB(t)
+	defer cleanupDB()
+	ctx := context.Background()
+
+	album, err := queries.CreateAlbum(ctx, sqlc.CreateAlbumParams{Title: "Test"})
+	if err != nil {
+		t.Fatalf("create album: %v", err)
+	}
+	w := NewWorker(db, storage.New(t.TempDir()), &config.Config{ImageFormat: "avif", AnimationMaxFrames: 10, AnimationMaxMB: 2})
+
+	// Without a profile the configured format is used
+	if p := w.albumProfile(ctx, album.ID); p.Format != "avif" || p.KeepOriginal {
+		t.Errorf("expected avif defaults, got %+v", p)
+	}
+	// with the configured animation limits
+	limits := pipeline.AnimationLimits{MaxFrames: 10, MaxBytes: 2 << 20}
+	if p := w.albumProfile(ctx, album.ID); p.Animation != limits {
+		t.Errorf("expected animation limits %+v, got %+v", limits, p.Animation)
+	}
+
+	profiles, err := queries.ListProcessingProfiles(ctx)
+	if err != nil || len(profiles) == 0 {
+		t.Fatalf("expected seeded profiles: %v", err)
+	}
+	chosen := profiles[0]
+	if err := queries.SetAlbumProcessingProfile(ctx, sqlc.SetAlbumProcessingProfileParams{
+		ProcessingProfileID: sql.NullInt64{Int64: chosen.ID, Valid: true},
+		ID:                  album.ID,
+	}); err != nil {
+		t.Fatalf("set profile: %v", err)
+	}
+	want := pipeline.ProfileFrom(chosen)
+	want.Animation = limits
+	if p := w.albumProfile(ctx, album.ID); p != want {
+		t.Errorf("expected album profile %+v, got %+v", want, p)
+	}
+}
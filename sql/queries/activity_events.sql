@@ -28,3 +28,7 @@ WHERE event_type = 'share_view' AND created_at >= ?;
 
 -- name: DeleteOldActivityEvents :exec
 DELETE FROM activity_events WHERE created_at < ?;
+
+-- name: CountReactionsSince :one
+SELECT COUNT(*) FROM activity_events
+WHERE event_type = 'reaction_add' AND created_at >= ?;
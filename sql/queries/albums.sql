@@ -4,10 +4,14 @@ VALUES (?, ?)
 RETURNING *;
 
 -- name: GetAlbum :one
+SELECT * FROM albums WHERE id = ? AND deleted_at IS NULL;
+
+-- name: GetAlbumIncludingDeleted :one
 SELECT * FROM albums WHERE id = ?;
 
 -- name: ListAlbums :many
 SELECT * FROM albums
+WHERE deleted_at IS NULL
 ORDER BY created_at DESC
 LIMIT ? OFFSET ?;
 
@@ -19,9 +23,14 @@ SELECT
     a.cover_photo_id,
     a.created_at,
     a.updated_at,
+    a.sort_mode,
+    a.deleted_at,
+    a.processing_profile_id,
+    a.cover_header,
     COUNT(p.id) as photo_count
 FROM albums a
-LEFT JOIN photos p ON p.album_id = a.id
+LEFT JOIN photos p ON p.album_id = a.id AND p.deleted_at IS NULL
+WHERE a.deleted_at IS NULL
 GROUP BY a.id
 ORDER BY a.created_at DESC
 LIMIT ? OFFSET ?;
@@ -38,7 +47,7 @@ DELETE FROM albums WHERE id = ?;
 SELECT * FROM photos WHERE album_id = ?;
 
 -- name: CountAlbums :one
-SELECT COUNT(*) FROM albums;
+SELECT COUNT(*) FROM albums WHERE deleted_at IS NULL;
 
 -- name: SetAlbumCover :exec
 UPDATE albums
@@ -50,11 +59,52 @@ SELECT
     a.*,
     COUNT(p.id) as photo_count
 FROM albums a
-LEFT JOIN photos p ON p.album_id = a.id
-WHERE a.id = ?
+LEFT JOIN photos p ON p.album_id = a.id AND p.deleted_at IS NULL
+WHERE a.id = ? AND a.deleted_at IS NULL
 GROUP BY a.id;
 
 -- name: ClearAlbumCoverIfPhoto :exec
 UPDATE albums
 SET cover_photo_id = NULL, updated_at = CURRENT_TIMESTAMP
 WHERE cover_photo_id = ?;
+
+-- name: SetAlbumSortMode :exec
+UPDATE albums
+SET sort_mode = ?, updated_at = CURRENT_TIMESTAMP
+WHERE id = ?;
+
+-- name: SetAlbumCoverHeader :exec
+UPDATE albums
+SET cover_header = ?, updated_at = CURRENT_TIMESTAMP
+WHERE id = ?;
+
+-- name: TrashAlbum :exec
+UPDATE albums SET deleted_at = CURRENT_TIMESTAMP WHERE id = ? AND deleted_at IS NULL;
+
+-- name: RestoreAlbum :exec
+UPDATE albums SET deleted_at = NULL WHERE id = ?;
+
+-- name: ListTrashedAlbums :many
+SELECT
+    a.*,
+    COUNT(p.id) as photo_count
+FROM albums a
+LEFT JOIN photos p ON p.album_id = a.id
+WHERE a.deleted_at IS NOT NULL
+GROUP BY a.id
+ORDER BY a.deleted_at DESC;
+
+-- name: PurgeTrashedAlbums :many
+DELETE FROM albums
+WHERE deleted_at < ?
+RETURNING id;
+
+-- name: SetAlbumProcessingProfile :exec
+UPDATE albums
+SET processing_profile_id = ?, updated_at = CURRENT_TIMESTAMP
+WHERE id = ?;
+
+-- name: ClearAlbumProcessingProfile :exec
+UPDATE albums
+SET processing_profile_id = NULL
+WHERE processing_profile_id = ?;
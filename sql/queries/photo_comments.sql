@@ -0,0 +1,33 @@
+-- name: CreatePhotoComment :one
+INSERT INTO photo_comments (photo_id, share_link_id, display_name, body)
+VALUES (?, ?, ?, ?)
+RETURNING *;
+
+-- name: GetPhotoComment :one
+SELECT * FROM photo_comments WHERE id = ?;
+
+-- name: ListVisiblePhotoComments :many
+SELECT * FROM photo_comments
+WHERE photo_id = ? AND share_link_id = ? AND hidden_at IS NULL
+ORDER BY created_at ASC, id ASC;
+
+-- name: ListPhotoCommentsWithDetails :many
+SELECT 
+    c.*,
+    p.album_id,
+    a.title as album_title,
+    sl.token as share_token
+FROM photo_comments c
+JOIN photos p ON p.id = c.photo_id
+JOIN albums a ON a.id = p.album_id
+JOIN share_links sl ON sl.id = c.share_link_id
+ORDER BY c.created_at DESC, c.id DESC
+LIMIT ? OFFSET ?;
+
+-- name: SetPhotoCommentHidden :exec
+UPDATE photo_comments
+SET hidden_at = ?
+WHERE id = ?;
+
+-- name: DeletePhotoComment :exec
+DELETE FROM photo_comments WHERE id = ?;
@@ -0,0 +1,36 @@
+-- name: UpsertPhotoReaction :exec
+INSERT INTO photo_reactions (photo_id, share_link_id, viewer_hash, reaction)
+VALUES (?, ?, ?, ?)
+ON CONFLICT (photo_id, viewer_hash) DO UPDATE SET
+    reaction = excluded.reaction,
+    share_link_id = excluded.share_link_id,
+    created_at = CURRENT_TIMESTAMP;
+
+-- name: DeletePhotoReaction :exec
+DELETE FROM photo_reactions WHERE photo_id = ? AND viewer_hash = ?;
+
+-- name: GetViewerReaction :one
+SELECT reaction FROM photo_reactions WHERE photo_id = ? AND viewer_hash = ?;
+
+-- name: ListViewerReactions :many
+SELECT photo_id, reaction FROM photo_reactions WHERE viewer_hash = ?;
+
+-- name: ListReactionCountsForPhoto :many
+SELECT photo_id, reaction, COUNT(*) AS count
+FROM photo_reactions
+WHERE photo_id = ?
+GROUP BY photo_id, reaction;
+
+-- name: ListReactionCountsForAlbum :many
+SELECT r.photo_id, r.reaction, COUNT(*) AS count
+FROM photo_reactions r
+JOIN photos p ON p.id = r.photo_id
+WHERE p.album_id = ?
+GROUP BY r.photo_id, r.reaction;
+
+-- name: ListReactionCountsForTag :many
+SELECT r.photo_id, r.reaction, COUNT(*) AS count
+FROM photo_reactions r
+JOIN photo_tags pt ON pt.photo_id = r.photo_id
+WHERE pt.tag_id = ?
+GROUP BY r.photo_id, r.reaction;
@@ -4,10 +4,36 @@ VALUES (?, ?, ?, ?, ?, ?)
 RETURNING *;
 
 -- name: GetPhoto :one
+SELECT p.* FROM photos p
+JOIN albums a ON a.id = p.album_id
+WHERE p.id = ? AND p.deleted_at IS NULL AND a.deleted_at IS NULL;
+
+-- name: GetPhotoIncludingDeleted :one
 SELECT * FROM photos WHERE id = ?;
 
 -- name: ListPhotosByAlbum :many
-SELECT * FROM photos WHERE album_id = ? ORDER BY created_at DESC LIMIT ? OFFSET ?;
+SELECT * FROM photos WHERE album_id = ? AND deleted_at IS NULL ORDER BY created_at DESC LIMIT ? OFFSET ?;
+
+-- name: ListPhotosByAlbumPosition :many
+SELECT * FROM photos WHERE album_id = ? AND deleted_at IS NULL ORDER BY position ASC, id ASC LIMIT ? OFFSET ?;
+
+-- name: ListPhotosByAlbumTakenAt :many
+SELECT * FROM photos WHERE album_id = ? AND deleted_at IS NULL
+ORDER BY COALESCE(taken_at, created_at) ASC, id ASC
+LIMIT ? OFFSET ?;
+
+-- name: ListPhotoIDsByAlbumPosition :many
+SELECT id FROM photos WHERE album_id = ? AND deleted_at IS NULL ORDER BY position ASC, id ASC;
+
+-- name: UpdatePhotoPosition :exec
+UPDATE photos
+SET position = ?
+WHERE id = ? AND album_id = ?;
+
+-- name: UpdatePhotoTakenAt :exec
+UPDATE photos
+SET taken_at = ?
+WHERE id = ?;
 
 -- name: ListAllPhotosWithAlbum :many
 SELECT 
@@ -15,6 +41,85 @@ SELECT
     a.title as album_title
 FROM photos p
 JOIN albums a ON p.album_id = a.id
+WHERE p.deleted_at IS NULL AND a.deleted_at IS NULL
+ORDER BY p.created_at DESC
+LIMIT ? OFFSET ?;
+
+-- name: ListTimelinePhotos :many
+-- Keyset page of ListAllPhotosWithAlbum in timeline order: newest capture
+-- time first, falling back to the upload time. Pass the sort key and id of
+-- the last photo of the previous page. The range on the sort key alone lets
+-- the timeline index seek to the page instead of scanning up to it.
+SELECT
+    p.*,
+    a.title as album_title,
+    substr(COALESCE(p.taken_at, p.created_at), 1, 19) as sort_key
+FROM photos p INDEXED BY idx_photos_timeline
+JOIN albums a ON p.album_id = a.id
+WHERE p.deleted_at IS NULL AND a.deleted_at IS NULL
+  AND substr(COALESCE(p.taken_at, p.created_at), 1, 19) <= sqlc.arg(before_key)
+  AND (substr(COALESCE(p.taken_at, p.created_at), 1, 19), p.id) < (sqlc.arg(before_key), sqlc.arg(before_id))
+ORDER BY substr(COALESCE(p.taken_at, p.created_at), 1, 19) DESC, p.id DESC
+LIMIT ?;
+
+-- name: ListTimelineMonths :many
+SELECT
+    substr(COALESCE(p.taken_at, p.created_at), 1, 7) as month,
+    COUNT(*) as count
+FROM photos p
+JOIN albums a ON p.album_id = a.id
+WHERE p.deleted_at IS NULL AND a.deleted_at IS NULL
+GROUP BY month
+ORDER BY month DESC;
+
+-- name: ListOnThisDayPhotos :many
+-- Photos taken on a month and day ("MM-DD") in years before the given one.
+SELECT
+    p.*,
+    a.title as album_title,
+    substr(COALESCE(p.taken_at, p.created_at), 1, 19) as sort_key
+FROM photos p
+JOIN albums a ON p.album_id = a.id
+WHERE p.deleted_at IS NULL AND a.deleted_at IS NULL
+  AND substr(COALESCE(p.taken_at, p.created_at), 6, 5) = sqlc.arg(month_day)
+  AND substr(COALESCE(p.taken_at, p.created_at), 1, 4) < sqlc.arg(year)
+ORDER BY substr(COALESCE(p.taken_at, p.created_at), 1, 19) DESC, p.id DESC
+LIMIT ?;
+
+-- name: UpdatePhotoLocation :exec
+UPDATE photos
+SET latitude = ?, longitude = ?
+WHERE id = ?;
+
+-- name: ClusterPhotoLocations :many
+-- Geotagged photos within a bounding box, grouped into a grid of cells of
+-- cell degrees. Each cluster is placed at the mean location of its photos
+-- and represented by its newest photo. album_id limits it to one album.
+SELECT
+    CAST((p.latitude + 90) / sqlc.arg(cell) AS INTEGER) as cell_y,
+    CAST((p.longitude + 180) / sqlc.arg(cell) AS INTEGER) as cell_x,
+    COUNT(*) as count,
+    CAST(AVG(p.latitude) AS REAL) as latitude,
+    CAST(AVG(p.longitude) AS REAL) as longitude,
+    CAST(MAX(p.id) AS INTEGER) as photo_id
+FROM photos p
+JOIN albums a ON p.album_id = a.id
+WHERE p.latitude IS NOT NULL AND p.deleted_at IS NULL AND a.deleted_at IS NULL
+  AND p.latitude BETWEEN sqlc.arg(south) AND sqlc.arg(north)
+  AND p.longitude BETWEEN sqlc.arg(west) AND sqlc.arg(east)
+  AND (sqlc.narg(album_id) IS NULL OR p.album_id = sqlc.narg(album_id))
+GROUP BY cell_y, cell_x
+ORDER BY count DESC
+LIMIT sqlc.arg(max_clusters);
+
+-- name: ListAllPhotosWithAlbumByTag :many
+SELECT 
+    p.*,
+    a.title as album_title
+FROM photos p
+JOIN albums a ON p.album_id = a.id
+JOIN photo_tags pt ON pt.photo_id = p.id
+WHERE pt.tag_id = ? AND p.deleted_at IS NULL AND a.deleted_at IS NULL
 ORDER BY p.created_at DESC
 LIMIT ? OFFSET ?;
 
@@ -22,7 +127,12 @@ LIMIT ? OFFSET ?;
 DELETE FROM photos WHERE id = ?;
 
 -- name: CountPhotos :one
-SELECT COUNT(*) FROM photos;
+SELECT COUNT(*) FROM photos p
+JOIN albums a ON a.id = p.album_id
+WHERE p.deleted_at IS NULL AND a.deleted_at IS NULL;
+
+-- name: CountAlbumPhotos :one
+SELECT COUNT(*) FROM photos WHERE album_id = ? AND deleted_at IS NULL;
 
 -- name: GetTotalStorageBytes :one
 SELECT COALESCE(SUM(size_bytes), 0) FROM photos;
@@ -32,7 +142,78 @@ UPDATE photos
 SET width = ?, height = ?, size_bytes = ?
 WHERE id = ?;
 
+-- name: UpdatePhotoCaption :exec
+UPDATE photos
+SET caption = ?
+WHERE id = ?;
+
 -- name: DeleteOrphanedPhotos :many
 DELETE FROM photos 
 WHERE album_id NOT IN (SELECT id FROM albums)
-RETURNING id, album_id, filename, format, created_at;
+RETURNING id, album_id, filename, format, created_at, original_format, animation_format;
+
+-- name: GetMaxPhotoPosition :one
+SELECT CAST(COALESCE(MAX(position), 0) AS INTEGER) FROM photos WHERE album_id = ?;
+
+-- name: MovePhotoToAlbum :exec
+UPDATE photos SET album_id = ?, position = ? WHERE id = ?;
+
+-- name: TrashPhoto :exec
+UPDATE photos SET deleted_at = CURRENT_TIMESTAMP WHERE id = ? AND deleted_at IS NULL;
+
+-- name: RestorePhoto :exec
+UPDATE photos SET deleted_at = NULL WHERE id = ?;
+
+-- name: ListTrashedPhotos :many
+-- Photos trashed on their own; photos of a trashed album go with the album.
+SELECT
+    p.*,
+    a.title as album_title
+FROM photos p
+JOIN albums a ON p.album_id = a.id
+WHERE p.deleted_at IS NOT NULL AND a.deleted_at IS NULL
+ORDER BY p.deleted_at DESC;
+
+-- name: ListPhotosForAlbumIncludingDeleted :many
+SELECT * FROM photos WHERE album_id = ?;
+
+-- name: PurgeTrashedPhotos :many
+DELETE FROM photos
+WHERE deleted_at < ?
+RETURNING id, album_id, format, created_at, original_format, animation_format;
+
+-- name: PurgePhotosOfTrashedAlbums :many
+DELETE FROM photos
+WHERE album_id IN (SELECT id FROM albums WHERE deleted_at < ?)
+RETURNING id, album_id, format, created_at, original_format, animation_format;
+
+-- name: SetPhotoOriginalFormat :exec
+UPDATE photos SET original_format = ? WHERE id = ?;
+
+-- name: ListReprocessCandidates :many
+-- Live photos without a pending reprocess job, named by their upload filename.
+SELECT p.id, p.album_id, p.format,
+    CAST(COALESCE((
+        SELECT j.original_filename FROM processing_queue j
+        WHERE j.photo_id = p.id AND j.kind = 'upload'
+        ORDER BY j.id LIMIT 1
+    ), p.filename) AS TEXT) AS filename
+FROM photos p
+JOIN albums a ON a.id = p.album_id
+WHERE p.deleted_at IS NULL AND a.deleted_at IS NULL
+  AND NOT EXISTS (
+    SELECT 1 FROM processing_queue j
+    WHERE j.photo_id = p.id AND j.kind = 'reprocess' AND j.status IN ('pending', 'processing')
+  )
+ORDER BY p.id;
+
+-- name: UpdatePhotoRendition :exec
+UPDATE photos
+SET filename = ?, format = ?, width = ?, height = ?, size_bytes = ?, edits = ?
+WHERE id = ?;
+
+-- name: SetPhotoEdits :exec
+UPDATE photos SET edits = ? WHERE id = ?;
+
+-- name: SetPhotoAnimationFormat :exec
+UPDATE photos SET animation_format = ? WHERE id = ?;
@@ -0,0 +1,31 @@
+-- name: ListProcessingProfiles :many
+SELECT * FROM processing_profiles
+ORDER BY name;
+
+-- name: GetProcessingProfile :one
+SELECT * FROM processing_profiles WHERE id = ?;
+
+-- name: CreateProcessingProfile :one
+INSERT INTO processing_profiles (name, max_dimension, format, quality, avif_speed, keep_originals, strip_metadata)
+VALUES (?, ?, ?, ?, ?, ?, ?)
+RETURNING *;
+
+-- name: UpdateProcessingProfile :exec
+UPDATE processing_profiles
+SET name = ?, max_dimension = ?, format = ?, quality = ?, avif_speed = ?,
+    keep_originals = ?, strip_metadata = ?, updated_at = CURRENT_TIMESTAMP
+WHERE id = ?;
+
+-- name: DeleteProcessingProfile :exec
+DELETE FROM processing_profiles WHERE id = ?;
+
+-- name: GetAlbumProcessingProfile :one
+SELECT pp.* FROM processing_profiles pp
+JOIN albums a ON a.processing_profile_id = pp.id
+WHERE a.id = ?;
+
+-- name: CountAlbumsByProcessingProfile :many
+SELECT processing_profile_id, COUNT(*) AS album_count
+FROM albums
+WHERE processing_profile_id IS NOT NULL AND deleted_at IS NULL
+GROUP BY processing_profile_id;
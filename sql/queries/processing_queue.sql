@@ -8,10 +8,12 @@ RETURNING *;
 
 -- name: GetNextPendingJob :one
 UPDATE processing_queue
-SET status = 'processing', updated_at = CURRENT_TIMESTAMP
+SET status = 'processing', heartbeat_at = CURRENT_TIMESTAMP,
+    lease_expires_at = datetime('now', sqlc.arg(lease)), updated_at = CURRENT_TIMESTAMP
 WHERE id = (
   SELECT id FROM processing_queue
   WHERE status = 'pending'
+    AND (next_attempt_at IS NULL OR next_attempt_at <= CURRENT_TIMESTAMP)
   ORDER BY created_at ASC
   LIMIT 1
 )
@@ -47,3 +49,90 @@ WHERE album_id = ? AND status = 'failed';
 -- name: CountActiveJobs :one
 SELECT COUNT(*) FROM processing_queue 
 WHERE album_id = ? AND status IN ('pending', 'processing');
+
+-- name: SetJobPhoto :exec
+UPDATE processing_queue
+SET photo_id = ?, updated_at = CURRENT_TIMESTAMP
+WHERE id = ?;
+
+-- name: MoveJobsToAlbum :exec
+UPDATE processing_queue
+SET album_id = ?, updated_at = CURRENT_TIMESTAMP
+WHERE photo_id = ?;
+
+-- name: GetJob :one
+SELECT * FROM processing_queue
+WHERE id = ?;
+
+-- name: RetryJobLater :exec
+UPDATE processing_queue
+SET status = 'pending', attempts = attempts + 1, error_message = ?,
+    next_attempt_at = datetime('now', sqlc.arg(delay)), updated_at = CURRENT_TIMESTAMP
+WHERE id = ?;
+
+-- name: RequeueFailedJob :exec
+UPDATE processing_queue
+SET status = 'pending', error_message = NULL, attempts = 0, next_attempt_at = NULL,
+    updated_at = CURRENT_TIMESTAMP
+WHERE id = ? AND status = 'failed';
+
+-- name: ListFailedJobsWithAlbum :many
+SELECT q.id, q.album_id, q.original_filename, q.temp_filepath, q.error_message,
+    q.attempts, q.updated_at, q.kind, a.title AS album_title
+FROM processing_queue q
+JOIN albums a ON a.id = q.album_id
+WHERE q.status = 'failed' AND a.deleted_at IS NULL
+ORDER BY q.updated_at DESC, q.id DESC;
+
+-- name: DeleteExpiredFailedJobs :many
+DELETE FROM processing_queue
+WHERE status = 'failed' AND updated_at < ?
+RETURNING temp_filepath;
+
+-- name: ListJobTempFiles :many
+-- Temp inputs still needed by a queued, running or failed job.
+SELECT temp_filepath FROM processing_queue
+WHERE status IN ('pending', 'processing', 'failed');
+
+-- name: HeartbeatJob :exec
+UPDATE processing_queue
+SET heartbeat_at = CURRENT_TIMESTAMP, lease_expires_at = datetime('now', sqlc.arg(lease))
+WHERE id = ? AND status = 'processing';
+
+-- name: RequeueJob :exec
+UPDATE processing_queue
+SET status = 'pending', lease_expires_at = NULL, heartbeat_at = NULL, updated_at = CURRENT_TIMESTAMP
+WHERE id = ? AND status = 'processing';
+
+-- name: ReclaimExpiredJobs :many
+UPDATE processing_queue
+SET status = 'pending', lease_expires_at = NULL, heartbeat_at = NULL, updated_at = CURRENT_TIMESTAMP
+WHERE status = 'processing'
+  AND (lease_expires_at IS NULL OR lease_expires_at < CURRENT_TIMESTAMP)
+RETURNING id;
+
+-- name: ListJobStatusesByAlbum :many
+SELECT id, status FROM processing_queue
+WHERE album_id = ?
+ORDER BY id;
+
+-- name: EnqueueReprocessJob :one
+INSERT INTO processing_queue (
+    album_id, original_filename, temp_filepath, status, kind, photo_id
+) VALUES (
+    ?, ?, '', 'pending', 'reprocess', ?
+)
+RETURNING *;
+
+-- name: DeleteFinishedReprocessJobs :exec
+DELETE FROM processing_queue
+WHERE kind = 'reprocess' AND status IN ('completed', 'failed');
+
+-- name: GetReprocessStatus :one
+SELECT
+    COUNT(CASE WHEN status = 'pending' THEN 1 END) AS pending_count,
+    COUNT(CASE WHEN status = 'processing' THEN 1 END) AS processing_count,
+    COUNT(CASE WHEN status = 'failed' THEN 1 END) AS failed_count,
+    COUNT(CASE WHEN status = 'completed' THEN 1 END) AS completed_count
+FROM processing_queue
+WHERE kind = 'reprocess' AND id >= ?;
@@ -0,0 +1,14 @@
+-- name: Search :many
+SELECT
+    kind,
+    CAST(rowid / 4 AS INTEGER) AS ref_id,
+    CAST(album_id AS INTEGER) AS album_id,
+    CAST(photo_id AS INTEGER) AS photo_id,
+    title,
+    body
+FROM search_index
+WHERE search_index MATCH sqlc.arg(query)
+  AND CAST(album_id AS INTEGER) NOT IN (SELECT id FROM albums WHERE deleted_at IS NOT NULL)
+  AND CAST(photo_id AS INTEGER) NOT IN (SELECT id FROM photos WHERE deleted_at IS NOT NULL)
+ORDER BY rank
+LIMIT ?;
@@ -0,0 +1,7 @@
+-- name: ListSettings :many
+SELECT * FROM settings ORDER BY key;
+
+-- name: UpsertSetting :exec
+INSERT INTO settings (key, value)
+VALUES (?, ?)
+ON CONFLICT(key) DO UPDATE SET value = excluded.value, updated_at = CURRENT_TIMESTAMP;
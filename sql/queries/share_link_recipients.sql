@@ -0,0 +1,35 @@
+-- name: CreateShareLinkRecipient :one
+INSERT INTO share_link_recipients (share_link_id, name, token)
+VALUES (?, ?, ?)
+RETURNING *;
+
+-- name: GetShareLinkRecipientByToken :one
+SELECT * FROM share_link_recipients WHERE token = ?;
+
+-- name: ListShareLinkRecipients :many
+SELECT
+    r.*,
+    (SELECT COUNT(DISTINCT viewer_hash) FROM share_link_views WHERE recipient_id = r.id) as views
+FROM share_link_recipients r
+WHERE r.share_link_id = ?
+ORDER BY r.id;
+
+-- name: ListRecipientsForShareLinks :many
+SELECT
+    r.*,
+    (SELECT COUNT(DISTINCT viewer_hash) FROM share_link_views WHERE recipient_id = r.id) as views
+FROM share_link_recipients r
+WHERE r.share_link_id IN (
+    SELECT id FROM share_links
+    ORDER BY created_at DESC, id DESC
+    LIMIT ? OFFSET ?
+)
+ORDER BY r.share_link_id, r.id;
+
+-- name: CountShareLinkRecipients :one
+SELECT COUNT(*) FROM share_link_recipients WHERE share_link_id = ?;
+
+-- name: RevokeShareLinkRecipient :execrows
+UPDATE share_link_recipients
+SET revoked_at = CURRENT_TIMESTAMP
+WHERE id = ? AND share_link_id = ? AND revoked_at IS NULL;
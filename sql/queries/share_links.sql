@@ -1,11 +1,14 @@
 -- name: CreateShareLink :one
-INSERT INTO share_links (token, target_type, target_id, max_views, expires_at, message)
-VALUES (?, ?, ?, ?, ?, ?)
+INSERT INTO share_links (token, target_type, target_id, max_views, expires_at, message, allow_comments, show_map, open_graph, slug, locale)
+VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
 RETURNING *;
 
 -- name: GetShareLinkByToken :one
 SELECT * FROM share_links WHERE token = ?;
 
+-- name: GetShareLinkBySlug :one
+SELECT * FROM share_links WHERE slug = ?;
+
 -- name: GetShareLink :one
 SELECT * FROM share_links WHERE id = ?;
 
@@ -20,16 +23,19 @@ SELECT
     CASE 
         WHEN sl.target_type = 'album' THEN a.title
         WHEN sl.target_type = 'photo' THEN (SELECT title FROM albums WHERE id = p.album_id)
+        WHEN sl.target_type = 'tag' THEN (SELECT name FROM tags WHERE id = sl.target_id)
     END as target_title,
     CASE
         WHEN sl.target_type = 'photo' THEN p.album_id
         ELSE NULL
     END as photo_album_id,
-    (SELECT COUNT(DISTINCT viewer_hash) FROM share_link_views WHERE share_link_id = sl.id) as current_views
+    (SELECT COUNT(DISTINCT viewer_hash) FROM share_link_views WHERE share_link_id = sl.id) as current_views,
+    (SELECT COUNT(*) FROM activity_events WHERE share_link_id = sl.id AND event_type = 'share_bot_view') as bot_views,
+    (SELECT COUNT(*) FROM share_link_recipients WHERE share_link_id = sl.id) as recipients
 FROM share_links sl
 LEFT JOIN albums a ON sl.target_type = 'album' AND sl.target_id = a.id
 LEFT JOIN photos p ON sl.target_type = 'photo' AND sl.target_id = p.id
-ORDER BY sl.created_at DESC
+ORDER BY sl.created_at DESC, sl.id DESC
 LIMIT ? OFFSET ?;
 
 -- name: ListActiveShareLinks :many
@@ -44,17 +50,30 @@ UPDATE share_links
 SET revoked_at = CURRENT_TIMESTAMP
 WHERE id = ?;
 
+-- name: UpdateShareLink :execrows
+UPDATE share_links
+SET max_views = ?, expires_at = ?, message = ?, slug = ?, locale = ?
+WHERE id = ?;
+
+-- name: ReactivateShareLink :execrows
+UPDATE share_links
+SET revoked_at = NULL
+WHERE id = ? AND revoked_at IS NOT NULL;
+
+-- name: ResetShareLinkViews :exec
+DELETE FROM share_link_views WHERE share_link_id = ?;
+
 -- name: CountShareLinks :one
 SELECT COUNT(*) FROM share_links;
 
 -- name: IncrementShareLinkView :exec
-INSERT OR IGNORE INTO share_link_views (share_link_id, viewer_hash) VALUES (?, ?);
+INSERT OR IGNORE INTO share_link_views (share_link_id, viewer_hash, recipient_id) VALUES (?, ?, ?);
 
 -- name: CountUniqueShareLinkViews :one
 SELECT COUNT(DISTINCT viewer_hash) FROM share_link_views WHERE share_link_id = ?;
 
 -- name: DeleteExpiredShareLinks :many
-DELETE FROM share_links 
-WHERE expires_at IS NOT NULL AND expires_at < CURRENT_TIMESTAMP
-   OR revoked_at IS NOT NULL
+DELETE FROM share_links
+WHERE expires_at IS NOT NULL AND expires_at < sqlc.arg(cutoff)
+   OR revoked_at IS NOT NULL AND revoked_at < sqlc.arg(cutoff)
 RETURNING id, target_type, target_id;
@@ -0,0 +1,68 @@
+-- name: CreateTag :one
+INSERT INTO tags (name)
+VALUES (?)
+RETURNING *;
+
+-- name: GetTag :one
+SELECT * FROM tags WHERE id = ?;
+
+-- name: GetTagByName :one
+SELECT * FROM tags WHERE name = ?;
+
+-- name: ListTags :many
+SELECT * FROM tags
+ORDER BY name ASC;
+
+-- name: ListTagsWithPhotoCount :many
+SELECT 
+    t.*,
+    COUNT(pt.photo_id) as photo_count
+FROM tags t
+LEFT JOIN photo_tags pt ON pt.tag_id = t.id
+    AND pt.photo_id IN (
+        SELECT p.id FROM photos p
+        JOIN albums a ON a.id = p.album_id
+        WHERE p.deleted_at IS NULL AND a.deleted_at IS NULL
+    )
+GROUP BY t.id
+ORDER BY t.name ASC;
+
+-- name: DeleteTag :exec
+DELETE FROM tags WHERE id = ?;
+
+-- name: AddPhotoTag :exec
+INSERT OR IGNORE INTO photo_tags (photo_id, tag_id) VALUES (?, ?);
+
+-- name: RemovePhotoTag :exec
+DELETE FROM photo_tags WHERE photo_id = ? AND tag_id = ?;
+
+-- name: ListTagsForPhoto :many
+SELECT t.* FROM tags t
+JOIN photo_tags pt ON pt.tag_id = t.id
+WHERE pt.photo_id = ?
+ORDER BY t.name ASC;
+
+-- name: ListTagsForAlbumPhotos :many
+SELECT pt.photo_id, t.id as tag_id, t.name
+FROM photo_tags pt
+JOIN tags t ON t.id = pt.tag_id
+JOIN photos p ON p.id = pt.photo_id
+WHERE p.album_id = ?
+ORDER BY t.name ASC;
+
+-- name: ListPhotosByTag :many
+SELECT p.* FROM photos p
+JOIN photo_tags pt ON pt.photo_id = p.id
+JOIN albums a ON a.id = p.album_id
+WHERE pt.tag_id = ? AND p.deleted_at IS NULL AND a.deleted_at IS NULL
+ORDER BY p.created_at DESC
+LIMIT ? OFFSET ?;
+
+-- name: CountPhotosByTag :one
+SELECT COUNT(*) FROM photos p
+JOIN photo_tags pt ON pt.photo_id = p.id
+JOIN albums a ON a.id = p.album_id
+WHERE pt.tag_id = ? AND p.deleted_at IS NULL AND a.deleted_at IS NULL;
+
+-- name: CountPhotoTag :one
+SELECT COUNT(*) FROM photo_tags WHERE photo_id = ? AND tag_id = ?;
@@ -0,0 +1,57 @@
+-- tags and photo_tags tables for labelling photos (people, places, events)
+CREATE TABLE IF NOT EXISTS tags (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    name TEXT NOT NULL UNIQUE COLLATE NOCASE,
+    created_at DATETIME DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE TABLE IF NOT EXISTS photo_tags (
+    photo_id INTEGER NOT NULL,
+    tag_id INTEGER NOT NULL,
+    created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (photo_id, tag_id),
+    FOREIGN KEY (photo_id) REFERENCES photos(id) ON DELETE CASCADE,
+    FOREIGN KEY (tag_id) REFERENCES tags(id) ON DELETE CASCADE
+);
+
+CREATE INDEX IF NOT EXISTS idx_photo_tags_tag_id ON photo_tags(tag_id);
+
+-- Rebuild share_links so target_type accepts 'tag'. SQLite cannot alter a
+-- CHECK constraint in place. share_link_views is copied aside first because
+-- dropping share_links would otherwise cascade-delete its rows.
+CREATE TABLE share_links_new (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    token TEXT UNIQUE NOT NULL,
+    target_type TEXT NOT NULL CHECK(target_type IN ('album', 'photo', 'tag')),
+    target_id INTEGER NOT NULL,
+    max_views INTEGER,
+    expires_at DATETIME,
+    created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+    revoked_at DATETIME,
+    message TEXT
+);
+
+INSERT INTO share_links_new (id, token, target_type, target_id, max_views, expires_at, created_at, revoked_at, message)
+SELECT id, token, target_type, target_id, max_views, expires_at, created_at, revoked_at, message FROM share_links;
+
+CREATE TABLE share_link_views_backup AS SELECT * FROM share_link_views;
+
+DROP TABLE share_link_views;
+DROP TABLE share_links;
+ALTER TABLE share_links_new RENAME TO share_links;
+
+CREATE TABLE share_link_views (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    share_link_id INTEGER NOT NULL,
+    viewer_hash TEXT NOT NULL,
+    created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+    FOREIGN KEY (share_link_id) REFERENCES share_links(id) ON DELETE CASCADE
+);
+
+INSERT INTO share_link_views (id, share_link_id, viewer_hash, created_at)
+SELECT id, share_link_id, viewer_hash, created_at FROM share_link_views_backup;
+
+DROP TABLE share_link_views_backup;
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_share_links_token ON share_links(token);
+CREATE UNIQUE INDEX IF NOT EXISTS idx_share_link_views_dedup ON share_link_views(share_link_id, viewer_hash);
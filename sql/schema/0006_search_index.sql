@@ -0,0 +1,92 @@
+-- Full-text search over albums, photo captions, upload filenames and tags.
+ALTER TABLE photos ADD COLUMN caption TEXT;
+
+-- Completed upload jobs remember the photo they produced so a search on the
+-- original filename can link straight to it.
+ALTER TABLE processing_queue ADD COLUMN photo_id INTEGER;
+
+-- One FTS5 table holds every searchable source. The rowid encodes the source
+-- so triggers can update a single entry without scanning:
+--   album = id*4, photo = id*4+1, upload job = id*4+2, tag = id*4+3
+-- album_id and photo_id are 0 when not applicable.
+CREATE VIRTUAL TABLE IF NOT EXISTS search_index USING fts5(
+    kind UNINDEXED,
+    album_id UNINDEXED,
+    photo_id UNINDEXED,
+    title,
+    body,
+    tokenize = 'unicode61 remove_diacritics 2'
+);
+
+CREATE TRIGGER IF NOT EXISTS search_albums_ai AFTER INSERT ON albums BEGIN
+    INSERT INTO search_index (rowid, kind, album_id, photo_id, title, body)
+    VALUES (new.id * 4, 'album', new.id, 0, new.title, COALESCE(new.description, ''));
+END;
+
+CREATE TRIGGER IF NOT EXISTS search_albums_au AFTER UPDATE OF title, description ON albums BEGIN
+    DELETE FROM search_index WHERE rowid = old.id * 4;
+    INSERT INTO search_index (rowid, kind, album_id, photo_id, title, body)
+    VALUES (new.id * 4, 'album', new.id, 0, new.title, COALESCE(new.description, ''));
+END;
+
+CREATE TRIGGER IF NOT EXISTS search_albums_ad AFTER DELETE ON albums BEGIN
+    DELETE FROM search_index WHERE rowid = old.id * 4;
+END;
+
+CREATE TRIGGER IF NOT EXISTS search_photos_ai AFTER INSERT ON photos BEGIN
+    INSERT INTO search_index (rowid, kind, album_id, photo_id, title, body)
+    VALUES (new.id * 4 + 1, 'photo', new.album_id, new.id, COALESCE(new.caption, ''), '');
+END;
+
+CREATE TRIGGER IF NOT EXISTS search_photos_au AFTER UPDATE OF caption, album_id ON photos BEGIN
+    DELETE FROM search_index WHERE rowid = old.id * 4 + 1;
+    INSERT INTO search_index (rowid, kind, album_id, photo_id, title, body)
+    VALUES (new.id * 4 + 1, 'photo', new.album_id, new.id, COALESCE(new.caption, ''), '');
+END;
+
+CREATE TRIGGER IF NOT EXISTS search_photos_ad AFTER DELETE ON photos BEGIN
+    DELETE FROM search_index WHERE rowid = old.id * 4 + 1;
+END;
+
+CREATE TRIGGER IF NOT EXISTS search_jobs_ai AFTER INSERT ON processing_queue BEGIN
+    INSERT INTO search_index (rowid, kind, album_id, photo_id, title, body)
+    VALUES (new.id * 4 + 2, 'upload', new.album_id, COALESCE(new.photo_id, 0), new.original_filename, '');
+END;
+
+CREATE TRIGGER IF NOT EXISTS search_jobs_au AFTER UPDATE OF photo_id, original_filename, album_id ON processing_queue BEGIN
+    DELETE FROM search_index WHERE rowid = old.id * 4 + 2;
+    INSERT INTO search_index (rowid, kind, album_id, photo_id, title, body)
+    VALUES (new.id * 4 + 2, 'upload', new.album_id, COALESCE(new.photo_id, 0), new.original_filename, '');
+END;
+
+CREATE TRIGGER IF NOT EXISTS search_jobs_ad AFTER DELETE ON processing_queue BEGIN
+    DELETE FROM search_index WHERE rowid = old.id * 4 + 2;
+END;
+
+CREATE TRIGGER IF NOT EXISTS search_tags_ai AFTER INSERT ON tags BEGIN
+    INSERT INTO search_index (rowid, kind, album_id, photo_id, title, body)
+    VALUES (new.id * 4 + 3, 'tag', 0, 0, new.name, '');
+END;
+
+CREATE TRIGGER IF NOT EXISTS search_tags_au AFTER UPDATE OF name ON tags BEGIN
+    DELETE FROM search_index WHERE rowid = old.id * 4 + 3;
+    INSERT INTO search_index (rowid, kind, album_id, photo_id, title, body)
+    VALUES (new.id * 4 + 3, 'tag', 0, 0, new.name, '');
+END;
+
+CREATE TRIGGER IF NOT EXISTS search_tags_ad AFTER DELETE ON tags BEGIN
+    DELETE FROM search_index WHERE rowid = old.id * 4 + 3;
+END;
+
+-- Backfill rows that existed before this migration
+INSERT INTO search_index (rowid, kind, album_id, photo_id, title, body)
+SELECT id * 4, 'album', id, 0, title, COALESCE(description, '') FROM albums;
+
+INSERT INTO search_index (rowid, kind, album_id, photo_id, title, body)
+SELECT id * 4 + 1, 'photo', album_id, id, COALESCE(caption, ''), '' FROM photos;
+
+INSERT INTO search_index (rowid, kind, album_id, photo_id, title, body)
+SELECT id * 4 + 2, 'upload', album_id, COALESCE(photo_id, 0), original_filename, '' FROM processing_queue;
+
+INSERT INTO search_index (rowid, kind, album_id, photo_id, title, body)
+SELECT id * 4 + 3, 'tag', 0, 0, name, '' FROM tags;
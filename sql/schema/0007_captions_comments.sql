@@ -0,0 +1,18 @@
+-- Guest comments on shared photos, enabled per share link.
+-- (photos.caption was added in 0006 for search.)
+ALTER TABLE share_links ADD COLUMN allow_comments BOOLEAN NOT NULL DEFAULT 0;
+
+CREATE TABLE IF NOT EXISTS photo_comments (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    photo_id INTEGER NOT NULL,
+    share_link_id INTEGER NOT NULL,
+    display_name TEXT NOT NULL,
+    body TEXT NOT NULL,
+    hidden_at DATETIME,
+    created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+    FOREIGN KEY (photo_id) REFERENCES photos(id) ON DELETE CASCADE,
+    FOREIGN KEY (share_link_id) REFERENCES share_links(id) ON DELETE CASCADE
+);
+
+CREATE INDEX IF NOT EXISTS idx_photo_comments_photo_link ON photo_comments(photo_id, share_link_id);
+CREATE INDEX IF NOT EXISTS idx_photo_comments_created_at ON photo_comments(created_at);
@@ -0,0 +1,17 @@
+-- Emoji reactions from share viewers. A viewer (identified by the per-link
+-- viewer hash) holds at most one reaction per photo; reacting again replaces it.
+-- Reactions outlive the link they were made through so favourites are kept
+-- after the janitor purges expired links.
+CREATE TABLE IF NOT EXISTS photo_reactions (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    photo_id INTEGER NOT NULL,
+    share_link_id INTEGER,
+    viewer_hash TEXT NOT NULL,
+    reaction TEXT NOT NULL,
+    created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+    FOREIGN KEY (photo_id) REFERENCES photos(id) ON DELETE CASCADE,
+    FOREIGN KEY (share_link_id) REFERENCES share_links(id) ON DELETE SET NULL
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_photo_reactions_viewer ON photo_reactions(photo_id, viewer_hash);
+CREATE INDEX IF NOT EXISTS idx_photo_reactions_share_link ON photo_reactions(share_link_id);
@@ -0,0 +1,26 @@
+-- Manual photo ordering and per-album sort mode.
+-- position is 1-based within an album; new photos are appended by trigger.
+ALTER TABLE photos ADD COLUMN position INTEGER NOT NULL DEFAULT 0;
+-- Capture time from EXIF (DateTimeOriginal), stored as UTC wall-clock time
+ALTER TABLE photos ADD COLUMN taken_at DATETIME;
+ALTER TABLE albums ADD COLUMN sort_mode TEXT NOT NULL DEFAULT 'upload'
+    CHECK (sort_mode IN ('manual', 'capture', 'upload'));
+
+-- Seed positions from upload order so switching to manual starts sensibly
+UPDATE photos SET position = (
+    SELECT COUNT(*) FROM photos p2
+    WHERE p2.album_id = photos.album_id
+      AND (p2.created_at < photos.created_at
+           OR (p2.created_at = photos.created_at AND p2.id <= photos.id))
+);
+
+CREATE TRIGGER IF NOT EXISTS photos_append_position AFTER INSERT ON photos
+WHEN new.position = 0
+BEGIN
+    UPDATE photos SET position = (
+        SELECT COALESCE(MAX(position), 0) + 1 FROM photos WHERE album_id = new.album_id
+    ) WHERE id = new.id;
+END;
+
+CREATE INDEX IF NOT EXISTS idx_photos_album_position ON photos(album_id, position);
+CREATE INDEX IF NOT EXISTS idx_photos_album_taken_at ON photos(album_id, taken_at);
@@ -0,0 +1,7 @@
+-- Soft delete: trashed photos and albums keep their rows and files until
+-- restored or purged (by an admin or by the janitor after the retention period).
+ALTER TABLE photos ADD COLUMN deleted_at DATETIME;
+ALTER TABLE albums ADD COLUMN deleted_at DATETIME;
+
+CREATE INDEX IF NOT EXISTS idx_photos_deleted_at ON photos(deleted_at);
+CREATE INDEX IF NOT EXISTS idx_albums_deleted_at ON albums(deleted_at);
@@ -0,0 +1,6 @@
+-- Failed jobs keep their temp input so they can be retried. Transient failures
+-- go back to pending and are picked up again once next_attempt_at has passed.
+ALTER TABLE processing_queue ADD COLUMN attempts INTEGER NOT NULL DEFAULT 0;
+ALTER TABLE processing_queue ADD COLUMN next_attempt_at DATETIME;
+
+CREATE INDEX IF NOT EXISTS idx_processing_queue_status ON processing_queue(status, updated_at);
@@ -0,0 +1,5 @@
+-- Jobs taken by a worker hold a lease that the worker renews with heartbeats.
+-- A processing job whose lease expired belongs to a crashed or hung worker and
+-- is put back in the queue.
+ALTER TABLE processing_queue ADD COLUMN lease_expires_at DATETIME;
+ALTER TABLE processing_queue ADD COLUMN heartbeat_at DATETIME;
@@ -0,0 +1,2 @@
+-- Upload progress is looked up per album
+CREATE INDEX IF NOT EXISTS idx_processing_queue_album_status ON processing_queue(album_id, status);
@@ -0,0 +1,24 @@
+-- Named processing profiles control how uploads are resized and encoded.
+-- Albums without a profile use the instance defaults.
+CREATE TABLE IF NOT EXISTS processing_profiles (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    name TEXT NOT NULL UNIQUE,
+    max_dimension INTEGER NOT NULL CHECK(max_dimension BETWEEN 320 AND 10000),
+    format TEXT NOT NULL CHECK(format IN ('webp', 'avif')),
+    quality INTEGER NOT NULL CHECK(quality BETWEEN 1 AND 100),
+    avif_speed INTEGER NOT NULL DEFAULT 6 CHECK(avif_speed BETWEEN 0 AND 10),
+    keep_originals BOOLEAN NOT NULL DEFAULT 0,
+    strip_metadata BOOLEAN NOT NULL DEFAULT 1,
+    created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+    updated_at DATETIME DEFAULT CURRENT_TIMESTAMP
+);
+
+ALTER TABLE albums ADD COLUMN processing_profile_id INTEGER REFERENCES processing_profiles(id) ON DELETE SET NULL;
+
+-- Format of the archived original upload, NULL when none was kept
+ALTER TABLE photos ADD COLUMN original_format TEXT;
+
+INSERT INTO processing_profiles (name, max_dimension, format, quality, avif_speed, keep_originals, strip_metadata)
+VALUES
+    ('High quality', 3840, 'webp', 92, 6, 1, 0),
+    ('Compact', 1280, 'webp', 70, 6, 0, 1);
@@ -0,0 +1,23 @@
+-- Reprocess jobs re-encode an existing photo (photo_id) instead of processing
+-- an uploaded file, so they have no temp file.
+ALTER TABLE processing_queue ADD COLUMN kind TEXT NOT NULL DEFAULT 'upload';
+
+-- Rotation applied in the admin, in degrees counter-clockwise. Reprocessing
+-- from the archived original applies it again.
+ALTER TABLE photos ADD COLUMN rotation INTEGER NOT NULL DEFAULT 0;
+
+-- Only uploads are searchable by their original filename
+DROP TRIGGER IF EXISTS search_jobs_ai;
+CREATE TRIGGER IF NOT EXISTS search_jobs_ai AFTER INSERT ON processing_queue
+WHEN new.kind = 'upload' BEGIN
+    INSERT INTO search_index (rowid, kind, album_id, photo_id, title, body)
+    VALUES (new.id * 4 + 2, 'upload', new.album_id, COALESCE(new.photo_id, 0), new.original_filename, '');
+END;
+
+DROP TRIGGER IF EXISTS search_jobs_au;
+CREATE TRIGGER IF NOT EXISTS search_jobs_au AFTER UPDATE OF photo_id, original_filename, album_id ON processing_queue
+WHEN new.kind = 'upload' BEGIN
+    DELETE FROM search_index WHERE rowid = old.id * 4 + 2;
+    INSERT INTO search_index (rowid, kind, album_id, photo_id, title, body)
+    VALUES (new.id * 4 + 2, 'upload', new.album_id, COALESCE(new.photo_id, 0), new.original_filename, '');
+END;
@@ -0,0 +1,10 @@
+-- Non-destructive edits (rotation, mirroring, crop, adjustments) as JSON.
+-- They are applied to the photo's archived original whenever it is rendered.
+ALTER TABLE photos ADD COLUMN edits TEXT;
+
+-- Rotations recorded for photos with an original become edits. Without an
+-- original the rotation is already part of the stored photo.
+UPDATE photos SET edits = json_object('rotate', rotation)
+WHERE rotation != 0 AND original_format IS NOT NULL;
+
+ALTER TABLE photos DROP COLUMN rotation;
@@ -0,0 +1,3 @@
+-- Format of the animation kept for an animated upload (gif, webp or avif).
+-- The stored photo is its still poster; NULL for still photos.
+ALTER TABLE photos ADD COLUMN animation_format TEXT;
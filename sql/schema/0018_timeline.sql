@@ -0,0 +1,10 @@
+-- Timeline across albums: photos by capture time, falling back to upload
+-- time. The sort key is the "YYYY-MM-DD HH:MM:SS" prefix of either, which
+-- every stored time format shares.
+CREATE INDEX IF NOT EXISTS idx_photos_timeline
+    ON photos(substr(COALESCE(taken_at, created_at), 1, 19), id)
+    WHERE deleted_at IS NULL;
+-- "On this day": photos by month and day of the sort key
+CREATE INDEX IF NOT EXISTS idx_photos_month_day
+    ON photos(substr(COALESCE(taken_at, created_at), 6, 5))
+    WHERE deleted_at IS NULL;
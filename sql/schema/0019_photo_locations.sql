@@ -0,0 +1,9 @@
+-- Capture location from EXIF GPS, in decimal degrees (WGS 84).
+-- NULL for photos without a location.
+ALTER TABLE photos ADD COLUMN latitude REAL;
+ALTER TABLE photos ADD COLUMN longitude REAL;
+CREATE INDEX IF NOT EXISTS idx_photos_location ON photos(latitude, longitude)
+    WHERE latitude IS NOT NULL AND deleted_at IS NULL;
+
+-- Album share links can show a map of the album's geotagged photos
+ALTER TABLE share_links ADD COLUMN show_map BOOLEAN NOT NULL DEFAULT 0;
@@ -0,0 +1,3 @@
+-- Share links can opt in to Open Graph link previews: the title, message and
+-- a preview image shown when the link is pasted into a chat app
+ALTER TABLE share_links ADD COLUMN open_graph BOOLEAN NOT NULL DEFAULT 0;
@@ -0,0 +1,4 @@
+-- Share link visits by bots are activity events of type 'share_bot_view';
+-- the share links page counts them per link
+CREATE INDEX IF NOT EXISTS idx_activity_events_share_link ON activity_events(share_link_id, event_type)
+    WHERE share_link_id IS NOT NULL;
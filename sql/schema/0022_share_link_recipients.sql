@@ -0,0 +1,20 @@
+-- Named recipients of a share link. Each recipient opens the link with their
+-- own token, derived from the link's, so views are tracked and access can be
+-- revoked per person. A link with recipients only opens through their tokens.
+CREATE TABLE IF NOT EXISTS share_link_recipients (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    share_link_id INTEGER NOT NULL,
+    name TEXT NOT NULL,
+    token TEXT NOT NULL,
+    created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+    revoked_at DATETIME,
+    FOREIGN KEY (share_link_id) REFERENCES share_links(id) ON DELETE CASCADE
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_share_link_recipients_token ON share_link_recipients(token);
+CREATE INDEX IF NOT EXISTS idx_share_link_recipients_share_link ON share_link_recipients(share_link_id);
+
+-- The recipient whose token a view came through
+ALTER TABLE share_link_views ADD COLUMN recipient_id INTEGER REFERENCES share_link_recipients(id) ON DELETE SET NULL;
+CREATE INDEX IF NOT EXISTS idx_share_link_views_recipient ON share_link_views(recipient_id)
+    WHERE recipient_id IS NOT NULL;
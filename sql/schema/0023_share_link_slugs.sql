@@ -0,0 +1,5 @@
+-- Share links can have a short slug chosen by the admin, which opens the link
+-- like its token does
+ALTER TABLE share_links ADD COLUMN slug TEXT;
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_share_links_slug ON share_links(slug) WHERE slug IS NOT NULL;
@@ -0,0 +1,3 @@
+-- Share links can be shown in a chosen language instead of the viewer's
+-- browser language
+ALTER TABLE share_links ADD COLUMN locale TEXT;
@@ -0,0 +1,10 @@
+-- Instance-wide settings, such as the branding of public pages, as
+-- key-value pairs edited from the admin settings page
+CREATE TABLE IF NOT EXISTS settings (
+    key TEXT PRIMARY KEY,
+    value TEXT NOT NULL,
+    updated_at DATETIME DEFAULT CURRENT_TIMESTAMP
+);
+
+-- Albums can show their cover photo as a header on share pages
+ALTER TABLE albums ADD COLUMN cover_header BOOLEAN NOT NULL DEFAULT 0;